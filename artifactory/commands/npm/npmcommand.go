@@ -95,6 +95,12 @@ func (nc *NpmCommand) SetRepo(repo string) *NpmCommand {
 	return nc
 }
 
+// Sets the directory of the npm project. Defaults to the current working directory.
+func (nc *NpmCommand) SetWorkingDirectory(workingDirectory string) *NpmCommand {
+	nc.workingDirectory = workingDirectory
+	return nc
+}
+
 func (nc *NpmCommand) Init() error {
 	// Read config file.
 	log.Debug("Preparing to read the config file", nc.configFilePath)
@@ -144,9 +150,10 @@ func (nc *NpmCommand) PreparePrerequisites(repo string) error {
 		return err
 	}
 
-	nc.workingDirectory, err = coreutils.GetWorkingDirectory()
-	if err != nil {
-		return err
+	if nc.workingDirectory == "" {
+		if nc.workingDirectory, err = coreutils.GetWorkingDirectory(); err != nil {
+			return err
+		}
 	}
 	log.Debug("Working directory set to:", nc.workingDirectory)
 	if err = nc.setArtifactoryAuth(); err != nil {
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
@@ -14,10 +13,11 @@ import (
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/exp/slices"
 )
 
 const (
-	// The actual field in the repository configuration is an array (plural) but in practice only one environment is allowed.
+	// The field in the repository configuration is an array (plural) of environments, which is asked for as a comma separated list.
 	// This is why the question differs from the repository configuration.
 	environmentsKey = "environments"
 )
@@ -41,9 +41,16 @@ func (rc *RepoCommand) PerformRepoCmd(isUpdate bool) (err error) {
 	if err != nil {
 		return err
 	}
+	warnOnProxyConflict(repoConfigMap)
 	// All the values in the template are strings
 	// Go over the confMap and write the values with the correct type using the writersMap
 	for key, value := range repoConfigMap {
+		if key == ContentSynchronisation {
+			if err = writeContentSynchronisationValue(&repoConfigMap, key, value); err != nil {
+				return
+			}
+			continue
+		}
 		if err = utils.ValidateMapEntry(key, value, writersMap); err != nil {
 			return
 		}
@@ -80,7 +87,27 @@ func (rc *RepoCommand) PerformRepoCmd(isUpdate bool) (err error) {
 	if handlerFunc == nil {
 		return errors.New("unsupported package type: " + packageType)
 	}
-	return handlerFunc(servicesManager, content, isUpdate)
+	if err = handlerFunc(servicesManager, content, isUpdate); err != nil {
+		return err
+	}
+	return setRemoteRepoDisableProxy(servicesManager, repoConfigMap)
+}
+
+// jfrog-client-go's remote repository params have no disableProxy, so the remote repositories are created and updated without it.
+// It's set afterwards by a partial update of the repository, which keeps the rest of its configuration.
+type remoteRepoDisableProxyParams struct {
+	Key          string `json:"key"`
+	Rclass       string `json:"rclass"`
+	DisableProxy bool   `json:"disableProxy"`
+}
+
+func setRemoteRepoDisableProxy(servicesManager artifactory.ArtifactoryServicesManager, repoConfigMap map[string]interface{}) error {
+	disableProxy, exists := repoConfigMap[DisableProxy].(bool)
+	if !exists || repoConfigMap[Rclass] != Remote {
+		return nil
+	}
+	params := remoteRepoDisableProxyParams{Key: fmt.Sprint(repoConfigMap[Key]), Rclass: Remote, DisableProxy: disableProxy}
+	return performRepoCmdWithParams(servicesManager, params, params.Key, true)
 }
 
 var writersMap = map[string]ioutils.AnswerWriter{
@@ -95,7 +122,7 @@ var writersMap = map[string]ioutils.AnswerWriter{
 	ExcludePatterns:                   ioutils.WriteStringAnswer,
 	RepoLayoutRef:                     ioutils.WriteStringAnswer,
 	ProjectKey:                        ioutils.WriteStringAnswer,
-	environmentsKey:                   ioutils.WriteStringArrayAnswer,
+	environmentsKey:                   writeEnvironmentsAnswer,
 	HandleReleases:                    ioutils.WriteBoolAnswer,
 	HandleSnapshots:                   ioutils.WriteBoolAnswer,
 	MaxUniqueSnapshots:                ioutils.WriteIntAnswer,
@@ -103,6 +130,8 @@ var writersMap = map[string]ioutils.AnswerWriter{
 	BlackedOut:                        ioutils.WriteBoolAnswer,
 	DownloadRedirect:                  ioutils.WriteBoolAnswer,
 	BlockPushingSchema1:               ioutils.WriteBoolAnswer,
+	PriorityResolution:                ioutils.WriteBoolAnswer,
+	CdnRedirect:                       ioutils.WriteBoolAnswer,
 	DebianTrivialLayout:               ioutils.WriteBoolAnswer,
 	ExternalDependenciesEnabled:       ioutils.WriteBoolAnswer,
 	ExternalDependenciesPatterns:      ioutils.WriteStringArrayAnswer,
@@ -117,9 +146,12 @@ var writersMap = map[string]ioutils.AnswerWriter{
 	DockerApiVersion:                  ioutils.WriteStringAnswer,
 	EnableFileListsIndexing:           ioutils.WriteBoolAnswer,
 	OptionalIndexCompressionFormats:   ioutils.WriteStringArrayAnswer,
+	PrimaryKeyPairRef:                 ioutils.WriteStringAnswer,
+	SecondaryKeyPairRef:               ioutils.WriteStringAnswer,
 	Username:                          ioutils.WriteStringAnswer,
 	Password:                          ioutils.WriteStringAnswer,
 	Proxy:                             ioutils.WriteStringAnswer,
+	DisableProxy:                      ioutils.WriteBoolAnswer,
 	RemoteRepoChecksumPolicyType:      ioutils.WriteStringAnswer,
 	HardFail:                          ioutils.WriteBoolAnswer,
 	Offline:                           ioutils.WriteBoolAnswer,
@@ -154,6 +186,11 @@ var writersMap = map[string]ioutils.AnswerWriter{
 	ListRemoteFolderItems:             ioutils.WriteBoolAnswer,
 	RejectInvalidJars:                 ioutils.WriteBoolAnswer,
 	PodsSpecsRepoUrl:                  ioutils.WriteStringAnswer,
+	GitRegistryUrl:                    ioutils.WriteStringAnswer,
+	CargoInternalIndex:                ioutils.WriteBoolAnswer,
+	CargoAnonymousAccess:              ioutils.WriteBoolAnswer,
+	TerraformType:                     writeTerraformType,
+	HuggingFaceRegistryUrl:            ioutils.WriteStringAnswer,
 	EnableTokenAuthentication:         ioutils.WriteBoolAnswer,
 	Repositories:                      ioutils.WriteStringArrayAnswer,
 	ArtifactoryRequestsCanRetrieveRemoteArtifacts: ioutils.WriteBoolAnswer,
@@ -163,47 +200,53 @@ var writersMap = map[string]ioutils.AnswerWriter{
 	ForceMavenAuthentication:             ioutils.WriteBoolAnswer,
 	ForceNugetAuthentication:             ioutils.WriteBoolAnswer,
 	ExternalDependenciesRemoteRepo:       ioutils.WriteStringAnswer,
+	Members:                              writeFederatedMembers,
 }
 
 func writeContentSynchronisation(resultMap *map[string]interface{}, key, value string) error {
-	answerArray := strings.Split(value, ",")
-	if len(answerArray) != 4 {
-		return errors.New("invalid value for Content Synchronisation")
-	}
-	var cs services.ContentSynchronisation
+	return writeContentSynchronisationValue(resultMap, key, value)
+}
 
-	enabled, err := strconv.ParseBool(answerArray[0])
-	if errorutils.CheckError(err) != nil {
+// contentSynchronisation is written to the template as a nested object, so unlike the other keys, its value isn't necessarily a string.
+func writeContentSynchronisationValue(resultMap *map[string]interface{}, key string, value interface{}) error {
+	contentSynchronisation, err := ParseContentSynchronisation(value)
+	if err != nil {
 		return err
 	}
-	cs.Enabled = &enabled
+	(*resultMap)[key] = contentSynchronisation
+	return nil
+}
 
-	enabled, err = strconv.ParseBool(answerArray[1])
-	if errorutils.CheckError(err) != nil {
-		return err
+// A single environment is written as a list with one environment, for backward compatibility with templates of a single environment.
+func writeEnvironmentsAnswer(resultMap *map[string]interface{}, key, value string) error {
+	if ioutils.VarPattern.MatchString(value) {
+		return ioutils.WriteStringAnswer(resultMap, key, value)
 	}
-	cs.Statistics = &services.ContentSynchronisationStatistics{
-		Enabled: &enabled,
+	environments := []string{}
+	for _, environment := range strings.Split(value, ",") {
+		environment = strings.TrimSpace(environment)
+		if environment != "" && !slices.Contains(environments, environment) {
+			environments = append(environments, environment)
+		}
 	}
+	(*resultMap)[key] = environments
+	return nil
+}
 
-	enabled, err = strconv.ParseBool(answerArray[2])
-	if errorutils.CheckError(err) != nil {
+func writeFederatedMembers(resultMap *map[string]interface{}, key, value string) error {
+	members, err := ParseFederatedMembers(value)
+	if err != nil {
 		return err
 	}
-	cs.Properties = &services.ContentSynchronisationProperties{
-		Enabled: &enabled,
-	}
+	(*resultMap)[key] = members
+	return nil
+}
 
-	enabled, err = strconv.ParseBool(answerArray[3])
-	if errorutils.CheckError(err) != nil {
-		return err
+func writeTerraformType(resultMap *map[string]interface{}, key, value string) error {
+	if value != TerraformModuleType && value != TerraformProviderType {
+		return errorutils.CheckErrorf("invalid value for %s: '%s'. The supported values are '%s' and '%s'", TerraformType, value, TerraformModuleType, TerraformProviderType)
 	}
-	cs.Source = &services.ContentSynchronisationSource{
-		OriginAbsenceDetection: &enabled,
-	}
-
-	(*resultMap)[key] = cs
-	return nil
+	return ioutils.WriteStringAnswer(resultMap, key, value)
 }
 
 // repoHandler is a function that gets serviceManager, JSON configuration content and a flag indicates is the operation in an update operation
@@ -236,6 +279,9 @@ var localRepoHandlers = map[string]repoHandler{
 	Chef:      localChefHandler,
 	Puppet:    localPuppetHandler,
 	Alpine:    localAlpineHandler,
+	Swift:     localSwiftHandler,
+	Cargo:     localCargoHandler,
+	Terraform: localTerraformHandler,
 	Generic:   localGenericHandler,
 }
 
@@ -589,6 +635,20 @@ func localAlpineHandler(servicesManager artifactory.ArtifactoryServicesManager,
 	return err
 }
 
+func localSwiftHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := services.NewSwiftLocalRepositoryParams()
+	err := json.Unmarshal(jsonConfig, &params)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if isUpdate {
+		err = servicesManager.UpdateLocalRepository().Swift(params)
+	} else {
+		err = servicesManager.CreateLocalRepository().Swift(params)
+	}
+	return err
+}
+
 func localGenericHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
 	params := services.NewGenericLocalRepositoryParams()
 	err := json.Unmarshal(jsonConfig, &params)
@@ -605,34 +665,38 @@ func localGenericHandler(servicesManager artifactory.ArtifactoryServicesManager,
 }
 
 var remoteRepoHandlers = map[string]repoHandler{
-	Maven:     remoteMavenHandler,
-	Gradle:    remoteGradleHandler,
-	Ivy:       remoteIvyHandler,
-	Sbt:       remoteSbtHandler,
-	Helm:      remoteHelmHandler,
-	Cocoapods: remoteCocoapodsHandler,
-	Opkg:      remoteOpkgHandler,
-	Rpm:       remoteRpmHandler,
-	Nuget:     remoteNugetHandler,
-	Cran:      remoteCranHandler,
-	Gems:      remoteGemsHandler,
-	Npm:       remoteNpmHandler,
-	Bower:     remoteBowerHandler,
-	Debian:    remoteDebianHandler,
-	Composer:  remoteComposerHandler,
-	Pypi:      remotePypiHandler,
-	Docker:    remoteDockerHandler,
-	Gitlfs:    remoteGitLfsHandler,
-	Go:        remoteGoHandler,
-	Yum:       remoteYumHandler,
-	Conan:     remoteConanHandler,
-	Chef:      remoteChefHandler,
-	Puppet:    remotePuppetHandler,
-	Conda:     remoteCondaHandler,
-	P2:        remoteP2Handler,
-	Vcs:       remoteVcsHandler,
-	Alpine:    remoteAlpineHandler,
-	Generic:   remoteGenericHandler,
+	Maven:         remoteMavenHandler,
+	Gradle:        remoteGradleHandler,
+	Ivy:           remoteIvyHandler,
+	Sbt:           remoteSbtHandler,
+	Helm:          remoteHelmHandler,
+	Cocoapods:     remoteCocoapodsHandler,
+	Opkg:          remoteOpkgHandler,
+	Rpm:           remoteRpmHandler,
+	Nuget:         remoteNugetHandler,
+	Cran:          remoteCranHandler,
+	Gems:          remoteGemsHandler,
+	Npm:           remoteNpmHandler,
+	Bower:         remoteBowerHandler,
+	Debian:        remoteDebianHandler,
+	Composer:      remoteComposerHandler,
+	Pypi:          remotePypiHandler,
+	Docker:        remoteDockerHandler,
+	Gitlfs:        remoteGitLfsHandler,
+	Go:            remoteGoHandler,
+	Yum:           remoteYumHandler,
+	Conan:         remoteConanHandler,
+	Chef:          remoteChefHandler,
+	Puppet:        remotePuppetHandler,
+	Conda:         remoteCondaHandler,
+	P2:            remoteP2Handler,
+	Vcs:           remoteVcsHandler,
+	Alpine:        remoteAlpineHandler,
+	Swift:         remoteSwiftHandler,
+	Cargo:         remoteCargoHandler,
+	Terraform:     remoteTerraformHandler,
+	HuggingFaceMl: remoteHuggingFaceMlHandler,
+	Generic:       remoteGenericHandler,
 }
 
 func remoteMavenHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
@@ -1013,6 +1077,20 @@ func remoteYumHandler(servicesManager artifactory.ArtifactoryServicesManager, js
 	return err
 }
 
+func remoteSwiftHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := services.NewSwiftRemoteRepositoryParams()
+	err := json.Unmarshal(jsonConfig, &params)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if isUpdate {
+		err = servicesManager.UpdateRemoteRepository().Swift(params)
+	} else {
+		err = servicesManager.CreateRemoteRepository().Swift(params)
+	}
+	return err
+}
+
 func remoteGenericHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
 	params := services.NewGenericRemoteRepositoryParams()
 	err := json.Unmarshal(jsonConfig, &params)
@@ -1052,6 +1130,7 @@ var federatedRepoHandlers = map[string]repoHandler{
 	Chef:      federatedChefHandler,
 	Puppet:    federatedPuppetHandler,
 	Alpine:    federatedAlpineHandler,
+	Swift:     federatedSwiftHandler,
 	Generic:   federatedGenericHandler,
 }
 
@@ -1344,6 +1423,18 @@ func federatedAlpineHandler(servicesManager artifactory.ArtifactoryServicesManag
 	return servicesManager.CreateFederatedRepository().Alpine(params)
 }
 
+func federatedSwiftHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := services.NewSwiftFederatedRepositoryParams()
+	err := json.Unmarshal(jsonConfig, &params)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if isUpdate {
+		return servicesManager.UpdateFederatedRepository().Swift(params)
+	}
+	return servicesManager.CreateFederatedRepository().Swift(params)
+}
+
 func federatedGenericHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
 	params := services.NewGenericFederatedRepositoryParams()
 	err := json.Unmarshal(jsonConfig, &params)
@@ -1358,30 +1449,33 @@ func federatedGenericHandler(servicesManager artifactory.ArtifactoryServicesMana
 }
 
 var virtualRepoHandlers = map[string]repoHandler{
-	Maven:   virtualMavenHandler,
-	Gradle:  virtualGradleHandler,
-	Ivy:     virtualIvyHandler,
-	Sbt:     virtualSbtHandler,
-	Helm:    virtualHelmHandler,
-	Rpm:     virtualRpmHandler,
-	Nuget:   virtualNugetHandler,
-	Cran:    virtualCranHandler,
-	Gems:    virtualGemsHandler,
-	Npm:     virtualNpmHandler,
-	Bower:   virtualBowerHandler,
-	Debian:  virtualDebianHandler,
-	Pypi:    virtualPypiHandler,
-	Docker:  virtualDockerHandler,
-	Gitlfs:  virtualGitLfsHandler,
-	Go:      virtualGoHandler,
-	Yum:     virtualYumHandler,
-	Conan:   virtualConanHandler,
-	Chef:    virtualChefHandler,
-	Puppet:  virtualPuppetHandler,
-	Conda:   virtualCondaHandler,
-	P2:      virtualP2Handler,
-	Alpine:  virtualAlpineHandler,
-	Generic: virtualGenericHandler,
+	Maven:     virtualMavenHandler,
+	Gradle:    virtualGradleHandler,
+	Ivy:       virtualIvyHandler,
+	Sbt:       virtualSbtHandler,
+	Helm:      virtualHelmHandler,
+	Rpm:       virtualRpmHandler,
+	Nuget:     virtualNugetHandler,
+	Cran:      virtualCranHandler,
+	Gems:      virtualGemsHandler,
+	Npm:       virtualNpmHandler,
+	Bower:     virtualBowerHandler,
+	Debian:    virtualDebianHandler,
+	Pypi:      virtualPypiHandler,
+	Docker:    virtualDockerHandler,
+	Gitlfs:    virtualGitLfsHandler,
+	Go:        virtualGoHandler,
+	Yum:       virtualYumHandler,
+	Conan:     virtualConanHandler,
+	Chef:      virtualChefHandler,
+	Puppet:    virtualPuppetHandler,
+	Conda:     virtualCondaHandler,
+	P2:        virtualP2Handler,
+	Alpine:    virtualAlpineHandler,
+	Swift:     virtualSwiftHandler,
+	Cargo:     virtualCargoHandler,
+	Terraform: virtualTerraformHandler,
+	Generic:   virtualGenericHandler,
 }
 
 func virtualMavenHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
@@ -1706,6 +1800,20 @@ func virtualCondaHandler(servicesManager artifactory.ArtifactoryServicesManager,
 	return err
 }
 
+func virtualSwiftHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := services.NewSwiftVirtualRepositoryParams()
+	err := json.Unmarshal(jsonConfig, &params)
+	if errorutils.CheckError(err) != nil {
+		return err
+	}
+	if isUpdate {
+		err = servicesManager.UpdateVirtualRepository().Swift(params)
+	} else {
+		err = servicesManager.CreateVirtualRepository().Swift(params)
+	}
+	return err
+}
+
 func virtualGenericHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
 	params := services.NewGenericVirtualRepositoryParams()
 	err := json.Unmarshal(jsonConfig, &params)
@@ -1719,3 +1827,101 @@ func virtualGenericHandler(servicesManager artifactory.ArtifactoryServicesManage
 	}
 	return err
 }
+
+// jfrog-client-go has no Terraform and Hugging Face repository params, so they are declared here, and the repositories are created and updated with them as is.
+type terraformLocalRepositoryParams struct {
+	services.LocalRepositoryBaseParams
+}
+
+type terraformRemoteRepositoryParams struct {
+	services.RemoteRepositoryBaseParams
+	TerraformType string `json:"terraformType,omitempty"`
+}
+
+type terraformVirtualRepositoryParams struct {
+	services.VirtualRepositoryBaseParams
+}
+
+func localTerraformHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := terraformLocalRepositoryParams{LocalRepositoryBaseParams: services.NewLocalRepositoryPackageParams(Terraform)}
+	if err := json.Unmarshal(jsonConfig, &params); errorutils.CheckError(err) != nil {
+		return err
+	}
+	return performRepoCmdWithParams(servicesManager, params, params.Key, isUpdate)
+}
+
+func remoteTerraformHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := terraformRemoteRepositoryParams{RemoteRepositoryBaseParams: services.NewRemoteRepositoryPackageParams(Terraform)}
+	if err := json.Unmarshal(jsonConfig, &params); errorutils.CheckError(err) != nil {
+		return err
+	}
+	return performRepoCmdWithParams(servicesManager, params, params.Key, isUpdate)
+}
+
+func virtualTerraformHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := terraformVirtualRepositoryParams{VirtualRepositoryBaseParams: services.NewVirtualRepositoryPackageParams(Terraform)}
+	if err := json.Unmarshal(jsonConfig, &params); errorutils.CheckError(err) != nil {
+		return err
+	}
+	return performRepoCmdWithParams(servicesManager, params, params.Key, isUpdate)
+}
+
+type huggingFaceMlRemoteRepositoryParams struct {
+	services.RemoteRepositoryBaseParams
+	HuggingFaceRegistryUrl string `json:"huggingFaceRegistryUrl,omitempty"`
+}
+
+func remoteHuggingFaceMlHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := huggingFaceMlRemoteRepositoryParams{RemoteRepositoryBaseParams: services.NewRemoteRepositoryPackageParams(HuggingFaceMl)}
+	if err := json.Unmarshal(jsonConfig, &params); errorutils.CheckError(err) != nil {
+		return err
+	}
+	return performRepoCmdWithParams(servicesManager, params, params.Key, isUpdate)
+}
+
+// The Cargo repository params of jfrog-client-go don't declare cargoInternalIndex, and it has no Cargo virtual repository params, so they are extended here.
+type cargoLocalRepositoryParams struct {
+	services.CargoLocalRepositoryParams
+	CargoInternalIndex *bool `json:"cargoInternalIndex,omitempty"`
+}
+
+type cargoRemoteRepositoryParams struct {
+	services.CargoRemoteRepositoryParams
+	CargoInternalIndex *bool `json:"cargoInternalIndex,omitempty"`
+}
+
+type cargoVirtualRepositoryParams struct {
+	services.VirtualRepositoryBaseParams
+	CargoInternalIndex *bool `json:"cargoInternalIndex,omitempty"`
+}
+
+func localCargoHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := cargoLocalRepositoryParams{CargoLocalRepositoryParams: services.NewCargoLocalRepositoryParams()}
+	if err := json.Unmarshal(jsonConfig, &params); errorutils.CheckError(err) != nil {
+		return err
+	}
+	return performRepoCmdWithParams(servicesManager, params, params.Key, isUpdate)
+}
+
+func remoteCargoHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := cargoRemoteRepositoryParams{CargoRemoteRepositoryParams: services.NewCargoRemoteRepositoryParams()}
+	if err := json.Unmarshal(jsonConfig, &params); errorutils.CheckError(err) != nil {
+		return err
+	}
+	return performRepoCmdWithParams(servicesManager, params, params.Key, isUpdate)
+}
+
+func virtualCargoHandler(servicesManager artifactory.ArtifactoryServicesManager, jsonConfig []byte, isUpdate bool) error {
+	params := cargoVirtualRepositoryParams{VirtualRepositoryBaseParams: services.NewVirtualRepositoryPackageParams(Cargo)}
+	if err := json.Unmarshal(jsonConfig, &params); errorutils.CheckError(err) != nil {
+		return err
+	}
+	return performRepoCmdWithParams(servicesManager, params, params.Key, isUpdate)
+}
+
+func performRepoCmdWithParams(servicesManager artifactory.ArtifactoryServicesManager, params interface{}, repoKey string, isUpdate bool) error {
+	if isUpdate {
+		return servicesManager.UpdateRepositoryWithParams(params, repoKey)
+	}
+	return servicesManager.CreateRepositoryWithParams(params, repoKey)
+}
@@ -2,26 +2,45 @@ package repository
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/c-bata/go-prompt"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	rtUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/ioutils"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
 )
 
 type RepoTemplateCommand struct {
-	path string
+	path          string
+	serverDetails *config.ServerDetails
+	previewDiff   bool
+	keyConvention string
+	values        map[string]string
+	format        string
 }
 
 const (
 	// Strings for prompt questions
 	SelectConfigKeyMsg = "Select the next configuration key" + ioutils.PressTabMsg
+	InsertRepoKeyMsg   = "Insert the repository key >"
+	// Entering this value for a patterns list writes an explicitly empty value to the template, instead of omitting the key.
+	ExplicitEmptyValue = ":empty"
 
 	TemplateType = "templateType"
 	Create       = "create"
@@ -29,6 +48,18 @@ const (
 
 	MandatoryUrl = "mandatoryUrl"
 
+	// Template file formats
+	JsonTemplateFormat = "json"
+	YamlTemplateFormat = "yaml"
+
+	// Repository key naming convention placeholders
+	teamPlaceholder        = "{team}"
+	packageTypePlaceholder = "{packageType}"
+	rclassPlaceholder      = "{rclass}"
+	envPlaceholder         = "{env}"
+	teamAnswerKey          = "team"
+	maxRepoKeyLength       = 64
+
 	// Common repository configuration JSON keys
 	Key             = "key"
 	Rclass          = "rclass"
@@ -51,11 +82,16 @@ const (
 	PropertySets                 = "propertySets"
 	DownloadRedirect             = "downloadRedirect"
 	BlockPushingSchema1          = "blockPushingSchema1"
+	CargoInternalIndex           = "cargoInternalIndex"
+	CargoAnonymousAccess         = "cargoAnonymousAccess"
+	PriorityResolution           = "priorityResolution"
+	CdnRedirect                  = "cdnRedirect"
 
 	// Mutual local and virtual repository configuration JSON keys
 	DebianTrivialLayout             = "debianTrivialLayout"
 	OptionalIndexCompressionFormats = "optionalIndexCompressionFormats"
 	PrimaryKeyPairRef               = "primaryKeyPairRef"
+	SecondaryKeyPairRef             = "secondaryKeyPairRef"
 
 	// Mutual remote and virtual repository configuration JSON keys
 	ExternalDependenciesEnabled  = "externalDependenciesEnabled"
@@ -77,6 +113,7 @@ const (
 	Username                          = "username"
 	Password                          = "password"
 	Proxy                             = "proxy"
+	DisableProxy                      = "disableProxy"
 	RemoteRepoChecksumPolicyType      = "remoteRepoChecksumPolicyType"
 	HardFail                          = "hardFail"
 	Offline                           = "offline"
@@ -112,6 +149,9 @@ const (
 	ListRemoteFolderItems             = "listRemoteFolderItems"
 	EnableTokenAuthentication         = "enableTokenAuthentication"
 	PodsSpecsRepoUrl                  = "podsSpecsRepoUrl"
+	GitRegistryUrl                    = "gitRegistryUrl"
+	TerraformType                     = "terraformType"
+	HuggingFaceRegistryUrl            = "huggingFaceRegistryUrl"
 
 	// Unique virtual repository configuration JSON keys
 	Repositories                                  = "repositories"
@@ -122,6 +162,9 @@ const (
 	ForceMavenAuthentication                      = "forceMavenAuthentication"
 	ExternalDependenciesRemoteRepo                = "externalDependenciesRemoteRepo"
 
+	// Unique federated repository configuration JSON keys
+	Members = "members"
+
 	// rclasses
 	Local     = "local"
 	Remote    = "remote"
@@ -129,35 +172,39 @@ const (
 	Federated = "federated"
 
 	// PackageTypes
-	Generic   = "generic"
-	Maven     = "maven"
-	Gradle    = "gradle"
-	Ivy       = "ivy"
-	Sbt       = "sbt"
-	Helm      = "helm"
-	Cocoapods = "cocoapods"
-	Opkg      = "opkg"
-	Rpm       = "rpm"
-	Nuget     = "nuget"
-	Cran      = "cran"
-	Gems      = "gems"
-	Npm       = "npm"
-	Bower     = "bower"
-	Debian    = "debian"
-	Composer  = "composer"
-	Pypi      = "pypi"
-	Docker    = "docker"
-	Vagrant   = "vagrant"
-	Gitlfs    = "gitlfs"
-	Go        = "go"
-	Yum       = "yum"
-	Conan     = "conan"
-	Chef      = "chef"
-	Puppet    = "puppet"
-	Vcs       = "vcs"
-	Alpine    = "alpine"
-	Conda     = "conda"
-	P2        = "p2"
+	Generic       = "generic"
+	Maven         = "maven"
+	Gradle        = "gradle"
+	Ivy           = "ivy"
+	Sbt           = "sbt"
+	Helm          = "helm"
+	Cocoapods     = "cocoapods"
+	Opkg          = "opkg"
+	Rpm           = "rpm"
+	Nuget         = "nuget"
+	Cran          = "cran"
+	Gems          = "gems"
+	Npm           = "npm"
+	Bower         = "bower"
+	Debian        = "debian"
+	Composer      = "composer"
+	Pypi          = "pypi"
+	Docker        = "docker"
+	Vagrant       = "vagrant"
+	Gitlfs        = "gitlfs"
+	Go            = "go"
+	Yum           = "yum"
+	Conan         = "conan"
+	Chef          = "chef"
+	Puppet        = "puppet"
+	Vcs           = "vcs"
+	Alpine        = "alpine"
+	Conda         = "conda"
+	P2            = "p2"
+	Swift         = "swift"
+	Cargo         = "cargo"
+	Terraform     = "terraform"
+	HuggingFaceMl = "huggingfaceml"
 
 	// Repo layout Refs
 	BowerDefaultRepoLayout    = "bower-default"
@@ -174,6 +221,7 @@ const (
 	puppetDefaultRepoLayout   = "puppet-default"
 	SbtDefaultRepoLayout      = "sbt-default"
 	SimpleDefaultRepoLayout   = "simple-default"
+	SwiftDefaultRepoLayout    = "swift-default"
 	VcsDefaultRepoLayout      = "vcs-default"
 
 	// Checksum Policies
@@ -215,6 +263,10 @@ const (
 	DiscardActiveRefrencePolicy = "discard_active_reference"
 	DiscardAnyReferencePolicy   = "discard_any_reference"
 	NothingPolicy               = "nothing"
+
+	// Terraform repository types
+	TerraformModuleType   = "module"
+	TerraformProviderType = "provider"
 )
 
 var optionalSuggestsMap = map[string]prompt.Suggest{
@@ -233,6 +285,8 @@ var optionalSuggestsMap = map[string]prompt.Suggest{
 	BlackedOut:                        {Text: BlackedOut},
 	DownloadRedirect:                  {Text: DownloadRedirect},
 	BlockPushingSchema1:               {Text: BlockPushingSchema1},
+	CdnRedirect:                       {Text: CdnRedirect, Description: "Redirect the download requests of the repository to the CDN"},
+	PriorityResolution:                {Text: PriorityResolution, Description: "Resolve the artifacts from the repository before the other repositories of the virtual repositories that include it"},
 	DebianTrivialLayout:               {Text: DebianTrivialLayout},
 	ExternalDependenciesEnabled:       {Text: ExternalDependenciesEnabled},
 	ExternalDependenciesPatterns:      {Text: ExternalDependenciesPatterns},
@@ -251,7 +305,9 @@ var optionalSuggestsMap = map[string]prompt.Suggest{
 	Username:                          {Text: Username},
 	Password:                          {Text: Password},
 	Proxy:                             {Text: Proxy},
+	DisableProxy:                      {Text: DisableProxy},
 	PrimaryKeyPairRef:                 {Text: PrimaryKeyPairRef},
+	SecondaryKeyPairRef:               {Text: SecondaryKeyPairRef, Description: "An additional key pair to sign the metadata with"},
 	RemoteRepoChecksumPolicyType:      {Text: RemoteRepoChecksumPolicyType},
 	HardFail:                          {Text: HardFail},
 	Offline:                           {Text: Offline},
@@ -286,6 +342,11 @@ var optionalSuggestsMap = map[string]prompt.Suggest{
 	ContentSynchronisation:            {Text: ContentSynchronisation},
 	ListRemoteFolderItems:             {Text: ListRemoteFolderItems},
 	PodsSpecsRepoUrl:                  {Text: PodsSpecsRepoUrl},
+	GitRegistryUrl:                    {Text: GitRegistryUrl, Description: "The URL of the git index of the remote Cargo registry"},
+	CargoInternalIndex:                {Text: CargoInternalIndex, Description: "Index the crates in Artifactory, instead of using the git index of the registry"},
+	CargoAnonymousAccess:              {Text: CargoAnonymousAccess, Description: "Allow anonymous users to download crates from the repository"},
+	TerraformType:                     {Text: TerraformType, Description: "Whether the repository proxies Terraform modules or providers"},
+	HuggingFaceRegistryUrl:            {Text: HuggingFaceRegistryUrl, Description: "The URL of the Hugging Face Hub API of the remote registry"},
 	EnableTokenAuthentication:         {Text: EnableTokenAuthentication},
 	Repositories:                      {Text: Repositories},
 	ArtifactoryRequestsCanRetrieveRemoteArtifacts: {Text: ArtifactoryRequestsCanRetrieveRemoteArtifacts},
@@ -295,11 +356,13 @@ var optionalSuggestsMap = map[string]prompt.Suggest{
 	ForceMavenAuthentication:             {Text: ForceMavenAuthentication},
 	ForceNugetAuthentication:             {Text: ForceNugetAuthentication},
 	ExternalDependenciesRemoteRepo:       {Text: ExternalDependenciesRemoteRepo},
+	Members:                              {Text: Members, Description: "The URLs of the member repositories of the federation"},
 }
 
 var baseLocalRepoConfKeys = []string{
 	Description, Notes, IncludePatterns, ExcludePatterns, RepoLayoutRef, ProjectKey, Environment, BlackedOut, XrayIndex,
 	PropertySets, ArchiveBrowsingEnabled, OptionalIndexCompressionFormats, DownloadRedirect, BlockPushingSchema1,
+	PriorityResolution, CdnRedirect,
 }
 
 var mavenGradleLocalRepoConfKeys = []string{
@@ -307,7 +370,7 @@ var mavenGradleLocalRepoConfKeys = []string{
 }
 
 var rpmLocalRepoConfKeys = []string{
-	YumRootDepth, CalculateYumMetadata, EnableFileListsIndexing, PrimaryKeyPairRef,
+	YumRootDepth, CalculateYumMetadata, EnableFileListsIndexing, PrimaryKeyPairRef, SecondaryKeyPairRef,
 }
 
 var nugetLocalRepoConfKeys = []string{
@@ -315,19 +378,28 @@ var nugetLocalRepoConfKeys = []string{
 }
 
 var debianLocalRepoConfKeys = []string{
-	DebianTrivialLayout, PrimaryKeyPairRef,
+	DebianTrivialLayout, PrimaryKeyPairRef, SecondaryKeyPairRef,
+}
+
+var alpineLocalRepoConfKeys = []string{
+	PrimaryKeyPairRef,
 }
 
 var dockerLocalRepoConfKeys = []string{
 	DockerApiVersion, MaxUniqueTags,
 }
 
+var cargoLocalRepoConfKeys = []string{
+	CargoInternalIndex, CargoAnonymousAccess,
+}
+
 var baseRemoteRepoConfKeys = []string{
-	Username, Password, Proxy, Description, Notes, IncludePatterns, ExcludePatterns, RepoLayoutRef, ProjectKey, Environment, HardFail, Offline,
+	Username, Password, Proxy, DisableProxy, Description, Notes, IncludePatterns, ExcludePatterns, RepoLayoutRef, ProjectKey, Environment, HardFail, Offline,
 	BlackedOut, XrayIndex, StoreArtifactsLocally, SocketTimeoutMillis, LocalAddress, RetrievalCachePeriodSecs, FailedRetrievalCachePeriodSecs,
 	MissedRetrievalCachePeriodSecs, UnusedArtifactsCleanupEnabled, UnusedArtifactsCleanupPeriodHours, AssumedOfflinePeriodSecs,
 	ShareConfiguration, SynchronizeProperties, BlockMismatchingMimeTypes, PropertySets, AllowAnyHostAuth, EnableCookieManagement,
-	BypassHeadRequests, ClientTlsCertificate, DownloadRedirect, BlockPushingSchema1, ContentSynchronisation,
+	BypassHeadRequests, ClientTlsCertificate, DownloadRedirect, BlockPushingSchema1, ContentSynchronisation, PriorityResolution,
+	CdnRedirect,
 }
 
 var mavenGradleRemoteRepoConfKeys = []string{
@@ -371,6 +443,18 @@ var composerRemoteRepoConfKeys = []string{
 	ComposerRegistryUrl,
 }
 
+var cargoRemoteRepoConfKeys = []string{
+	GitRegistryUrl, CargoInternalIndex, CargoAnonymousAccess,
+}
+
+var terraformRemoteRepoConfKeys = []string{
+	TerraformType,
+}
+
+var huggingFaceMlRemoteRepoConfKeys = []string{
+	HuggingFaceRegistryUrl,
+}
+
 var pypiRemoteRepoConfKeys = []string{
 	PyPIRegistryUrl, ListRemoteFolderItems,
 }
@@ -389,7 +473,7 @@ var vcsRemoteRepoConfKeys = []string{
 
 var baseVirtualRepoConfKeys = []string{
 	Repositories, Description, Notes, IncludePatterns, ExcludePatterns, RepoLayoutRef, ProjectKey, Environment, ArtifactoryRequestsCanRetrieveRemoteArtifacts,
-	DefaultDeploymentRepo, OptionalIndexCompressionFormats, PrimaryKeyPairRef,
+	DefaultDeploymentRepo, OptionalIndexCompressionFormats, PrimaryKeyPairRef, SecondaryKeyPairRef,
 }
 
 var mavenGradleVirtualRepoConfKeys = []string{
@@ -416,21 +500,29 @@ var goVirtualRepoConfKeys = []string{
 	ExternalDependenciesEnabled, ExternalDependenciesPatterns,
 }
 
+var cargoVirtualRepoConfKeys = []string{
+	CargoInternalIndex,
+}
+
 var commonPkgTypes = []string{
 	Maven, Gradle, Ivy, Sbt, Helm, Rpm, Nuget, Cran, Gems, Npm, Bower, Debian, Pypi, Docker, Gitlfs, Go, Conan,
-	Chef, Puppet, Alpine, Generic,
+	Chef, Puppet, Alpine, Swift, Generic,
 }
 
 var localRepoAdditionalPkgTypes = []string{
-	Cocoapods, Opkg, Composer, Vagrant, Yum,
+	Cocoapods, Opkg, Composer, Vagrant, Yum, Cargo, Terraform,
 }
 
 var remoteRepoAdditionalPkgTypes = []string{
-	Cocoapods, Opkg, Composer, Conda, P2, Vcs, Yum,
+	Cocoapods, Opkg, Composer, Conda, P2, Vcs, Yum, Cargo, Terraform, HuggingFaceMl,
 }
 
 var virtualRepoAdditionalPkgTypes = []string{
-	Conda, P2, Yum,
+	Conda, P2, Yum, Cargo, Terraform,
+}
+
+var baseFederatedRepoConfKeys = []string{
+	Members,
 }
 
 var federatedRepoAdditionalPkgTypes = []string{
@@ -438,35 +530,45 @@ var federatedRepoAdditionalPkgTypes = []string{
 }
 
 var pkgTypeSuggestsMap = map[string]prompt.Suggest{
-	Generic:   {Text: Generic},
-	Maven:     {Text: Maven},
-	Gradle:    {Text: Gradle},
-	Ivy:       {Text: Ivy},
-	Sbt:       {Text: Sbt},
-	Helm:      {Text: Helm},
-	Cocoapods: {Text: Cocoapods},
-	Opkg:      {Text: Opkg},
-	Rpm:       {Text: Rpm},
-	Nuget:     {Text: Nuget},
-	Cran:      {Text: Cran},
-	Gems:      {Text: Gems},
-	Npm:       {Text: Npm},
-	Bower:     {Text: Bower},
-	Debian:    {Text: Debian},
-	Composer:  {Text: Composer},
-	Pypi:      {Text: Pypi},
-	Docker:    {Text: Docker},
-	Vagrant:   {Text: Vagrant},
-	Gitlfs:    {Text: Gitlfs},
-	Go:        {Text: Go},
-	Yum:       {Text: Yum},
-	Conan:     {Text: Conan},
-	Chef:      {Text: Chef},
-	Puppet:    {Text: Puppet},
-	Vcs:       {Text: Vcs},
-	Conda:     {Text: Conda},
-	P2:        {Text: P2},
-	Alpine:    {Text: Alpine},
+	Generic:       {Text: Generic},
+	Maven:         {Text: Maven},
+	Gradle:        {Text: Gradle},
+	Ivy:           {Text: Ivy},
+	Sbt:           {Text: Sbt},
+	Helm:          {Text: Helm},
+	Cocoapods:     {Text: Cocoapods},
+	Opkg:          {Text: Opkg},
+	Rpm:           {Text: Rpm},
+	Nuget:         {Text: Nuget},
+	Cran:          {Text: Cran},
+	Gems:          {Text: Gems},
+	Npm:           {Text: Npm},
+	Bower:         {Text: Bower},
+	Debian:        {Text: Debian},
+	Composer:      {Text: Composer},
+	Pypi:          {Text: Pypi},
+	Docker:        {Text: Docker},
+	Vagrant:       {Text: Vagrant},
+	Gitlfs:        {Text: Gitlfs},
+	Go:            {Text: Go},
+	Yum:           {Text: Yum},
+	Conan:         {Text: Conan},
+	Chef:          {Text: Chef},
+	Puppet:        {Text: Puppet},
+	Vcs:           {Text: Vcs},
+	Conda:         {Text: Conda},
+	P2:            {Text: P2},
+	Alpine:        {Text: Alpine},
+	Swift:         {Text: Swift},
+	Cargo:         {Text: Cargo},
+	Terraform:     {Text: Terraform},
+	HuggingFaceMl: {Text: HuggingFaceMl},
+}
+
+// The repository layouts that the templates of new repositories of these package types get by default, since the default layout of Artifactory doesn't suit them.
+// The layout can still be changed by answering the repoLayoutRef optional key.
+var defaultRepoLayoutRefs = map[string]string{
+	Swift: SwiftDefaultRepoLayout,
 }
 
 func NewRepoTemplateCommand() *RepoTemplateCommand {
@@ -478,6 +580,41 @@ func (rtc *RepoTemplateCommand) SetTemplatePath(path string) *RepoTemplateComman
 	return rtc
 }
 
+// Server details are used to fetch the current configuration of the repository, when previewing the changes of an update template,
+// and to suggest the key pairs and the repository layouts that are configured in Artifactory.
+func (rtc *RepoTemplateCommand) SetServerDetails(serverDetails *config.ServerDetails) *RepoTemplateCommand {
+	rtc.serverDetails = serverDetails
+	return rtc
+}
+
+// When enabled and creating an update template, the keys that the template changes and the keys it leaves untouched are shown before the template is written.
+func (rtc *RepoTemplateCommand) SetPreviewDiff(previewDiff bool) *RepoTemplateCommand {
+	rtc.previewDiff = previewDiff
+	return rtc
+}
+
+// When set, the repository key is computed from the given naming convention instead of being asked for.
+// The convention may include the {team}, {packageType}, {rclass} and {env} placeholders, for example: "{team}-{packageType}-{rclass}-{env}".
+func (rtc *RepoTemplateCommand) SetKeyConvention(keyConvention string) *RepoTemplateCommand {
+	rtc.keyConvention = keyConvention
+	return rtc
+}
+
+// When set, the template is generated from the given values, mapped by the template keys, instead of asking for them.
+// This allows generating templates in CI pipelines. The values must include templateType, rclass and packageType, key (unless a key convention is set),
+// and url for the templates of new remote repositories. The rest of the keys must be allowed for the chosen rclass and package type.
+func (rtc *RepoTemplateCommand) SetValues(values map[string]string) *RepoTemplateCommand {
+	rtc.values = values
+	return rtc
+}
+
+// The format of the template file: json (default) or yaml.
+// Both formats write the keys in alphabetical order, so the diffs between versions of a template are readable.
+func (rtc *RepoTemplateCommand) SetFormat(format string) *RepoTemplateCommand {
+	rtc.format = format
+	return rtc
+}
+
 func (rtc *RepoTemplateCommand) ServerDetails() (*config.ServerDetails, error) {
 	// Since it's a local command, usage won't be reported.
 	return nil, nil
@@ -488,15 +625,33 @@ func (rtc *RepoTemplateCommand) Run() (err error) {
 	if err != nil {
 		return
 	}
-	repoTemplateQuestionnaire := &ioutils.InteractiveQuestionnaire{
-		MandatoryQuestionsKeys: []string{TemplateType, Key, Rclass},
-		QuestionsMap:           questionMap,
+	if rtc.format != "" && rtc.format != JsonTemplateFormat && rtc.format != YamlTemplateFormat {
+		return errorutils.CheckErrorf("unsupported template format '%s'. The supported formats are: %s, %s", rtc.format, JsonTemplateFormat, YamlTemplateFormat)
+	}
+	var answersMap map[string]interface{}
+	if rtc.values != nil {
+		answersMap, err = rtc.getAnswersFromValues()
+	} else {
+		answersMap, err = rtc.performQuestionnaire()
 	}
-	err = repoTemplateQuestionnaire.Perform()
 	if err != nil {
 		return err
 	}
-	resBytes, err := json.Marshal(repoTemplateQuestionnaire.AnswersMap)
+	warnOnProxyConflict(answersMap)
+	// We don't need the templateType value in the final configuration
+	templateType := answersMap[TemplateType]
+	delete(answersMap, TemplateType)
+	if rtc.previewDiff && templateType == Update && rtc.serverDetails != nil {
+		if err = rtc.previewUpdateDiff(answersMap); err != nil {
+			return err
+		}
+	}
+	var resBytes []byte
+	if rtc.format == YamlTemplateFormat {
+		resBytes, err = marshalYamlTemplate(answersMap)
+	} else {
+		resBytes, err = json.Marshal(answersMap)
+	}
 	if err != nil {
 		return errorutils.CheckError(err)
 	}
@@ -508,14 +663,443 @@ func (rtc *RepoTemplateCommand) Run() (err error) {
 	return nil
 }
 
+func (rtc *RepoTemplateCommand) performQuestionnaire() (map[string]interface{}, error) {
+	repoTemplateQuestionnaire := &ioutils.InteractiveQuestionnaire{
+		MandatoryQuestionsKeys: []string{TemplateType, Key, Rclass},
+		QuestionsMap:           questionMap,
+	}
+	if rtc.serverDetails != nil {
+		repoTemplateQuestionnaire.QuestionsMap = rtc.addKeyPairsSuggestions(repoTemplateQuestionnaire.QuestionsMap)
+		repoTemplateQuestionnaire.QuestionsMap = rtc.addRepoLayoutsSuggestions(repoTemplateQuestionnaire.QuestionsMap)
+	}
+	if rtc.keyConvention != "" {
+		// The key is computed after all the other values were answered, so the project key prefix is added only then.
+		repoTemplateQuestionnaire.MandatoryQuestionsKeys = []string{TemplateType, Rclass}
+		repoTemplateQuestionnaire.QuestionsMap = maps.Clone(repoTemplateQuestionnaire.QuestionsMap)
+		projectKeyQuestion := repoTemplateQuestionnaire.QuestionsMap[ProjectKey]
+		projectKeyQuestion.Callback = nil
+		repoTemplateQuestionnaire.QuestionsMap[ProjectKey] = projectKeyQuestion
+	}
+	if err := repoTemplateQuestionnaire.Perform(); err != nil {
+		return nil, err
+	}
+	if rtc.keyConvention != "" {
+		if err := rtc.setConventionKey(repoTemplateQuestionnaire); err != nil {
+			return nil, err
+		}
+	}
+	return repoTemplateQuestionnaire.AnswersMap, nil
+}
+
+// Write the template answers from the values that were set, validating them as the questionnaire does.
+func (rtc *RepoTemplateCommand) getAnswersFromValues() (map[string]interface{}, error) {
+	values := maps.Clone(rtc.values)
+	iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{}}
+	templateType, err := popMandatoryValue(values, TemplateType, Create, Update)
+	if err != nil {
+		return nil, err
+	}
+	rclass, err := popMandatoryValue(values, Rclass, Local, Remote, Virtual, Federated)
+	if err != nil {
+		return nil, err
+	}
+	pkgType, err := popMandatoryValue(values, PackageType, getRclassPkgTypes(rclass)...)
+	if err != nil {
+		return nil, err
+	}
+	iq.AnswersMap[TemplateType] = templateType
+	iq.AnswersMap[Rclass] = rclass
+	iq.AnswersMap[PackageType] = pkgType
+	if layout, exists := defaultRepoLayoutRefs[pkgType]; exists && templateType == Create {
+		iq.AnswersMap[RepoLayoutRef] = layout
+	}
+	if rclass == Remote && templateType == Create {
+		if iq.AnswersMap[Url], err = popMandatoryValue(values, Url); err != nil {
+			return nil, err
+		}
+	}
+	if rtc.keyConvention == "" {
+		if iq.AnswersMap[Key], err = popMandatoryValue(values, Key); err != nil {
+			return nil, err
+		}
+		if err = validateRepoKeyAnswer(fmt.Sprint(iq.AnswersMap[Key])); err != nil {
+			return nil, err
+		}
+	} else if team, ok := values[teamAnswerKey]; ok {
+		// The team is used only to compute the key from the convention
+		iq.AnswersMap[teamAnswerKey] = team
+		delete(values, teamAnswerKey)
+	}
+
+	allowedKeys := getSuggestsTexts(getOptionalRepoConfKeys(rclass, pkgType, templateType))
+	allowedKeys = slices.DeleteFunc(allowedKeys, func(key string) bool { return key == ioutils.SaveAndExit })
+	keys := maps.Keys(values)
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !slices.Contains(allowedKeys, key) {
+			return nil, errorutils.CheckErrorf("the key '%s' isn't allowed in the template of a %s %s repository. The allowed keys are: %s", key, rclass, pkgType, strings.Join(allowedKeys, ", "))
+		}
+		if err = writeAnswerFromValue(iq, key, values[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	if rtc.keyConvention != "" {
+		if err = rtc.setConventionKey(iq); err != nil {
+			return nil, err
+		}
+	} else if projectKey, ok := iq.AnswersMap[ProjectKey]; ok {
+		if _, err = projectKeyCallback(iq, fmt.Sprint(projectKey)); err != nil {
+			return nil, err
+		}
+	}
+	return iq.AnswersMap, nil
+}
+
+// Remove a mandatory value from the values and return it. If options are given, the value must be one of them.
+func popMandatoryValue(values map[string]string, key string, options ...string) (string, error) {
+	value := strings.TrimSpace(values[key])
+	delete(values, key)
+	if value == "" {
+		return "", errorutils.CheckErrorf("the value of '%s' is missing", key)
+	}
+	if len(options) > 0 && !slices.Contains(options, value) {
+		return "", errorutils.CheckErrorf("invalid value for %s: '%s'. The allowed values are: %s", key, value, strings.Join(options, ", "))
+	}
+	return value, nil
+}
+
+// Write the answer of an optional key from its value, like its question does when it's answered.
+func writeAnswerFromValue(iq *ioutils.InteractiveQuestionnaire, key, value string) error {
+	question := questionMap[key]
+	switch key {
+	case ContentSynchronisation:
+		contentSynchronisation, err := buildContentSynchronisationAnswer(strings.Split(value, ","))
+		if err != nil {
+			return err
+		}
+		iq.AnswersMap[key] = contentSynchronisation
+		return nil
+	}
+	isVar := question.AllowVars && ioutils.VarPattern.MatchString(value)
+	if question.Options != nil && !question.SuggestOnly && !isVar && !slices.Contains(getSuggestsTexts(question.Options), value) {
+		return errorutils.CheckErrorf("invalid value for %s: '%s'. The allowed values are: %s", key, value, strings.Join(getSuggestsTexts(question.Options), ", "))
+	}
+	mapKey := question.MapKey
+	if mapKey == "" {
+		mapKey = key
+	}
+	writer := question.Writer
+	if writer == nil {
+		writer = ioutils.WriteStringAnswer
+	}
+	return writer(&iq.AnswersMap, mapKey, value)
+}
+
+// A remote repository either uses the given proxy or no proxy at all, so setting both proxy and disableProxy is likely a mistake.
+func warnOnProxyConflict(configMap map[string]interface{}) {
+	if proxy, ok := configMap[Proxy]; ok && fmt.Sprint(configMap[DisableProxy]) == ioutils.True {
+		log.Warn(fmt.Sprintf("Both %s '%v' and %s are set. With %s, the repository doesn't use any proxy.", Proxy, proxy, DisableProxy, DisableProxy))
+	}
+}
+
+// The string values of the template are quoted, so they are read back as strings even when they look like other types, or after their vars are replaced.
+func marshalYamlTemplate(answersMap map[string]interface{}) ([]byte, error) {
+	var templateNode yaml.Node
+	if err := templateNode.Encode(answersMap); err != nil {
+		return nil, err
+	}
+	quoteYamlStringValues(&templateNode)
+	return yaml.Marshal(&templateNode)
+}
+
+func quoteYamlStringValues(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		// The content of a mapping node alternates between keys and values
+		for i := 1; i < len(node.Content); i += 2 {
+			quoteYamlStringValues(node.Content[i])
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			quoteYamlStringValues(item)
+		}
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	}
+}
+
 func (rtc *RepoTemplateCommand) CommandName() string {
 	return "rt_repo_template"
 }
 
+// The key pair fields that reference the key pairs configured in Artifactory by their names.
+var keyPairRefKeys = []string{PrimaryKeyPairRef, SecondaryKeyPairRef, KeyPair}
+
+// The key pairs are suggested for the key pair fields, and typing a name that isn't among them shows a warning.
+// If the key pairs can't be fetched (for example, without admin permissions), the fields remain free strings.
+func (rtc *RepoTemplateCommand) addKeyPairsSuggestions(questionsMap map[string]ioutils.QuestionInfo) map[string]ioutils.QuestionInfo {
+	keyPairNames, err := rtc.getKeyPairNames()
+	if err != nil {
+		log.Warn("Couldn't fetch the key pairs from Artifactory, so they won't be suggested:", err.Error())
+		return questionsMap
+	}
+	questionsMap = maps.Clone(questionsMap)
+	for _, key := range keyPairRefKeys {
+		questionsMap[key] = getKeyPairRefQuestionInfo(keyPairNames)
+	}
+	return questionsMap
+}
+
+type keyPair struct {
+	PairName string `json:"pairName"`
+}
+
+// Returns the names of the key pairs that are configured in Artifactory.
+func (rtc *RepoTemplateCommand) getKeyPairNames() ([]string, error) {
+	body, err := rtc.sendGet("api/security/keypair")
+	if err != nil {
+		return nil, err
+	}
+	var keyPairs []keyPair
+	if err = json.Unmarshal(body, &keyPairs); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var keyPairNames []string
+	for _, pair := range keyPairs {
+		keyPairNames = append(keyPairNames, pair.PairName)
+	}
+	return keyPairNames, nil
+}
+
+// The layouts that are configured in Artifactory, including the custom ones, are suggested for the repoLayoutRef field.
+// If the layouts can't be fetched (for example, without admin permissions), the default layouts of Artifactory are suggested.
+func (rtc *RepoTemplateCommand) addRepoLayoutsSuggestions(questionsMap map[string]ioutils.QuestionInfo) map[string]ioutils.QuestionInfo {
+	repoLayoutNames, err := rtc.getRepoLayoutNames()
+	if err != nil {
+		log.Warn("Couldn't fetch the repository layouts from Artifactory, so only the default layouts will be suggested:", err.Error())
+		return questionsMap
+	}
+	questionsMap = maps.Clone(questionsMap)
+	repoLayoutQuestion := questionsMap[RepoLayoutRef]
+	repoLayoutQuestion.Options = ioutils.ConvertToSuggests(repoLayoutNames)
+	questionsMap[RepoLayoutRef] = repoLayoutQuestion
+	return questionsMap
+}
+
+// The repository layouts in the configuration descriptor of Artifactory.
+type repoLayoutsConfig struct {
+	RepoLayouts []struct {
+		Name string `xml:"name"`
+	} `xml:"repoLayouts>repoLayout"`
+}
+
+// Returns the names of the repository layouts that are configured in Artifactory.
+func (rtc *RepoTemplateCommand) getRepoLayoutNames() ([]string, error) {
+	body, err := rtc.sendGet("api/system/configuration")
+	if err != nil {
+		return nil, err
+	}
+	var layoutsConfig repoLayoutsConfig
+	if err = xml.Unmarshal(body, &layoutsConfig); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var repoLayoutNames []string
+	for _, layout := range layoutsConfig.RepoLayouts {
+		repoLayoutNames = append(repoLayoutNames, layout.Name)
+	}
+	if len(repoLayoutNames) == 0 {
+		return nil, errorutils.CheckErrorf("no repository layouts were found in the configuration")
+	}
+	return repoLayoutNames, nil
+}
+
+// Sends a GET request to the given REST API of Artifactory, and returns the response body.
+func (rtc *RepoTemplateCommand) sendGet(restApi string) ([]byte, error) {
+	servicesManager, err := rtUtils.CreateServiceManager(rtc.serverDetails, -1, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	httpClientDetails := servicesManager.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+	resp, body, _, err := servicesManager.Client().SendGet(strings.TrimSuffix(rtc.serverDetails.ArtifactoryUrl, "/")+"/"+restApi, true, &httpClientDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func getKeyPairRefQuestionInfo(keyPairNames []string) ioutils.QuestionInfo {
+	return ioutils.QuestionInfo{
+		Options:     ioutils.ConvertToSuggests(keyPairNames),
+		SuggestOnly: true,
+		Writer:      ioutils.WriteStringAnswer,
+		Callback: func(iq *ioutils.InteractiveQuestionnaire, answer string) (string, error) {
+			warnOnUnknownKeyPair(answer, keyPairNames)
+			return "", nil
+		},
+	}
+}
+
+func warnOnUnknownKeyPair(keyPairName string, keyPairNames []string) {
+	if !slices.Contains(keyPairNames, keyPairName) {
+		log.Warn(fmt.Sprintf("The key pair '%s' isn't configured in Artifactory. The available key pairs are: %s", keyPairName, strings.Join(keyPairNames, ", ")))
+	}
+}
+
+// Compute the repository key from the naming convention and the answered values, and add it to the answers.
+func (rtc *RepoTemplateCommand) setConventionKey(iq *ioutils.InteractiveQuestionnaire) error {
+	if strings.Contains(rtc.keyConvention, teamPlaceholder) {
+		// The team isn't part of the repository configuration, so we ask for it (unless it was given) only to compute the key.
+		if _, ok := iq.AnswersMap[teamAnswerKey]; !ok {
+			if _, err := iq.AskQuestion(teamQuestionInfo); err != nil {
+				return err
+			}
+		}
+		defer delete(iq.AnswersMap, teamAnswerKey)
+	}
+	repoKey, err := computeConventionKey(rtc.keyConvention, iq.AnswersMap)
+	if err != nil {
+		return err
+	}
+	iq.AnswersMap[Key] = repoKey
+	if projectKey, ok := iq.AnswersMap[ProjectKey]; ok {
+		if _, err = projectKeyCallback(iq, fmt.Sprint(projectKey)); err != nil {
+			return err
+		}
+	}
+	return validateRepoKey(fmt.Sprint(iq.AnswersMap[Key]))
+}
+
+// Replace the placeholders of the naming convention with the answered values.
+func computeConventionKey(keyConvention string, answersMap map[string]interface{}) (string, error) {
+	placeholdersValues := map[string]string{
+		teamPlaceholder:        teamAnswerKey,
+		packageTypePlaceholder: PackageType,
+		rclassPlaceholder:      Rclass,
+		envPlaceholder:         environmentsKey,
+	}
+	repoKey := keyConvention
+	for placeholder, answerKey := range placeholdersValues {
+		if !strings.Contains(repoKey, placeholder) {
+			continue
+		}
+		value, ok := answersMap[answerKey]
+		if !ok || fmt.Sprint(value) == "" {
+			return "", errorutils.CheckErrorf("the repository key convention '%s' requires a value for %s, which wasn't provided", keyConvention, placeholder)
+		}
+		if placeholder == envPlaceholder && strings.Contains(fmt.Sprint(value), ",") {
+			return "", errorutils.CheckErrorf("the repository key convention '%s' requires a single environment for %s, but got '%s'", keyConvention, placeholder, value)
+		}
+		repoKey = strings.ReplaceAll(repoKey, placeholder, fmt.Sprint(value))
+	}
+	return repoKey, nil
+}
+
+// Artifactory repository keys are limited in length and may not include spaces or special characters.
+func validateRepoKey(repoKey string) error {
+	if repoKey == "" || len(repoKey) > maxRepoKeyLength {
+		return errorutils.CheckErrorf("the repository key '%s' must be between 1 and %d characters long", repoKey, maxRepoKeyLength)
+	}
+	if !repoKeyPattern.MatchString(repoKey) {
+		return errorutils.CheckErrorf("the repository key '%s' may contain only letters, digits, dots, dashes and underscores", repoKey)
+	}
+	return nil
+}
+
+// The keys of an update template, compared to the current configuration of the repository.
+type templateDiff struct {
+	// Keys whose values will be changed by the template
+	Changed []string
+	// Keys in the template whose values are the same as the current ones
+	Unchanged []string
+	// Keys of the current configuration which are not in the template
+	Untouched []string
+}
+
+// Fetch the current configuration of the repository and show which keys the update template changes.
+func (rtc *RepoTemplateCommand) previewUpdateDiff(templateMap map[string]interface{}) error {
+	diff, err := rtc.getUpdateDiff(templateMap)
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Comparing the template to the current configuration of the '%s' repository:", templateMap[Key]))
+	log.Info("Keys to be changed: " + strings.Join(diff.Changed, ", "))
+	log.Info("Keys with the same values: " + strings.Join(diff.Unchanged, ", "))
+	log.Info("Keys left untouched: " + strings.Join(diff.Untouched, ", "))
+	return nil
+}
+
+func (rtc *RepoTemplateCommand) getUpdateDiff(templateMap map[string]interface{}) (*templateDiff, error) {
+	repoKey, ok := templateMap[Key].(string)
+	if !ok {
+		return nil, errorutils.CheckErrorf("repository key is missing in configuration map")
+	}
+	servicesManager, err := rtUtils.CreateServiceManager(rtc.serverDetails, -1, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	currentConfig := map[string]interface{}{}
+	if err = servicesManager.GetRepository(repoKey, &currentConfig); err != nil {
+		return nil, err
+	}
+	return compareTemplateToConfig(templateMap, currentConfig)
+}
+
+func compareTemplateToConfig(templateMap, currentConfig map[string]interface{}) (*templateDiff, error) {
+	// Write the template values with their actual types, so they can be compared to the current configuration
+	typedTemplate := map[string]interface{}{}
+	for key, value := range templateMap {
+		if key == ContentSynchronisation {
+			if err := writeContentSynchronisationValue(&typedTemplate, key, value); err == nil {
+				continue
+			}
+		} else if writer, ok := writersMap[key]; ok {
+			if err := writer(&typedTemplate, key, fmt.Sprint(value)); err == nil {
+				continue
+			}
+		}
+		// Unknown keys and values that can't be converted (like vars) are compared as is
+		typedTemplate[key] = value
+	}
+	content, err := json.Marshal(typedTemplate)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	typedTemplate = map[string]interface{}{}
+	if err = json.Unmarshal(content, &typedTemplate); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+
+	diff := &templateDiff{}
+	for key, value := range typedTemplate {
+		if currentValue, exists := currentConfig[key]; exists && reflect.DeepEqual(currentValue, value) {
+			diff.Unchanged = append(diff.Unchanged, key)
+		} else {
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+	for key := range currentConfig {
+		if _, exists := typedTemplate[key]; !exists {
+			diff.Untouched = append(diff.Untouched, key)
+		}
+	}
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Unchanged)
+	sort.Strings(diff.Untouched)
+	return diff, nil
+}
+
 func rclassCallback(iq *ioutils.InteractiveQuestionnaire, rclass string) (string, error) {
-	var pkgTypes = commonPkgTypes
-	switch rclass {
-	case Remote:
+	pkgTypes := getRclassPkgTypes(rclass)
+	if pkgTypes == nil {
+		return "", errors.New("unsupported rclass")
+	}
+	if rclass == Remote {
 		// For create template url is mandatory, for update we will allow url as an optional key
 		if _, ok := iq.AnswersMap[TemplateType]; !ok {
 			return "", errors.New("package type is missing in configuration map")
@@ -526,15 +1110,6 @@ func rclassCallback(iq *ioutils.InteractiveQuestionnaire, rclass string) (string
 				return "", err
 			}
 		}
-		pkgTypes = append(pkgTypes, remoteRepoAdditionalPkgTypes...)
-	case Local:
-		pkgTypes = append(pkgTypes, localRepoAdditionalPkgTypes...)
-	case Virtual:
-		pkgTypes = append(pkgTypes, virtualRepoAdditionalPkgTypes...)
-	case Federated:
-		pkgTypes = append(pkgTypes, federatedRepoAdditionalPkgTypes...)
-	default:
-		return "", errors.New("unsupported rclass")
 	}
 	// PackageType is also mandatory. Since the possible types depend on which rcalss was chosen, we ask the question here.
 	var pkgTypeQuestion = ioutils.QuestionInfo{
@@ -555,27 +1130,74 @@ func pkgTypeCallback(iq *ioutils.InteractiveQuestionnaire, pkgType string) (stri
 	if _, ok := iq.AnswersMap[Rclass]; !ok {
 		return "", errors.New("rclass is missing in configuration map")
 	}
-	switch iq.AnswersMap[Rclass] {
-	case Local:
-		iq.OptionalKeysSuggests = getLocalRepoConfKeys(pkgType)
-	case Remote:
+	if layout, exists := defaultRepoLayoutRefs[pkgType]; exists && iq.AnswersMap[TemplateType] == Create {
+		iq.AnswersMap[RepoLayoutRef] = layout
+	}
+	if iq.AnswersMap[Rclass] == Remote {
 		// For update template we need to allow url as an optional key
 		if _, ok := iq.AnswersMap[TemplateType]; !ok {
 			return "", errors.New("package type is missing in configuration map")
 		}
-		iq.OptionalKeysSuggests = getRemoteRepoConfKeys(pkgType, fmt.Sprint(iq.AnswersMap[TemplateType]))
-	case Virtual:
-		iq.OptionalKeysSuggests = getVirtualRepoConfKeys(pkgType)
-	case Federated:
-		iq.OptionalKeysSuggests = getLocalRepoConfKeys(pkgType)
-	default:
+	}
+	iq.OptionalKeysSuggests = getOptionalRepoConfKeys(fmt.Sprint(iq.AnswersMap[Rclass]), pkgType, fmt.Sprint(iq.AnswersMap[TemplateType]))
+	if iq.OptionalKeysSuggests == nil {
 		return "", errors.New("unsupported rclass was configured")
 	}
-	// We don't need the templateType value in the final configuration
-	delete(iq.AnswersMap, TemplateType)
 	return "", nil
 }
 
+// Returns the package types of the given rclass, or nil if the rclass isn't supported.
+func getRclassPkgTypes(rclass string) []string {
+	pkgTypes := slices.Clone(commonPkgTypes)
+	switch rclass {
+	case Local:
+		return append(pkgTypes, localRepoAdditionalPkgTypes...)
+	case Remote:
+		return append(pkgTypes, remoteRepoAdditionalPkgTypes...)
+	case Virtual:
+		return append(pkgTypes, virtualRepoAdditionalPkgTypes...)
+	case Federated:
+		return append(pkgTypes, federatedRepoAdditionalPkgTypes...)
+	}
+	return nil
+}
+
+// Each combination of (rclass,packageType) has its own optional configuration keys. Returns nil if the rclass isn't supported.
+func getOptionalRepoConfKeys(rclass, pkgType, templateType string) []prompt.Suggest {
+	switch rclass {
+	case Local:
+		return getLocalRepoConfKeys(pkgType)
+	case Federated:
+		return getFederatedRepoConfKeys(pkgType)
+	case Remote:
+		return getRemoteRepoConfKeys(pkgType, templateType)
+	case Virtual:
+		return getVirtualRepoConfKeys(pkgType)
+	}
+	return nil
+}
+
+// Artifactory rejects an invalid repository key only when the template is used, so the key is validated here and asked for again until it's valid.
+func repoKeyCallback(iq *ioutils.InteractiveQuestionnaire, repoKey string) (string, error) {
+	for {
+		err := validateRepoKeyAnswer(repoKey)
+		if err == nil {
+			return "", nil
+		}
+		log.Error(err.Error())
+		repoKey = ioutils.AskString("", InsertRepoKeyMsg, false, true)
+		iq.AnswersMap[Key] = repoKey
+	}
+}
+
+// Variables are replaced only when the template is used, so they can't be validated.
+func validateRepoKeyAnswer(repoKey string) error {
+	if ioutils.VarPattern.MatchString(repoKey) {
+		return nil
+	}
+	return validateRepoKey(repoKey)
+}
+
 // Repo key must have a prefix of "<projectKey>-". This callback adds the prefix to the repo key if it is missing.
 func projectKeyCallback(iq *ioutils.InteractiveQuestionnaire, projectKey string) (string, error) {
 	if _, ok := iq.AnswersMap[Key]; !ok {
@@ -591,6 +1213,10 @@ func projectKeyCallback(iq *ioutils.InteractiveQuestionnaire, projectKey string)
 		newRepoKey := requiredProjectPrefix + currentRepoKey
 		log.Info("Repository key should start with the projectKey followed by a dash. Modifying repo key to: '" + newRepoKey + "'.")
 		iq.AnswersMap[Key] = newRepoKey
+		// The prefix may make the key too long
+		if !ioutils.VarPattern.MatchString(currentRepoKey) {
+			return "", validateRepoKey(newRepoKey)
+		}
 	}
 	return "", nil
 }
@@ -607,12 +1233,21 @@ func getLocalRepoConfKeys(pkgType string) []prompt.Suggest {
 		optionalKeys = append(optionalKeys, nugetLocalRepoConfKeys...)
 	case Debian:
 		optionalKeys = append(optionalKeys, debianLocalRepoConfKeys...)
+	case Alpine:
+		optionalKeys = append(optionalKeys, alpineLocalRepoConfKeys...)
 	case Docker:
 		optionalKeys = append(optionalKeys, dockerLocalRepoConfKeys...)
+	case Cargo:
+		optionalKeys = append(optionalKeys, cargoLocalRepoConfKeys...)
 	}
 	return ioutils.GetSuggestsFromKeys(optionalKeys, optionalSuggestsMap)
 }
 
+// Federated repositories have the keys of local repositories, and the members of the federation.
+func getFederatedRepoConfKeys(pkgType string) []prompt.Suggest {
+	return append(getLocalRepoConfKeys(pkgType), ioutils.GetSuggestsFromKeys(baseFederatedRepoConfKeys, optionalSuggestsMap)...)
+}
+
 func getRemoteRepoConfKeys(pkgType, templateType string) []prompt.Suggest {
 	optionalKeys := []string{ioutils.SaveAndExit}
 	if templateType == Update {
@@ -648,6 +1283,12 @@ func getRemoteRepoConfKeys(pkgType, templateType string) []prompt.Suggest {
 		optionalKeys = append(optionalKeys, gitlfsRemoteRepoConfKeys...)
 	case Vcs:
 		optionalKeys = append(optionalKeys, vcsRemoteRepoConfKeys...)
+	case Cargo:
+		optionalKeys = append(optionalKeys, cargoRemoteRepoConfKeys...)
+	case Terraform:
+		optionalKeys = append(optionalKeys, terraformRemoteRepoConfKeys...)
+	case HuggingFaceMl:
+		optionalKeys = append(optionalKeys, huggingFaceMlRemoteRepoConfKeys...)
 	}
 	return ioutils.GetSuggestsFromKeys(optionalKeys, optionalSuggestsMap)
 }
@@ -668,33 +1309,162 @@ func getVirtualRepoConfKeys(pkgType string) []prompt.Suggest {
 		optionalKeys = append(optionalKeys, debianVirtualRepoConfKeys...)
 	case Go:
 		optionalKeys = append(optionalKeys, goVirtualRepoConfKeys...)
+	case Cargo:
+		optionalKeys = append(optionalKeys, cargoVirtualRepoConfKeys...)
 	}
 	return ioutils.GetSuggestsFromKeys(optionalKeys, optionalSuggestsMap)
 }
 
-func contentSynchronisationCallBack(iq *ioutils.InteractiveQuestionnaire, answer string) (value string, err error) {
-	// contentSynchronisation has an object value with 4 bool fields.
-	// We ask for the rest of the values and writes the values in comma separated list.
-	if err != nil {
-		return "", nil
+// The fields of the contentSynchronisation object, in the order they are asked for and listed in the legacy comma separated value.
+var contentSynchronisationFields = []string{"enabled", "statistics.enabled", "properties.enabled", "source.originAbsenceDetection"}
+
+// contentSynchronisation has an object value with 4 bool fields.
+// The answer is the value of enabled. We ask for the rest of the values and write the nested object to the template.
+func contentSynchronisationCallBack(iq *ioutils.InteractiveQuestionnaire, answer string) (string, error) {
+	answers := []string{answer}
+	for _, field := range contentSynchronisationFields[1:] {
+		answers = append(answers, ioutils.AskFromList("", "Insert the value for "+field+" >", false, ioutils.GetBoolSuggests(), ""))
 	}
-	answer += "," + ioutils.AskFromList("", "Insert the value for statistic.enable >", false, ioutils.GetBoolSuggests(), "")
-	// cs.Statistics.Enabled, err = strconv.ParseBool(enabled)
+	contentSynchronisation, err := buildContentSynchronisationAnswer(answers)
 	if err != nil {
-		return "", nil
+		return "", err
 	}
-	answer += "," + ioutils.AskFromList("", "Insert the value for properties.enable >", false, ioutils.GetBoolSuggests(), "")
-	// cs.Properties.Enabled, err = strconv.ParseBool(enabled)
-	if err != nil {
-		return "", nil
+	iq.AnswersMap[ContentSynchronisation] = contentSynchronisation
+	return "", nil
+}
+
+// Builds the nested contentSynchronisation object from the answers of its fields.
+// Each answer must be a boolean, or a variable which is kept as is, to be replaced when the template is used.
+func buildContentSynchronisationAnswer(answers []string) (map[string]interface{}, error) {
+	if len(answers) != len(contentSynchronisationFields) {
+		return nil, errorutils.CheckErrorf("invalid value for %s: expected %d values but got %d", ContentSynchronisation, len(contentSynchronisationFields), len(answers))
 	}
-	answer += "," + ioutils.AskFromList("", "Insert the value for source.originAbsenceDetection >", false, ioutils.GetBoolSuggests(), "")
-	// cs.Source.OriginAbsenceDetection, err = strconv.ParseBool(enabled)
-	if err != nil {
-		return "", nil
+	contentSynchronisation := map[string]interface{}{}
+	for i, field := range contentSynchronisationFields {
+		answer := strings.TrimSpace(answers[i])
+		var value interface{} = answer
+		if !ioutils.VarPattern.MatchString(answer) {
+			enabled, err := strconv.ParseBool(answer)
+			if err != nil {
+				return nil, errorutils.CheckErrorf("invalid value for %s.%s: '%s' isn't a boolean", ContentSynchronisation, field, answers[i])
+			}
+			value = enabled
+		}
+		// Nested fields, like 'statistics.enabled', are written as objects
+		parent, name, nested := strings.Cut(field, ".")
+		if !nested {
+			contentSynchronisation[field] = value
+			continue
+		}
+		if _, ok := contentSynchronisation[parent]; !ok {
+			contentSynchronisation[parent] = map[string]interface{}{}
+		}
+		contentSynchronisation[parent].(map[string]interface{})[name] = value
 	}
-	iq.AnswersMap[ContentSynchronisation] = answer
-	return "", nil
+	return contentSynchronisation, nil
+}
+
+// Parses the template value of contentSynchronisation.
+// The value is either the nested object, or the comma separated list of templates created by older versions: 'enabled,statistics.enabled,properties.enabled,source.originAbsenceDetection'.
+func ParseContentSynchronisation(value interface{}) (*services.ContentSynchronisation, error) {
+	var fieldValues []interface{}
+	switch typedValue := value.(type) {
+	case string:
+		values := strings.Split(typedValue, ",")
+		if len(values) != len(contentSynchronisationFields) {
+			return nil, errorutils.CheckErrorf("invalid value for %s: '%s'. The expected format is '%s'", ContentSynchronisation, typedValue, strings.Join(contentSynchronisationFields, ","))
+		}
+		for _, fieldValue := range values {
+			fieldValues = append(fieldValues, fieldValue)
+		}
+	case map[string]interface{}:
+		for _, field := range contentSynchronisationFields {
+			fieldValue, err := getContentSynchronisationField(typedValue, field)
+			if err != nil {
+				return nil, err
+			}
+			fieldValues = append(fieldValues, fieldValue)
+		}
+	default:
+		return nil, errorutils.CheckErrorf("invalid value for %s: expected an object but got '%v'", ContentSynchronisation, value)
+	}
+
+	enabledValues := make([]*bool, len(fieldValues))
+	for i, fieldValue := range fieldValues {
+		switch typedValue := fieldValue.(type) {
+		case nil:
+			// The field isn't set in the object
+		case bool:
+			enabledValues[i] = &typedValue
+		case string:
+			enabled, err := strconv.ParseBool(strings.TrimSpace(typedValue))
+			if err != nil {
+				return nil, errorutils.CheckErrorf("invalid value for %s.%s: '%s' isn't a boolean", ContentSynchronisation, contentSynchronisationFields[i], typedValue)
+			}
+			enabledValues[i] = &enabled
+		default:
+			return nil, errorutils.CheckErrorf("invalid value for %s.%s: '%v' isn't a boolean", ContentSynchronisation, contentSynchronisationFields[i], fieldValue)
+		}
+	}
+	contentSynchronisation := &services.ContentSynchronisation{Enabled: enabledValues[0]}
+	if enabledValues[1] != nil {
+		contentSynchronisation.Statistics = &services.ContentSynchronisationStatistics{Enabled: enabledValues[1]}
+	}
+	if enabledValues[2] != nil {
+		contentSynchronisation.Properties = &services.ContentSynchronisationProperties{Enabled: enabledValues[2]}
+	}
+	if enabledValues[3] != nil {
+		contentSynchronisation.Source = &services.ContentSynchronisationSource{OriginAbsenceDetection: enabledValues[3]}
+	}
+	return contentSynchronisation, nil
+}
+
+// Returns the value of a field of the contentSynchronisation object, or nil if it isn't set.
+func getContentSynchronisationField(contentSynchronisation map[string]interface{}, field string) (interface{}, error) {
+	parent, name, nested := strings.Cut(field, ".")
+	if !nested {
+		return contentSynchronisation[field], nil
+	}
+	parentValue, ok := contentSynchronisation[parent]
+	if !ok {
+		return nil, nil
+	}
+	parentObject, ok := parentValue.(map[string]interface{})
+	if !ok {
+		return nil, errorutils.CheckErrorf("invalid value for %s.%s: expected an object but got '%v'", ContentSynchronisation, parent, parentValue)
+	}
+	return parentObject[name], nil
+}
+
+func getSuggestsTexts(suggests []prompt.Suggest) (texts []string) {
+	for _, suggest := range suggests {
+		texts = append(texts, suggest.Text)
+	}
+	return
+}
+
+// The members are written to the template as a comma separated list of their URLs, after they are validated.
+func writeMembersAnswer(resultMap *map[string]interface{}, key, value string) error {
+	if !ioutils.VarPattern.MatchString(value) {
+		if _, err := ParseFederatedMembers(value); err != nil {
+			return err
+		}
+	}
+	return ioutils.WriteStringAnswer(resultMap, key, value)
+}
+
+// Parses a comma separated list of the URLs of the member repositories of a federated repository.
+func ParseFederatedMembers(value string) ([]services.FederatedRepositoryMember, error) {
+	enabled := true
+	var members []services.FederatedRepositoryMember
+	for _, memberUrl := range strings.Split(value, ",") {
+		memberUrl = strings.TrimSpace(memberUrl)
+		if !strings.HasPrefix(memberUrl, "http://") && !strings.HasPrefix(memberUrl, "https://") {
+			return nil, errorutils.CheckErrorf("invalid value for %s: '%s' isn't the URL of a repository", Members, memberUrl)
+		}
+		members = append(members, services.FederatedRepositoryMember{Url: memberUrl, Enabled: &enabled})
+	}
+	return members, nil
 }
 
 // Specific writers for repo templates, since all the values in the templates should be written as string
@@ -717,6 +1487,32 @@ var StringListToStringQuestionInfo = ioutils.QuestionInfo{
 	Writer:    ioutils.WriteStringAnswer,
 }
 
+// Patterns lists can be explicitly empty, to override the default patterns when the template is applied.
+var PatternsListToStringQuestionInfo = ioutils.QuestionInfo{
+	Msg:       ioutils.CommaSeparatedListMsg + ". Enter " + ExplicitEmptyValue + " to set an empty list",
+	Options:   nil,
+	AllowVars: true,
+	Writer:    writePatternsListAnswer,
+}
+
+func writePatternsListAnswer(resultMap *map[string]interface{}, key, value string) error {
+	if value == ExplicitEmptyValue {
+		value = ""
+	}
+	return ioutils.WriteStringAnswer(resultMap, key, value)
+}
+
+var repoKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+var teamQuestionInfo = ioutils.QuestionInfo{
+	Msg:          "",
+	PromptPrefix: "Insert the team name for the repository key >",
+	AllowVars:    false,
+	Writer:       ioutils.WriteStringAnswer,
+	MapKey:       teamAnswerKey,
+	Callback:     nil,
+}
+
 var questionMap = map[string]ioutils.QuestionInfo{
 	TemplateType: {
 		Options: []prompt.Suggest{
@@ -740,11 +1536,11 @@ var questionMap = map[string]ioutils.QuestionInfo{
 	},
 	Key: {
 		Msg:          "",
-		PromptPrefix: "Insert the repository key >",
+		PromptPrefix: InsertRepoKeyMsg,
 		AllowVars:    true,
 		Writer:       ioutils.WriteStringAnswer,
 		MapKey:       Key,
-		Callback:     nil,
+		Callback:     repoKeyCallback,
 	},
 	Rclass: {
 		Options: []prompt.Suggest{
@@ -771,8 +1567,8 @@ var questionMap = map[string]ioutils.QuestionInfo{
 	Url:             ioutils.FreeStringQuestionInfo,
 	Description:     ioutils.FreeStringQuestionInfo,
 	Notes:           ioutils.FreeStringQuestionInfo,
-	IncludePatterns: StringListToStringQuestionInfo,
-	ExcludePatterns: StringListToStringQuestionInfo,
+	IncludePatterns: PatternsListToStringQuestionInfo,
+	ExcludePatterns: PatternsListToStringQuestionInfo,
 	RepoLayoutRef: {
 		Options: []prompt.Suggest{
 			{Text: BowerDefaultRepoLayout},
@@ -789,6 +1585,7 @@ var questionMap = map[string]ioutils.QuestionInfo{
 			{Text: puppetDefaultRepoLayout},
 			{Text: SbtDefaultRepoLayout},
 			{Text: SimpleDefaultRepoLayout},
+			{Text: SwiftDefaultRepoLayout},
 			{Text: VcsDefaultRepoLayout},
 		},
 		AllowVars: true,
@@ -801,7 +1598,8 @@ var questionMap = map[string]ioutils.QuestionInfo{
 		Callback:  projectKeyCallback,
 	},
 	Environment: {
-		PromptPrefix: "Insert the name of the environment to assign to >",
+		Msg:          ioutils.CommaSeparatedListMsg,
+		PromptPrefix: "Insert the names of the environments to assign to >",
 		AllowVars:    true,
 		MapKey:       environmentsKey,
 		Writer:       ioutils.WriteStringAnswer,
@@ -813,6 +1611,8 @@ var questionMap = map[string]ioutils.QuestionInfo{
 	BlackedOut:                   BoolToStringQuestionInfo,
 	DownloadRedirect:             BoolToStringQuestionInfo,
 	BlockPushingSchema1:          BoolToStringQuestionInfo,
+	PriorityResolution:           BoolToStringQuestionInfo,
+	CdnRedirect:                  BoolToStringQuestionInfo,
 	DebianTrivialLayout:          BoolToStringQuestionInfo,
 	ExternalDependenciesEnabled:  BoolToStringQuestionInfo,
 	ExternalDependenciesPatterns: StringListToStringQuestionInfo,
@@ -854,10 +1654,12 @@ var questionMap = map[string]ioutils.QuestionInfo{
 		AllowVars: true,
 		Writer:    ioutils.WriteStringAnswer,
 	},
-	PrimaryKeyPairRef: ioutils.FreeStringQuestionInfo,
-	Username: ioutils.FreeStringQuestionInfo,
-	Password: ioutils.FreeStringQuestionInfo,
-	Proxy:    ioutils.FreeStringQuestionInfo,
+	PrimaryKeyPairRef:   ioutils.FreeStringQuestionInfo,
+	SecondaryKeyPairRef: ioutils.FreeStringQuestionInfo,
+	Username:            ioutils.FreeStringQuestionInfo,
+	Password:            ioutils.FreeStringQuestionInfo,
+	Proxy:               ioutils.FreeStringQuestionInfo,
+	DisableProxy:        BoolToStringQuestionInfo,
 	RemoteRepoChecksumPolicyType: {
 		Options: []prompt.Suggest{
 			{Text: GenerateIfAbsentPolicy},
@@ -918,6 +1720,18 @@ var questionMap = map[string]ioutils.QuestionInfo{
 	ListRemoteFolderItems:     BoolToStringQuestionInfo,
 	EnableTokenAuthentication: BoolToStringQuestionInfo,
 	PodsSpecsRepoUrl:          ioutils.FreeStringQuestionInfo,
+	GitRegistryUrl:            ioutils.FreeStringQuestionInfo,
+	HuggingFaceRegistryUrl:    ioutils.FreeStringQuestionInfo,
+	CargoInternalIndex:        BoolToStringQuestionInfo,
+	CargoAnonymousAccess:      BoolToStringQuestionInfo,
+	TerraformType: {
+		Options: []prompt.Suggest{
+			{Text: TerraformModuleType},
+			{Text: TerraformProviderType},
+		},
+		AllowVars: true,
+		Writer:    ioutils.WriteStringAnswer,
+	},
 	ContentSynchronisation: {
 		Options:   ioutils.GetBoolSuggests(),
 		AllowVars: true,
@@ -925,6 +1739,12 @@ var questionMap = map[string]ioutils.QuestionInfo{
 		Callback:  contentSynchronisationCallBack,
 	},
 	Repositories: StringListToStringQuestionInfo,
+	Members: {
+		Msg:          ioutils.CommaSeparatedListMsg,
+		PromptPrefix: "Insert the URLs of the member repositories, such as https://jpd.example.com/artifactory/federated-repo >",
+		AllowVars:    true,
+		Writer:       writeMembersAnswer,
+	},
 	ArtifactoryRequestsCanRetrieveRemoteArtifacts: BoolToStringQuestionInfo,
 	KeyPair: ioutils.FreeStringQuestionInfo,
 	PomRepositoryReferencesCleanupPolicy: {
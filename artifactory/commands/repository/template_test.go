@@ -0,0 +1,659 @@
+package repository
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	commonTests "github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/ioutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUpdateDiff(t *testing.T) {
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/api/repositories/test-repo" {
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"key":"test-repo","rclass":"local","packageType":"maven","description":"old description","handleReleases":true,"maxUniqueSnapshots":5,"propertySets":["artifactory"],"xrayIndex":false}`))
+			assert.NoError(t, err)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+
+	templateCmd := NewRepoTemplateCommand().SetServerDetails(&config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"}).SetPreviewDiff(true)
+	diff, err := templateCmd.getUpdateDiff(map[string]interface{}{
+		Key:                "test-repo",
+		Rclass:             "local",
+		PackageType:        "maven",
+		Description:        "new description",
+		HandleReleases:     "true",
+		MaxUniqueSnapshots: "10",
+		PropertySets:       "artifactory",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{Description, MaxUniqueSnapshots}, diff.Changed)
+	assert.Equal(t, []string{HandleReleases, Key, PackageType, PropertySets, Rclass}, diff.Unchanged)
+	assert.Equal(t, []string{XrayIndex}, diff.Untouched)
+}
+
+func TestComputeConventionKey(t *testing.T) {
+	answers := map[string]interface{}{
+		teamAnswerKey:   "devops",
+		PackageType:     "npm",
+		Rclass:          "remote",
+		environmentsKey: "PROD",
+	}
+	repoKey, err := computeConventionKey("{team}-{packageType}-{rclass}-{env}", answers)
+	assert.NoError(t, err)
+	assert.Equal(t, "devops-npm-remote-PROD", repoKey)
+
+	// A placeholder without an answered value
+	delete(answers, environmentsKey)
+	_, err = computeConventionKey("{team}-{packageType}-{rclass}-{env}", answers)
+	assert.ErrorContains(t, err, envPlaceholder)
+}
+
+func TestSetConventionKey(t *testing.T) {
+	iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{
+		PackageType:     "maven",
+		Rclass:          "local",
+		environmentsKey: "DEV",
+		ProjectKey:      "proj",
+	}}
+	templateCmd := NewRepoTemplateCommand().SetKeyConvention("{packageType}-{rclass}-{env}")
+	assert.NoError(t, templateCmd.setConventionKey(iq))
+	// The computed key is prefixed with the project key
+	assert.Equal(t, "proj-maven-local-DEV", iq.AnswersMap[Key])
+
+	// The computed key must be a valid repository key
+	iq.AnswersMap[environmentsKey] = "my env"
+	assert.Error(t, templateCmd.setConventionKey(iq))
+}
+
+func TestExplicitlyEmptyPatterns(t *testing.T) {
+	answers := map[string]interface{}{}
+	assert.NoError(t, questionMap[IncludePatterns].Writer(&answers, IncludePatterns, ExplicitEmptyValue))
+	assert.NoError(t, questionMap[ExcludePatterns].Writer(&answers, ExcludePatterns, "**/*.tmp,**/*.bak"))
+	content, err := json.Marshal(answers)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"includesPattern":"","excludesPattern":"**/*.tmp,**/*.bak"}`, string(content))
+}
+
+func TestKeyPairsSuggestions(t *testing.T) {
+	keyPairsAvailable := true
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/api/security/keypair" && keyPairsAvailable {
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`[{"pairName":"rpm-signing","pairType":"GPG","alias":"rpm"},{"pairName":"debian-signing","pairType":"GPG","alias":"debian"}]`))
+			assert.NoError(t, err)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	})
+	defer testServer.Close()
+	templateCmd := NewRepoTemplateCommand().SetServerDetails(&config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"})
+
+	questionsMap := templateCmd.addKeyPairsSuggestions(questionMap)
+	for _, key := range []string{PrimaryKeyPairRef, SecondaryKeyPairRef, KeyPair} {
+		question := questionsMap[key]
+		assert.True(t, question.SuggestOnly, key)
+		assert.Equal(t, ioutils.ConvertToSuggests([]string{"rpm-signing", "debian-signing"}), question.Options, key)
+	}
+	// The shared questions map isn't modified.
+	assert.Nil(t, questionMap[PrimaryKeyPairRef].Options)
+
+	// Unknown key pairs are accepted, with a warning.
+	_, logBuffer, previousLog := tests.RedirectLogOutputToBuffer()
+	defer log.SetLogger(previousLog)
+	iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{}}
+	_, err := questionsMap[PrimaryKeyPairRef].Callback(iq, "rpm-signing")
+	assert.NoError(t, err)
+	assert.Empty(t, logBuffer.String())
+	_, err = questionsMap[PrimaryKeyPairRef].Callback(iq, "rpm-signnig")
+	assert.NoError(t, err)
+	assert.Contains(t, logBuffer.String(), "The key pair 'rpm-signnig' isn't configured in Artifactory. The available key pairs are: rpm-signing, debian-signing")
+
+	// When the key pairs can't be fetched, the fields remain free strings.
+	keyPairsAvailable = false
+	questionsMap = templateCmd.addKeyPairsSuggestions(questionMap)
+	assert.Nil(t, questionsMap[PrimaryKeyPairRef].Options)
+	assert.False(t, questionsMap[PrimaryKeyPairRef].SuggestOnly)
+}
+
+func TestSwiftFederatedTemplate(t *testing.T) {
+	// Swift and CocoaPods are offered for federated repositories
+	federatedPkgTypes := append(commonPkgTypes, federatedRepoAdditionalPkgTypes...)
+	assert.Contains(t, federatedPkgTypes, Swift)
+	assert.Contains(t, federatedPkgTypes, Cocoapods)
+	assert.Equal(t, Swift, pkgTypeSuggestsMap[Swift].Text)
+	assert.NotNil(t, federatedRepoHandlers[Swift])
+	assert.NotNil(t, federatedRepoHandlers[Cocoapods])
+
+	// A new Swift repository gets the Swift layout by default, and the optional keys of a local repository and the members of the federation
+	iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{TemplateType: Create, Rclass: Federated}}
+	_, err := pkgTypeCallback(iq, Swift)
+	assert.NoError(t, err)
+	assert.Equal(t, SwiftDefaultRepoLayout, iq.AnswersMap[RepoLayoutRef])
+	assert.Equal(t, getFederatedRepoConfKeys(Swift), iq.OptionalKeysSuggests)
+	assert.Contains(t, getSuggestsTexts(iq.OptionalKeysSuggests), RepoLayoutRef)
+
+	// The layout of an existing repository isn't changed by an update template
+	iq = &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{TemplateType: Update, Rclass: Federated}}
+	_, err = pkgTypeCallback(iq, Swift)
+	assert.NoError(t, err)
+	assert.NotContains(t, iq.AnswersMap, RepoLayoutRef)
+
+	// Other package types keep the default layout of Artifactory
+	iq = &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{TemplateType: Create, Rclass: Federated}}
+	_, err = pkgTypeCallback(iq, Cocoapods)
+	assert.NoError(t, err)
+	assert.NotContains(t, iq.AnswersMap, RepoLayoutRef)
+}
+
+func TestCargoTemplate(t *testing.T) {
+	// Cargo is offered for local, remote and virtual repositories, with the internal index key
+	for _, rclass := range []string{Local, Remote, Virtual} {
+		iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{TemplateType: Create, Rclass: rclass}}
+		_, err := pkgTypeCallback(iq, Cargo)
+		assert.NoError(t, err)
+		assert.Contains(t, getSuggestsTexts(iq.OptionalKeysSuggests), CargoInternalIndex, rclass)
+	}
+	assert.Contains(t, getSuggestsTexts(getLocalRepoConfKeys(Cargo)), CargoAnonymousAccess)
+	assert.Contains(t, getSuggestsTexts(getRemoteRepoConfKeys(Cargo, Create)), CargoAnonymousAccess)
+	assert.NotContains(t, federatedRepoAdditionalPkgTypes, Cargo)
+	assert.Equal(t, Cargo, pkgTypeSuggestsMap[Cargo].Text)
+
+	// The git registry URL is unique to remote repositories
+	assert.Contains(t, getSuggestsTexts(getRemoteRepoConfKeys(Cargo, Create)), GitRegistryUrl)
+	assert.NotContains(t, getSuggestsTexts(getLocalRepoConfKeys(Cargo)), GitRegistryUrl)
+
+	// The templates create the Cargo repositories with all of their keys
+	createdRepos := map[string]map[string]interface{}{}
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.HasPrefix(r.RequestURI, "/api/repositories/cargo-") {
+			var createdRepo map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&createdRepo))
+			createdRepos[strings.TrimPrefix(r.RequestURI, "/api/repositories/")] = createdRepo
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+	templates := map[string]map[string]interface{}{
+		"cargo-local":   {Key: "cargo-local", Rclass: Local, PackageType: Cargo, CargoInternalIndex: "true", CargoAnonymousAccess: "true"},
+		"cargo-remote":  {Key: "cargo-remote", Rclass: Remote, PackageType: Cargo, Url: "https://index.crates.io", GitRegistryUrl: "https://github.com/rust-lang/crates.io-index", CargoInternalIndex: "false"},
+		"cargo-virtual": {Key: "cargo-virtual", Rclass: Virtual, PackageType: Cargo, CargoInternalIndex: "true"},
+	}
+	for repoKey, template := range templates {
+		templatePath := filepath.Join(t.TempDir(), "template.json")
+		content, err := json.Marshal(template)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(templatePath, content, 0644))
+		repoCmd := &RepoCommand{serverDetails: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"}, templatePath: templatePath}
+		assert.NoError(t, repoCmd.PerformRepoCmd(false), repoKey)
+	}
+	assert.Equal(t, map[string]interface{}{"key": "cargo-local", "rclass": Local, "packageType": Cargo, "cargoInternalIndex": true, "cargoAnonymousAccess": true}, createdRepos["cargo-local"])
+	assert.Equal(t, map[string]interface{}{"key": "cargo-remote", "rclass": Remote, "packageType": Cargo, "url": "https://index.crates.io", "gitRegistryUrl": "https://github.com/rust-lang/crates.io-index", "cargoInternalIndex": false}, createdRepos["cargo-remote"])
+	assert.Equal(t, map[string]interface{}{"key": "cargo-virtual", "rclass": Virtual, "packageType": Cargo, "cargoInternalIndex": true}, createdRepos["cargo-virtual"])
+}
+
+func TestTerraformTemplate(t *testing.T) {
+	// The Terraform type is offered for remote repositories, and constrained to the valid types
+	iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{TemplateType: Create, Rclass: Remote}}
+	_, err := pkgTypeCallback(iq, Terraform)
+	assert.NoError(t, err)
+	assert.Contains(t, getSuggestsTexts(iq.OptionalKeysSuggests), TerraformType)
+	assert.Equal(t, []string{TerraformModuleType, TerraformProviderType}, getSuggestsTexts(questionMap[TerraformType].Options))
+	assert.False(t, questionMap[TerraformType].SuggestOnly)
+	for _, pkgTypes := range [][]string{localRepoAdditionalPkgTypes, remoteRepoAdditionalPkgTypes, virtualRepoAdditionalPkgTypes} {
+		assert.Contains(t, pkgTypes, Terraform)
+	}
+
+	// The template that the questionnaire produces creates a Terraform repository
+	var createdRepo map[string]interface{}
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.RequestURI == "/api/repositories/terraform-remote" {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&createdRepo))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+	answers := map[string]interface{}{Key: "terraform-remote", Rclass: Remote, PackageType: Terraform, Url: "https://registry.terraform.io"}
+	assert.NoError(t, questionMap[TerraformType].Writer(&answers, TerraformType, TerraformProviderType))
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	content, err := json.Marshal(answers)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(templatePath, content, 0644))
+	repoCmd := &RepoCommand{serverDetails: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"}, templatePath: templatePath}
+	assert.NoError(t, repoCmd.PerformRepoCmd(false))
+	assert.Equal(t, map[string]interface{}{"key": "terraform-remote", "rclass": Remote, "packageType": Terraform, "url": "https://registry.terraform.io", "terraformType": TerraformProviderType}, createdRepo)
+
+	// An invalid Terraform type is rejected
+	answers[TerraformType] = "backend"
+	content, err = json.Marshal(answers)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(templatePath, content, 0644))
+	assert.ErrorContains(t, repoCmd.PerformRepoCmd(false), "invalid value for terraformType: 'backend'")
+}
+
+func TestHuggingFaceMlTemplate(t *testing.T) {
+	// Hugging Face repositories are offered as remote repositories, with the registry URL key
+	assert.Contains(t, remoteRepoAdditionalPkgTypes, HuggingFaceMl)
+	assert.NotContains(t, localRepoAdditionalPkgTypes, HuggingFaceMl)
+	assert.Equal(t, HuggingFaceMl, pkgTypeSuggestsMap[HuggingFaceMl].Text)
+	iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{TemplateType: Create, Rclass: Remote}}
+	_, err := pkgTypeCallback(iq, HuggingFaceMl)
+	assert.NoError(t, err)
+	assert.Contains(t, getSuggestsTexts(iq.OptionalKeysSuggests), HuggingFaceRegistryUrl)
+
+	// The template creates a Hugging Face remote repository with the registry URL
+	var createdRepo map[string]interface{}
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.RequestURI == "/api/repositories/hf-remote" {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&createdRepo))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+	answers := map[string]interface{}{Key: "hf-remote", Rclass: Remote, PackageType: HuggingFaceMl, Url: "https://huggingface.co"}
+	assert.NoError(t, questionMap[HuggingFaceRegistryUrl].Writer(&answers, HuggingFaceRegistryUrl, "https://huggingface.co"))
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	content, err := json.Marshal(answers)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(templatePath, content, 0644))
+	repoCmd := &RepoCommand{serverDetails: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"}, templatePath: templatePath}
+	assert.NoError(t, repoCmd.PerformRepoCmd(false))
+	assert.Equal(t, map[string]interface{}{"key": "hf-remote", "rclass": Remote, "packageType": HuggingFaceMl, "url": "https://huggingface.co", "huggingFaceRegistryUrl": "https://huggingface.co"}, createdRepo)
+}
+
+func TestPriorityResolution(t *testing.T) {
+	// Priority resolution is offered for local and remote repositories
+	assert.Contains(t, getSuggestsTexts(getLocalRepoConfKeys(Maven)), PriorityResolution)
+	assert.Contains(t, getSuggestsTexts(getRemoteRepoConfKeys(Maven, Create)), PriorityResolution)
+	assert.Equal(t, ioutils.GetBoolSuggests(), questionMap[PriorityResolution].Options)
+
+	// The template creates a local Maven repository with priority resolution
+	var createdRepo map[string]interface{}
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.RequestURI == "/api/repositories/maven-local" {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&createdRepo))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+	answers := map[string]interface{}{Key: "maven-local", Rclass: Local, PackageType: Maven}
+	assert.NoError(t, questionMap[PriorityResolution].Writer(&answers, PriorityResolution, "true"))
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	content, err := json.Marshal(answers)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(templatePath, content, 0644))
+	repoCmd := &RepoCommand{serverDetails: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"}, templatePath: templatePath}
+	assert.NoError(t, repoCmd.PerformRepoCmd(false))
+	assert.Equal(t, true, createdRepo[PriorityResolution])
+}
+
+func TestCdnRedirect(t *testing.T) {
+	// CDN redirect is offered for local and remote repositories
+	assert.Contains(t, getSuggestsTexts(getLocalRepoConfKeys(Generic)), CdnRedirect)
+	assert.Contains(t, getSuggestsTexts(getRemoteRepoConfKeys(Generic, Create)), CdnRedirect)
+
+	// The answer is written to the template as a string, like the other boolean keys
+	answers := map[string]interface{}{}
+	assert.NoError(t, questionMap[CdnRedirect].Writer(&answers, CdnRedirect, "true"))
+	assert.NoError(t, questionMap[BlackedOut].Writer(&answers, BlackedOut, "false"))
+	content, err := json.Marshal(answers)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"cdnRedirect":"true","blackedOut":"false"}`, string(content))
+
+	// The template value is serialized as a boolean in the repository configuration
+	assert.NoError(t, writersMap[CdnRedirect](&answers, CdnRedirect, "true"))
+	content, err = json.Marshal(map[string]interface{}{CdnRedirect: answers[CdnRedirect]})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"cdnRedirect":true}`, string(content))
+}
+
+func TestContentSynchronisation(t *testing.T) {
+	// The answers are written to the template as a nested object of booleans
+	contentSynchronisation, err := buildContentSynchronisationAnswer([]string{"true", "true", "true", "true"})
+	assert.NoError(t, err)
+	content, err := json.Marshal(contentSynchronisation)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"enabled":true,"statistics":{"enabled":true},"properties":{"enabled":true},"source":{"originAbsenceDetection":true}}`, string(content))
+
+	// Variables are kept as is, to be replaced when the template is used
+	contentSynchronisation, err = buildContentSynchronisationAnswer([]string{"true", "false", "${props}", " false"})
+	assert.NoError(t, err)
+	content, err = json.Marshal(contentSynchronisation)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"enabled":true,"statistics":{"enabled":false},"properties":{"enabled":"${props}"},"source":{"originAbsenceDetection":false}}`, string(content))
+
+	// Malformed answers
+	_, err = buildContentSynchronisationAnswer([]string{"true", "yes", "true", "true"})
+	assert.ErrorContains(t, err, "invalid value for contentSynchronisation.statistics.enabled: 'yes' isn't a boolean")
+	_, err = buildContentSynchronisationAnswer([]string{"true", "true"})
+	assert.ErrorContains(t, err, "expected 4 values but got 2")
+
+	// The template value is serialized as the nested object in the repository configuration, whether it was written as an object or as a comma separated list
+	var templateValue map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"enabled":true,"statistics":{"enabled":"false"},"source":{"originAbsenceDetection":true}}`), &templateValue))
+	for _, value := range []interface{}{templateValue, "true,false,true,true"} {
+		configMap := map[string]interface{}{}
+		assert.NoError(t, writeContentSynchronisationValue(&configMap, ContentSynchronisation, value))
+		content, err = json.Marshal(configMap)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"statistics":{"enabled":false}`)
+		assert.Contains(t, string(content), `"source":{"originAbsenceDetection":true}`)
+	}
+
+	// Malformed template values
+	_, err = ParseContentSynchronisation("true,false,true")
+	assert.ErrorContains(t, err, "The expected format is 'enabled,statistics.enabled,properties.enabled,source.originAbsenceDetection'")
+	_, err = ParseContentSynchronisation("true,false,maybe,true")
+	assert.ErrorContains(t, err, "invalid value for contentSynchronisation.properties.enabled: 'maybe' isn't a boolean")
+	_, err = ParseContentSynchronisation(map[string]interface{}{"statistics": true})
+	assert.ErrorContains(t, err, "invalid value for contentSynchronisation.statistics: expected an object")
+	_, err = ParseContentSynchronisation(map[string]interface{}{"enabled": 1.0})
+	assert.ErrorContains(t, err, "'1' isn't a boolean")
+	_, err = ParseContentSynchronisation(true)
+	assert.ErrorContains(t, err, "expected an object")
+}
+
+func TestRepoTemplateFromValues(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	templateCmd := NewRepoTemplateCommand().SetTemplatePath(templatePath).SetValues(map[string]string{
+		TemplateType:           Create,
+		Key:                    "libs",
+		Rclass:                 Remote,
+		Url:                    "https://repo.maven.apache.org/maven2",
+		PackageType:            Maven,
+		ProjectKey:             "proj",
+		HandleReleases:         "true",
+		SocketTimeoutMillis:    "${timeout}",
+		Environment:            "DEV",
+		ContentSynchronisation: "true,false,true,false",
+	})
+	assert.NoError(t, templateCmd.Run())
+	content, err := os.ReadFile(templatePath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key":"proj-libs","rclass":"remote","url":"https://repo.maven.apache.org/maven2","packageType":"maven","projectKey":"proj","handleReleases":"true","socketTimeoutMillis":"${timeout}","environments":"DEV",
+		"contentSynchronisation":{"enabled":true,"statistics":{"enabled":false},"properties":{"enabled":true},"source":{"originAbsenceDetection":false}}}`, string(content))
+
+	// The key may be computed from the naming convention
+	templatePath = filepath.Join(t.TempDir(), "template.json")
+	templateCmd.SetTemplatePath(templatePath).SetKeyConvention("{team}-{packageType}-{rclass}").SetValues(map[string]string{TemplateType: Update, Rclass: Virtual, PackageType: Npm, teamAnswerKey: "devops"})
+	assert.NoError(t, templateCmd.Run())
+	content, err = os.ReadFile(templatePath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key":"devops-npm-virtual","rclass":"virtual","packageType":"npm"}`, string(content))
+
+	// Invalid values
+	templateCmd.SetKeyConvention("")
+	tests := []struct {
+		values        map[string]string
+		expectedError string
+	}{
+		{map[string]string{TemplateType: Create, Rclass: Local, PackageType: Maven}, "the value of 'key' is missing"},
+		{map[string]string{TemplateType: Create, Key: "libs", Rclass: "physical", PackageType: Maven}, "invalid value for rclass: 'physical'"},
+		{map[string]string{TemplateType: Create, Key: "libs", Rclass: Local, PackageType: HuggingFaceMl}, "invalid value for packageType: 'huggingfaceml'"},
+		{map[string]string{TemplateType: Create, Key: "libs", Rclass: Remote, PackageType: Npm}, "the value of 'url' is missing"},
+		{map[string]string{TemplateType: Create, Key: "libs", Rclass: Local, PackageType: Npm, HandleReleases: "true"},
+			"the key 'handleReleases' isn't allowed in the template of a local npm repository. The allowed keys are: description, notes"},
+		{map[string]string{TemplateType: Create, Key: "libs", Rclass: Local, PackageType: Maven, HandleReleases: "yes"}, "invalid value for handleReleases: 'yes'"},
+	}
+	for _, test := range tests {
+		templateCmd.SetTemplatePath(filepath.Join(t.TempDir(), "template.json"))
+		assert.ErrorContains(t, templateCmd.SetValues(test.values).Run(), test.expectedError)
+	}
+}
+
+func TestYamlTemplate(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "template.yaml")
+	templateCmd := NewRepoTemplateCommand().SetTemplatePath(templatePath).SetFormat(YamlTemplateFormat).SetValues(map[string]string{
+		TemplateType:           Create,
+		Key:                    "npm-remote",
+		Rclass:                 Remote,
+		Url:                    "https://registry.npmjs.org",
+		PackageType:            Npm,
+		HardFail:               "true",
+		SocketTimeoutMillis:    "${timeout}",
+		ContentSynchronisation: "true,false,true,false",
+	})
+	assert.NoError(t, templateCmd.Run())
+	content, err := os.ReadFile(templatePath)
+	assert.NoError(t, err)
+	// The keys are sorted, and the values keep their template types
+	assert.Equal(t, `contentSynchronisation:
+    enabled: true
+    properties:
+        enabled: true
+    source:
+        originAbsenceDetection: false
+    statistics:
+        enabled: false
+hardFail: "true"
+key: "npm-remote"
+packageType: "npm"
+rclass: "remote"
+socketTimeoutMillis: "${timeout}"
+url: "https://registry.npmjs.org"
+`, string(content))
+
+	// The YAML template is read like a JSON template
+	repoConfigMap, err := utils.ConvertTemplateToMap(&RepoCommand{templatePath: templatePath, vars: "timeout=15000"})
+	assert.NoError(t, err)
+	assert.Equal(t, "15000", repoConfigMap[SocketTimeoutMillis])
+	assert.Equal(t, "true", repoConfigMap[HardFail])
+	assert.Equal(t, map[string]interface{}{"enabled": false}, repoConfigMap[ContentSynchronisation].(map[string]interface{})["statistics"])
+
+	// Unsupported formats
+	templateCmd.SetTemplatePath(filepath.Join(t.TempDir(), "template.xml")).SetFormat("xml")
+	assert.ErrorContains(t, templateCmd.Run(), "unsupported template format 'xml'")
+}
+
+func TestRepoKeyValidation(t *testing.T) {
+	iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{}}
+	// Valid keys and variables are accepted without asking again
+	for _, repoKey := range []string{"libs-release_1.0", "${repoKey}"} {
+		_, err := repoKeyCallback(iq, repoKey)
+		assert.NoError(t, err)
+	}
+
+	// Invalid keys
+	assert.ErrorContains(t, validateRepoKeyAnswer("libs release"), "may contain only letters, digits, dots, dashes and underscores")
+	assert.ErrorContains(t, validateRepoKeyAnswer("-libs"), "may contain only letters")
+	assert.ErrorContains(t, validateRepoKeyAnswer("libs/release"), "may contain only letters")
+	assert.ErrorContains(t, validateRepoKeyAnswer(strings.Repeat("a", maxRepoKeyLength+1)), "must be between 1 and 64 characters long")
+
+	// The project key prefix may make the key too long
+	iq.AnswersMap[Key] = strings.Repeat("a", maxRepoKeyLength-2)
+	_, err := projectKeyCallback(iq, "proj")
+	assert.ErrorContains(t, err, "must be between 1 and 64 characters long")
+
+	// Templates generated from values are validated as well
+	templateCmd := NewRepoTemplateCommand().SetTemplatePath(filepath.Join(t.TempDir(), "template.json"))
+	templateCmd.SetValues(map[string]string{TemplateType: Create, Key: "libs release", Rclass: Local, PackageType: Generic})
+	assert.ErrorContains(t, templateCmd.Run(), "the repository key 'libs release' may contain only")
+}
+
+func TestEnvironments(t *testing.T) {
+	// The environments are asked for as a comma separated list, and written to the template as is
+	assert.Equal(t, environmentsKey, questionMap[Environment].MapKey)
+	answers := map[string]interface{}{}
+	assert.NoError(t, questionMap[Environment].Writer(&answers, environmentsKey, "DEV,PROD"))
+	assert.Equal(t, "DEV,PROD", answers[environmentsKey])
+
+	// The template value is serialized as a list, whether it has a single environment or more
+	for value, expected := range map[string]string{"DEV": `["DEV"]`, "DEV, PROD,DEV,": `["DEV","PROD"]`, "${envs}": `"${envs}"`} {
+		configMap := map[string]interface{}{}
+		assert.NoError(t, writersMap[environmentsKey](&configMap, environmentsKey, value))
+		content, err := json.Marshal(configMap)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"environments":`+expected+`}`, string(content))
+	}
+
+	// The key convention requires a single environment
+	_, err := computeConventionKey("{packageType}-{env}", map[string]interface{}{PackageType: "npm", environmentsKey: "DEV,PROD"})
+	assert.ErrorContains(t, err, "requires a single environment for {env}")
+}
+
+func TestRepoLayoutsSuggestions(t *testing.T) {
+	layoutsAvailable := true
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/api/system/configuration" && layoutsAvailable {
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`<config><repoLayouts><repoLayout><name>maven-2-default</name></repoLayout><repoLayout><name>acme-layout</name></repoLayout></repoLayouts></config>`))
+			assert.NoError(t, err)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	})
+	defer testServer.Close()
+	templateCmd := NewRepoTemplateCommand().SetServerDetails(&config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"})
+
+	// The layouts configured in Artifactory, including the custom ones, are suggested
+	questionsMap := templateCmd.addRepoLayoutsSuggestions(questionMap)
+	assert.Equal(t, ioutils.ConvertToSuggests([]string{Maven2DefaultRepoLayout, "acme-layout"}), questionsMap[RepoLayoutRef].Options)
+	assert.True(t, questionsMap[RepoLayoutRef].AllowVars)
+	// The shared questions map isn't modified.
+	assert.Contains(t, questionMap[RepoLayoutRef].Options, prompt.Suggest{Text: SimpleDefaultRepoLayout})
+
+	// When the layouts can't be fetched, the default layouts are suggested.
+	layoutsAvailable = false
+	questionsMap = templateCmd.addRepoLayoutsSuggestions(questionMap)
+	assert.Equal(t, questionMap[RepoLayoutRef].Options, questionsMap[RepoLayoutRef].Options)
+}
+
+func TestDisableProxy(t *testing.T) {
+	// Disabling the proxy is offered for remote repositories only
+	assert.Contains(t, getSuggestsTexts(getRemoteRepoConfKeys(Npm, Create)), DisableProxy)
+	assert.NotContains(t, getSuggestsTexts(getLocalRepoConfKeys(Npm)), DisableProxy)
+
+	// The template value is serialized as a boolean in the repository configuration
+	configMap := map[string]interface{}{}
+	assert.NoError(t, writersMap[DisableProxy](&configMap, DisableProxy, "true"))
+	assert.Equal(t, true, configMap[DisableProxy])
+
+	// Setting both a proxy and disableProxy shows a warning
+	_, logBuffer, previousLog := tests.RedirectLogOutputToBuffer()
+	defer log.SetLogger(previousLog)
+	warnOnProxyConflict(map[string]interface{}{Proxy: "corporate-proxy", DisableProxy: "false"})
+	assert.Empty(t, logBuffer.String())
+	warnOnProxyConflict(map[string]interface{}{Proxy: "corporate-proxy", DisableProxy: "true"})
+	assert.Contains(t, logBuffer.String(), "Both proxy 'corporate-proxy' and disableProxy are set")
+
+	// The repository is created, and then disableProxy is set by a partial update of it
+	var createdRepo, updatedRepo map[string]interface{}
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/api/repositories/npm-remote" {
+			switch r.Method {
+			case http.MethodPut:
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&createdRepo))
+				w.WriteHeader(http.StatusOK)
+				return
+			case http.MethodPost:
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&updatedRepo))
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+	answers := map[string]interface{}{Key: "npm-remote", Rclass: Remote, PackageType: Npm, Url: "https://registry.npmjs.org", DisableProxy: "true"}
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	content, err := json.Marshal(answers)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(templatePath, content, 0644))
+	repoCmd := &RepoCommand{serverDetails: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"}, templatePath: templatePath}
+	assert.NoError(t, repoCmd.PerformRepoCmd(false))
+	assert.Equal(t, "https://registry.npmjs.org", createdRepo[Url])
+	assert.Equal(t, map[string]interface{}{"key": "npm-remote", "rclass": Remote, "disableProxy": true}, updatedRepo)
+}
+
+func TestCondaRemoteTemplate(t *testing.T) {
+	// The base URL of the Conda channel is the url of the remote repository, which is mandatory for new repositories
+	templateCmd := NewRepoTemplateCommand().SetTemplatePath(filepath.Join(t.TempDir(), "template.json"))
+	templateCmd.SetValues(map[string]string{TemplateType: Create, Key: "conda-remote", Rclass: Remote, PackageType: Conda})
+	assert.ErrorContains(t, templateCmd.Run(), "the value of 'url' is missing")
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	templateCmd.SetTemplatePath(templatePath).SetValues(map[string]string{TemplateType: Create, Key: "conda-remote", Rclass: Remote, PackageType: Conda, Url: "https://repo.anaconda.com/pkgs/main"})
+	assert.NoError(t, templateCmd.Run())
+	content, err := os.ReadFile(templatePath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key":"conda-remote","rclass":"remote","packageType":"conda","url":"https://repo.anaconda.com/pkgs/main"}`, string(content))
+
+	// The url of an existing repository may be changed by an update template
+	assert.Contains(t, getSuggestsTexts(getRemoteRepoConfKeys(Conda, Update)), Url)
+	assert.NotNil(t, remoteRepoHandlers[Conda])
+}
+
+func TestAlpineKeyPairRef(t *testing.T) {
+	// The primary key pair that signs the index is offered for local and federated Alpine repositories
+	for _, rclass := range []string{Local, Federated} {
+		iq := &ioutils.InteractiveQuestionnaire{AnswersMap: map[string]interface{}{TemplateType: Create, Rclass: rclass}}
+		_, err := pkgTypeCallback(iq, Alpine)
+		assert.NoError(t, err)
+		assert.Contains(t, getSuggestsTexts(iq.OptionalKeysSuggests), PrimaryKeyPairRef, rclass)
+	}
+	assert.NotContains(t, getSuggestsTexts(getLocalRepoConfKeys(Generic)), PrimaryKeyPairRef)
+
+	// The key pair is sent in the configuration of the repository
+	var requestBody map[string]interface{}
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&requestBody))
+		w.WriteHeader(http.StatusOK)
+	})
+	defer testServer.Close()
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	assert.NoError(t, os.WriteFile(templatePath, []byte(`{"key":"alpine-local","rclass":"local","packageType":"alpine","primaryKeyPairRef":"alpine-signing"}`), 0644))
+	repoCmd := &RepoCommand{serverDetails: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"}, templatePath: templatePath}
+	assert.NoError(t, repoCmd.PerformRepoCmd(false))
+	assert.Equal(t, "alpine-signing", requestBody[PrimaryKeyPairRef])
+}
+
+func TestFederatedMembers(t *testing.T) {
+	// The members are offered for federated repositories only
+	assert.Contains(t, getSuggestsTexts(getOptionalRepoConfKeys(Federated, Generic, Create)), Members)
+	assert.NotContains(t, getSuggestsTexts(getOptionalRepoConfKeys(Local, Generic, Create)), Members)
+
+	// The answer is validated and kept as is in the template
+	answers := map[string]interface{}{}
+	members := "https://jpd1.example.com/artifactory/generic-fed, https://jpd2.example.com/artifactory/generic-fed"
+	assert.NoError(t, questionMap[Members].Writer(&answers, Members, members))
+	assert.Equal(t, members, answers[Members])
+	assert.NoError(t, questionMap[Members].Writer(&answers, Members, "${members}"))
+	assert.ErrorContains(t, questionMap[Members].Writer(&answers, Members, "https://jpd1.example.com/artifactory/generic-fed,generic-fed"),
+		"invalid value for members: 'generic-fed' isn't the URL of a repository")
+
+	// The template value is serialized as the list of members that the API expects
+	var requestBody map[string]interface{}
+	testServer := commonTests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&requestBody))
+		w.WriteHeader(http.StatusOK)
+	})
+	defer testServer.Close()
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	assert.NoError(t, os.WriteFile(templatePath, []byte(`{"key":"generic-fed","rclass":"federated","packageType":"generic","members":"`+members+`"}`), 0644))
+	repoCmd := &RepoCommand{serverDetails: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/"}, templatePath: templatePath}
+	assert.NoError(t, repoCmd.PerformRepoCmd(false))
+	content, err := json.Marshal(requestBody[Members])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"url":"https://jpd1.example.com/artifactory/generic-fed","enabled":true},{"url":"https://jpd2.example.com/artifactory/generic-fed","enabled":true}]`, string(content))
+}
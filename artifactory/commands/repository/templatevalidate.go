@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/ioutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Validates an existing repository template, for example after it was edited by hand, without sending it to Artifactory.
+type RepoTemplateValidateCommand struct {
+	RepoCommand
+}
+
+func NewRepoTemplateValidateCommand() *RepoTemplateValidateCommand {
+	return &RepoTemplateValidateCommand{}
+}
+
+func (rtvc *RepoTemplateValidateCommand) SetTemplatePath(path string) *RepoTemplateValidateCommand {
+	rtvc.templatePath = path
+	return rtvc
+}
+
+func (rtvc *RepoTemplateValidateCommand) SetVars(vars string) *RepoTemplateValidateCommand {
+	rtvc.vars = vars
+	return rtvc
+}
+
+func (rtvc *RepoTemplateValidateCommand) ServerDetails() (*config.ServerDetails, error) {
+	// Since it's a local command, usage won't be reported.
+	return nil, nil
+}
+
+func (rtvc *RepoTemplateValidateCommand) CommandName() string {
+	return "rt_repo_template_validate"
+}
+
+func (rtvc *RepoTemplateValidateCommand) Run() error {
+	templateMap, err := utils.ConvertTemplateToMap(rtvc)
+	if err != nil {
+		return err
+	}
+	if err = validateTemplateMap(rtvc.templatePath, templateMap); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("The repository template at %s is valid.", rtvc.templatePath))
+	return nil
+}
+
+// Validates the repository template at the given path, and returns an error with all of its violations, if there are any.
+// Values that are vars aren't validated, since they are replaced only when the template is used.
+func ValidateTemplate(path string) error {
+	return NewRepoTemplateValidateCommand().SetTemplatePath(path).Run()
+}
+
+func validateTemplateMap(path string, templateMap map[string]interface{}) error {
+	violations := getTemplateViolations(templateMap)
+	if len(violations) == 0 {
+		return nil
+	}
+	return errorutils.CheckErrorf("the repository template at %s is invalid:\n- %s", path, strings.Join(violations, "\n- "))
+}
+
+// Checks that every key is allowed for the rclass and package type of the template, and that every value can be written with its actual type.
+func getTemplateViolations(templateMap map[string]interface{}) (violations []string) {
+	for _, key := range []string{Key, Rclass, PackageType} {
+		if value, ok := templateMap[key].(string); !ok || value == "" {
+			violations = append(violations, fmt.Sprintf("the value of '%s' is missing", key))
+		}
+	}
+	if len(violations) > 0 {
+		return
+	}
+	rclass, pkgType := templateMap[Rclass].(string), templateMap[PackageType].(string)
+	pkgTypes := getRclassPkgTypes(rclass)
+	if pkgTypes == nil {
+		return []string{fmt.Sprintf("invalid value for %s: '%s'. The allowed values are: %s", Rclass, rclass, strings.Join([]string{Local, Remote, Virtual, Federated}, ", "))}
+	}
+	if !slices.Contains(pkgTypes, pkgType) {
+		return []string{fmt.Sprintf("invalid value for %s: '%s'. The allowed values for a %s repository are: %s", PackageType, pkgType, rclass, strings.Join(pkgTypes, ", "))}
+	}
+	if err := validateRepoKeyAnswer(templateMap[Key].(string)); err != nil {
+		violations = append(violations, err.Error())
+	}
+
+	// The template may be of a new or of an existing repository, so the url of a remote repository is allowed but not required.
+	allowedKeys := getTemplateKeysQuestions(getOptionalRepoConfKeys(rclass, pkgType, Update))
+	keys := maps.Keys(templateMap)
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == Key || key == Rclass || key == PackageType {
+			continue
+		}
+		questionKey, ok := allowedKeys[key]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("the key '%s' isn't allowed in the template of a %s %s repository. The allowed keys are: %s", key, rclass, pkgType, strings.Join(getSortedKeys(allowedKeys), ", ")))
+			continue
+		}
+		if err := validateTemplateValue(key, questionMap[questionKey], templateMap[key]); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+	return
+}
+
+// Maps the keys that a template may include to the keys of their questions, which may be different (e.g. 'environments' is written by the 'environment' question).
+func getTemplateKeysQuestions(optionalKeys []prompt.Suggest) map[string]string {
+	templateKeys := map[string]string{}
+	for _, questionKey := range getSuggestsTexts(optionalKeys) {
+		if questionKey == ioutils.SaveAndExit {
+			continue
+		}
+		templateKey := questionMap[questionKey].MapKey
+		if templateKey == "" {
+			templateKey = questionKey
+		}
+		templateKeys[templateKey] = questionKey
+	}
+	return templateKeys
+}
+
+func getSortedKeys(keysMap map[string]string) []string {
+	keys := maps.Keys(keysMap)
+	sort.Strings(keys)
+	return keys
+}
+
+func validateTemplateValue(key string, question ioutils.QuestionInfo, value interface{}) error {
+	typedValues := map[string]interface{}{}
+	if key == ContentSynchronisation {
+		return writeContentSynchronisationValue(&typedValues, key, value)
+	}
+	stringValue, ok := value.(string)
+	if !ok {
+		return errorutils.CheckErrorf("the value of '%s' isn't a string", key)
+	}
+	if ioutils.VarPattern.MatchString(stringValue) {
+		return nil
+	}
+	if question.Options != nil && !question.SuggestOnly && !slices.Contains(getSuggestsTexts(question.Options), stringValue) {
+		return errorutils.CheckErrorf("invalid value for %s: '%s'. The allowed values are: %s", key, stringValue, strings.Join(getSuggestsTexts(question.Options), ", "))
+	}
+	// The writers convert the values to their actual types, so they fail for values that can't be converted, like non-numeric values of int keys.
+	if writer, ok := writersMap[key]; ok {
+		if err := writer(&typedValues, key, stringValue); err != nil {
+			return errorutils.CheckErrorf("invalid value for %s: '%s'. %s", key, stringValue, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTemplate := func(content string) string {
+		templatePath := filepath.Join(tmpDir, "template.json")
+		assert.NoError(t, os.WriteFile(templatePath, []byte(content), 0644))
+		return templatePath
+	}
+
+	// A valid template, including a var and a key whose question writes it under another name
+	assert.NoError(t, ValidateTemplate(writeTemplate(`{"key":"libs-release","rclass":"local","packageType":"maven","handleReleases":"true",
+		"maxUniqueSnapshots":"${max}","environments":"DEV","checksumPolicyType":"client-checksums"}`)))
+	assert.NoError(t, ValidateTemplate(writeTemplate(`{"key":"npm-remote","rclass":"remote","packageType":"npm","url":"https://registry.npmjs.org",
+		"contentSynchronisation":{"enabled":true,"statistics":{"enabled":false}}}`)))
+
+	// All the violations are reported at once
+	err := ValidateTemplate(writeTemplate(`{"key":"libs release","rclass":"local","packageType":"maven","handleReleases":"yes",
+		"maxUniqueSnapshots":"many","checksumPolicyType":"trust-me","url":"https://repo1.maven.org"}`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "the repository key 'libs release' may contain only")
+		assert.Contains(t, err.Error(), "invalid value for handleReleases: 'yes'")
+		assert.Contains(t, err.Error(), "invalid value for maxUniqueSnapshots: 'many'")
+		assert.Contains(t, err.Error(), "invalid value for checksumPolicyType: 'trust-me'. The allowed values are: client-checksums, server-generated-checksums")
+		assert.Contains(t, err.Error(), "the key 'url' isn't allowed in the template of a local maven repository")
+	}
+
+	// The rclass and package type must be valid to check the rest of the keys
+	assert.ErrorContains(t, ValidateTemplate(writeTemplate(`{"key":"libs","packageType":"maven"}`)), "the value of 'rclass' is missing")
+	assert.ErrorContains(t, ValidateTemplate(writeTemplate(`{"key":"libs","rclass":"local","packageType":"huggingfaceml"}`)),
+		"invalid value for packageType: 'huggingfaceml'. The allowed values for a local repository are")
+}
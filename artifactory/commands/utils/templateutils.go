@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
@@ -10,6 +11,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"gopkg.in/yaml.v3"
 )
 
 const pathErrorSuffixMsg = " please enter a path, in which the new template file will be created"
@@ -34,10 +36,20 @@ func ConvertTemplateToMap(tuc TemplateUserCommand) (map[string]interface{}, erro
 	}
 	// Unmarshal template to a map
 	var configMap map[string]interface{}
-	err = json.Unmarshal(content, &configMap)
+	if isYamlTemplate(tuc.TemplatePath()) {
+		err = yaml.Unmarshal(content, &configMap)
+	} else {
+		err = json.Unmarshal(content, &configMap)
+	}
 	return configMap, errorutils.CheckError(err)
 }
 
+// Templates are written in JSON, unless their file extension is of YAML.
+func isYamlTemplate(templatePath string) bool {
+	extension := strings.ToLower(filepath.Ext(templatePath))
+	return extension == ".yaml" || extension == ".yml"
+}
+
 func ValidateMapEntry(key string, value interface{}, writersMap map[string]ioutils.AnswerWriter) error {
 	if _, ok := writersMap[key]; !ok {
 		return errorutils.CheckErrorf("template syntax error: unknown key: \"" + key + "\".")
@@ -102,7 +102,7 @@ func (yc *YarnCommand) Run() (err error) {
 	if err != nil {
 		return errors.Join(err, restoreYarnrcFunc())
 	}
-	backupEnvMap, err := ModifyYarnConfigurations(yc.executablePath, yc.registry, yc.npmAuthIdent)
+	backupEnvMap, err := ModifyYarnConfigurations(yc.executablePath, yc.workingDirectory, yc.registry, yc.npmAuthIdent)
 	if err != nil {
 		return errors.Join(err, restoreYarnrcFunc())
 	}
@@ -296,7 +296,7 @@ func restoreEnvironmentVariables(envVarsBackup map[string]*string) error {
 	return nil
 }
 
-func ModifyYarnConfigurations(execPath, registry, npmAuthIdent string) (map[string]*string, error) {
+func ModifyYarnConfigurations(execPath, workingDir, registry, npmAuthIdent string) (map[string]*string, error) {
 	envVarsUpdated := map[string]string{
 		yarnNpmRegistryServerEnv: registry,
 		yarnNpmAuthIndent:        npmAuthIdent,
@@ -311,11 +311,11 @@ func ModifyYarnConfigurations(execPath, registry, npmAuthIdent string) (map[stri
 		envVarsBackup[key] = &oldVal
 	}
 	// Update scoped registries (these cannot be set in environment variables)
-	return envVarsBackup, errorutils.CheckError(updateScopeRegistries(execPath, registry, npmAuthIdent))
+	return envVarsBackup, errorutils.CheckError(updateScopeRegistries(execPath, workingDir, registry, npmAuthIdent))
 }
 
-func updateScopeRegistries(execPath, registry, npmAuthIdent string) error {
-	npmScopesStr, err := yarn.ConfigGet(NpmScopesConfigName, execPath, true)
+func updateScopeRegistries(execPath, workingDir, registry, npmAuthIdent string) error {
+	npmScopesStr, err := yarn.ConfigGet(NpmScopesConfigName, execPath, workingDir, true)
 	if err != nil {
 		return err
 	}
@@ -332,7 +332,7 @@ func updateScopeRegistries(execPath, registry, npmAuthIdent string) error {
 	if err != nil {
 		return errorutils.CheckError(err)
 	}
-	return yarn.ConfigSet(NpmScopesConfigName, string(updatedNpmScopesStr), execPath, true)
+	return yarn.ConfigSet(NpmScopesConfigName, string(updatedNpmScopesStr), execPath, workingDir, true)
 }
 
 type yarnNpmScope struct {
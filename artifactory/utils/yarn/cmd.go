@@ -11,6 +11,8 @@ type YarnConfig struct {
 	CommandFlags []string
 	StrWriter    io.WriteCloser
 	ErrWriter    io.WriteCloser
+	// The directory to run the command in. Defaults to the current working directory.
+	WorkingDir string
 }
 
 func (yc *YarnConfig) GetCmd() *exec.Cmd {
@@ -18,7 +20,9 @@ func (yc *YarnConfig) GetCmd() *exec.Cmd {
 	cmd = append(cmd, yc.Executable)
 	cmd = append(cmd, yc.Command...)
 	cmd = append(cmd, yc.CommandFlags...)
-	return exec.Command(cmd[0], cmd[1:]...)
+	command := exec.Command(cmd[0], cmd[1:]...)
+	command.Dir = yc.WorkingDir
+	return command
 }
 
 func (yc *YarnConfig) GetEnv() map[string]string {
@@ -6,12 +6,12 @@ import (
 	"strings"
 )
 
-func ConfigGet(key, executablePath string, jsonOutput bool) (string, error) {
+func ConfigGet(key, executablePath, workingDir string, jsonOutput bool) (string, error) {
 	var flags []string = nil
 	if jsonOutput {
 		flags = append(flags, "--json")
 	}
-	configGetCmdConfig := createConfigGetCmdConfig(executablePath, key, flags)
+	configGetCmdConfig := createConfigGetCmdConfig(executablePath, workingDir, key, flags)
 	output, err := gofrogcmd.RunCmdOutput(configGetCmdConfig)
 	if err != nil {
 		return "", errorutils.CheckError(err)
@@ -21,9 +21,10 @@ func ConfigGet(key, executablePath string, jsonOutput bool) (string, error) {
 	return confValue, nil
 }
 
-func createConfigGetCmdConfig(executablePath, confName string, flags []string) *YarnConfig {
+func createConfigGetCmdConfig(executablePath, workingDir, confName string, flags []string) *YarnConfig {
 	return &YarnConfig{
 		Executable:   executablePath,
+		WorkingDir:   workingDir,
 		Command:      []string{"config", "get", confName},
 		CommandFlags: flags,
 		StrWriter:    nil,
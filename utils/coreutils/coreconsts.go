@@ -10,6 +10,7 @@ const (
 	TokenRefreshDefaultInterval = 60
 
 	// Home Dir
+	JfrogAuditHistoryDirName            = "audit-history"
 	JfrogBackupDirName                  = "backup"
 	JfrogCertsDirName                   = "certs"
 	JfrogConfigFile                     = "jfrog-cli.conf"
@@ -12,6 +12,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
@@ -21,20 +22,29 @@ import (
 type Technology string
 
 const (
-	Maven  Technology = "maven"
-	Gradle Technology = "gradle"
-	Npm    Technology = "npm"
-	Yarn   Technology = "yarn"
-	Go     Technology = "go"
-	Pip    Technology = "pip"
-	Pipenv Technology = "pipenv"
-	Poetry Technology = "poetry"
-	Nuget  Technology = "nuget"
-	Dotnet Technology = "dotnet"
-	Docker Technology = "docker"
+	Maven    Technology = "maven"
+	Gradle   Technology = "gradle"
+	Npm      Technology = "npm"
+	Yarn     Technology = "yarn"
+	Go       Technology = "go"
+	Pip      Technology = "pip"
+	Pipenv   Technology = "pipenv"
+	Poetry   Technology = "poetry"
+	Nuget    Technology = "nuget"
+	Dotnet   Technology = "dotnet"
+	Docker   Technology = "docker"
+	Helm     Technology = "helm"
+	Bundler  Technology = "bundler"
+	Composer Technology = "composer"
+	Conan    Technology = "conan"
+	Swift    Technology = "swift"
+	Bazel    Technology = "bazel"
 )
 
-const Pypi = "pypi"
+const (
+	Pypi = "pypi"
+	Gems = "gems"
+)
 
 type TechData struct {
 	// The name of the package type used in this technology.
@@ -135,6 +145,44 @@ var technologiesData = map[Technology]TechData{
 		packageDescriptors: []string{".sln", ".csproj"},
 		formal:             ".NET",
 	},
+	Helm: {
+		indicators:         []string{"Chart.yaml"},
+		packageDescriptors: []string{"Chart.yaml"},
+	},
+	Bundler: {
+		packageType:        Gems,
+		indicators:         []string{"Gemfile.lock"},
+		packageDescriptors: []string{"Gemfile", "Gemfile.lock"},
+	},
+	Composer: {
+		indicators:         []string{"composer.json", "composer.lock"},
+		packageDescriptors: []string{"composer.json", "composer.lock"},
+	},
+	Conan: {
+		indicators:         []string{"conanfile.txt", "conanfile.py"},
+		packageDescriptors: []string{"conanfile.txt", "conanfile.py", "conan.lock"},
+	},
+	Swift: {
+		indicators:         []string{"Package.swift", "Package.resolved"},
+		packageDescriptors: []string{"Package.swift", "Package.resolved"},
+	},
+	Bazel: {
+		indicators:         []string{"MODULE.bazel", "WORKSPACE", "WORKSPACE.bazel"},
+		packageDescriptors: []string{"MODULE.bazel", "WORKSPACE", "WORKSPACE.bazel", "maven_install.json"},
+	},
+}
+
+// Technologies that may be detected in the same directory, while only one of them should build the directory's dependency tree.
+// The order of each group breaks the ties of the descriptor precedence.
+var conflictingTechnologies = [][]Technology{{Yarn, Npm}, {Poetry, Pipenv, Pip}}
+
+// The descriptors that decide which of the conflicting technologies is used in a directory, by order of precedence.
+// Lockfiles are preferred over manifests, since they describe the dependencies that are actually installed.
+var defaultDescriptorPrecedence = map[Technology][]string{
+	Yarn:   {"yarn.lock"},
+	Npm:    {"package-lock.json", "npm-shrinkwrap.json"},
+	Poetry: {"poetry.lock"},
+	Pipenv: {"Pipfile.lock"},
 }
 
 func (tech Technology) ToFormal() string {
@@ -148,6 +196,17 @@ func (tech Technology) String() string {
 	return string(tech)
 }
 
+// Returns true if the technology is preferred over the other technology, when both may build the dependency tree of the same directory.
+func (tech Technology) IsPreferredOver(other Technology) bool {
+	for _, group := range conflictingTechnologies {
+		techIndex, otherIndex := slices.Index(group, tech), slices.Index(group, other)
+		if techIndex >= 0 && otherIndex >= 0 {
+			return techIndex < otherIndex
+		}
+	}
+	return false
+}
+
 func (tech Technology) GetExecCommandName() string {
 	if technologiesData[tech].execCommand == "" {
 		return tech.String()
@@ -206,12 +265,14 @@ func detectedTechnologiesListInPath(path string, recursive bool) (technologies [
 // If recursive is true, the search will not be limited to files in the root path.
 // If requestedTechs is empty, all technologies will be checked.
 // If excludePathPattern is not empty, files/directories that match the wildcard pattern will be excluded from the search.
-func DetectTechnologiesDescriptors(path string, recursive bool, requestedTechs []string, requestedDescriptors map[Technology][]string, excludePathPattern string) (technologiesDetected map[Technology]map[string][]string, err error) {
+// descriptorPrecedence decides which of the conflicting technologies of a directory is detected (see applyDescriptorPrecedence), on top of the defaults.
+func DetectTechnologiesDescriptors(path string, recursive bool, requestedTechs []string, requestedDescriptors map[Technology][]string, descriptorPrecedence map[Technology][]string, excludePathPattern string) (technologiesDetected map[Technology]map[string][]string, err error) {
 	filesList, err := fspatterns.ListFiles(path, recursive, false, true, true, excludePathPattern)
 	if err != nil {
 		return
 	}
 	workingDirectoryToIndicators, excludedTechAtWorkingDir := mapFilesToRelevantWorkingDirectories(filesList, requestedDescriptors)
+	applyDescriptorPrecedence(workingDirectoryToIndicators, excludedTechAtWorkingDir, descriptorPrecedence)
 	var strJson string
 	if strJson, err = GetJsonIndent(workingDirectoryToIndicators); err != nil {
 		return
@@ -263,6 +324,64 @@ func mapFilesToRelevantWorkingDirectories(files []string, requestedDescriptors m
 	return
 }
 
+// When the indicators of several conflicting technologies are found in a working directory, only one of them should be detected.
+// The technology with the highest-precedence descriptor in the directory wins, and the other technologies are excluded from the directory.
+// Technologies with configured precedence are preferred over the technologies with the default precedence.
+// If none of the conflicting technologies has a precedence descriptor in the directory, the technologies' exclusions apply as usual.
+func applyDescriptorPrecedence(workingDirectoryToIndicators map[string][]string, excludedTechAtWorkingDir map[string][]Technology, descriptorPrecedence map[Technology][]string) {
+	for wd, files := range workingDirectoryToIndicators {
+		for _, group := range conflictingTechnologies {
+			winner := getPrecedenceWinner(group, files, descriptorPrecedence)
+			if winner == "" {
+				continue
+			}
+			log.Debug(fmt.Sprintf("%s was chosen over the other technologies in '%s' by the descriptors precedence.", winner.ToFormal(), wd))
+			var excluded []Technology
+			for _, tech := range excludedTechAtWorkingDir[wd] {
+				if !slices.Contains(group, tech) {
+					excluded = append(excluded, tech)
+				}
+			}
+			for _, tech := range group {
+				if tech != winner {
+					excluded = append(excluded, tech)
+				}
+			}
+			excludedTechAtWorkingDir[wd] = excluded
+		}
+	}
+}
+
+// Returns the technology of the group that has the highest-precedence descriptor in the given files.
+// Returns an empty technology if less than two technologies of the group have indicators in the files, or none of them has a precedence descriptor.
+func getPrecedenceWinner(group []Technology, files []string, descriptorPrecedence map[Technology][]string) (winner Technology) {
+	candidates := 0
+	bestRank, bestConfigured := 0, false
+	for _, tech := range group {
+		if !slices.ContainsFunc(files, func(path string) bool { return isIndicator(path, technologiesData[tech]) }) {
+			continue
+		}
+		candidates++
+		descriptors, configured := descriptorPrecedence[tech]
+		if !configured {
+			descriptors = defaultDescriptorPrecedence[tech]
+		}
+		rank := slices.IndexFunc(descriptors, func(descriptor string) bool {
+			return slices.ContainsFunc(files, func(path string) bool { return strings.HasSuffix(path, descriptor) })
+		})
+		if rank < 0 {
+			continue
+		}
+		if winner == "" || (configured && !bestConfigured) || (configured == bestConfigured && rank < bestRank) {
+			winner, bestRank, bestConfigured = tech, rank, configured
+		}
+	}
+	if candidates < 2 {
+		return ""
+	}
+	return
+}
+
 func isDescriptor(path string, techData TechData) bool {
 	for _, descriptor := range techData.packageDescriptors {
 		if strings.HasSuffix(path, descriptor) {
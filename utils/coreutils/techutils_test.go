@@ -23,6 +23,8 @@ func TestDetectTechnologiesByFilePaths(t *testing.T) {
 		{"windowsPipenvTest", []string{"c:\\users\\test\\package\\Pipfile"}, map[Technology]bool{Pipenv: true}},
 		{"golangTest", []string{"/Users/eco/dev/jfrog-cli-core/go.mod"}, map[Technology]bool{Go: true}},
 		{"windowsNugetTest", []string{"c:\\users\\test\\package\\project.sln"}, map[Technology]bool{Nuget: true, Dotnet: true}},
+		{"swiftTest", []string{"/Users/eco/dev/acme-app/Package.resolved"}, map[Technology]bool{Swift: true}},
+		{"bazelTest", []string{"/Users/eco/dev/acme-app/MODULE.bazel"}, map[Technology]bool{Bazel: true}},
 		{"noTechTest", []string{"pomxml"}, map[Technology]bool{}},
 	}
 
@@ -481,3 +483,68 @@ func TestContainsApplicabilityScannableTech(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyDescriptorPrecedence(t *testing.T) {
+	npmAndYarnFiles := []string{filepath.Join("dir", "package.json"), filepath.Join("dir", "package-lock.json"), filepath.Join("dir", "yarn.lock")}
+	pythonFiles := []string{filepath.Join("dir", "pyproject.toml"), filepath.Join("dir", "poetry.lock"), filepath.Join("dir", "requirements.txt")}
+	tests := []struct {
+		name                 string
+		files                []string
+		excluded             []Technology
+		descriptorPrecedence map[Technology][]string
+		expectedExcluded     []Technology
+	}{
+		{
+			name:             "Default precedence between npm and Yarn lockfiles",
+			files:            npmAndYarnFiles,
+			excluded:         []Technology{Npm},
+			expectedExcluded: []Technology{Npm},
+		},
+		{
+			name:                 "Configured precedence wins over the default",
+			files:                npmAndYarnFiles,
+			excluded:             []Technology{Npm},
+			descriptorPrecedence: map[Technology][]string{Npm: {"package-lock.json"}},
+			expectedExcluded:     []Technology{Yarn},
+		},
+		{
+			name:             "Lockfile is preferred over a Yarn manifest",
+			files:            []string{filepath.Join("dir", "package.json"), filepath.Join("dir", "package-lock.json"), filepath.Join("dir", ".yarnrc.yml")},
+			excluded:         []Technology{Npm},
+			expectedExcluded: []Technology{Yarn},
+		},
+		{
+			name:             "No precedence descriptors keeps the exclusions",
+			files:            []string{filepath.Join("dir", "package.json"), filepath.Join("dir", ".yarnrc.yml")},
+			excluded:         []Technology{Npm},
+			expectedExcluded: []Technology{Npm},
+		},
+		{
+			name:             "Default precedence between Poetry and Pip",
+			files:            pythonFiles,
+			excluded:         []Technology{Pip},
+			expectedExcluded: []Technology{Pipenv, Pip},
+		},
+		{
+			name:                 "Configured precedence between Poetry and Pip",
+			files:                pythonFiles,
+			excluded:             []Technology{Pip},
+			descriptorPrecedence: map[Technology][]string{Pip: {"requirements.txt"}},
+			expectedExcluded:     []Technology{Poetry, Pipenv},
+		},
+		{
+			name:                 "Non conflicting exclusions are kept",
+			files:                append([]string{filepath.Join("dir", "pom.xml")}, npmAndYarnFiles...),
+			excluded:             []Technology{Maven, Npm},
+			descriptorPrecedence: map[Technology][]string{Npm: {"package-lock.json"}},
+			expectedExcluded:     []Technology{Maven, Yarn},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			excludedTechAtWorkingDir := map[string][]Technology{"dir": test.excluded}
+			applyDescriptorPrecedence(map[string][]string{"dir": test.files}, excludedTechAtWorkingDir, test.descriptorPrecedence)
+			assert.ElementsMatch(t, test.expectedExcluded, excludedTechAtWorkingDir["dir"])
+		})
+	}
+}
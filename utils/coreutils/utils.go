@@ -361,6 +361,14 @@ func GetJfrogBackupDir() (string, error) {
 	return filepath.Join(homeDir, JfrogBackupDirName), nil
 }
 
+func GetJfrogAuditHistoryDir() (string, error) {
+	homeDir, err := GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, JfrogAuditHistoryDirName), nil
+}
+
 func GetJfrogPluginsDir() (string, error) {
 	homeDir, err := GetJfrogHomeDir()
 	if err != nil {
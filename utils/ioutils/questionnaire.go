@@ -51,6 +51,8 @@ type QuestionInfo struct {
 	Writer       AnswerWriter
 	MapKey       string
 	Callback     questionCallback
+	// If set, the options are only suggested, and answers that are not among them are accepted as well.
+	SuggestOnly bool
 }
 
 const (
@@ -173,6 +175,22 @@ func validateAnswer(answer string, options []prompt.Suggest, allowVars bool) boo
 	return false
 }
 
+// Ask question with free string answer, while suggesting the given options.
+// The answer may not be empty.
+func AskStringWithSuggestions(msg, promptPrefix string, options []prompt.Suggest) string {
+	if msg != "" {
+		log.Output(msg + PressTabMsg)
+	}
+	for {
+		answer := prompt.Input(promptPrefix+" ", prefixCompleter(options), interruptKeyBind())
+		answer = strings.TrimSpace(answer)
+		if answer != "" {
+			return answer
+		}
+		log.Output(EmptyValueMsg)
+	}
+}
+
 // Ask question with list of possible answers.
 // If the provided answer does not appear in list, confirm the choice.
 func AskFromListWithMismatchConfirmation(promptPrefix, misMatchMsg string, options []prompt.Suggest) string {
@@ -198,7 +216,9 @@ func AskFromListWithMismatchConfirmation(promptPrefix, misMatchMsg string, optio
 //  3. Run callback (if provided)
 func (iq *InteractiveQuestionnaire) AskQuestion(question QuestionInfo) (value string, err error) {
 	var answer string
-	if question.Options != nil {
+	if question.Options != nil && question.SuggestOnly {
+		answer = AskStringWithSuggestions(question.Msg, question.PromptPrefix, question.Options)
+	} else if question.Options != nil {
 		answer = AskFromList(question.Msg, question.PromptPrefix, question.AllowVars, question.Options, "")
 	} else {
 		answer = AskString(question.Msg, question.PromptPrefix, false, question.AllowVars)
@@ -64,6 +64,15 @@ func GetPypiRepoUrl(serverDetails *config.ServerDetails, repository string) (str
 }
 
 func ConfigPoetryRepo(url, username, password, configRepoName string) error {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return ConfigPoetryProjectRepo(currentDir, url, username, password, configRepoName)
+}
+
+// Configures the poetry repository, and adds it to the pyproject.toml file of the project in the given directory.
+func ConfigPoetryProjectRepo(projectDir, url, username, password, configRepoName string) error {
 	// Add the poetry repository config
 	err := runPoetryConfigCommand([]string{poetryConfigRepoPrefix + configRepoName, url}, false)
 	if err != nil {
@@ -77,11 +86,7 @@ func ConfigPoetryRepo(url, username, password, configRepoName string) error {
 	}
 
 	// Add the repository config to the pyproject.toml
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return errorutils.CheckError(err)
-	}
-	return addRepoToPyprojectFile(filepath.Join(currentDir, pyproject), configRepoName, url)
+	return addRepoToPyprojectFile(filepath.Join(projectDir, pyproject), configRepoName, url)
 }
 
 func runPoetryConfigCommand(args []string, maskArgs bool) error {
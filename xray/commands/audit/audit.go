@@ -2,12 +2,17 @@ package audit
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/dependencies"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/scangraph"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray"
 	"github.com/jfrog/jfrog-client-go/xray/services"
@@ -24,6 +29,12 @@ type AuditCommand struct {
 	IncludeLicenses        bool
 	Fail                   bool
 	PrintExtendedTable     bool
+	// Whether to compare the results with the last results of the current git branch, and save them for the next comparison.
+	trackHistory bool
+	// The directory of the saved results. Defaults to the audit history directory under the JFrog home directory.
+	historyDir string
+	// Maps Xray severities to the SARIF levels of their issues in the SARIF output, overriding the default mapping.
+	severityMapping map[string]string
 	AuditParams
 }
 
@@ -66,6 +77,21 @@ func (auditCmd *AuditCommand) SetPrintExtendedTable(printExtendedTable bool) *Au
 	return auditCmd
 }
 
+func (auditCmd *AuditCommand) SetTrackHistory(trackHistory bool) *AuditCommand {
+	auditCmd.trackHistory = trackHistory
+	return auditCmd
+}
+
+func (auditCmd *AuditCommand) SetHistoryDir(historyDir string) *AuditCommand {
+	auditCmd.historyDir = historyDir
+	return auditCmd
+}
+
+func (auditCmd *AuditCommand) SetSeverityMapping(severityMapping map[string]string) *AuditCommand {
+	auditCmd.severityMapping = severityMapping
+	return auditCmd
+}
+
 func (auditCmd *AuditCommand) CreateXrayGraphScanParams() *services.XrayGraphScanParams {
 	params := &services.XrayGraphScanParams{
 		RepoPath: auditCmd.targetRepoPath,
@@ -78,7 +104,8 @@ func (auditCmd *AuditCommand) CreateXrayGraphScanParams() *services.XrayGraphSca
 		params.ProjectKey = auditCmd.projectKey
 	}
 	params.IncludeVulnerabilities = auditCmd.IncludeVulnerabilities
-	params.IncludeLicenses = auditCmd.IncludeLicenses
+	// The allowed licenses are evaluated on the licenses that Xray returns.
+	params.IncludeLicenses = auditCmd.IncludeLicenses || auditCmd.licenseInventoryOnly || len(auditCmd.allowedLicenses) > 0
 	return params
 }
 
@@ -91,10 +118,50 @@ func (auditCmd *AuditCommand) Run() (err error) {
 		SetXrayGraphScanParams(auditCmd.CreateXrayGraphScanParams()).
 		SetWorkingDirs(workingDirs).
 		SetMinSeverityFilter(auditCmd.minSeverityFilter).
+		SetSeverityFilterByTech(auditCmd.severityFilterByTech).
 		SetFixableOnly(auditCmd.fixableOnly).
 		SetGraphBasicParams(auditCmd.AuditBasicParams).
 		SetThirdPartyApplicabilityScan(auditCmd.thirdPartyApplicabilityScan).
-		SetExclusions(auditCmd.exclusions)
+		SetExclusions(auditCmd.exclusions).
+		SetAdditionalXrayServers(auditCmd.additionalXrayServers).
+		SetSkipPrereleaseScan(auditCmd.skipPrereleaseScan).
+		SetLicenseInventoryOnly(auditCmd.licenseInventoryOnly).
+		SetDumpXrayTrafficDir(auditCmd.dumpXrayTrafficDir).
+		SetDescriptorPrecedence(auditCmd.descriptorPrecedence).
+		SetDiffBaseRef(auditCmd.diffBaseRef).
+		SetFailOnNoTechnologies(auditCmd.failOnNoTechnologies).
+		SetDeduplicateIdenticalModules(auditCmd.deduplicateIdenticalModules).
+		SetPostScanWebhook(auditCmd.postScanWebhook).
+		SetPostScanCommand(auditCmd.postScanCommand).
+		SetFailOnPostScanHookError(auditCmd.failOnPostScanHookError).
+		SetUseMonorepoConfig(auditCmd.useMonorepoConfig).
+		SetResumeFrom(auditCmd.resumeFrom).
+		SetBuildInfoGraph(auditCmd.buildName, auditCmd.buildNumber).
+		SetLogFormat(auditCmd.logFormat).
+		SetTransitiveOnly(auditCmd.transitiveOnly).
+		SetAllowedLicenses(auditCmd.allowedLicenses).
+		SetCheckStaleness(auditCmd.checkStaleness).
+		SetStalenessRegistries(auditCmd.stalenessRegistries).
+		SetImpactPathWorkers(auditCmd.impactPathWorkers).
+		SetScanPlanFile(auditCmd.scanPlanFile).
+		SetScanPlanOutputFile(auditCmd.scanPlanOutputFile).
+		SetCvesPublishedSince(auditCmd.cvesPublishedSince).
+		SetIncludeUndatedCves(auditCmd.includeUndatedCves).
+		SetOnTechnologyMismatch(auditCmd.onTechnologyMismatch).
+		SetMaxParallelScans(auditCmd.maxParallelScans).
+		SetDependencyTreeCache(auditCmd.dependencyTreeCache).
+		SetScanTimeout(auditCmd.scanTimeout).
+		SetExcludePatternsMode(auditCmd.excludePatternsMode).
+		SetDisabledDefaultExcludePatterns(auditCmd.disabledDefaultExcludePatterns).
+		SetIncludePatterns(auditCmd.includePatterns).
+		SetDependencyTreeOutputFile(auditCmd.dependencyTreeOutputFile).
+		SetSbomOutput(auditCmd.sbomOutputPath, auditCmd.sbomFormat).
+		SetMaxParallelGraphScans(auditCmd.maxParallelGraphScans).
+		SetScanRetries(auditCmd.scanRetries).
+		SetScanRetryBaseDelay(auditCmd.scanRetryBaseDelay).
+		SetOfflineTreeOnly(auditCmd.offlineTreeOnly).
+		SetChangedSince(auditCmd.changedSince).
+		SetDryRun(auditCmd.dryRun)
 	auditResults, err := RunAudit(auditParams)
 	if err != nil {
 		return
@@ -104,6 +171,16 @@ func (auditCmd *AuditCommand) Run() (err error) {
 			return
 		}
 	}
+	if auditCmd.dryRun {
+		// Nothing was scanned, so there are no results to print.
+		return auditResults.ScaError
+	}
+	if auditCmd.licenseInventoryOnly {
+		if err = xrayutils.PrintLicenseInventory(xrayutils.GetLicenseInventory(auditResults), auditCmd.OutputFormat()); err != nil {
+			return
+		}
+		return auditResults.ScaError
+	}
 	var messages []string
 	if !auditResults.ExtendedScanResults.EntitledForJas {
 		messages = []string{coreutils.PrintTitle("The ‘jf audit’ command also supports JFrog Advanced Security features, such as 'Contextual Analysis', 'Secret Detection', 'IaC Scan' and ‘SAST’.\nThis feature isn't enabled on your system. Read more - ") + coreutils.PrintLink("https://jfrog.com/xray/")}
@@ -118,6 +195,11 @@ func (auditCmd *AuditCommand) Run() (err error) {
 			SetOutputFormat(auditCmd.OutputFormat()).
 			SetPrintExtendedTable(auditCmd.PrintExtendedTable).
 			SetExtraMessages(messages).
+			SetSeverityMapping(auditCmd.severityMapping).
+			SetTransitiveOnly(auditCmd.transitiveOnly).
+			SetAllowedLicenses(auditCmd.allowedLicenses).
+			SetCvesPublishedSince(auditCmd.cvesPublishedSince).
+			SetIncludeUndatedCves(auditCmd.includeUndatedCves).
 			SetScanType(services.Dependency).
 			PrintScanResults(); err != nil {
 			return
@@ -126,6 +208,11 @@ func (auditCmd *AuditCommand) Run() (err error) {
 	if err = errors.Join(auditResults.ScaError, auditResults.JasError); err != nil {
 		return
 	}
+	if auditCmd.trackHistory {
+		if err = auditCmd.trackAuditHistory(auditResults); err != nil {
+			return
+		}
+	}
 
 	// Only in case Xray's context was given (!auditCmd.IncludeVulnerabilities), and the user asked to fail the build accordingly, do so.
 	if auditCmd.Fail && !auditCmd.IncludeVulnerabilities && xrayutils.CheckIfFailBuild(auditResults.GetScaScansXrayResults()) {
@@ -134,6 +221,43 @@ func (auditCmd *AuditCommand) Run() (err error) {
 	return
 }
 
+// Prints the delta between the results and the last results of the current git branch, and saves the results for the next run.
+func (auditCmd *AuditCommand) trackAuditHistory(auditResults *xrayutils.Results) (err error) {
+	branch, err := getCurrentGitBranch()
+	if err != nil {
+		return
+	}
+	if branch == "" {
+		log.Warn("Skipping the comparison with the previous audit, since the current git branch could not be determined.")
+		return
+	}
+	historyDir := auditCmd.historyDir
+	if historyDir == "" {
+		if historyDir, err = coreutils.GetJfrogAuditHistoryDir(); err != nil {
+			return
+		}
+	}
+	delta, err := xrayutils.TrackAuditHistory(historyDir, branch, auditResults)
+	if err != nil || delta == nil {
+		return
+	}
+	xrayutils.PrintAuditHistoryDelta(delta)
+	return
+}
+
+// Returns the name of the git branch of the working directory, or an empty string if it isn't in a git repository or HEAD is detached.
+func getCurrentGitBranch() (string, error) {
+	dotGitParent, exists, err := fileutils.FindUpstream(".git", fileutils.Any)
+	if err != nil || !exists {
+		return "", err
+	}
+	gitManager := clientutils.NewGitManager(filepath.Join(dotGitParent, ".git"))
+	if err = gitManager.ReadConfig(); err != nil {
+		return "", err
+	}
+	return gitManager.GetBranch(), nil
+}
+
 func (auditCmd *AuditCommand) CommandName() string {
 	return "generic_audit"
 }
@@ -149,27 +273,54 @@ func RunAudit(auditParams *AuditParams) (results *xrayutils.Results, err error)
 	if err != nil {
 		return
 	}
-	var xrayManager *xray.XrayServicesManager
-	if xrayManager, auditParams.xrayVersion, err = xrayutils.CreateXrayServiceManagerAndGetVersion(serverDetails); err != nil {
+	if auditParams.dryRun {
+		// Only the plan of the SCA scans is logged, so neither Xray nor the Advanced Security scanners are needed.
+		results.ScaError = runScaScan(auditParams, results)
 		return
 	}
-	if err = clientutils.ValidateMinimumVersion(clientutils.Xray, auditParams.xrayVersion, scangraph.GraphScanMinXrayVersion); err != nil {
-		return
-	}
-	results.XrayVersion = auditParams.xrayVersion
-	results.ExtendedScanResults.EntitledForJas, err = isEntitledForJas(xrayManager, auditParams.xrayVersion)
-	if err != nil {
-		return
+	if auditParams.offlineTreeOnly {
+		// Xray isn't contacted at all, so neither its version nor the entitlement for Advanced Security are checked.
+		if err = validateOfflineTreeOnly(auditParams); err != nil {
+			return
+		}
+		log.Info("Building the dependency trees without scanning them with Xray...")
+	} else {
+		var xrayManager *xray.XrayServicesManager
+		if xrayManager, auditParams.xrayVersion, err = xrayutils.CreateXrayServiceManagerAndGetVersion(serverDetails); err != nil {
+			return
+		}
+		if err = clientutils.ValidateMinimumVersion(clientutils.Xray, auditParams.xrayVersion, scangraph.GraphScanMinXrayVersion); err != nil {
+			return
+		}
+		results.XrayVersion = auditParams.xrayVersion
+		results.ExtendedScanResults.EntitledForJas, err = isEntitledForJas(xrayManager, auditParams.xrayVersion)
+		if err != nil {
+			return
+		}
 	}
 
+	// The Advanced Security scanners are not relevant for a license inventory.
+	runJasScanners := results.ExtendedScanResults.EntitledForJas && !auditParams.licenseInventoryOnly
 	errGroup := new(errgroup.Group)
-	if results.ExtendedScanResults.EntitledForJas {
+	if runJasScanners {
 		// Download (if needed) the analyzer manager in a background routine.
 		errGroup.Go(dependencies.DownloadAnalyzerManagerIfNeeded)
 	}
 
 	// The sca scan doesn't require the analyzer manager, so it can run separately from the analyzer manager download routine.
 	results.ScaError = runScaScan(auditParams, results) // runScaScan(auditParams, results)
+	if coverage := results.GetScaScansCoverage(); coverage != nil {
+		log.Info(fmt.Sprintf("The SCA scans covered %.1f%% of the dependencies.", coverage.Coverage()*100))
+	}
+	if auditParams.checkStaleness {
+		addDependenciesStaleness(results, getPackageMetadataRegistries(auditParams), time.Now())
+	}
+	// A failure of the hooks fails the audit only once the Advanced Security scanners are done, so their results are still set.
+	hookErr := runPostScanHooks(auditParams, results)
+	if hookErr != nil && !auditParams.failOnPostScanHookError {
+		log.Warn(hookErr.Error())
+		hookErr = nil
+	}
 
 	// Wait for the Download of the AnalyzerManager to complete.
 	if err = errGroup.Wait(); err != nil {
@@ -177,12 +328,24 @@ func RunAudit(auditParams *AuditParams) (results *xrayutils.Results, err error)
 	}
 
 	// Run scanners only if the user is entitled for Advanced Security
-	if results.ExtendedScanResults.EntitledForJas {
+	if runJasScanners {
 		results.JasError = runJasScannersAndSetResults(results, auditParams.DirectDependencies(), serverDetails, auditParams.workingDirs, auditParams.Progress(), auditParams.xrayGraphScanParams.MultiScanId, auditParams.thirdPartyApplicabilityScan)
 	}
+	err = errors.Join(err, hookErr)
 	return
 }
 
+// The audits that need the JFrog Platform for more than the Xray scan can't run offline.
+func validateOfflineTreeOnly(auditParams *AuditParams) error {
+	if auditParams.buildName != "" {
+		return errorutils.CheckErrorf("the dependency graph of a build can't be audited offline, since it's read from Artifactory")
+	}
+	if auditParams.resumeFrom != "" {
+		return errorutils.CheckErrorf("an offline audit can't be resumed from a checkpoint, since its scans have no Xray results to record")
+	}
+	return nil
+}
+
 func isEntitledForJas(xrayManager *xray.XrayServicesManager, xrayVersion string) (entitled bool, err error) {
 	if e := clientutils.ValidateMinimumVersion(clientutils.Xray, xrayVersion, xrayutils.EntitlementsMinVersion); e != nil {
 		log.Debug(e)
@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The format of the messages that report the plan and the progress of the SCA scans.
+type LogFormat string
+
+const (
+	// Human-readable messages.
+	TextLogFormat LogFormat = "text"
+	// A JSON object in each message, with stable field names, for log consumers.
+	JsonLogFormat LogFormat = "json"
+)
+
+func GetLogFormat(logFormat string) (LogFormat, error) {
+	switch LogFormat(logFormat) {
+	case "", TextLogFormat:
+		return TextLogFormat, nil
+	case JsonLogFormat:
+		return JsonLogFormat, nil
+	}
+	return "", errorutils.CheckErrorf("unsupported log format '%s', the supported formats are '%s' and '%s'", logFormat, TextLogFormat, JsonLogFormat)
+}
+
+// The events of the SCA scans, that are logged in the JSON log format.
+const (
+	scansPlannedEvent  = "scaScansPlanned"
+	scanStartedEvent   = "scaScanStarted"
+	scanSharedEvent    = "scaScanShared"
+	scanRestoredEvent  = "scaScanRestored"
+	scanCompletedEvent = "scaScanCompleted"
+	scanFailedEvent    = "scaScanFailed"
+)
+
+type scaScanLogEvent struct {
+	Event string `json:"event"`
+	// The planned scans, in the scaScansPlanned event.
+	Scans []scaScanLogEntry `json:"scans,omitempty"`
+	// The scan that the event refers to, in the other events.
+	*scaScanLogEntry
+	// The working directory of the identical module whose results are used, in the scaScanShared event.
+	SharedScanWorkingDirectory string `json:"sharedScanWorkingDirectory,omitempty"`
+	Error                      string `json:"error,omitempty"`
+}
+
+type scaScanLogEntry struct {
+	Technology       string   `json:"technology"`
+	WorkingDirectory string   `json:"workingDirectory"`
+	Descriptors      []string `json:"descriptors,omitempty"`
+}
+
+func newScaScanLogEntry(scan *xrayutils.ScaScanResult) *scaScanLogEntry {
+	return &scaScanLogEntry{Technology: scan.Technology.String(), WorkingDirectory: scan.WorkingDirectory, Descriptors: scan.Descriptors}
+}
+
+func logScaScanEvent(event scaScanLogEvent) {
+	content, err := json.Marshal(event)
+	if err != nil {
+		log.Debug("Couldn't marshal the", event.Event, "log event:", err.Error())
+		return
+	}
+	log.Info(string(content))
+}
+
+func logScaScansPlanned(params *AuditParams, scans []*xrayutils.ScaScanResult) error {
+	if params.logFormat == JsonLogFormat {
+		event := scaScanLogEvent{Event: scansPlannedEvent, Scans: []scaScanLogEntry{}}
+		for _, scan := range scans {
+			event.Scans = append(event.Scans, *newScaScanLogEntry(scan))
+		}
+		logScaScanEvent(event)
+		return nil
+	}
+	scanInfo, err := coreutils.GetJsonIndent(scans)
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Preforming %d SCA scans:\n%s", len(scans), scanInfo))
+	return nil
+}
+
+func logScaScanStarted(params *AuditParams, scan *xrayutils.ScaScanResult) {
+	if params.logFormat == JsonLogFormat {
+		logScaScanEvent(scaScanLogEvent{Event: scanStartedEvent, scaScanLogEntry: newScaScanLogEntry(scan)})
+		return
+	}
+	log.Info("Running SCA scan for", scan.Technology, "vulnerable dependencies in", scan.WorkingDirectory, "directory...")
+}
+
+func logScaScanShared(params *AuditParams, scan, sharedScan *xrayutils.ScaScanResult) {
+	if params.logFormat == JsonLogFormat {
+		logScaScanEvent(scaScanLogEvent{Event: scanSharedEvent, scaScanLogEntry: newScaScanLogEntry(scan), SharedScanWorkingDirectory: sharedScan.WorkingDirectory})
+		return
+	}
+	log.Info(fmt.Sprintf("The %s module in '%s' is identical to the module in '%s'. Using its scan results...", scan.Technology.ToFormal(), scan.WorkingDirectory, sharedScan.WorkingDirectory))
+}
+
+func logScaScanRestored(params *AuditParams, scan *xrayutils.ScaScanResult) {
+	if params.logFormat == JsonLogFormat {
+		logScaScanEvent(scaScanLogEvent{Event: scanRestoredEvent, scaScanLogEntry: newScaScanLogEntry(scan)})
+		return
+	}
+	log.Info(fmt.Sprintf("The %s scan in '%s' was already completed according to the checkpoint. Using its recorded results...", scan.Technology.ToFormal(), scan.WorkingDirectory))
+}
+
+// The completion and the failure of the scans are logged as events in the JSON log format only.
+// In the text format, the failures are reported with the audit's error.
+func logScaScanDone(params *AuditParams, scan *xrayutils.ScaScanResult, scanErr error) {
+	if params.logFormat != JsonLogFormat {
+		return
+	}
+	if scanErr != nil {
+		logScaScanEvent(scaScanLogEvent{Event: scanFailedEvent, scaScanLogEntry: newScaScanLogEntry(scan), Error: scanErr.Error()})
+		return
+	}
+	logScaScanEvent(scaScanLogEvent{Event: scanCompletedEvent, scaScanLogEntry: newScaScanLogEntry(scan)})
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonLogFormat(t *testing.T) {
+	_, logBuffer, previousLog := tests.RedirectLogOutputToBuffer()
+	defer log.SetLogger(previousLog)
+
+	params := NewAuditParams().SetLogFormat(JsonLogFormat)
+	scans := []*xrayutils.ScaScanResult{
+		{Technology: coreutils.Npm, WorkingDirectory: "/project/frontend", Descriptors: []string{"/project/frontend/package.json"}},
+		{Technology: coreutils.Go, WorkingDirectory: "/project/backend"},
+	}
+	assert.NoError(t, logScaScansPlanned(params, scans))
+	logScaScanStarted(params, scans[0])
+	logScaScanDone(params, scans[0], nil)
+	logScaScanDone(params, scans[1], errors.New("no dependencies were found"))
+
+	var events []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+		// Each message is a JSON object, following the log level prefix.
+		event := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(line[strings.Index(line, "{"):]), &event), line)
+		events = append(events, event)
+	}
+	if !assert.Len(t, events, 4) {
+		return
+	}
+	assert.Equal(t, map[string]any{
+		"event": "scaScansPlanned",
+		"scans": []any{
+			map[string]any{"technology": "npm", "workingDirectory": "/project/frontend", "descriptors": []any{"/project/frontend/package.json"}},
+			map[string]any{"technology": "go", "workingDirectory": "/project/backend"},
+		},
+	}, events[0])
+	assert.Equal(t, map[string]any{"event": "scaScanStarted", "technology": "npm", "workingDirectory": "/project/frontend", "descriptors": []any{"/project/frontend/package.json"}}, events[1])
+	assert.Equal(t, "scaScanCompleted", events[2]["event"])
+	assert.Equal(t, map[string]any{"event": "scaScanFailed", "technology": "go", "workingDirectory": "/project/backend", "error": "no dependencies were found"}, events[3])
+}
+
+func TestGetLogFormat(t *testing.T) {
+	for _, value := range []string{"", "text"} {
+		logFormat, err := GetLogFormat(value)
+		assert.NoError(t, err)
+		assert.Equal(t, TextLogFormat, logFormat)
+	}
+	logFormat, err := GetLogFormat("json")
+	assert.NoError(t, err)
+	assert.Equal(t, JsonLogFormat, logFormat)
+	_, err = GetLogFormat("xml")
+	assert.ErrorContains(t, err, "unsupported log format 'xml'")
+}
@@ -1,21 +1,120 @@
 package audit
 
 import (
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	"github.com/jfrog/jfrog-client-go/xray/services"
+	"golang.org/x/exp/slices"
 )
 
 type AuditParams struct {
 	xrayGraphScanParams *services.XrayGraphScanParams
 	workingDirs         []string
-	exclusions          []string
-	installFunc         func(tech string) error
-	fixableOnly         bool
-	minSeverityFilter   string
+	// Patterns of paths to exclude from the technologies detection, projects in these paths are not scanned at all.
+	// To detect and scan a project while excluding some of its sub-paths from the dependencies walk, use the scan exclusions instead.
+	exclusions        []string
+	installFunc       func(tech string) error
+	fixableOnly       bool
+	minSeverityFilter string
+	// Maps technologies to the minimum severity of their issues, overriding the global minSeverityFilter for their scans.
+	severityFilterByTech map[coreutils.Technology]string
 	*xrayutils.AuditBasicParams
 	xrayVersion string
 	// Include third party dependencies source code in the applicability scan.
 	thirdPartyApplicabilityScan bool
+	// Additional Xray servers (for example, other JPDs in a federation) to scan the dependency trees with.
+	// The results of all the servers are merged into a single result.
+	additionalXrayServers []*config.ServerDetails
+	// Exclude pre-release components (such as SNAPSHOT versions) from the Xray scan, while keeping them in the dependency trees.
+	skipPrereleaseScan bool
+	// Scan only for the licenses of the dependencies, to create a license inventory.
+	// Vulnerability-focused processing, such as the Advanced Security scanners, is skipped.
+	licenseInventoryOnly bool
+	// If set, the submitted graph and the Xray response of each scan are written to files in this directory, for debugging.
+	dumpXrayTrafficDir string
+	// Decides which technology is detected in a directory with descriptors of conflicting technologies (such as npm and Yarn).
+	// Maps each technology to its descriptors by order of precedence, overriding the default precedence, which prefers lockfiles over manifests.
+	descriptorPrecedence map[coreutils.Technology][]string
+	// If set, only the dependencies that were added since this git ref (for example, the base branch of a pull request) are scanned.
+	diffBaseRef string
+	// Fail the audit when no technologies are detected, instead of skipping the SCA scan.
+	failOnNoTechnologies bool
+	// Scan the modules of the same technology and identical descriptors once, and share the results between them.
+	deduplicateIdenticalModules bool
+	// If set, a summary of the SCA results is sent to this URL in an HTTP POST request, when the SCA scan is done.
+	postScanWebhook string
+	// If set, this command is executed when the SCA scan is done, with the path of a file with a summary of the SCA results as its last argument.
+	postScanCommand []string
+	// Fail the audit if the post-scan webhook or command fails. By default, the failure is only logged.
+	failOnPostScanHookError bool
+	// Scan the projects that are declared by the monorepo orchestrator (Nx, Lerna or Turborepo) of the working directory as the modules, instead of detecting them recursively.
+	useMonorepoConfig bool
+	// If set, the completed SCA scans are recorded in this checkpoint file, and the scans that were already recorded in it are not run again.
+	// This allows resuming an interrupted audit.
+	resumeFrom string
+	// If set, the dependency graph is taken from the published build-info of this build, instead of being built from the project in the working directory.
+	buildName   string
+	buildNumber string
+	// The format of the messages that report the plan and the progress of the SCA scans.
+	logFormat LogFormat
+	// Report only the findings of the transitive dependencies. The scan itself isn't affected, and the full results still include the findings of the direct dependencies.
+	transitiveOnly bool
+	// The licenses that the components may have. Components with other licenses are reported as violations of this local policy, which is evaluated on the client regardless of the Xray watches.
+	allowedLicenses []string
+	// Annotate the direct dependencies with their latest available versions and the age of their releases, by querying their registries.
+	checkStaleness bool
+	// The URLs of the registries to read the release metadata of the direct dependencies from when checking their staleness, by their package types ('npm' or 'pypi').
+	// Such a URL may be of an Artifactory remote repository, which is accessed with the credentials of the server. The public registries are used for the package types without URLs.
+	stalenessRegistries map[string]string
+	// The exclude patterns that are applied when detecting the technologies, pinned when they are first resolved.
+	effectiveExclusions []string
+	// The number of workers that build the impact paths of the findings in parallel. The impact paths are built sequentially by default.
+	impactPathWorkers int
+	// If set, the scans to perform are read from this scan plan file, instead of being detected.
+	scanPlanFile string
+	// If set, the plan of the scans to perform is written to this file, so it can be edited and given back as the scan plan file.
+	scanPlanOutputFile string
+	// If set, only the vulnerabilities and violations with CVEs that were published since this time are reported, by the publication dates of the CVEs.
+	// The scan itself isn't affected, and the full results still include all the findings.
+	cvesPublishedSince time.Time
+	// Report the findings whose CVEs have no known publication dates when filtering by the publication dates of the CVEs.
+	includeUndatedCves bool
+	// How to handle a requested technology whose descriptors weren't detected in a requested directory. Its scan is skipped with a warning by default.
+	onTechnologyMismatch TechnologyMismatchPolicy
+	// The maximum number of working directories whose SCA scans run in parallel. The scans run one at a time by default.
+	maxParallelScans int
+	// Whether to reuse the dependency trees that previous audits built, as long as the descriptors of their projects weren't changed.
+	dependencyTreeCache bool
+	// The maximum duration of the scan of each working directory. Zero means no timeout.
+	scanTimeout time.Duration
+	// Whether the requested exclusions replace the default exclusions or are added to them.
+	excludePatternsMode ExcludePatternsMode
+	// Default exclude patterns that aren't applied, even when the default exclusions are.
+	disabledDefaultExcludePatterns []string
+	// The path of the file to write the built dependency trees to, as JSON.
+	dependencyTreeOutputFile string
+	// The dependency trees to write to the dependency tree output file, by the technologies and the working directories of their scans.
+	exportedDependencyTrees map[string]*exportedDependencyTree
+	// The path of the file to write the SBOM of the scanned projects to, and its format.
+	sbomOutputPath string
+	sbomFormat     SbomFormat
+	// The maximum number of graph scan requests of a single working directory that are submitted to Xray in parallel, when its tree is split into batches.
+	maxParallelGraphScans int
+	// The number of times a graph scan that failed with a transient error is retried, and the wait before the first retry, which is doubled before each of the following retries.
+	scanRetries        int
+	scanRetryBaseDelay time.Duration
+	// Whether to only build the dependency trees, without scanning them with Xray, so the audit can run where Xray is unreachable.
+	offlineTreeOnly bool
+	// If set, only the detected modules that have files that were changed since this git ref are scanned.
+	changedSince string
+	// Whether to only log the plan of the SCA scans, without executing them.
+	dryRun bool
+	// If set, only the detected projects whose directories, relative to the current working directory, match one of these patterns are scanned.
+	// The exclusions take precedence, so a project in a path that matches both an include pattern and an exclusion isn't scanned.
+	includePatterns []string
 }
 
 func NewAuditParams() *AuditParams {
@@ -47,9 +146,43 @@ func (params *AuditParams) Exclusions() []string {
 
 func (params *AuditParams) SetExclusions(exclusions []string) *AuditParams {
 	params.exclusions = exclusions
+	params.effectiveExclusions = nil
 	return params
 }
 
+// Returns the exclude patterns that are applied when detecting the technologies: the default exclusions if none were requested.
+// Otherwise, the requested exclusions, which replace the default exclusions, or follow them in the append mode.
+// The disabled default patterns are removed from the default exclusions in both cases.
+// The patterns are copied when they are first resolved, so all the steps of the audit apply the same patterns, even if the defaults are modified meanwhile.
+func (params *AuditParams) EffectiveExclusions() []string {
+	if params.effectiveExclusions == nil {
+		switch {
+		case len(params.exclusions) == 0:
+			params.effectiveExclusions = params.enabledDefaultExcludePatterns()
+		case params.ExcludePatternsMode() == AppendExcludePatterns:
+			params.effectiveExclusions = params.enabledDefaultExcludePatterns()
+			for _, exclusion := range params.exclusions {
+				if !slices.Contains(params.effectiveExclusions, exclusion) {
+					params.effectiveExclusions = append(params.effectiveExclusions, exclusion)
+				}
+			}
+		default:
+			params.effectiveExclusions = slices.Clone(params.exclusions)
+		}
+	}
+	return params.effectiveExclusions
+}
+
+func (params *AuditParams) enabledDefaultExcludePatterns() []string {
+	enabledPatterns := []string{}
+	for _, pattern := range DefaultExcludePatterns {
+		if !slices.Contains(params.disabledDefaultExcludePatterns, pattern) {
+			enabledPatterns = append(enabledPatterns, pattern)
+		}
+	}
+	return enabledPatterns
+}
+
 func (params *AuditParams) SetXrayGraphScanParams(xrayGraphScanParams *services.XrayGraphScanParams) *AuditParams {
 	params.xrayGraphScanParams = xrayGraphScanParams
 	return params
@@ -88,6 +221,23 @@ func (params *AuditParams) SetMinSeverityFilter(minSeverityFilter string) *Audit
 	return params
 }
 
+func (params *AuditParams) SeverityFilterByTech() map[coreutils.Technology]string {
+	return params.severityFilterByTech
+}
+
+func (params *AuditParams) SetSeverityFilterByTech(severityFilterByTech map[coreutils.Technology]string) *AuditParams {
+	params.severityFilterByTech = severityFilterByTech
+	return params
+}
+
+// Returns the minimum severity filter of the given technology, falling back to the global filter.
+func (params *AuditParams) minSeverityFilterOfTech(tech coreutils.Technology) string {
+	if severity, exists := params.severityFilterByTech[tech]; exists {
+		return severity
+	}
+	return params.minSeverityFilter
+}
+
 func (params *AuditParams) SetThirdPartyApplicabilityScan(includeThirdPartyDeps bool) *AuditParams {
 	params.thirdPartyApplicabilityScan = includeThirdPartyDeps
 	return params
@@ -97,3 +247,418 @@ func (params *AuditParams) SetDepsRepo(depsRepo string) *AuditParams {
 	params.AuditBasicParams.SetDepsRepo(depsRepo)
 	return params
 }
+
+func (params *AuditParams) SetScanExclusions(scanExclusions []string) *AuditParams {
+	params.AuditBasicParams.SetScanExclusions(scanExclusions)
+	return params
+}
+
+func (params *AuditParams) SetScanHelmImages(scanHelmImages bool) *AuditParams {
+	params.AuditBasicParams.SetScanHelmImages(scanHelmImages)
+	return params
+}
+
+func (params *AuditParams) SetMavenOffline(mavenOffline bool) *AuditParams {
+	params.AuditBasicParams.SetMavenOffline(mavenOffline)
+	return params
+}
+
+func (params *AuditParams) SetScanVendored(scanVendored bool) *AuditParams {
+	params.AuditBasicParams.SetScanVendored(scanVendored)
+	return params
+}
+
+func (params *AuditParams) SetScanDockerfileBaseImages(scanDockerfileBaseImages bool) *AuditParams {
+	params.AuditBasicParams.SetScanDockerfileBaseImages(scanDockerfileBaseImages)
+	return params
+}
+
+func (params *AuditParams) SetUseGradleDaemon(useGradleDaemon bool) *AuditParams {
+	params.AuditBasicParams.SetUseGradleDaemon(useGradleDaemon)
+	return params
+}
+
+func (params *AuditParams) SetUseGradleConfigurationCache(useGradleConfigurationCache bool) *AuditParams {
+	params.AuditBasicParams.SetUseGradleConfigurationCache(useGradleConfigurationCache)
+	return params
+}
+
+func (params *AuditParams) SetResolutionFallback(repo string) *AuditParams {
+	params.AuditBasicParams.SetResolutionFallback(repo)
+	return params
+}
+
+func (params *AuditParams) AdditionalXrayServers() []*config.ServerDetails {
+	return params.additionalXrayServers
+}
+
+func (params *AuditParams) SetAdditionalXrayServers(additionalXrayServers []*config.ServerDetails) *AuditParams {
+	params.additionalXrayServers = additionalXrayServers
+	return params
+}
+
+func (params *AuditParams) SkipPrereleaseScan() bool {
+	return params.skipPrereleaseScan
+}
+
+func (params *AuditParams) SetSkipPrereleaseScan(skipPrereleaseScan bool) *AuditParams {
+	params.skipPrereleaseScan = skipPrereleaseScan
+	return params
+}
+
+func (params *AuditParams) LicenseInventoryOnly() bool {
+	return params.licenseInventoryOnly
+}
+
+func (params *AuditParams) SetLicenseInventoryOnly(licenseInventoryOnly bool) *AuditParams {
+	params.licenseInventoryOnly = licenseInventoryOnly
+	return params
+}
+
+func (params *AuditParams) DumpXrayTrafficDir() string {
+	return params.dumpXrayTrafficDir
+}
+
+func (params *AuditParams) SetDumpXrayTrafficDir(dumpXrayTrafficDir string) *AuditParams {
+	params.dumpXrayTrafficDir = dumpXrayTrafficDir
+	return params
+}
+
+func (params *AuditParams) DescriptorPrecedence() map[coreutils.Technology][]string {
+	return params.descriptorPrecedence
+}
+
+func (params *AuditParams) SetDescriptorPrecedence(descriptorPrecedence map[coreutils.Technology][]string) *AuditParams {
+	params.descriptorPrecedence = descriptorPrecedence
+	return params
+}
+
+func (params *AuditParams) DiffBaseRef() string {
+	return params.diffBaseRef
+}
+
+func (params *AuditParams) SetDiffBaseRef(gitRef string) *AuditParams {
+	params.diffBaseRef = gitRef
+	return params
+}
+
+func (params *AuditParams) FailOnNoTechnologies() bool {
+	return params.failOnNoTechnologies
+}
+
+func (params *AuditParams) SetFailOnNoTechnologies(failOnNoTechnologies bool) *AuditParams {
+	params.failOnNoTechnologies = failOnNoTechnologies
+	return params
+}
+
+func (params *AuditParams) DeduplicateIdenticalModules() bool {
+	return params.deduplicateIdenticalModules
+}
+
+func (params *AuditParams) SetDeduplicateIdenticalModules(deduplicateIdenticalModules bool) *AuditParams {
+	params.deduplicateIdenticalModules = deduplicateIdenticalModules
+	return params
+}
+
+func (params *AuditParams) PostScanWebhook() string {
+	return params.postScanWebhook
+}
+
+func (params *AuditParams) SetPostScanWebhook(url string) *AuditParams {
+	params.postScanWebhook = url
+	return params
+}
+
+func (params *AuditParams) PostScanCommand() []string {
+	return params.postScanCommand
+}
+
+func (params *AuditParams) SetPostScanCommand(cmd []string) *AuditParams {
+	params.postScanCommand = cmd
+	return params
+}
+
+func (params *AuditParams) FailOnPostScanHookError() bool {
+	return params.failOnPostScanHookError
+}
+
+func (params *AuditParams) SetFailOnPostScanHookError(failOnPostScanHookError bool) *AuditParams {
+	params.failOnPostScanHookError = failOnPostScanHookError
+	return params
+}
+
+func (params *AuditParams) UseMonorepoConfig() bool {
+	return params.useMonorepoConfig
+}
+
+func (params *AuditParams) SetUseMonorepoConfig(useMonorepoConfig bool) *AuditParams {
+	params.useMonorepoConfig = useMonorepoConfig
+	return params
+}
+
+func (params *AuditParams) ResumeFrom() string {
+	return params.resumeFrom
+}
+
+func (params *AuditParams) SetResumeFrom(checkpointPath string) *AuditParams {
+	params.resumeFrom = checkpointPath
+	return params
+}
+
+func (params *AuditParams) BuildInfoGraph() (buildName, buildNumber string) {
+	return params.buildName, params.buildNumber
+}
+
+func (params *AuditParams) SetBuildInfoGraph(buildName, buildNumber string) *AuditParams {
+	params.buildName = buildName
+	params.buildNumber = buildNumber
+	return params
+}
+
+func (params *AuditParams) LogFormat() LogFormat {
+	return params.logFormat
+}
+
+func (params *AuditParams) SetLogFormat(logFormat LogFormat) *AuditParams {
+	params.logFormat = logFormat
+	return params
+}
+
+func (params *AuditParams) TransitiveOnly() bool {
+	return params.transitiveOnly
+}
+
+func (params *AuditParams) SetTransitiveOnly(transitiveOnly bool) *AuditParams {
+	params.transitiveOnly = transitiveOnly
+	return params
+}
+
+func (params *AuditParams) AllowedLicenses() []string {
+	return params.allowedLicenses
+}
+
+func (params *AuditParams) SetAllowedLicenses(allowedLicenses []string) *AuditParams {
+	params.allowedLicenses = allowedLicenses
+	return params
+}
+
+func (params *AuditParams) CheckStaleness() bool {
+	return params.checkStaleness
+}
+
+func (params *AuditParams) SetCheckStaleness(checkStaleness bool) *AuditParams {
+	params.checkStaleness = checkStaleness
+	return params
+}
+
+func (params *AuditParams) StalenessRegistries() map[string]string {
+	return params.stalenessRegistries
+}
+
+func (params *AuditParams) SetStalenessRegistries(stalenessRegistries map[string]string) *AuditParams {
+	params.stalenessRegistries = stalenessRegistries
+	return params
+}
+
+func (params *AuditParams) ImpactPathWorkers() int {
+	return params.impactPathWorkers
+}
+
+func (params *AuditParams) SetImpactPathWorkers(impactPathWorkers int) *AuditParams {
+	params.impactPathWorkers = impactPathWorkers
+	return params
+}
+
+func (params *AuditParams) ScanPlanFile() string {
+	return params.scanPlanFile
+}
+
+func (params *AuditParams) SetScanPlanFile(scanPlanFile string) *AuditParams {
+	params.scanPlanFile = scanPlanFile
+	return params
+}
+
+func (params *AuditParams) ScanPlanOutputFile() string {
+	return params.scanPlanOutputFile
+}
+
+func (params *AuditParams) SetScanPlanOutputFile(scanPlanOutputFile string) *AuditParams {
+	params.scanPlanOutputFile = scanPlanOutputFile
+	return params
+}
+
+func (params *AuditParams) CvesPublishedSince() time.Time {
+	return params.cvesPublishedSince
+}
+
+func (params *AuditParams) SetCvesPublishedSince(cvesPublishedSince time.Time) *AuditParams {
+	params.cvesPublishedSince = cvesPublishedSince
+	return params
+}
+
+func (params *AuditParams) IncludeUndatedCves() bool {
+	return params.includeUndatedCves
+}
+
+func (params *AuditParams) SetIncludeUndatedCves(includeUndatedCves bool) *AuditParams {
+	params.includeUndatedCves = includeUndatedCves
+	return params
+}
+
+func (params *AuditParams) OnTechnologyMismatch() TechnologyMismatchPolicy {
+	if params.onTechnologyMismatch == "" {
+		return WarnOnTechnologyMismatch
+	}
+	return params.onTechnologyMismatch
+}
+
+func (params *AuditParams) SetOnTechnologyMismatch(onTechnologyMismatch TechnologyMismatchPolicy) *AuditParams {
+	params.onTechnologyMismatch = onTechnologyMismatch
+	return params
+}
+
+func (params *AuditParams) MaxParallelScans() int {
+	if params.maxParallelScans <= 0 {
+		return 1
+	}
+	return params.maxParallelScans
+}
+
+func (params *AuditParams) SetMaxParallelScans(maxParallelScans int) *AuditParams {
+	params.maxParallelScans = maxParallelScans
+	return params
+}
+
+func (params *AuditParams) DependencyTreeCache() bool {
+	return params.dependencyTreeCache
+}
+
+func (params *AuditParams) SetDependencyTreeCache(dependencyTreeCache bool) *AuditParams {
+	params.dependencyTreeCache = dependencyTreeCache
+	return params
+}
+
+func (params *AuditParams) ScanTimeout() time.Duration {
+	return params.scanTimeout
+}
+
+func (params *AuditParams) SetScanTimeout(scanTimeout time.Duration) *AuditParams {
+	params.scanTimeout = scanTimeout
+	return params
+}
+
+func (params *AuditParams) ExcludePatternsMode() ExcludePatternsMode {
+	if params.excludePatternsMode == "" {
+		return ReplaceExcludePatterns
+	}
+	return params.excludePatternsMode
+}
+
+func (params *AuditParams) SetExcludePatternsMode(excludePatternsMode ExcludePatternsMode) *AuditParams {
+	params.excludePatternsMode = excludePatternsMode
+	params.effectiveExclusions = nil
+	return params
+}
+
+func (params *AuditParams) DisabledDefaultExcludePatterns() []string {
+	return params.disabledDefaultExcludePatterns
+}
+
+// Disables the given patterns of the default exclusions, for example '*/test/*' to detect the projects in the test directories.
+func (params *AuditParams) SetDisabledDefaultExcludePatterns(disabledDefaultExcludePatterns []string) *AuditParams {
+	params.disabledDefaultExcludePatterns = disabledDefaultExcludePatterns
+	params.effectiveExclusions = nil
+	return params
+}
+
+func (params *AuditParams) DependencyTreeOutputFile() string {
+	return params.dependencyTreeOutputFile
+}
+
+func (params *AuditParams) SetDependencyTreeOutputFile(dependencyTreeOutputFile string) *AuditParams {
+	params.dependencyTreeOutputFile = dependencyTreeOutputFile
+	return params
+}
+
+func (params *AuditParams) SbomOutputPath() string {
+	return params.sbomOutputPath
+}
+
+func (params *AuditParams) SbomFormat() SbomFormat {
+	if params.sbomFormat == "" {
+		return CycloneDxJsonSbomFormat
+	}
+	return params.sbomFormat
+}
+
+func (params *AuditParams) SetSbomOutput(sbomOutputPath string, sbomFormat SbomFormat) *AuditParams {
+	params.sbomOutputPath = sbomOutputPath
+	params.sbomFormat = sbomFormat
+	return params
+}
+
+func (params *AuditParams) MaxParallelGraphScans() int {
+	return params.maxParallelGraphScans
+}
+
+func (params *AuditParams) SetMaxParallelGraphScans(maxParallelGraphScans int) *AuditParams {
+	params.maxParallelGraphScans = maxParallelGraphScans
+	return params
+}
+
+func (params *AuditParams) ScanRetries() int {
+	return params.scanRetries
+}
+
+func (params *AuditParams) SetScanRetries(scanRetries int) *AuditParams {
+	params.scanRetries = scanRetries
+	return params
+}
+
+func (params *AuditParams) ScanRetryBaseDelay() time.Duration {
+	if params.scanRetryBaseDelay <= 0 {
+		return defaultScanRetryBaseDelay
+	}
+	return params.scanRetryBaseDelay
+}
+
+func (params *AuditParams) SetScanRetryBaseDelay(scanRetryBaseDelay time.Duration) *AuditParams {
+	params.scanRetryBaseDelay = scanRetryBaseDelay
+	return params
+}
+
+func (params *AuditParams) OfflineTreeOnly() bool {
+	return params.offlineTreeOnly
+}
+
+func (params *AuditParams) SetOfflineTreeOnly(offlineTreeOnly bool) *AuditParams {
+	params.offlineTreeOnly = offlineTreeOnly
+	return params
+}
+
+func (params *AuditParams) ChangedSince() string {
+	return params.changedSince
+}
+
+func (params *AuditParams) SetChangedSince(gitRef string) *AuditParams {
+	params.changedSince = gitRef
+	return params
+}
+
+func (params *AuditParams) DryRun() bool {
+	return params.dryRun
+}
+
+func (params *AuditParams) SetDryRun(dryRun bool) *AuditParams {
+	params.dryRun = dryRun
+	return params
+}
+
+func (params *AuditParams) IncludePatterns() []string {
+	return params.includePatterns
+}
+
+func (params *AuditParams) SetIncludePatterns(includePatterns []string) *AuditParams {
+	params.includePatterns = includePatterns
+	return params
+}
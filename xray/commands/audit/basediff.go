@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// Returns a flat tree of the dependencies that were added to the project in the working directory since the given git ref.
+// The project's dependency tree is built in a temporary worktree of the ref, and its dependencies are removed from the given flat tree.
+// If the project doesn't exist in the ref, all the dependencies are considered added.
+func getDependenciesAddedSinceRef(ctx context.Context, params *AuditParams, tech coreutils.Technology, workingDir string, flatTree *xrayCmdUtils.GraphNode) (addedDependencies *xrayCmdUtils.GraphNode, err error) {
+	baseFlatTree, err := getDependenciesAtRef(ctx, params, tech, workingDir)
+	if err != nil {
+		return
+	}
+	addedDependencies = sca.GetAddedDependencies(flatTree, baseFlatTree)
+	log.Info(fmt.Sprintf("%d of the %d %s dependencies were added since '%s'.", len(addedDependencies.Nodes), len(flatTree.Nodes), tech.ToFormal(), params.diffBaseRef))
+	return
+}
+
+// Builds the flat dependency tree of the project in the working directory, as it is in the given git ref.
+// Returns nil if the project doesn't exist in the ref.
+func getDependenciesAtRef(ctx context.Context, params *AuditParams, tech coreutils.Technology, workingDir string) (flatTree *xrayCmdUtils.GraphNode, err error) {
+	gitRoot, err := runGitCommand(workingDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return
+	}
+	relativeWorkingDir, err := filepath.Rel(getRealPath(gitRoot), getRealPath(workingDir))
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	worktreeDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, fileutils.RemoveTempDir(worktreeDir))
+	}()
+	if _, err = runGitCommand(workingDir, "worktree", "add", "--detach", worktreeDir, params.diffBaseRef); err != nil {
+		return
+	}
+	defer func() {
+		_, e := runGitCommand(workingDir, "worktree", "remove", "--force", worktreeDir)
+		err = errors.Join(err, e)
+	}()
+	baseWorkingDir := filepath.Join(worktreeDir, relativeWorkingDir)
+	exists, err := fileutils.IsDirExists(baseWorkingDir, false)
+	if err != nil || !exists {
+		log.Debug(fmt.Sprintf("'%s' doesn't exist in '%s'.", relativeWorkingDir, params.diffBaseRef))
+		return
+	}
+	log.Info(fmt.Sprintf("Calculating the %s dependencies of '%s'...", tech.ToFormal(), params.diffBaseRef))
+	if flatTree, _, _, err = getTechDependencyTree(ctx, params.AuditBasicParams, tech, baseWorkingDir); err != nil {
+		err = fmt.Errorf("failed while building the '%s' dependency tree of '%s':\n%s", tech, params.diffBaseRef, err.Error())
+	}
+	return
+}
+
+func runGitCommand(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", errorutils.CheckErrorf("'git %s' failed: %s\n%s", strings.Join(args, " "), err.Error(), strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testChartFile = "apiVersion: v2\nname: my-app\nversion: 0.1.0\ndependencies:\n  - name: redis\n    version: 17.x.x\n"
+	baseChartLock = "dependencies:\n- name: redis\n  repository: https://charts.bitnami.com/bitnami\n  version: 17.3.7\n"
+	prChartLock   = baseChartLock + "- name: postgresql\n  repository: https://charts.bitnami.com/bitnami\n  version: 12.1.2\n"
+)
+
+func TestGetDependenciesAddedSinceRef(t *testing.T) {
+	repoDir := t.TempDir()
+	chartDir := filepath.Join(repoDir, "chart")
+	assert.NoError(t, os.MkdirAll(chartDir, 0755))
+	runTestGitCommand(t, repoDir, "init", "-q")
+	// The base branch locks one dependency
+	assert.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(testChartFile), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.lock"), []byte(baseChartLock), 0644))
+	runTestGitCommand(t, repoDir, "add", "-A")
+	runTestGitCommand(t, repoDir, "commit", "-q", "-m", "base")
+	runTestGitCommand(t, repoDir, "tag", "base")
+	// The pull request adds another dependency
+	assert.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.lock"), []byte(prChartLock), 0644))
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	params := NewAuditParams().SetDiffBaseRef("base")
+	flatTree, _, err := GetTechDependencyTree(params.AuditBasicParams, coreutils.Helm, chartDir)
+	assert.NoError(t, err)
+	assert.Len(t, flatTree.Nodes, 2)
+
+	added, err := getDependenciesAddedSinceRef(context.Background(), params, coreutils.Helm, chartDir, flatTree)
+	assert.NoError(t, err)
+	assert.Equal(t, []*xrayCmdUtils.GraphNode{{Id: "helm://postgresql:12.1.2"}}, added.Nodes)
+	// The working directory isn't changed, and the temporary worktree is removed
+	currentDir, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, wd, currentDir)
+	worktrees, err := runGitCommand(repoDir, "worktree", "list")
+	assert.NoError(t, err)
+	assert.NotContains(t, worktrees, "detached")
+
+	// A project that doesn't exist in the base ref, all of its dependencies are added
+	newChartDir := filepath.Join(repoDir, "new-chart")
+	assert.NoError(t, os.MkdirAll(newChartDir, 0755))
+	added, err = getDependenciesAddedSinceRef(context.Background(), params, coreutils.Helm, newChartDir, flatTree)
+	assert.NoError(t, err)
+	assert.Len(t, added.Nodes, 2)
+}
+
+func runTestGitCommand(t *testing.T, dir string, args ...string) {
+	_, err := runGitCommand(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@jfrog.com"}, args...)...)
+	assert.NoError(t, err)
+}
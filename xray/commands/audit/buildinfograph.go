@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/java"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Maps the types of the build-info modules to the technologies of their dependencies.
+// Modules of other types (such as Docker and generic modules) have no dependency graph to scan.
+var buildInfoModuleTypeToTech = map[buildinfo.ModuleType]coreutils.Technology{
+	buildinfo.Maven:  coreutils.Maven,
+	buildinfo.Gradle: coreutils.Gradle,
+	buildinfo.Npm:    coreutils.Npm,
+	buildinfo.Go:     coreutils.Go,
+	buildinfo.Python: coreutils.Pip,
+	buildinfo.Nuget:  coreutils.Nuget,
+}
+
+// The prefixes of the component IDs of each technology in Xray.
+var techToPackageTypeIdentifier = map[coreutils.Technology]string{
+	coreutils.Maven:  java.GavPackageTypeIdentifier,
+	coreutils.Gradle: java.GavPackageTypeIdentifier,
+	coreutils.Npm:    xrayutils.NpmPackageTypeIdentifier,
+	coreutils.Go:     "go://",
+	coreutils.Pip:    "pypi://",
+	coreutils.Nuget:  "nuget://",
+}
+
+// The dependency trees of the build-info modules of a technology.
+type buildInfoDependencyTrees struct {
+	fullDependencyTrees []*xrayCmdUtils.GraphNode
+	uniqueDeps          *datastructures.Set[string]
+}
+
+// Scan the dependency graph of the published build-info, instead of building it from the project in the working directory.
+// The modules of each technology are scanned together, as a single scan.
+func runBuildInfoScaScan(currentWorkingDir string, serverDetails *config.ServerDetails, params *AuditParams, results *xrayutils.Results) (err error) {
+	techTrees, err := getBuildInfoDependencyTrees(serverDetails, params)
+	if err != nil {
+		return
+	}
+	if len(techTrees) == 0 {
+		log.Info(fmt.Sprintf("The build-info of build %s/%s has no dependencies of a supported technology. Skipping the SCA scan...", params.buildName, params.buildNumber))
+		return
+	}
+	techs := maps.Keys(techTrees)
+	slices.Sort(techs)
+	for _, tech := range techs {
+		scan := &xrayutils.ScaScanResult{Technology: tech, WorkingDirectory: currentWorkingDir}
+		log.Info("Running SCA scan for", tech, "vulnerable dependencies of build", params.buildName+"/"+params.buildNumber+"...")
+		var flattenTree *xrayCmdUtils.GraphNode
+		if flattenTree, err = createFlatTree(techTrees[tech].uniqueDeps.ToSlice()); err != nil {
+			return
+		}
+		outcome := xrayutils.ScaScanOutcome{Technology: tech, WorkingDirectory: currentWorkingDir}
+		if scanErr := scanDependencyTrees(serverDetails, params, scan, flattenTree, techTrees[tech].fullDependencyTrees); scanErr != nil {
+			outcome.Err = fmt.Errorf("audit command of the %s dependencies of the build failed:\n%s", tech.ToFormal(), scanErr.Error())
+			err = errors.Join(err, outcome.Err)
+		} else {
+			results.ScaResults = append(results.ScaResults, *scan)
+		}
+		results.ScaScansOutcomes = append(results.ScaScansOutcomes, outcome)
+	}
+	return
+}
+
+// Fetches the published build-info and returns the dependency trees of its modules, by their technologies.
+func getBuildInfoDependencyTrees(serverDetails *config.ServerDetails, params *AuditParams) (map[coreutils.Technology]*buildInfoDependencyTrees, error) {
+	servicesManager, err := utils.CreateServiceManager(serverDetails, -1, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	buildInfoParams := services.BuildInfoParams{BuildName: params.buildName, BuildNumber: params.buildNumber, ProjectKey: params.xrayGraphScanParams.ProjectKey}
+	publishedBuildInfo, found, err := servicesManager.GetBuildInfo(buildInfoParams)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errorutils.CheckErrorf("build %s/%s was not found in Artifactory", params.buildName, params.buildNumber)
+	}
+	techTrees := map[coreutils.Technology]*buildInfoDependencyTrees{}
+	for _, module := range publishedBuildInfo.BuildInfo.Modules {
+		tech, supported := buildInfoModuleTypeToTech[module.Type]
+		if !supported {
+			log.Debug(fmt.Sprintf("Skipping the '%s' module of the build-info, its type '%s' isn't supported.", module.Id, module.Type))
+			continue
+		}
+		if _, exists := techTrees[tech]; !exists {
+			techTrees[tech] = &buildInfoDependencyTrees{uniqueDeps: datastructures.MakeSet[string]()}
+		}
+		moduleTree, moduleUniqueDeps := getBuildInfoModuleDependencyTree(module, techToPackageTypeIdentifier[tech])
+		techTrees[tech].fullDependencyTrees = append(techTrees[tech].fullDependencyTrees, moduleTree)
+		for _, dependency := range moduleUniqueDeps {
+			techTrees[tech].uniqueDeps.Add(dependency)
+		}
+	}
+	return techTrees, nil
+}
+
+// Returns the dependency tree of a build-info module.
+// The parents of each dependency are the first elements of its 'requestedBy' paths. A dependency without paths is a direct dependency of the module.
+func getBuildInfoModuleDependencyTree(module buildinfo.Module, packageTypeIdentifier string) (*xrayCmdUtils.GraphNode, []string) {
+	rootId := packageTypeIdentifier + module.Id
+	treeHelper := map[string][]string{}
+	addChild := func(parentId, childId string) {
+		if !slices.Contains(treeHelper[parentId], childId) {
+			treeHelper[parentId] = append(treeHelper[parentId], childId)
+		}
+	}
+	for _, dependency := range module.Dependencies {
+		dependencyId := packageTypeIdentifier + dependency.Id
+		if len(dependency.RequestedBy) == 0 {
+			addChild(rootId, dependencyId)
+			continue
+		}
+		for _, requestedByPath := range dependency.RequestedBy {
+			if len(requestedByPath) == 0 {
+				addChild(rootId, dependencyId)
+				continue
+			}
+			addChild(packageTypeIdentifier+requestedByPath[0], dependencyId)
+		}
+	}
+	return sca.BuildXrayDependencyTree(treeHelper, rootId)
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"os"
+	"testing"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/maps"
+)
+
+var testBuildInfo = buildinfo.PublishedBuildInfo{BuildInfo: buildinfo.BuildInfo{
+	Name:   "my-build",
+	Number: "1",
+	Modules: []buildinfo.Module{
+		{
+			Type: buildinfo.Npm,
+			Id:   "my-app:1.0.0",
+			Dependencies: []buildinfo.Dependency{
+				{Id: "express:4.18.2", RequestedBy: [][]string{{"my-app:1.0.0"}}},
+				{Id: "body-parser:1.20.1", RequestedBy: [][]string{{"express:4.18.2", "my-app:1.0.0"}}},
+				{Id: "bytes:3.1.2", RequestedBy: [][]string{{"body-parser:1.20.1", "express:4.18.2", "my-app:1.0.0"}, {"express:4.18.2", "my-app:1.0.0"}}},
+			},
+		},
+		{
+			Type: buildinfo.Maven,
+			Id:   "org.example:my-service:1.0",
+			Dependencies: []buildinfo.Dependency{
+				{Id: "junit:junit:4.13.2"},
+				{Id: "org.hamcrest:hamcrest-core:1.3", RequestedBy: [][]string{{"junit:junit:4.13.2", "org.example:my-service:1.0"}}},
+			},
+		},
+		{Type: buildinfo.Docker, Id: "my-image:1.0", Dependencies: []buildinfo.Dependency{{Id: "sha256:abc"}}},
+	},
+}}
+
+func TestRunScaScanWithBuildInfoGraph(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.Chdir(wd))
+	}()
+	// The working directory has no projects, the dependency graph is taken from the build-info.
+	assert.NoError(t, os.Chdir(t.TempDir()))
+
+	var scanRequests int
+	testServer := createCustomXrayScanGraphMockServer(t, func(*xrayCmdUtils.GraphNode) (string, int) {
+		scanRequests++
+		return "scan", 0
+	}, func(string) any {
+		return services.ScanResponse{ScanId: "scan"}
+	}, map[string]any{"api/build/my-build/1": testBuildInfo})
+	defer testServer.Close()
+	serverDetails := &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/", XrayUrl: testServer.URL + "/xray/"}
+	params := NewAuditParams().SetBuildInfoGraph("my-build", "1")
+	params.SetServerDetails(serverDetails)
+	params.xrayVersion = "3.80.0"
+
+	t.Run("Dependency trees", func(t *testing.T) {
+		techTrees, err := getBuildInfoDependencyTrees(serverDetails, params)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []coreutils.Technology{coreutils.Npm, coreutils.Maven}, maps.Keys(techTrees))
+
+		npmTrees := techTrees[coreutils.Npm]
+		if assert.Len(t, npmTrees.fullDependencyTrees, 1) {
+			root := npmTrees.fullDependencyTrees[0]
+			assert.Equal(t, "npm://my-app:1.0.0", root.Id)
+			if assert.Len(t, root.Nodes, 1) {
+				express := root.Nodes[0]
+				assert.Equal(t, "npm://express:4.18.2", express.Id)
+				assert.ElementsMatch(t, []string{"npm://body-parser:1.20.1", "npm://bytes:3.1.2"}, nodeIds(express.Nodes))
+			}
+		}
+		assert.ElementsMatch(t, []string{"npm://my-app:1.0.0", "npm://express:4.18.2", "npm://body-parser:1.20.1", "npm://bytes:3.1.2"}, npmTrees.uniqueDeps.ToSlice())
+
+		mavenTrees := techTrees[coreutils.Maven]
+		if assert.Len(t, mavenTrees.fullDependencyTrees, 1) {
+			root := mavenTrees.fullDependencyTrees[0]
+			assert.Equal(t, "gav://org.example:my-service:1.0", root.Id)
+			if assert.Len(t, root.Nodes, 1) {
+				assert.Equal(t, "gav://junit:junit:4.13.2", root.Nodes[0].Id)
+				assert.Equal(t, []string{"gav://org.hamcrest:hamcrest-core:1.3"}, nodeIds(root.Nodes[0].Nodes))
+			}
+		}
+	})
+
+	t.Run("SCA scan", func(t *testing.T) {
+		scanRequests = 0
+		results := xrayutils.NewAuditResults()
+		assert.NoError(t, runScaScan(params, results))
+		if assert.Len(t, results.ScaResults, 2) {
+			assert.Equal(t, coreutils.Maven, results.ScaResults[0].Technology)
+			assert.Equal(t, coreutils.Npm, results.ScaResults[1].Technology)
+		}
+		assert.Equal(t, 2, scanRequests)
+	})
+
+	t.Run("Build not found", func(t *testing.T) {
+		_, err := getBuildInfoDependencyTrees(serverDetails, NewAuditParams().SetBuildInfoGraph("missing-build", "1"))
+		assert.ErrorContains(t, err, "build missing-build/1 was not found")
+	})
+}
+
+func nodeIds(nodes []*xrayCmdUtils.GraphNode) (ids []string) {
+	for _, node := range nodes {
+		ids = append(ids, node.Id)
+	}
+	return
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Keeps only the scans of the modules that have files that were changed since the given git ref.
+// Each changed file belongs to the module of the nearest working directory that contains it, so a change in a nested module doesn't cause the scan of its parent module.
+// The changes are taken from the git repository of the current working directory, and include the uncommitted and the untracked files.
+// If the changes can't be determined, for example because git isn't installed, all the scans are kept.
+func filterScansOfChangedModules(currentWorkingDir, changedSince string, scans []*xrayutils.ScaScanResult) []*xrayutils.ScaScanResult {
+	changedFiles, err := getFilesChangedSinceRef(currentWorkingDir, changedSince)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't determine the files that were changed since '%s', so all the detected modules are scanned:\n%s", changedSince, err.Error()))
+		return scans
+	}
+	workingDirs := datastructures.MakeSet[string]()
+	for _, scan := range scans {
+		workingDirs.Add(getWorkingDirKey(getRealPath(scan.WorkingDirectory)))
+	}
+	changedWorkingDirs := datastructures.MakeSet[string]()
+	for _, changedFile := range changedFiles {
+		if workingDir := getNearestWorkingDir(changedFile, workingDirs); workingDir != "" {
+			changedWorkingDirs.Add(workingDir)
+		}
+	}
+	var changedScans []*xrayutils.ScaScanResult
+	for _, scan := range scans {
+		if !changedWorkingDirs.Exists(getWorkingDirKey(getRealPath(scan.WorkingDirectory))) {
+			log.Info(fmt.Sprintf("Skipping the %s scan in '%s', since none of its files were changed since '%s'.", scan.Technology.ToFormal(), scan.WorkingDirectory, changedSince))
+			continue
+		}
+		changedScans = append(changedScans, scan)
+	}
+	return changedScans
+}
+
+// Returns the absolute paths of the files that were changed since the given git ref, in the git repository of the given directory.
+func getFilesChangedSinceRef(dir, gitRef string) (changedFiles []string, err error) {
+	gitRoot, err := runGitCommand(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return
+	}
+	// The committed, staged and unstaged changes of the tracked files, including the deleted files.
+	changed, err := runGitCommand(gitRoot, "diff", "--name-only", gitRef, "--")
+	if err != nil {
+		return
+	}
+	untracked, err := runGitCommand(gitRoot, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return
+	}
+	for _, relativePath := range strings.Split(changed+"\n"+untracked, "\n") {
+		if relativePath != "" {
+			changedFiles = append(changedFiles, filepath.Join(getRealPath(gitRoot), filepath.FromSlash(relativePath)))
+		}
+	}
+	return
+}
+
+// Returns the key of the nearest of the given working directories that contains the file, or an empty string if none of them contains it.
+// The working directories are given by their keys.
+func getNearestWorkingDir(file string, workingDirs *datastructures.Set[string]) string {
+	for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+		if dirKey := getWorkingDirKey(dir); workingDirs.Exists(dirKey) {
+			return dirKey
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
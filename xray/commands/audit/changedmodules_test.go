@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScaScansToPreformChangedSince(t *testing.T) {
+	repoDir := t.TempDir()
+	modules := []string{"service-a", "service-b", filepath.Join("service-b", "nested")}
+	for _, module := range modules {
+		assert.NoError(t, os.MkdirAll(filepath.Join(repoDir, module), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(repoDir, module, "index.js"), []byte("module.exports = {};\n"), 0644))
+	}
+	// Two npm projects, and a Go module that is nested in one of them
+	for _, module := range modules[:2] {
+		assert.NoError(t, os.WriteFile(filepath.Join(repoDir, module, "package.json"), []byte(`{"name": "module", "version": "1.0.0"}`), 0644))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(repoDir, modules[2], "go.mod"), []byte("module nested\n\ngo 1.20\n"), 0644))
+	runTestGitCommand(t, repoDir, "init", "-q")
+	runTestGitCommand(t, repoDir, "add", "-A")
+	runTestGitCommand(t, repoDir, "commit", "-q", "-m", "base")
+	runTestGitCommand(t, repoDir, "tag", "base")
+	// A committed change in service-a, and an untracked file in the nested module of service-b
+	assert.NoError(t, os.WriteFile(filepath.Join(repoDir, "service-a", "index.js"), []byte("module.exports = {a: 1};\n"), 0644))
+	runTestGitCommand(t, repoDir, "commit", "-q", "-a", "-m", "change")
+	assert.NoError(t, os.WriteFile(filepath.Join(repoDir, "service-b", "nested", "new.js"), []byte("\n"), 0644))
+
+	getScannedDirs := func(params *AuditParams) (scannedDirs []string) {
+		params.SetTechnologies([]string{"npm", "go"})
+		scans, err := getScaScansToPreform(repoDir, params)
+		assert.NoError(t, err)
+		for _, scan := range scans {
+			relativePath, err := filepath.Rel(getRealPath(repoDir), getRealPath(scan.WorkingDirectory))
+			assert.NoError(t, err)
+			scannedDirs = append(scannedDirs, relativePath)
+		}
+		return
+	}
+
+	t.Run("All modules", func(t *testing.T) {
+		assert.ElementsMatch(t, modules, getScannedDirs(NewAuditParams()))
+	})
+
+	t.Run("Changed modules", func(t *testing.T) {
+		// The change in the nested module doesn't cause the scan of service-b.
+		assert.ElementsMatch(t, []string{"service-a", filepath.Join("service-b", "nested")}, getScannedDirs(NewAuditParams().SetChangedSince("base")))
+	})
+
+	t.Run("No changes", func(t *testing.T) {
+		runTestGitCommand(t, repoDir, "add", "-A")
+		runTestGitCommand(t, repoDir, "commit", "-q", "-m", "nested")
+		assert.Empty(t, getScannedDirs(NewAuditParams().SetChangedSince("HEAD")))
+	})
+
+	t.Run("Unknown ref", func(t *testing.T) {
+		// The changes can't be determined, so all the modules are scanned.
+		assert.ElementsMatch(t, modules, getScannedDirs(NewAuditParams().SetChangedSince("no-such-ref")))
+	})
+}
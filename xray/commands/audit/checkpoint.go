@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// The content of the checkpoint file of an audit, which records the SCA scans that were completed, with their results.
+type scaScanCheckpoint struct {
+	CompletedScans []xrayutils.ScaScanResult `json:"CompletedScans"`
+}
+
+// Reads the checkpoint file in the given path. A missing file is an empty checkpoint, as in the first run of the audit.
+func readScaScanCheckpoint(checkpointPath string) (*scaScanCheckpoint, error) {
+	checkpoint := &scaScanCheckpoint{}
+	content, err := os.ReadFile(checkpointPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return checkpoint, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	if err = json.Unmarshal(content, checkpoint); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the audit checkpoint file '%s': %s", checkpointPath, err.Error())
+	}
+	// The technology of the issues isn't serialized, restore it from their scans.
+	for _, scan := range checkpoint.CompletedScans {
+		for _, scanResponse := range scan.XrayResults {
+			for i := range scanResponse.Vulnerabilities {
+				scanResponse.Vulnerabilities[i].Technology = scan.Technology.String()
+			}
+			for i := range scanResponse.Violations {
+				scanResponse.Violations[i].Technology = scan.Technology.String()
+			}
+		}
+	}
+	return checkpoint, nil
+}
+
+// Returns the recorded results of the given scan, or nil if it wasn't completed.
+// Scans are identified by their technology and working directory, compared by its key so different spellings of it match.
+func (checkpoint *scaScanCheckpoint) completedScan(scan *xrayutils.ScaScanResult) *xrayutils.ScaScanResult {
+	if checkpoint == nil {
+		return nil
+	}
+	workingDirKey := getWorkingDirKey(scan.WorkingDirectory)
+	for i := range checkpoint.CompletedScans {
+		completedScan := &checkpoint.CompletedScans[i]
+		if completedScan.Technology == scan.Technology && getWorkingDirKey(completedScan.WorkingDirectory) == workingDirKey {
+			return completedScan
+		}
+	}
+	return nil
+}
+
+// Records the given scan as completed and writes the checkpoint to the given path.
+// The checkpoint is written to a temporary file that replaces the previous one, so an interruption while writing doesn't corrupt it.
+func (checkpoint *scaScanCheckpoint) addCompletedScan(checkpointPath string, scan *xrayutils.ScaScanResult) error {
+	checkpoint.CompletedScans = append(checkpoint.CompletedScans, *scan)
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	tempPath := checkpointPath + ".tmp"
+	if err = os.WriteFile(tempPath, content, 0644); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.Rename(tempPath, checkpointPath))
+}
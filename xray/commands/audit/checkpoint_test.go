@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	biutils "github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunScaScanResumeFromCheckpoint(t *testing.T) {
+	skipIfNpmIsMissing(t)
+	tmpDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fileutils.RemoveTempDir(tmpDir))
+	}()
+	projectDir := filepath.Join(tmpDir, "project")
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "npm-identical-modules"), projectDir, true, nil))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.Chdir(wd))
+	}()
+	assert.NoError(t, os.Chdir(projectDir))
+
+	var scanRequests int
+	testServer := createCustomXrayScanGraphMockServer(t, func(*xrayUtils.GraphNode) (string, int) {
+		scanRequests++
+		return "scan", 0
+	}, func(string) any {
+		return services.ScanResponse{ScanId: "scan", Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Severity: "High", Components: map[string]services.Component{"npm://underscore:1.13.6": {}}}}}
+	}, nil)
+	defer testServer.Close()
+
+	checkpointPath := filepath.Join(tmpDir, "checkpoint.json")
+	runScaScanWithCheckpoint := func() *xrayutils.Results {
+		params := NewAuditParams().SetResumeFrom(checkpointPath)
+		params.SetServerDetails(&config.ServerDetails{XrayUrl: testServer.URL + "/xray/"})
+		params.xrayVersion = "3.80.0"
+		results := xrayutils.NewAuditResults()
+		assert.NoError(t, runScaScan(params, results))
+		return results
+	}
+
+	// The first run scans both modules and records them in the checkpoint.
+	firstResults := runScaScanWithCheckpoint()
+	assert.Equal(t, 2, scanRequests)
+	checkpoint, err := readScaScanCheckpoint(checkpointPath)
+	assert.NoError(t, err)
+	assert.Len(t, checkpoint.CompletedScans, 2)
+
+	// Simulate an interruption after the first module was scanned.
+	checkpoint.CompletedScans = checkpoint.CompletedScans[:1]
+	content, err := json.Marshal(checkpoint)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(checkpointPath, content, 0644))
+
+	// The resumed run scans only the second module, and merges its results with the recorded ones.
+	scanRequests = 0
+	resumedResults := runScaScanWithCheckpoint()
+	assert.Equal(t, 1, scanRequests)
+	if assert.Len(t, resumedResults.ScaResults, 2) {
+		for i, scan := range resumedResults.ScaResults {
+			assert.Equal(t, firstResults.ScaResults[i].WorkingDirectory, scan.WorkingDirectory)
+			assert.Equal(t, firstResults.ScaResults[i].XrayResults, scan.XrayResults)
+		}
+	}
+
+	// A run with a complete checkpoint doesn't scan at all.
+	scanRequests = 0
+	assert.Len(t, runScaScanWithCheckpoint().ScaResults, 2)
+	assert.Equal(t, 0, scanRequests)
+}
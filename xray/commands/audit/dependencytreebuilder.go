@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// Builds the dependency trees of the projects of a technology, for package managers that the audit doesn't support, or to replace the built-in dependency tree builder of a technology.
+type DependencyTreeBuilder interface {
+	// Builds the dependency trees of the project in the given working directory, and returns them with the unique dependencies of the trees.
+	// The IDs of the dependencies are Xray component IDs, such as 'npm://lodash:4.17.21'.
+	// The context is done once the scan times out, and the commands that the builder executes should be killed then, for example by exec.CommandContext.
+	BuildTree(ctx context.Context, workingDir string, params xrayutils.AuditParams) (dependencyTrees []*xrayCmdUtils.GraphNode, uniqueDeps []string, err error)
+}
+
+var (
+	dependencyTreeBuilders      = map[coreutils.Technology]DependencyTreeBuilder{}
+	dependencyTreeBuildersMutex sync.RWMutex
+)
+
+// Registers the dependency tree builder of the given technology, which is used instead of the built-in one, if there is.
+// Registering a nil builder removes the registered builder of the technology.
+// The projects of technologies that aren't detected by the audit can be scanned by listing them in a scan plan file.
+func RegisterDependencyTreeBuilder(tech coreutils.Technology, builder DependencyTreeBuilder) {
+	dependencyTreeBuildersMutex.Lock()
+	defer dependencyTreeBuildersMutex.Unlock()
+	if builder == nil {
+		delete(dependencyTreeBuilders, tech)
+		return
+	}
+	dependencyTreeBuilders[tech] = builder
+}
+
+func getDependencyTreeBuilder(tech coreutils.Technology) (builder DependencyTreeBuilder, exists bool) {
+	dependencyTreeBuildersMutex.RLock()
+	defer dependencyTreeBuildersMutex.RUnlock()
+	builder, exists = dependencyTreeBuilders[tech]
+	return
+}
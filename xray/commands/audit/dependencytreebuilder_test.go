@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDependencyTreeBuilder struct {
+	workingDirs []string
+}
+
+func (builder *fakeDependencyTreeBuilder) BuildTree(_ context.Context, workingDir string, _ xrayutils.AuditParams) ([]*xrayCmdUtils.GraphNode, []string, error) {
+	builder.workingDirs = append(builder.workingDirs, workingDir)
+	dependency := &xrayCmdUtils.GraphNode{Id: "generic://acme-lib:1.0.0"}
+	return []*xrayCmdUtils.GraphNode{{Id: "acme-project", Nodes: []*xrayCmdUtils.GraphNode{dependency}}}, []string{dependency.Id}, nil
+}
+
+func TestRegisterDependencyTreeBuilder(t *testing.T) {
+	tmpDir := t.TempDir()
+	acme := coreutils.Technology("acme")
+	builder := &fakeDependencyTreeBuilder{}
+	RegisterDependencyTreeBuilder(acme, builder)
+	defer RegisterDependencyTreeBuilder(acme, nil)
+
+	flatTree, fullDependencyTrees, err := GetTechDependencyTree(&xrayutils.AuditBasicParams{}, acme, tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{tmpDir}, builder.workingDirs)
+	if assert.Len(t, fullDependencyTrees, 1) {
+		assert.Equal(t, "acme-project", fullDependencyTrees[0].Id)
+	}
+	if assert.NotNil(t, flatTree) && assert.Len(t, flatTree.Nodes, 1) {
+		assert.Equal(t, "generic://acme-lib:1.0.0", flatTree.Nodes[0].Id)
+	}
+
+	// The builder isn't invoked for other technologies.
+	_, _, err = GetTechDependencyTree(&xrayutils.AuditBasicParams{}, coreutils.Technology("other"), tmpDir)
+	assert.ErrorContains(t, err, "other is currently not supported")
+	assert.Len(t, builder.workingDirs, 1)
+
+	// Once removed, the technology isn't supported anymore.
+	RegisterDependencyTreeBuilder(acme, nil)
+	_, _, err = GetTechDependencyTree(&xrayutils.AuditBasicParams{}, acme, tmpDir)
+	assert.ErrorContains(t, err, "acme is currently not supported")
+	assert.Len(t, builder.workingDirs, 1)
+}
@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// The name of the directory of the cached dependency trees, under the temp directory of the CLI.
+const dependencyTreesCacheDirName = "jfrog-audit-dependency-trees"
+
+// A dependency tree that was built by a previous audit, with the information recorded while building it.
+type dependencyTreeCacheEntry struct {
+	// The modification times of the descriptors when the tree was built, by their paths.
+	DescriptorsModTimes    map[string]time.Time      `json:"DescriptorsModTimes"`
+	FlatTree               *xrayCmdUtils.GraphNode   `json:"FlatTree"`
+	FullDependencyTrees    []*xrayCmdUtils.GraphNode `json:"FullDependencyTrees"`
+	DependenciesScopes     map[string][]string       `json:"DependenciesScopes,omitempty"`
+	GitSourcedDependencies map[string]string         `json:"GitSourcedDependencies,omitempty"`
+}
+
+// Builds the dependency tree of the scan, or reuses the tree that a previous audit built, if the dependency trees cache is enabled. The tree is returned with the information that was recorded while building it.
+// A cached tree is used only if the content and the modification times of the scan's descriptors weren't changed since it was built.
+// Scans without descriptors, such as scans of a plan that doesn't list them, are never cached.
+func getTechDependencyTreeWithCache(ctx context.Context, params *AuditParams, scan *xrayutils.ScaScanResult) (flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode, treesInfo *xrayutils.DependencyTreesInfo, err error) {
+	if !params.DependencyTreeCache() || len(scan.Descriptors) == 0 {
+		return getTechDependencyTree(ctx, params.AuditBasicParams, scan.Technology, scan.WorkingDirectory)
+	}
+	treesInfo = xrayutils.NewDependencyTreesInfo(params.LogCommands())
+	cachePath, err := getDependencyTreeCachePath(scan, params.AuditBasicParams)
+	if err != nil {
+		return
+	}
+	descriptorsModTimes, err := getDescriptorsModTimes(scan.Descriptors)
+	if err != nil {
+		return
+	}
+	if entry := readDependencyTreeCacheEntry(cachePath, descriptorsModTimes); entry != nil {
+		log.Info(fmt.Sprintf("Using the cached %s dependency tree of '%s', since its descriptors weren't changed.", scan.Technology.ToFormal(), scan.WorkingDirectory))
+		treesInfo.DependenciesScopes = entry.DependenciesScopes
+		treesInfo.GitSourcedDependencies = entry.GitSourcedDependencies
+		return entry.FlatTree, entry.FullDependencyTrees, treesInfo, nil
+	}
+	if flatTree, fullDependencyTrees, treesInfo, err = getTechDependencyTree(ctx, params.AuditBasicParams, scan.Technology, scan.WorkingDirectory); err != nil || flatTree == nil {
+		return
+	}
+	entry := &dependencyTreeCacheEntry{
+		DescriptorsModTimes:    descriptorsModTimes,
+		FlatTree:               flatTree,
+		FullDependencyTrees:    fullDependencyTrees,
+		DependenciesScopes:     treesInfo.DependenciesScopes,
+		GitSourcedDependencies: treesInfo.GitSourcedDependencies,
+	}
+	// Failing to cache the tree shouldn't fail the audit, the next audit would only build it again.
+	if cacheErr := writeDependencyTreeCacheEntry(cachePath, entry); cacheErr != nil {
+		log.Debug(fmt.Sprintf("Couldn't cache the %s dependency tree of '%s': %s", scan.Technology.ToFormal(), scan.WorkingDirectory, cacheErr.Error()))
+	}
+	return
+}
+
+// Returns the path of the cached dependency tree of the scan, which is keyed by its working directory, its technology and the content of its descriptors.
+// Trees that were built without the dev dependencies, or that were truncated to a maximum depth, are cached separately.
+func getDependencyTreeCachePath(scan *xrayutils.ScaScanResult, params xrayutils.AuditParams) (string, error) {
+	descriptorsHash, err := getDescriptorsHash(scan)
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%t\x00%d", getWorkingDirKey(scan.WorkingDirectory), descriptorsHash, params.ExcludeDevDependencies(), params.MaxTreeDepth())))
+	return filepath.Join(fileutils.GetTempDirBase(), dependencyTreesCacheDirName, hex.EncodeToString(key[:])+".json"), nil
+}
+
+func getDescriptorsModTimes(descriptors []string) (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, len(descriptors))
+	for _, descriptor := range descriptors {
+		info, err := os.Stat(descriptor)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		modTimes[descriptor] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// Reads the cached dependency tree in the given path. Returns nil if there is none, or if the modification times of its descriptors were changed.
+func readDependencyTreeCacheEntry(cachePath string, descriptorsModTimes map[string]time.Time) *dependencyTreeCacheEntry {
+	content, err := os.ReadFile(cachePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Debug(fmt.Sprintf("Couldn't read the cached dependency tree '%s': %s", cachePath, err.Error()))
+		}
+		return nil
+	}
+	entry := &dependencyTreeCacheEntry{}
+	if err = json.Unmarshal(content, entry); err != nil {
+		log.Debug(fmt.Sprintf("Couldn't parse the cached dependency tree '%s': %s", cachePath, err.Error()))
+		return nil
+	}
+	if entry.FlatTree == nil || len(entry.DescriptorsModTimes) != len(descriptorsModTimes) {
+		return nil
+	}
+	for descriptor, modTime := range descriptorsModTimes {
+		if cachedModTime, ok := entry.DescriptorsModTimes[descriptor]; !ok || !cachedModTime.Equal(modTime) {
+			return nil
+		}
+	}
+	return entry
+}
+
+// Writes the cached dependency tree to the given path.
+// The tree is written to a temporary file that replaces the previous one, so audits that run at the same time don't read a partially written tree.
+func writeDependencyTreeCacheEntry(cachePath string, entry *dependencyTreeCacheEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(cachePath), filepath.Base(cachePath)+".*.tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = tempFile.Write(content)
+	if err = errors.Join(err, tempFile.Close()); err != nil {
+		return errorutils.CheckError(errors.Join(err, os.Remove(tempFile.Name())))
+	}
+	return errorutils.CheckError(os.Rename(tempFile.Name(), cachePath))
+}
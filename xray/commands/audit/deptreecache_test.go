@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTechDependencyTreeWithCache(t *testing.T) {
+	previousTempDirBase := fileutils.GetTempDirBase()
+	fileutils.SetTempDirBase(t.TempDir())
+	defer fileutils.SetTempDirBase(previousTempDirBase)
+
+	acme := coreutils.Technology("acme")
+	builder := &fakeDependencyTreeBuilder{}
+	RegisterDependencyTreeBuilder(acme, builder)
+	defer RegisterDependencyTreeBuilder(acme, nil)
+
+	projectDir := t.TempDir()
+	descriptor := filepath.Join(projectDir, "acme.json")
+	require.NoError(t, os.WriteFile(descriptor, []byte(`{"dependencies":["acme-lib"]}`), 0644))
+	scan := &xrayutils.ScaScanResult{Technology: acme, WorkingDirectory: projectDir, Descriptors: []string{descriptor}}
+	params := NewAuditParams().SetDependencyTreeCache(true)
+
+	assertTree := func(expectedBuilds int) {
+		flatTree, fullDependencyTrees, _, err := getTechDependencyTreeWithCache(context.Background(), params, scan)
+		assert.NoError(t, err)
+		assert.Len(t, builder.workingDirs, expectedBuilds)
+		if assert.NotNil(t, flatTree) && assert.Len(t, flatTree.Nodes, 1) {
+			assert.Equal(t, "generic://acme-lib:1.0.0", flatTree.Nodes[0].Id)
+		}
+		if assert.Len(t, fullDependencyTrees, 1) {
+			assert.Equal(t, "acme-project", fullDependencyTrees[0].Id)
+		}
+	}
+
+	// The first audit builds the tree, the second one reuses it.
+	assertTree(1)
+	assertTree(1)
+
+	// Changing the content of a descriptor invalidates the cached tree.
+	require.NoError(t, os.WriteFile(descriptor, []byte(`{"dependencies":["acme-lib","acme-utils"]}`), 0644))
+	assertTree(2)
+	assertTree(2)
+
+	// Touching a descriptor invalidates the cached tree as well.
+	modTime := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(descriptor, modTime, modTime))
+	assertTree(3)
+	assertTree(3)
+
+	// Without the cache, the tree is always built.
+	params.SetDependencyTreeCache(false)
+	assertTree(4)
+	assertTree(5)
+}
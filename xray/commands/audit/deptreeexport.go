@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+// The dependency trees that were built for the scan of a working directory, as written to the dependency tree output file.
+type exportedDependencyTree struct {
+	Technology       coreutils.Technology `json:"technology"`
+	WorkingDirectory string               `json:"workingDirectory"`
+	// The unique dependencies of the working directory, as direct children of a single node.
+	FlatTree            *xrayCmdUtils.GraphNode   `json:"flatTree"`
+	FullDependencyTrees []*xrayCmdUtils.GraphNode `json:"fullDependencyTrees"`
+}
+
+// Guards the recorded dependency trees. Separate from the dependency trees mutex, which a scan that timed out may still hold.
+var exportedDependencyTreesMutex sync.Mutex
+
+// Records the dependency trees that were built for the given scan, if writing them to the dependency tree output file or to the SBOM was requested.
+// The trees are recorded as they were built, before the dependencies that aren't sent to Xray, such as the pre-release dependencies, are filtered out.
+func recordDependencyTrees(params *AuditParams, scan *xrayutils.ScaScanResult, flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode) {
+	if params.dependencyTreeOutputFile == "" && params.sbomOutputPath == "" {
+		return
+	}
+	exportedDependencyTreesMutex.Lock()
+	defer exportedDependencyTreesMutex.Unlock()
+	if params.exportedDependencyTrees == nil {
+		params.exportedDependencyTrees = map[string]*exportedDependencyTree{}
+	}
+	params.exportedDependencyTrees[getExportedDependencyTreeKey(scan.Technology, scan.WorkingDirectory)] = &exportedDependencyTree{
+		Technology:          scan.Technology,
+		WorkingDirectory:    scan.WorkingDirectory,
+		FlatTree:            flatTree,
+		FullDependencyTrees: fullDependencyTrees,
+	}
+}
+
+// Writes the recorded dependency trees to the given file, in the order of the scans.
+// The scans whose dependency trees weren't built, such as failed scans or scans restored from a checkpoint, are omitted.
+func writeDependencyTrees(outputPath string, params *AuditParams, scans []*xrayutils.ScaScanResult) error {
+	exportedDependencyTreesMutex.Lock()
+	dependencyTrees := []*exportedDependencyTree{}
+	for _, scan := range scans {
+		if dependencyTree, exists := params.exportedDependencyTrees[getExportedDependencyTreeKey(scan.Technology, scan.WorkingDirectory)]; exists {
+			dependencyTrees = append(dependencyTrees, dependencyTree)
+		}
+	}
+	exportedDependencyTreesMutex.Unlock()
+	content, err := json.MarshalIndent(dependencyTrees, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(outputPath, content, 0644))
+}
+
+// Returns the full dependency trees that were recorded for the given scan, or for the scan whose results it shares.
+func getRecordedFullDependencyTrees(params *AuditParams, scan *xrayutils.ScaScanResult) []*xrayCmdUtils.GraphNode {
+	workingDir := scan.WorkingDirectory
+	if scan.SharedScanWorkingDirectory != "" {
+		workingDir = scan.SharedScanWorkingDirectory
+	}
+	exportedDependencyTreesMutex.Lock()
+	defer exportedDependencyTreesMutex.Unlock()
+	if dependencyTree, exists := params.exportedDependencyTrees[getExportedDependencyTreeKey(scan.Technology, workingDir)]; exists {
+		return dependencyTree.FullDependencyTrees
+	}
+	return nil
+}
+
+func getExportedDependencyTreeKey(tech coreutils.Technology, workingDir string) string {
+	return tech.String() + "\x00" + getWorkingDirKey(workingDir)
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunScaScanWithDependencyTreeOutputFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	modules := []string{"module1", "broken", "module2"}
+	for _, module := range modules {
+		require.NoError(t, os.Mkdir(filepath.Join(tmpDir, module), 0755))
+	}
+	acme := coreutils.Technology("acme")
+	RegisterDependencyTreeBuilder(acme, &failingDependencyTreeBuilder{failingDir: "broken"})
+	defer RegisterDependencyTreeBuilder(acme, nil)
+
+	plan := &scaScanPlan{}
+	for _, module := range modules {
+		plan.Scans = append(plan.Scans, scaScanPlanEntry{Technology: acme, WorkingDirectory: filepath.Join(tmpDir, module)})
+	}
+	content, err := json.Marshal(plan)
+	require.NoError(t, err)
+	planPath := filepath.Join(t.TempDir(), "scan-plan.json")
+	require.NoError(t, os.WriteFile(planPath, content, 0644))
+
+	xrayServer, serverDetails := createXrayScanGraphMockServer(t, "jpd1", services.ScanResponse{})
+	defer xrayServer.Close()
+	outputPath := filepath.Join(t.TempDir(), "dependency-trees.json")
+	params := NewAuditParams().SetScanPlanFile(planPath).SetMaxParallelScans(2).SetDependencyTreeOutputFile(outputPath)
+	params.SetServerDetails(serverDetails)
+	params.xrayVersion = "3.80.0"
+
+	err = runScaScan(params, xrayutils.NewAuditResults())
+	assert.ErrorContains(t, err, fmt.Sprintf("audit command in '%s' failed", filepath.Join(tmpDir, "broken")))
+
+	// The trees of the successful scans are written in the order of the scans, even though another scan failed.
+	content, err = os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var dependencyTrees []exportedDependencyTree
+	require.NoError(t, json.Unmarshal(content, &dependencyTrees))
+	if assert.Len(t, dependencyTrees, 2) {
+		for i, module := range []string{"module1", "module2"} {
+			assert.Equal(t, acme, dependencyTrees[i].Technology)
+			assert.Equal(t, filepath.Join(tmpDir, module), dependencyTrees[i].WorkingDirectory)
+			if assert.Len(t, dependencyTrees[i].FullDependencyTrees, 1) {
+				assert.Equal(t, "acme-project", dependencyTrees[i].FullDependencyTrees[0].Id)
+			}
+			if assert.NotNil(t, dependencyTrees[i].FlatTree) && assert.Len(t, dependencyTrees[i].FlatTree.Nodes, 1) {
+				assert.Equal(t, "generic://acme-lib:1.0.0", dependencyTrees[i].FlatTree.Nodes[0].Id)
+			}
+		}
+	}
+}
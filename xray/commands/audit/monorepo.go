@@ -0,0 +1,205 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	nxConfigFile          = "nx.json"
+	nxWorkspaceFile       = "workspace.json"
+	lernaConfigFile       = "lerna.json"
+	turboConfigFile       = "turbo.json"
+	packageJsonFile       = "package.json"
+	pnpmWorkspaceFile     = "pnpm-workspace.yaml"
+	lernaDefaultPackages  = "packages/*"
+	globExclusionPrefix   = "!"
+	recursiveGlobWildcard = "**"
+)
+
+// A monorepo orchestrator, detected by its config file, and the way to read the projects it declares.
+type monorepoTool struct {
+	name       string
+	configFile string
+	// Returns the patterns of the directories of the projects, relative to the monorepo root.
+	getProjectPatterns func(rootDir string) ([]string, error)
+}
+
+// The tools are detected in this order, since an Nx or Lerna monorepo may use Turborepo for running tasks as well.
+var monorepoTools = []monorepoTool{
+	{name: "Nx", configFile: nxConfigFile, getProjectPatterns: getNxProjectPatterns},
+	{name: "Lerna", configFile: lernaConfigFile, getProjectPatterns: getLernaProjectPatterns},
+	{name: "Turborepo", configFile: turboConfigFile, getProjectPatterns: getWorkspacesPatterns},
+}
+
+// Returns the directories of the projects that are declared by the monorepo orchestrator in the given directory, sorted.
+// Returns an empty list if no orchestrator config file exists in the directory, or if it declares no projects.
+func getMonorepoProjects(rootDir string) (projects []string, err error) {
+	for _, tool := range monorepoTools {
+		var exists bool
+		if exists, err = fileutils.IsFileExists(filepath.Join(rootDir, tool.configFile), false); err != nil || !exists {
+			if err != nil {
+				return
+			}
+			continue
+		}
+		var patterns []string
+		if patterns, err = tool.getProjectPatterns(rootDir); err != nil {
+			return
+		}
+		if projects, err = expandProjectPatterns(rootDir, patterns); err != nil {
+			return
+		}
+		log.Info(fmt.Sprintf("Found %d projects in the %s config of '%s'.", len(projects), tool.name, rootDir))
+		return
+	}
+	return
+}
+
+// Nx declares its projects in the 'projects' of nx.json or workspace.json, mapping each project to its directory, or to its configuration that includes its root directory.
+func getNxProjectPatterns(rootDir string) (patterns []string, err error) {
+	for _, configFile := range []string{nxConfigFile, nxWorkspaceFile} {
+		var config struct {
+			Projects map[string]json.RawMessage `json:"projects"`
+		}
+		var exists bool
+		if exists, err = readJsonFileIfExists(filepath.Join(rootDir, configFile), &config); err != nil || !exists {
+			if err != nil {
+				return
+			}
+			continue
+		}
+		for name, project := range config.Projects {
+			var projectDir string
+			if err = json.Unmarshal(project, &projectDir); err != nil {
+				var projectConfig struct {
+					Root string `json:"root"`
+				}
+				if err = json.Unmarshal(project, &projectConfig); err != nil {
+					return nil, errorutils.CheckErrorf("failed to parse the '%s' project in '%s': %s", name, configFile, err.Error())
+				}
+				projectDir = projectConfig.Root
+			}
+			if projectDir != "" {
+				patterns = append(patterns, projectDir)
+			}
+		}
+	}
+	return
+}
+
+// Lerna declares the patterns of its packages in the 'packages' of lerna.json, or uses the workspaces of the package manager.
+func getLernaProjectPatterns(rootDir string) (patterns []string, err error) {
+	var config struct {
+		Packages      []string `json:"packages"`
+		UseWorkspaces bool     `json:"useWorkspaces"`
+	}
+	if _, err = readJsonFileIfExists(filepath.Join(rootDir, lernaConfigFile), &config); err != nil {
+		return
+	}
+	if len(config.Packages) > 0 {
+		return config.Packages, nil
+	}
+	if patterns, err = getWorkspacesPatterns(rootDir); err != nil || len(patterns) > 0 || config.UseWorkspaces {
+		return
+	}
+	return []string{lernaDefaultPackages}, nil
+}
+
+// Returns the patterns of the workspaces of the package manager, from the 'workspaces' of package.json or from pnpm-workspace.yaml.
+func getWorkspacesPatterns(rootDir string) (patterns []string, err error) {
+	var packageJson struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	exists, err := readJsonFileIfExists(filepath.Join(rootDir, packageJsonFile), &packageJson)
+	if err != nil {
+		return
+	}
+	if exists && len(packageJson.Workspaces) > 0 {
+		// The workspaces are a list of patterns, or an object with the list of patterns in its 'packages'.
+		if err = json.Unmarshal(packageJson.Workspaces, &patterns); err != nil {
+			var workspaces struct {
+				Packages []string `json:"packages"`
+			}
+			if err = json.Unmarshal(packageJson.Workspaces, &workspaces); err != nil {
+				return nil, errorutils.CheckErrorf("failed to parse the workspaces in '%s': %s", packageJsonFile, err.Error())
+			}
+			patterns = workspaces.Packages
+		}
+		return
+	}
+	pnpmWorkspacePath := filepath.Join(rootDir, pnpmWorkspaceFile)
+	if exists, err = fileutils.IsFileExists(pnpmWorkspacePath, false); err != nil || !exists {
+		return
+	}
+	content, err := os.ReadFile(pnpmWorkspacePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var pnpmWorkspace struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err = yaml.Unmarshal(content, &pnpmWorkspace); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse '%s': %s", pnpmWorkspacePath, err.Error())
+	}
+	return pnpmWorkspace.Packages, nil
+}
+
+// Returns the directories that match the given patterns, except for the directories that match the patterns with the '!' prefix.
+// A '**' wildcard matches a single directory level, like '*'.
+func expandProjectPatterns(rootDir string, patterns []string) ([]string, error) {
+	projects := datastructures.MakeSet[string]()
+	excluded := datastructures.MakeSet[string]()
+	for _, pattern := range patterns {
+		isExclusion := strings.HasPrefix(pattern, globExclusionPrefix)
+		pattern = strings.ReplaceAll(strings.TrimPrefix(pattern, globExclusionPrefix), recursiveGlobWildcard, "*")
+		matches, err := filepath.Glob(filepath.Join(rootDir, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, errorutils.CheckErrorf("invalid project pattern '%s': %s", pattern, err.Error())
+		}
+		for _, match := range matches {
+			if isDir, err := fileutils.IsDirExists(match, false); err != nil {
+				return nil, err
+			} else if !isDir {
+				continue
+			}
+			if isExclusion {
+				excluded.Add(match)
+			} else {
+				projects.Add(match)
+			}
+		}
+	}
+	var projectDirs []string
+	for _, project := range projects.ToSlice() {
+		if !excluded.Exists(project) {
+			projectDirs = append(projectDirs, project)
+		}
+	}
+	sort.Strings(projectDirs)
+	return projectDirs, nil
+}
+
+func readJsonFileIfExists(path string, target any) (exists bool, err error) {
+	if exists, err = fileutils.IsFileExists(path, false); err != nil || !exists {
+		return
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	if err = json.Unmarshal(content, target); err != nil {
+		return false, errorutils.CheckErrorf("failed to parse '%s': %s", path, err.Error())
+	}
+	return
+}
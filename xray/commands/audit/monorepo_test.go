@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	biutils "github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScaScansToPreformWithMonorepoConfig(t *testing.T) {
+	tmpDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fileutils.RemoveTempDir(tmpDir))
+	}()
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "nx-monorepo"), tmpDir, true, nil))
+
+	// Without the monorepo config, all the package.json files are scanned as a single project in the root directory
+	scans, err := getScaScansToPreform(tmpDir, NewAuditParams())
+	assert.NoError(t, err)
+	if assert.Len(t, scans, 1) {
+		assert.Equal(t, tmpDir, scans[0].WorkingDirectory)
+		assert.Len(t, scans[0].Descriptors, 4)
+	}
+
+	// With the monorepo config, only the projects that are declared in nx.json are scanned
+	scans, err = getScaScansToPreform(tmpDir, NewAuditParams().SetUseMonorepoConfig(true))
+	assert.NoError(t, err)
+	if assert.Len(t, scans, 2) {
+		assert.Equal(t, coreutils.Npm, scans[0].Technology)
+		assert.Equal(t, filepath.Join(tmpDir, "apps", "web"), scans[0].WorkingDirectory)
+		assert.Equal(t, coreutils.Npm, scans[1].Technology)
+		assert.Equal(t, filepath.Join(tmpDir, "libs", "utils"), scans[1].WorkingDirectory)
+	}
+}
+
+func TestGetMonorepoProjects(t *testing.T) {
+	testCases := []struct {
+		name             string
+		files            map[string]string
+		expectedProjects []string
+	}{
+		{
+			name:             "No orchestrator",
+			files:            map[string]string{"package.json": `{"workspaces": ["packages/*"]}`},
+			expectedProjects: nil,
+		},
+		{
+			name:             "Lerna default packages",
+			files:            map[string]string{"lerna.json": `{"version": "1.0.0"}`},
+			expectedProjects: []string{"packages/a", "packages/b"},
+		},
+		{
+			name:             "Lerna declared packages",
+			files:            map[string]string{"lerna.json": `{"packages": ["apps/*", "packages/a"]}`},
+			expectedProjects: []string{"apps/web", "packages/a"},
+		},
+		{
+			name:             "Turborepo with package.json workspaces",
+			files:            map[string]string{"turbo.json": `{}`, "package.json": `{"workspaces": {"packages": ["packages/**", "!packages/b"]}}`},
+			expectedProjects: []string{"packages/a"},
+		},
+		{
+			name:             "Turborepo with pnpm workspaces",
+			files:            map[string]string{"turbo.json": `{}`, "pnpm-workspace.yaml": "packages:\n  - 'apps/*'\n  - 'packages/b'\n"},
+			expectedProjects: []string{"apps/web", "packages/b"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			for _, dir := range []string{"apps/web", "packages/a", "packages/b"} {
+				assert.NoError(t, os.MkdirAll(filepath.Join(rootDir, dir), 0755))
+			}
+			for file, content := range tc.files {
+				assert.NoError(t, os.WriteFile(filepath.Join(rootDir, file), []byte(content), 0644))
+			}
+			var expectedProjects []string
+			for _, project := range tc.expectedProjects {
+				expectedProjects = append(expectedProjects, filepath.Join(rootDir, filepath.FromSlash(project)))
+			}
+			projects, err := getMonorepoProjects(rootDir)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedProjects, projects)
+		})
+	}
+}
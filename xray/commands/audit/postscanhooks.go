@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+)
+
+// The summary of the SCA results, that is passed to the post-scan webhook and command.
+type ScaScanSummary struct {
+	Scans []ScaScanSummaryEntry `json:"scans"`
+	// The error of the SCA scan, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+type ScaScanSummaryEntry struct {
+	Technology       string   `json:"technology"`
+	WorkingDirectory string   `json:"workingDirectory"`
+	Descriptors      []string `json:"descriptors,omitempty"`
+	// Maps each severity to the number of issues of this severity.
+	Vulnerabilities map[string]int `json:"vulnerabilities"`
+	Violations      map[string]int `json:"violations"`
+	Licenses        int            `json:"licenses"`
+}
+
+func GetScaScanSummary(results *xrayutils.Results) *ScaScanSummary {
+	summary := &ScaScanSummary{Scans: []ScaScanSummaryEntry{}}
+	if results.ScaError != nil {
+		summary.Error = results.ScaError.Error()
+	}
+	for _, scan := range results.ScaResults {
+		entry := ScaScanSummaryEntry{
+			Technology:       scan.Technology.String(),
+			WorkingDirectory: scan.WorkingDirectory,
+			Descriptors:      getSortedDescriptors(scan.Descriptors),
+			Vulnerabilities:  map[string]int{},
+			Violations:       map[string]int{},
+		}
+		for _, response := range scan.XrayResults {
+			for _, vulnerability := range response.Vulnerabilities {
+				entry.Vulnerabilities[vulnerability.Severity]++
+			}
+			for _, violation := range response.Violations {
+				entry.Violations[violation.Severity]++
+			}
+			entry.Licenses += len(response.Licenses)
+		}
+		summary.Scans = append(summary.Scans, entry)
+	}
+	return summary
+}
+
+// Returns a sorted copy of the descriptors, so the summary is reproducible between runs.
+func getSortedDescriptors(descriptors []string) []string {
+	sortedDescriptors := slices.Clone(descriptors)
+	slices.Sort(sortedDescriptors)
+	return sortedDescriptors
+}
+
+// Sends the summary of the SCA results to the post-scan webhook, and runs the post-scan command, if they were configured.
+func runPostScanHooks(params *AuditParams, results *xrayutils.Results) (err error) {
+	if params.postScanWebhook == "" && len(params.postScanCommand) == 0 {
+		return
+	}
+	content, err := json.Marshal(GetScaScanSummary(results))
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if params.postScanWebhook != "" {
+		err = sendPostScanWebhook(params.postScanWebhook, content)
+	}
+	if len(params.postScanCommand) > 0 {
+		err = errors.Join(err, runPostScanCommand(params.postScanCommand, content))
+	}
+	return
+}
+
+func sendPostScanWebhook(url string, content []byte) error {
+	log.Info("Sending the scan summary to the post-scan webhook...")
+	client, err := httpclient.ClientBuilder().SetRetries(3).Build()
+	if err != nil {
+		return err
+	}
+	httpClientDetails := httputils.HttpClientDetails{Headers: map[string]string{"Content-Type": "application/json"}}
+	resp, body, err := client.SendPost(url, content, httpClientDetails, "")
+	if err != nil {
+		return fmt.Errorf("the post-scan webhook failed: %s", err.Error())
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errorutils.CheckErrorf("the post-scan webhook failed: %s %s", resp.Status, string(body))
+	}
+	return nil
+}
+
+// Runs the post-scan command, with the path of a temporary file that contains the scan summary as its last argument.
+func runPostScanCommand(command []string, content []byte) (err error) {
+	summaryFile, err := fileutils.CreateTempFile()
+	if err != nil {
+		return
+	}
+	defer func() {
+		err = errors.Join(err, errorutils.CheckError(os.Remove(summaryFile.Name())))
+	}()
+	_, err = summaryFile.Write(content)
+	err = errors.Join(errorutils.CheckError(err), errorutils.CheckError(summaryFile.Close()))
+	if err != nil {
+		return
+	}
+	log.Info(fmt.Sprintf("Running the post-scan command '%s'...", strings.Join(command, " ")))
+	cmd := exec.Command(command[0], append(command[1:], summaryFile.Name())...)
+	output, e := cmd.CombinedOutput()
+	log.Debug(string(output))
+	if e != nil {
+		return errorutils.CheckErrorf("the post-scan command '%s' failed: %s\n%s", strings.Join(command, " "), e.Error(), strings.TrimSpace(string(output)))
+	}
+	return
+}
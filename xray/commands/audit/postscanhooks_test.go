@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	coretests "github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPostScanHooksWebhook(t *testing.T) {
+	results := xrayutils.NewAuditResults()
+	results.ScaError = errors.New("yarn scan failed")
+	results.ScaResults = []xrayutils.ScaScanResult{{
+		Technology:       coreutils.Npm,
+		WorkingDirectory: "/project",
+		Descriptors:      []string{"/project/package.json"},
+		XrayResults: []services.ScanResponse{{
+			Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Severity: "High"}, {IssueId: "XRAY-2", Severity: "High"}, {IssueId: "XRAY-3", Severity: "Low"}},
+			Violations:      []services.Violation{{IssueId: "XRAY-1", Severity: "High"}},
+			Licenses:        []services.License{{Key: "MIT"}},
+		}},
+	}}
+	var received *ScaScanSummary
+	status := http.StatusOK
+	testServer := coretests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = &ScaScanSummary{}
+		assert.NoError(t, json.Unmarshal(body, received))
+		w.WriteHeader(status)
+	})
+	defer testServer.Close()
+
+	params := NewAuditParams().SetPostScanWebhook(testServer.URL + "/hook")
+	assert.NoError(t, runPostScanHooks(params, results))
+	assert.Equal(t, &ScaScanSummary{
+		Error: "yarn scan failed",
+		Scans: []ScaScanSummaryEntry{{
+			Technology:       "npm",
+			WorkingDirectory: "/project",
+			Descriptors:      []string{"/project/package.json"},
+			Vulnerabilities:  map[string]int{"High": 2, "Low": 1},
+			Violations:       map[string]int{"High": 1},
+			Licenses:         1,
+		}},
+	}, received)
+
+	// A rejected payload fails the hook
+	status = http.StatusBadRequest
+	assert.ErrorContains(t, runPostScanHooks(params, results), "the post-scan webhook failed")
+
+	// Without hooks, nothing is sent
+	received = nil
+	assert.NoError(t, runPostScanHooks(NewAuditParams(), results))
+	assert.Nil(t, received)
+}
+
+func TestRunAuditPostScanHookError(t *testing.T) {
+	testServer := coretests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "api/v1/system/version"):
+			_, err := w.Write([]byte(`{"xray_version": "3.80.0"}`))
+			assert.NoError(t, err)
+		case strings.Contains(r.URL.Path, "api/v1/entitlements/feature/"):
+			_, err := w.Write([]byte(`{"entitled": false}`))
+			assert.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	defer testServer.Close()
+
+	for _, failOnPostScanHookError := range []bool{false, true} {
+		params := NewAuditParams().SetWorkingDirs([]string{t.TempDir()}).SetPostScanWebhook(testServer.URL + "/hook").SetFailOnPostScanHookError(failOnPostScanHookError)
+		params.SetServerDetails(&config.ServerDetails{XrayUrl: testServer.URL + "/xray/"})
+		results, err := RunAudit(params)
+		if failOnPostScanHookError {
+			assert.ErrorContains(t, err, "the post-scan webhook failed")
+		} else {
+			assert.NoError(t, err)
+		}
+		// The audit completes, and its results are set, regardless of the failure of the hook.
+		assert.Equal(t, "3.80.0", results.XrayVersion)
+	}
+}
@@ -0,0 +1,275 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// The format of the SBOM of the scanned projects.
+type SbomFormat string
+
+const (
+	// CycloneDX 1.5 JSON, which is the default.
+	CycloneDxJsonSbomFormat SbomFormat = "cyclonedx-json"
+	// SPDX 2.3 JSON.
+	SpdxJsonSbomFormat SbomFormat = "spdx-json"
+)
+
+// The source of the vulnerabilities in the SBOM.
+var xraySbomSource = &cdx.Source{Name: "JFrog Xray"}
+
+func GetSbomFormat(format string) (SbomFormat, error) {
+	switch SbomFormat(format) {
+	case "", CycloneDxJsonSbomFormat:
+		return CycloneDxJsonSbomFormat, nil
+	case SpdxJsonSbomFormat:
+		return SpdxJsonSbomFormat, nil
+	}
+	return "", errorutils.CheckErrorf("unsupported SBOM format '%s', the supported formats are '%s' and '%s'", format, CycloneDxJsonSbomFormat, SpdxJsonSbomFormat)
+}
+
+// Writes the SBOM of the scanned projects to the SBOM output file, in the requested format.
+// The SBOM includes the projects, their dependencies and the vulnerabilities and licenses that Xray found in them.
+// The dependencies of scans whose dependency trees weren't built, such as scans restored from a checkpoint, are included only if Xray reported them.
+func writeSbom(params *AuditParams, scaResults []xrayutils.ScaScanResult) error {
+	bom := createCycloneDxSbom(scaResults, func(scan *xrayutils.ScaScanResult) []*xrayCmdUtils.GraphNode {
+		return getRecordedFullDependencyTrees(params, scan)
+	}, time.Now())
+	var content []byte
+	switch params.SbomFormat() {
+	case CycloneDxJsonSbomFormat:
+		var buffer bytes.Buffer
+		if err := cdx.NewBOMEncoder(&buffer, cdx.BOMFileFormatJSON).SetPretty(true).EncodeVersion(bom, cdx.SpecVersion1_5); err != nil {
+			return errorutils.CheckError(err)
+		}
+		content = buffer.Bytes()
+	case SpdxJsonSbomFormat:
+		var err error
+		if content, err = json.MarshalIndent(createSpdxDocument(bom), "", "  "); err != nil {
+			return errorutils.CheckError(err)
+		}
+	default:
+		return errorutils.CheckErrorf("unsupported SBOM format '%s', the supported formats are '%s' and '%s'", params.SbomFormat(), CycloneDxJsonSbomFormat, SpdxJsonSbomFormat)
+	}
+	return errorutils.CheckError(os.WriteFile(params.SbomOutputPath(), content, 0644))
+}
+
+// Creates a CycloneDX SBOM of the given scans, whose dependency trees are returned by the given function.
+// The components are referenced by their Xray component IDs, and carry the purls of the package types that have them.
+func createCycloneDxSbom(scaResults []xrayutils.ScaScanResult, getFullDependencyTrees func(scan *xrayutils.ScaScanResult) []*xrayCmdUtils.GraphNode, timestamp time.Time) *cdx.BOM {
+	builder := &cycloneDxSbomBuilder{componentsIndexes: map[string]int{}, dependencies: map[string][]string{}, vulnerabilitiesIndexes: map[string]int{}}
+	for i := range scaResults {
+		scan := &scaResults[i]
+		for _, tree := range getFullDependencyTrees(scan) {
+			builder.addTree(tree, cdx.ComponentTypeApplication)
+		}
+		for _, xrayResult := range scan.XrayResults {
+			for _, vulnerability := range xrayResult.Vulnerabilities {
+				builder.addVulnerability(vulnerability)
+			}
+			for _, license := range xrayResult.Licenses {
+				builder.addLicense(license)
+			}
+		}
+	}
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{Timestamp: timestamp.UTC().Format(time.RFC3339)}
+	if len(builder.components) > 0 {
+		bom.Components = &builder.components
+		dependencies := make([]cdx.Dependency, 0, len(builder.components))
+		for _, component := range builder.components {
+			dependency := cdx.Dependency{Ref: component.BOMRef}
+			if dependsOn := builder.dependencies[component.BOMRef]; len(dependsOn) > 0 {
+				slices.Sort(dependsOn)
+				dependency.Dependencies = &dependsOn
+			}
+			dependencies = append(dependencies, dependency)
+		}
+		bom.Dependencies = &dependencies
+	}
+	if len(builder.vulnerabilities) > 0 {
+		bom.Vulnerabilities = &builder.vulnerabilities
+	}
+	return bom
+}
+
+type cycloneDxSbomBuilder struct {
+	components []cdx.Component
+	// The indexes of the components, by their BOM references.
+	componentsIndexes map[string]int
+	// The BOM references of the direct dependencies of each component, by its BOM reference.
+	dependencies    map[string][]string
+	vulnerabilities []cdx.Vulnerability
+	// The indexes of the vulnerabilities, by their BOM references.
+	vulnerabilitiesIndexes map[string]int
+}
+
+// Adds the component of the given Xray component ID, if it wasn't added yet, and returns its index.
+func (builder *cycloneDxSbomBuilder) addComponent(componentId string, componentType cdx.ComponentType) int {
+	if index, exists := builder.componentsIndexes[componentId]; exists {
+		return index
+	}
+	component := cdx.Component{BOMRef: componentId, Type: componentType}
+	component.Group, component.Name, component.Version, component.PackageURL = parseComponentId(componentId)
+	builder.componentsIndexes[componentId] = len(builder.components)
+	builder.components = append(builder.components, component)
+	return len(builder.components) - 1
+}
+
+// Adds the components of the given tree, and the dependencies between them.
+func (builder *cycloneDxSbomBuilder) addTree(node *xrayCmdUtils.GraphNode, componentType cdx.ComponentType) {
+	builder.addComponent(node.Id, componentType)
+	for _, child := range node.Nodes {
+		if !slices.Contains(builder.dependencies[node.Id], child.Id) {
+			builder.dependencies[node.Id] = append(builder.dependencies[node.Id], child.Id)
+		}
+		builder.addTree(child, cdx.ComponentTypeLibrary)
+	}
+}
+
+// Adds the given Xray vulnerability. A vulnerability that was reported by several scans is added once, affecting the components of all of them.
+func (builder *cycloneDxSbomBuilder) addVulnerability(vulnerability services.Vulnerability) {
+	id := vulnerability.IssueId
+	if len(vulnerability.Cves) > 0 && vulnerability.Cves[0].Id != "" {
+		id = vulnerability.Cves[0].Id
+	}
+	bomRef := vulnerability.IssueId
+	if bomRef == "" {
+		bomRef = id
+	}
+	index, exists := builder.vulnerabilitiesIndexes[bomRef]
+	if !exists {
+		builder.vulnerabilitiesIndexes[bomRef] = len(builder.vulnerabilities)
+		builder.vulnerabilities = append(builder.vulnerabilities, cdx.Vulnerability{
+			BOMRef:      bomRef,
+			ID:          id,
+			Source:      xraySbomSource,
+			Ratings:     &[]cdx.VulnerabilityRating{{Source: xraySbomSource, Severity: toCycloneDxSeverity(vulnerability.Severity)}},
+			Description: vulnerability.Summary,
+			Affects:     &[]cdx.Affects{},
+		})
+		index = len(builder.vulnerabilities) - 1
+	}
+	cdxVulnerability := &builder.vulnerabilities[index]
+	componentIds := maps.Keys(vulnerability.Components)
+	slices.Sort(componentIds)
+	var recommendations []string
+	for _, componentId := range componentIds {
+		builder.addComponent(componentId, cdx.ComponentTypeLibrary)
+		if !slices.ContainsFunc(*cdxVulnerability.Affects, func(affects cdx.Affects) bool { return affects.Ref == componentId }) {
+			*cdxVulnerability.Affects = append(*cdxVulnerability.Affects, cdx.Affects{Ref: componentId})
+		}
+		if len(vulnerability.Components[componentId].FixedVersions) > 0 {
+			var fixedVersions []string
+			for _, fixedVersion := range vulnerability.Components[componentId].FixedVersions {
+				fixedVersions = append(fixedVersions, strings.Trim(fixedVersion, "[]"))
+			}
+			recommendations = append(recommendations, fmt.Sprintf("Upgrade %s to %s", componentId, strings.Join(fixedVersions, ", ")))
+		}
+	}
+	if cdxVulnerability.Recommendation == "" && len(recommendations) > 0 {
+		cdxVulnerability.Recommendation = strings.Join(recommendations, ". ")
+	}
+}
+
+// Adds the given Xray license to its components. The license is added by its name, since Xray's license keys aren't necessarily SPDX identifiers.
+func (builder *cycloneDxSbomBuilder) addLicense(license services.License) {
+	componentIds := maps.Keys(license.Components)
+	slices.Sort(componentIds)
+	for _, componentId := range componentIds {
+		component := &builder.components[builder.addComponent(componentId, cdx.ComponentTypeLibrary)]
+		if component.Licenses == nil {
+			component.Licenses = &cdx.Licenses{}
+		}
+		if !slices.ContainsFunc(*component.Licenses, func(choice cdx.LicenseChoice) bool { return choice.License.Name == license.Key }) {
+			*component.Licenses = append(*component.Licenses, cdx.LicenseChoice{License: &cdx.License{Name: license.Key}})
+		}
+	}
+}
+
+func toCycloneDxSeverity(severity string) cdx.Severity {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return cdx.SeverityCritical
+	case "high":
+		return cdx.SeverityHigh
+	case "medium":
+		return cdx.SeverityMedium
+	case "low":
+		return cdx.SeverityLow
+	case "information", "info":
+		return cdx.SeverityInfo
+	}
+	return cdx.SeverityUnknown
+}
+
+// Returns the group, the name, the version and the purl of the given Xray component ID, such as 'npm://@scope/name:1.0.0'.
+// IDs without a package type, such as the IDs of some of the projects, are returned as names. The purl is empty for package types that don't have purls.
+func parseComponentId(componentId string) (group, name, version, purl string) {
+	packageType, packageId, found := strings.Cut(componentId, "://")
+	if !found {
+		return "", componentId, "", ""
+	}
+	if index := strings.LastIndex(packageId, ":"); index >= 0 {
+		name, version = packageId[:index], packageId[index+1:]
+	} else {
+		name = packageId
+	}
+	var purlType string
+	switch packageType {
+	case "gav":
+		// Maven identifiers look like this: gav://group:artifact:version.
+		purlType = "maven"
+		group, name, _ = strings.Cut(name, ":")
+	case "npm", "composer":
+		// The scope of npm packages and the vendor of Composer packages are their namespaces, such as '@scope/name' and 'vendor/name'.
+		purlType = packageType
+		if index := strings.LastIndex(name, "/"); index >= 0 {
+			group, name = name[:index], name[index+1:]
+		}
+	case "go", "swift":
+		// Go modules and Swift packages are named by their paths, such as 'github.com/owner/name'.
+		purlType = map[string]string{"go": "golang", "swift": "swift"}[packageType]
+		if index := strings.LastIndex(name, "/"); index >= 0 {
+			group, name = name[:index], name[index+1:]
+		}
+	case "pypi", "pip":
+		purlType = "pypi"
+	case "nuget", "gem", "conan", "docker", "generic":
+		purlType = packageType
+	}
+	if purlType == "" {
+		return
+	}
+	purl = "pkg:" + purlType + "/"
+	if group != "" {
+		var escapedSegments []string
+		for _, segment := range strings.Split(group, "/") {
+			escapedSegments = append(escapedSegments, escapePurlSegment(segment))
+		}
+		purl += strings.Join(escapedSegments, "/") + "/"
+	}
+	purl += escapePurlSegment(name)
+	if version != "" {
+		purl += "@" + escapePurlSegment(version)
+	}
+	return
+}
+
+// Escapes a segment of a purl. Unlike in URL paths, the '@' character separates the version, so it must be escaped as well.
+func escapePurlSegment(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
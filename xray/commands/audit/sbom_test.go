@@ -0,0 +1,181 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCycloneDxSbom(t *testing.T) {
+	var content bytes.Buffer
+	require.NoError(t, cdx.NewBOMEncoder(&content, cdx.BOMFileFormatJSON).SetPretty(true).EncodeVersion(createNpmProjectSbom(), cdx.SpecVersion1_5))
+	expected, err := os.ReadFile(filepath.Join("..", "testdata", "sbom", "npm-project-cyclonedx.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), content.String())
+}
+
+func TestCreateSpdxDocument(t *testing.T) {
+	content, err := json.MarshalIndent(createSpdxDocument(createNpmProjectSbom()), "", "  ")
+	require.NoError(t, err)
+	expected, err := os.ReadFile(filepath.Join("..", "testdata", "sbom", "npm-project-spdx.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(content))
+	// The document is reproducible.
+	repeatedContent, err := json.MarshalIndent(createSpdxDocument(createNpmProjectSbom()), "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(repeatedContent))
+}
+
+func TestGetSpdxId(t *testing.T) {
+	usedSpdxIds := map[string]bool{}
+	assert.Equal(t, "SPDXRef-Package-npm-acme-logger-2.0.0", getSpdxId("npm://@acme/logger:2.0.0", usedSpdxIds))
+	// Different references that are sanitized to the same identifier get unique identifiers.
+	assert.Equal(t, "SPDXRef-Package-npm-acme-logger-2.0.0-2", getSpdxId("npm://acme/logger:2.0.0", usedSpdxIds))
+}
+
+// Returns the SBOM of an npm project with a vulnerable dependency, which is also a dependency of its other dependency.
+func createNpmProjectSbom() *cdx.BOM {
+	lodash := &xrayCmdUtils.GraphNode{Id: "npm://lodash:4.17.20"}
+	scopedPackage := &xrayCmdUtils.GraphNode{Id: "npm://@acme/logger:2.0.0", Nodes: []*xrayCmdUtils.GraphNode{{Id: "npm://lodash:4.17.20"}}}
+	tree := &xrayCmdUtils.GraphNode{Id: "npm://npm-project:1.0.0", Nodes: []*xrayCmdUtils.GraphNode{scopedPackage, lodash}}
+	scan := xrayutils.ScaScanResult{
+		Technology:       coreutils.Npm,
+		WorkingDirectory: "npm-project",
+		XrayResults: []services.ScanResponse{{
+			Vulnerabilities: []services.Vulnerability{{
+				IssueId:    "XRAY-140562",
+				Cves:       []services.Cve{{Id: "CVE-2021-23337"}},
+				Severity:   "High",
+				Summary:    "Command injection in lodash",
+				Components: map[string]services.Component{"npm://lodash:4.17.20": {FixedVersions: []string{"[4.17.21]"}}},
+			}},
+			Licenses: []services.License{{Key: "MIT", Components: map[string]services.Component{"npm://lodash:4.17.20": {}, "npm://@acme/logger:2.0.0": {}}}},
+		}},
+	}
+	return createCycloneDxSbom([]xrayutils.ScaScanResult{scan}, func(*xrayutils.ScaScanResult) []*xrayCmdUtils.GraphNode {
+		return []*xrayCmdUtils.GraphNode{tree}
+	}, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+}
+
+func TestParseComponentId(t *testing.T) {
+	testCases := []struct {
+		componentId string
+		group       string
+		name        string
+		version     string
+		purl        string
+	}{
+		{"npm://lodash:4.17.20", "", "lodash", "4.17.20", "pkg:npm/lodash@4.17.20"},
+		{"npm://@acme/logger:2.0.0", "@acme", "logger", "2.0.0", "pkg:npm/%40acme/logger@2.0.0"},
+		{"gav://org.apache.commons:commons-lang3:3.12.0", "org.apache.commons", "commons-lang3", "3.12.0", "pkg:maven/org.apache.commons/commons-lang3@3.12.0"},
+		{"go://github.com/gin-gonic/gin:v1.9.1", "github.com/gin-gonic", "gin", "v1.9.1", "pkg:golang/github.com/gin-gonic/gin@v1.9.1"},
+		{"pypi://requests:2.31.0", "", "requests", "2.31.0", "pkg:pypi/requests@2.31.0"},
+		{"composer://monolog/monolog:2.9.1", "monolog", "monolog", "2.9.1", "pkg:composer/monolog/monolog@2.9.1"},
+		{"helm://redis:17.3.7", "", "redis", "17.3.7", ""},
+		{"acme-project", "", "acme-project", "", ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.componentId, func(t *testing.T) {
+			group, name, version, purl := parseComponentId(testCase.componentId)
+			assert.Equal(t, testCase.group, group)
+			assert.Equal(t, testCase.name, name)
+			assert.Equal(t, testCase.version, version)
+			assert.Equal(t, testCase.purl, purl)
+		})
+	}
+}
+
+func TestRunScaScanWithSbomOutput(t *testing.T) {
+	projectDir := t.TempDir()
+	acme := coreutils.Technology("acme")
+	RegisterDependencyTreeBuilder(acme, &fakeDependencyTreeBuilder{})
+	defer RegisterDependencyTreeBuilder(acme, nil)
+	planPath := filepath.Join(t.TempDir(), "scan-plan.json")
+	require.NoError(t, writeScaScanPlan(planPath, []*xrayutils.ScaScanResult{{Technology: acme, WorkingDirectory: projectDir}}))
+
+	vulnerability := services.Vulnerability{IssueId: "XRAY-1", Severity: "Critical", Components: map[string]services.Component{"generic://acme-lib:1.0.0": {}}}
+	xrayServer, serverDetails := createXrayScanGraphMockServer(t, "jpd1", services.ScanResponse{Vulnerabilities: []services.Vulnerability{vulnerability}})
+	defer xrayServer.Close()
+	sbomPath := filepath.Join(t.TempDir(), "sbom.json")
+	params := NewAuditParams().SetScanPlanFile(planPath).SetSbomOutput(sbomPath, CycloneDxJsonSbomFormat)
+	params.SetServerDetails(serverDetails)
+	params.xrayVersion = "3.80.0"
+	require.NoError(t, runScaScan(params, xrayutils.NewAuditResults()))
+
+	file, err := os.Open(sbomPath)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	bom := &cdx.BOM{}
+	require.NoError(t, cdx.NewBOMDecoder(file, cdx.BOMFileFormatJSON).Decode(bom))
+	assert.Equal(t, cdx.SpecVersion1_5, bom.SpecVersion)
+	if assert.NotNil(t, bom.Components) && assert.Len(t, *bom.Components, 2) {
+		assert.Equal(t, "acme-project", (*bom.Components)[0].Name)
+		assert.Equal(t, "pkg:generic/acme-lib@1.0.0", (*bom.Components)[1].PackageURL)
+	}
+	if assert.NotNil(t, bom.Vulnerabilities) && assert.Len(t, *bom.Vulnerabilities, 1) {
+		assert.Equal(t, "XRAY-1", (*bom.Vulnerabilities)[0].ID)
+	}
+}
+
+func TestRunAuditOfflineTreeOnlyWithSbomOutput(t *testing.T) {
+	projectDir := t.TempDir()
+	acme := coreutils.Technology("acme")
+	RegisterDependencyTreeBuilder(acme, &fakeDependencyTreeBuilder{})
+	defer RegisterDependencyTreeBuilder(acme, nil)
+	planPath := filepath.Join(t.TempDir(), "scan-plan.json")
+	require.NoError(t, writeScaScanPlan(planPath, []*xrayutils.ScaScanResult{{Technology: acme, WorkingDirectory: projectDir}}))
+
+	// No server details are configured, so contacting Xray would fail the audit.
+	sbomPath := filepath.Join(t.TempDir(), "sbom.json")
+	params := NewAuditParams().SetScanPlanFile(planPath).SetSbomOutput(sbomPath, CycloneDxJsonSbomFormat).SetOfflineTreeOnly(true)
+	results, err := RunAudit(params)
+	require.NoError(t, err)
+	require.NoError(t, results.ScaError)
+	if assert.Len(t, results.ScaResults, 1) {
+		assert.NotNil(t, results.ScaResults[0].XrayResults)
+		assert.Empty(t, results.ScaResults[0].XrayResults)
+		assert.Equal(t, []string{"generic://acme-lib:1.0.0"}, results.ScaResults[0].DirectDependencies)
+	}
+	assert.False(t, results.ExtendedScanResults.EntitledForJas)
+
+	file, err := os.Open(sbomPath)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	bom := &cdx.BOM{}
+	require.NoError(t, cdx.NewBOMDecoder(file, cdx.BOMFileFormatJSON).Decode(bom))
+	if assert.NotNil(t, bom.Components) {
+		assert.Len(t, *bom.Components, 2)
+	}
+	assert.Nil(t, bom.Vulnerabilities)
+
+	t.Run("Build scan", func(t *testing.T) {
+		_, err := RunAudit(NewAuditParams().SetOfflineTreeOnly(true).SetBuildInfoGraph("build", "1"))
+		assert.ErrorContains(t, err, "can't be audited offline")
+	})
+}
+
+func TestGetSbomFormat(t *testing.T) {
+	format, err := GetSbomFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, CycloneDxJsonSbomFormat, format)
+	format, err = GetSbomFormat("spdx-json")
+	assert.NoError(t, err)
+	assert.Equal(t, SpdxJsonSbomFormat, format)
+	_, err = GetSbomFormat("spdx-tag-value")
+	assert.ErrorContains(t, err, "unsupported SBOM format 'spdx-tag-value'")
+}
@@ -0,0 +1,159 @@
+package bazel
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/java"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	moduleBazelName  = "MODULE.bazel"
+	mavenInstallName = "maven_install.json"
+)
+
+// Matches the name of the module in its MODULE.bazel file, such as 'module(name = "my_app", version = "1.0")'.
+var moduleNameRegex = regexp.MustCompile(`module\(\s*name\s*=\s*"([^"]+)"`)
+
+// The content of a maven_install.json lock file of rules_jvm_external that is relevant for building the dependency tree.
+// Version 2 of the file maps the artifacts to their versions and to the artifacts they depend on, while the older versions list them under 'dependency_tree'.
+type mavenInstall struct {
+	Artifacts map[string]struct {
+		Version string `json:"version"`
+	} `json:"artifacts"`
+	Dependencies   map[string][]string `json:"dependencies"`
+	DependencyTree *struct {
+		Dependencies []struct {
+			Coord              string   `json:"coord"`
+			DirectDependencies []string `json:"directDependencies"`
+		} `json:"dependencies"`
+	} `json:"dependency_tree"`
+}
+
+// Builds the dependency tree of the Bazel workspace in the working directory from the maven_install.json lock file of its external Maven dependencies.
+// The Maven artifacts are identified by their Maven coordinates, the same as in the Maven and Gradle dependency trees.
+// The file doesn't record which artifacts the workspace requires, so the artifacts that no other artifact depends on are the direct dependencies of the workspace.
+// Dependencies on other Bazel modules aren't included, since Xray doesn't know them.
+func BuildDependencyTree(params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
+	lockPath := filepath.Join(workingDir, mavenInstallName)
+	exists, err := fileutils.IsFileExists(lockPath, false)
+	if err != nil {
+		return
+	}
+	if !exists {
+		err = errorutils.CheckErrorf("the %s file wasn't found in '%s'. Run 'bazel run @maven//:pin' to create it", mavenInstallName, workingDir)
+		return
+	}
+	content, err := os.ReadFile(lockPath)
+	if err != nil {
+		err = errorutils.CheckError(err)
+		return
+	}
+	dependenciesMap, err := parseMavenInstall(lockPath, content)
+	if err != nil {
+		return
+	}
+	rootId := getModuleName(workingDir)
+	dependenciesMap[rootId] = getRootDependencies(dependenciesMap)
+	rootNode, uniqueDepsWithRoot := sca.BuildXrayDependencyTree(dependenciesMap, rootId)
+	for _, dependency := range uniqueDepsWithRoot {
+		if dependency != rootId {
+			uniqueDeps = append(uniqueDeps, dependency)
+		}
+	}
+	log.Debug(fmt.Sprintf("Found %d Maven dependencies in '%s'", len(uniqueDeps), lockPath))
+	dependencyTree = []*xrayUtils.GraphNode{rootNode}
+	return
+}
+
+// Parses the maven_install.json lock file into a map from the IDs of the artifacts to the IDs of the artifacts they depend on.
+func parseMavenInstall(lockPath string, content []byte) (dependenciesMap map[string][]string, err error) {
+	lock := &mavenInstall{}
+	if err = json.Unmarshal(content, lock); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse '%s': %s", lockPath, err.Error())
+	}
+	dependenciesMap = map[string][]string{}
+	if lock.DependencyTree != nil {
+		for _, dependency := range lock.DependencyTree.Dependencies {
+			id := getGavComponentId(dependency.Coord)
+			dependenciesMap[id] = appendUnique(dependenciesMap[id], mapToComponentIds(dependency.DirectDependencies, getGavComponentId)...)
+		}
+		return
+	}
+	// In version 2 of the file, the artifacts are keyed by their coordinates without their versions.
+	getVersionedComponentId := func(key string) string {
+		return getGavComponentId(key + ":" + lock.Artifacts[key].Version)
+	}
+	for key := range lock.Artifacts {
+		id := getVersionedComponentId(key)
+		dependenciesMap[id] = appendUnique(dependenciesMap[id], mapToComponentIds(lock.Dependencies[key], getVersionedComponentId)...)
+	}
+	return
+}
+
+// Returns the artifacts that no other artifact depends on, sorted by their IDs.
+func getRootDependencies(dependenciesMap map[string][]string) (roots []string) {
+	dependents := map[string]bool{}
+	for _, dependencies := range dependenciesMap {
+		for _, dependency := range dependencies {
+			dependents[dependency] = true
+		}
+	}
+	for _, id := range maps.Keys(dependenciesMap) {
+		if !dependents[id] {
+			roots = append(roots, id)
+		}
+	}
+	slices.Sort(roots)
+	return
+}
+
+func mapToComponentIds(coordinates []string, getComponentId func(string) string) (ids []string) {
+	for _, coordinate := range coordinates {
+		ids = append(ids, getComponentId(coordinate))
+	}
+	return
+}
+
+func appendUnique(ids []string, newIds ...string) []string {
+	for _, id := range newIds {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Returns the Xray component ID of the artifact with the given Maven coordinates, such as 'gav://com.google.guava:guava:31.1-jre'.
+// The coordinates are 'group:artifact:version' or 'group:artifact:packaging:classifier:version', and the packaging and the classifier are dropped,
+// since Xray identifies the Maven artifacts by their group, artifact and version.
+func getGavComponentId(coordinates string) string {
+	parts := strings.Split(coordinates, ":")
+	if len(parts) > 3 {
+		parts = []string{parts[0], parts[1], parts[len(parts)-1]}
+	}
+	return java.GavPackageTypeIdentifier + strings.Join(parts, ":")
+}
+
+// Returns the name of the module from its MODULE.bazel file, or the name of its directory if the name can't be found.
+func getModuleName(workingDir string) string {
+	content, err := os.ReadFile(filepath.Join(workingDir, moduleBazelName))
+	if err == nil {
+		if match := moduleNameRegex.FindSubmatch(content); match != nil {
+			return string(match[1])
+		}
+	}
+	return filepath.Base(workingDir)
+}
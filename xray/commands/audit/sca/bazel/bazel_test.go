@@ -0,0 +1,66 @@
+package bazel
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyTree(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "bazel-project")
+	defer cleanUp()
+
+	dependencyTree, uniqueDeps, err := BuildDependencyTree(&xrayutils.AuditBasicParams{}, projectDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"gav://com.google.guava:guava:31.1-jre",
+		"gav://com.google.code.findbugs:jsr305:3.0.2",
+		"gav://com.google.guava:failureaccess:1.0.1",
+		"gav://junit:junit:4.13.2",
+		"gav://org.hamcrest:hamcrest-core:1.3",
+	}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		root := dependencyTree[0]
+		assert.Equal(t, "bazel_project", root.Id)
+		// The artifacts that no other artifact depends on are the direct dependencies.
+		assert.Equal(t, []string{"gav://com.google.guava:guava:31.1-jre", "gav://junit:junit:4.13.2"}, getChildrenIds(root))
+		assert.Equal(t, []string{"gav://com.google.code.findbugs:jsr305:3.0.2", "gav://com.google.guava:failureaccess:1.0.1"}, getChildrenIds(root.Nodes[0]))
+	}
+}
+
+func TestBuildDependencyTreeWithoutLockFile(t *testing.T) {
+	_, _, err := BuildDependencyTree(&xrayutils.AuditBasicParams{}, t.TempDir())
+	assert.ErrorContains(t, err, "Run 'bazel run @maven//:pin' to create it")
+}
+
+func TestParseMavenInstallV1(t *testing.T) {
+	lock := `{"dependency_tree": {"version": "0.1.0", "dependencies": [
+		{"coord": "com.google.guava:guava:31.1-jre", "directDependencies": ["com.google.guava:failureaccess:1.0.1"], "dependencies": ["com.google.guava:failureaccess:1.0.1"]},
+		{"coord": "com.google.guava:failureaccess:1.0.1", "dependencies": []},
+		{"coord": "io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.94.Final", "dependencies": []}
+	]}}`
+	dependenciesMap, err := parseMavenInstall("maven_install.json", []byte(lock))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"gav://com.google.guava:guava:31.1-jre":                    {"gav://com.google.guava:failureaccess:1.0.1"},
+		"gav://com.google.guava:failureaccess:1.0.1":               nil,
+		"gav://io.netty:netty-transport-native-epoll:4.1.94.Final": nil,
+	}, dependenciesMap)
+	assert.Equal(t, []string{"gav://com.google.guava:guava:31.1-jre", "gav://io.netty:netty-transport-native-epoll:4.1.94.Final"}, getRootDependencies(dependenciesMap))
+}
+
+func TestGetGavComponentId(t *testing.T) {
+	assert.Equal(t, "gav://junit:junit:4.13.2", getGavComponentId("junit:junit:4.13.2"))
+	// The packaging and the classifier are dropped.
+	assert.Equal(t, "gav://io.netty:netty-tcnative:2.0.61.Final", getGavComponentId("io.netty:netty-tcnative:jar:linux-x86_64:2.0.61.Final"))
+}
+
+func getChildrenIds(node *xrayUtils.GraphNode) (ids []string) {
+	for _, child := range node.Nodes {
+		ids = append(ids, child.Id)
+	}
+	return
+}
@@ -0,0 +1,240 @@
+package bundler
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// The Xray package type of RubyGems, which is also the type of their purls: 'pkg:gem/<name>@<version>'.
+	gemPackageTypeIdentifier = "gem://"
+	gemfileName              = "Gemfile"
+	gemfileLockName          = "Gemfile.lock"
+	// The group of the gems that are declared outside of any group.
+	defaultGroup = "default"
+)
+
+// The groups whose gems are excluded when the test dependencies are excluded.
+var testGroups = []string{"development", "test"}
+
+var (
+	// A gem declaration in the Gemfile: 'gem "name", ...'.
+	gemDeclarationPattern = regexp.MustCompile(`^gem\s*\(?\s*["']([^"']+)["']`)
+	// The group options of a gem declaration: 'group: :test', 'groups: [:development, :test]' or ':group => :test'.
+	groupOptionPattern = regexp.MustCompile(`:?groups?:?\s*(?:=>)?\s*(\[[^\]]*\]|:\w+|["']\w+["'])`)
+	// The group names in a group block or option, as symbols or strings.
+	groupNamePattern = regexp.MustCompile(`:(\w+)|["'](\w+)["']`)
+)
+
+// The content of a Gemfile.lock file that is relevant for building the dependency tree.
+type gemfileLock struct {
+	// Maps the names of the locked gems to their versions.
+	versions map[string]string
+	// Maps the names of the locked gems to the names of their dependencies.
+	dependencies map[string][]string
+	// The names of the gems that the Gemfile declares.
+	directDependencies []string
+}
+
+// Builds the dependency tree of the Bundler project in the working directory from its Gemfile.lock file.
+// The direct dependencies are scoped by their groups in the Gemfile, and the gems of the development and test groups are excluded if the test dependencies are excluded.
+// The scopes of the gems are returned with the tree.
+func BuildDependencyTree(params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *utils.DependencyTreesInfo, err error) {
+	content, err := os.ReadFile(filepath.Join(workingDir, gemfileLockName))
+	if err != nil {
+		err = errorutils.CheckError(err)
+		return
+	}
+	lock := parseGemfileLock(content)
+	gemsGroups, err := getGemsGroups(filepath.Join(workingDir, gemfileName))
+	if err != nil {
+		return
+	}
+	uniqueDepsSet := datastructures.MakeSet[string]()
+	treesInfo = &utils.DependencyTreesInfo{}
+	rootNode := &xrayUtils.GraphNode{Id: filepath.Base(workingDir), Nodes: []*xrayUtils.GraphNode{}}
+	for _, directDependency := range lock.directDependencies {
+		groups := gemsGroups[directDependency]
+		if len(groups) == 0 {
+			groups = []string{defaultGroup}
+		}
+		if params.ExcludeTestDependencies() && isInTestGroupsOnly(groups) {
+			continue
+		}
+		if _, locked := lock.versions[directDependency]; !locked {
+			continue
+		}
+		rootNode.Nodes = append(rootNode.Nodes, lock.buildGemTree(directDependency, rootNode, groups, treesInfo, uniqueDepsSet))
+	}
+	dependencyTree = []*xrayUtils.GraphNode{rootNode}
+	uniqueDeps = uniqueDepsSet.ToSlice()
+	return
+}
+
+// Creates the node of the given gem, and populates it with the gem's dependencies. The dependencies get the groups of the direct dependency that brought them.
+func (lock *gemfileLock) buildGemTree(name string, parent *xrayUtils.GraphNode, groups []string, treesInfo *utils.DependencyTreesInfo, uniqueDepsSet *datastructures.Set[string]) *xrayUtils.GraphNode {
+	node := &xrayUtils.GraphNode{Id: getGemComponentId(name, lock.versions[name]), Nodes: []*xrayUtils.GraphNode{}, Parent: parent}
+	uniqueDepsSet.Add(node.Id)
+	treesInfo.AddDependencyScopes(node.Id, groups...)
+	if node.NodeHasLoop() {
+		return node
+	}
+	for _, dependency := range lock.dependencies[name] {
+		if _, locked := lock.versions[dependency]; locked {
+			node.Nodes = append(node.Nodes, lock.buildGemTree(dependency, node, groups, treesInfo, uniqueDepsSet))
+		}
+	}
+	return node
+}
+
+// Returns the Xray component ID of the given gem.
+func getGemComponentId(name, version string) string {
+	return gemPackageTypeIdentifier + name + ":" + version
+}
+
+func isInTestGroupsOnly(groups []string) bool {
+	for _, group := range groups {
+		if !slices.Contains(testGroups, group) {
+			return false
+		}
+	}
+	return true
+}
+
+// Parses the sections of a Gemfile.lock file.
+// The gems are locked under the 'specs' of the GEM, GIT and PATH sections, with their dependencies indented below them:
+//
+//	GEM
+//	  remote: https://rubygems.org/
+//	  specs:
+//	    loofah (2.19.1)
+//	      crass (~> 1.0.2)
+//
+// The declared gems are listed in the DEPENDENCIES section, with their requirements.
+func parseGemfileLock(content []byte) *gemfileLock {
+	lock := &gemfileLock{versions: map[string]string{}, dependencies: map[string][]string{}}
+	var section, currentGem string
+	inSpecs := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indentation := len(line) - len(strings.TrimLeft(line, " "))
+		if indentation == 0 {
+			section, inSpecs, currentGem = strings.TrimSpace(line), false, ""
+			continue
+		}
+		name, requirement := parseGemEntry(line)
+		switch {
+		case section == "DEPENDENCIES" && indentation == 2:
+			// Gems from git or path sources are marked with a '!' suffix.
+			lock.directDependencies = append(lock.directDependencies, strings.TrimSuffix(name, "!"))
+		case indentation == 2:
+			inSpecs = strings.TrimSpace(line) == "specs:"
+		case inSpecs && indentation == 4:
+			currentGem = ""
+			// Platform specific gems may be locked for several platforms, with the same version.
+			if _, exists := lock.versions[name]; !exists {
+				lock.versions[name] = getGemVersion(requirement)
+				currentGem = name
+			}
+		case inSpecs && indentation == 6 && currentGem != "":
+			lock.dependencies[currentGem] = append(lock.dependencies[currentGem], name)
+		}
+	}
+	return lock
+}
+
+// Splits an entry of the Gemfile.lock, such as 'loofah (~> 2.19, >= 2.19.1)', to the gem's name and the content of the parentheses.
+func parseGemEntry(line string) (name, requirement string) {
+	name, requirement, _ = strings.Cut(strings.TrimSpace(line), " ")
+	requirement = strings.TrimSuffix(strings.TrimPrefix(requirement, "("), ")")
+	return
+}
+
+// Returns the version of a locked gem, without the platform that platform specific gems are suffixed with, such as '1.14.2-x86_64-linux'.
+func getGemVersion(lockedVersion string) string {
+	version, _, _ := strings.Cut(lockedVersion, "-")
+	return version
+}
+
+// Maps the gems that are declared in the Gemfile to their groups.
+// The groups are declared by group blocks ('group :development, :test do ... end') and by group options ('gem "rspec", group: :test').
+// Gems that are declared outside of any group aren't mapped. A missing Gemfile maps no gems.
+func getGemsGroups(gemfilePath string) (map[string][]string, error) {
+	gemsGroups := map[string][]string{}
+	exists, err := fileutils.IsFileExists(gemfilePath, false)
+	if err != nil || !exists {
+		return gemsGroups, err
+	}
+	content, err := os.ReadFile(gemfilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	// The groups of each open block. Blocks that aren't group blocks, like platforms blocks, have no groups.
+	var blocksGroups [][]string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "end" {
+			if len(blocksGroups) > 0 {
+				blocksGroups = blocksGroups[:len(blocksGroups)-1]
+			}
+			continue
+		}
+		if strings.HasSuffix(line, " do") || strings.Contains(line, " do |") {
+			var groups []string
+			if strings.HasPrefix(line, "group ") || strings.HasPrefix(line, "group(") {
+				groups = getGroupNames(strings.TrimPrefix(line, "group"))
+			}
+			blocksGroups = append(blocksGroups, groups)
+			continue
+		}
+		match := gemDeclarationPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		var groups []string
+		for _, blockGroups := range blocksGroups {
+			groups = append(groups, blockGroups...)
+		}
+		if optionMatch := groupOptionPattern.FindStringSubmatch(line); optionMatch != nil {
+			groups = append(groups, getGroupNames(optionMatch[1])...)
+		}
+		if len(groups) > 0 {
+			sort.Strings(groups)
+			gemsGroups[match[1]] = groups
+		}
+	}
+	return gemsGroups, errorutils.CheckError(scanner.Err())
+}
+
+func getGroupNames(declaration string) (groups []string) {
+	// The block's 'do' isn't a group name.
+	declaration = strings.TrimSuffix(strings.TrimSpace(declaration), " do")
+	for _, match := range groupNamePattern.FindAllStringSubmatch(declaration, -1) {
+		if match[1] != "" {
+			groups = append(groups, match[1])
+		} else {
+			groups = append(groups, match[2])
+		}
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package bundler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyTree(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "bundler-project")
+	defer cleanUp()
+
+	params := &xrayutils.AuditBasicParams{}
+	dependencyTree, uniqueDeps, treesInfo, err := BuildDependencyTree(params, projectDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"gem://puma:6.1.0", "gem://nio4r:2.5.8",
+		"gem://rails-html-sanitizer:1.5.0", "gem://loofah:2.19.1", "gem://crass:1.0.6", "gem://nokogiri:1.14.2", "gem://racc:1.6.2",
+		"gem://rspec:3.12.0", "gem://rspec-core:3.12.1", "gem://rspec-expectations:3.12.2", "gem://diff-lcs:1.5.0",
+		"gem://rubocop:1.46.0", "gem://parser:3.2.1.0", "gem://ast:2.4.2",
+	}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		assert.Equal(t, []string{"gem://puma:6.1.0", "gem://rails-html-sanitizer:1.5.0", "gem://rspec:3.12.0", "gem://rubocop:1.46.0"}, getChildrenIds(dependencyTree[0]))
+		// The transitive dependencies are nested under the gems that depend on them.
+		sanitizer := dependencyTree[0].Nodes[1]
+		if assert.Equal(t, []string{"gem://loofah:2.19.1"}, getChildrenIds(sanitizer)) {
+			assert.Equal(t, []string{"gem://crass:1.0.6", "gem://nokogiri:1.14.2"}, getChildrenIds(sanitizer.Nodes[0]))
+		}
+	}
+	// The gems are scoped by the groups of the direct dependencies that brought them.
+	scopes := treesInfo.DependenciesScopes
+	assert.Equal(t, []string{"default"}, scopes["gem://nokogiri:1.14.2"])
+	assert.Equal(t, []string{"development", "test"}, scopes["gem://diff-lcs:1.5.0"])
+	assert.Equal(t, []string{"development"}, scopes["gem://ast:2.4.2"])
+}
+
+func TestBuildDependencyTreeExcludeTestDependencies(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "bundler-project")
+	defer cleanUp()
+
+	params := &xrayutils.AuditBasicParams{}
+	dependencyTree, uniqueDeps, _, err := BuildDependencyTree(params.SetExcludeTestDependencies(true), projectDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"gem://puma:6.1.0", "gem://nio4r:2.5.8",
+		"gem://rails-html-sanitizer:1.5.0", "gem://loofah:2.19.1", "gem://crass:1.0.6", "gem://nokogiri:1.14.2", "gem://racc:1.6.2",
+	}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		assert.Equal(t, []string{"gem://puma:6.1.0", "gem://rails-html-sanitizer:1.5.0"}, getChildrenIds(dependencyTree[0]))
+	}
+}
+
+func TestGetGemsGroups(t *testing.T) {
+	gemfile := filepath.Join(t.TempDir(), gemfileName)
+	assert.NoError(t, os.WriteFile(gemfile, []byte(`source "https://rubygems.org"
+gem "rails"
+group :development, :test do
+  platforms :mri do
+    gem "byebug"
+  end
+  gem "rspec-rails", group: :ci
+end
+gem 'rubocop', require: false, groups: [:development, :lint]
+gem "pry", :group => "debug"
+`), 0644))
+	gemsGroups, err := getGemsGroups(gemfile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"byebug":      {"development", "test"},
+		"rspec-rails": {"ci", "development", "test"},
+		"rubocop":     {"development", "lint"},
+		"pry":         {"debug"},
+	}, gemsGroups)
+}
+
+func getChildrenIds(node *xrayUtils.GraphNode) (ids []string) {
+	for _, child := range node.Nodes {
+		ids = append(ids, child.Id)
+	}
+	return
+}
@@ -1,6 +1,7 @@
 package sca
 
 import (
+	"context"
 	"fmt"
 	biutils "github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
@@ -14,6 +15,8 @@ import (
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -21,7 +24,11 @@ import (
 	"testing"
 )
 
-const maxUniqueAppearances = 10
+const (
+	maxUniqueAppearances = 10
+	// The default separator between the node IDs of a node path.
+	DefaultNodePathSeparator = ">"
+)
 
 func BuildXrayDependencyTree(treeHelper map[string][]string, nodeId string) (*xrayUtils.GraphNode, []string) {
 	rootNode := &xrayUtils.GraphNode{
@@ -110,6 +117,23 @@ func GetModule(modules []*xrayUtils.GraphNode, moduleId string) *xrayUtils.Graph
 	return nil
 }
 
+// Creates the command of the given executable, which is killed once the given context is done. A nil context is never done.
+func CreateCommand(ctx context.Context, executable string, args ...string) *exec.Cmd {
+	if ctx == nil {
+		return exec.Command(executable, args...)
+	}
+	return exec.CommandContext(ctx, executable, args...)
+}
+
+// Returns the error of the given context if it's done, so a dependency tree builder stops before its next step. A nil context is never done.
+// Used by the builders whose package manager commands are run by build-info-go, which can't kill them once the context is done.
+func CheckContext(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	return errorutils.CheckError(ctx.Err())
+}
+
 // GetExecutableVersion gets an executable version and prints to the debug log if possible.
 // Only supported for package managers that use "--version".
 func LogExecutableVersion(executable string) {
@@ -129,15 +153,21 @@ func LogExecutableVersion(executable string) {
 // BuildImpactPathsForScanResponse builds the full impact paths for each vulnerability found in the scanResult argument, using the dependencyTrees argument.
 // Returns the updated services.ScanResponse slice.
 func BuildImpactPathsForScanResponse(scanResult []services.ScanResponse, dependencyTree []*xrayUtils.GraphNode) []services.ScanResponse {
+	return BuildImpactPathsForScanResponseWithWorkers(scanResult, dependencyTree, 1)
+}
+
+// BuildImpactPathsForScanResponseWithWorkers builds the impact paths like BuildImpactPathsForScanResponse, walking the dependency trees with up to the given number of workers in parallel.
+// The results are identical to the sequential ones.
+func BuildImpactPathsForScanResponseWithWorkers(scanResult []services.ScanResponse, dependencyTree []*xrayUtils.GraphNode, workers int) []services.ScanResponse {
 	for _, result := range scanResult {
 		if len(result.Vulnerabilities) > 0 {
-			buildVulnerabilitiesImpactPaths(result.Vulnerabilities, dependencyTree)
+			buildVulnerabilitiesImpactPaths(result.Vulnerabilities, dependencyTree, workers)
 		}
 		if len(result.Violations) > 0 {
-			buildViolationsImpactPaths(result.Violations, dependencyTree)
+			buildViolationsImpactPaths(result.Violations, dependencyTree, workers)
 		}
 		if len(result.Licenses) > 0 {
-			buildLicensesImpactPaths(result.Licenses, dependencyTree)
+			buildLicensesImpactPaths(result.Licenses, dependencyTree, workers)
 		}
 	}
 	return scanResult
@@ -150,41 +180,80 @@ func fillIssuesMapWithEmptyImpactPaths(issuesImpactPathsMap map[string][][]servi
 	}
 }
 
-// Set the impact paths for each issue in the map
-func buildImpactPaths(issuesImpactPathsMap map[string][][]services.ImpactPathNode, dependencyTrees []*xrayUtils.GraphNode) {
-	for _, dependency := range dependencyTrees {
-		setPathsForIssues(dependency, issuesImpactPathsMap, []services.ImpactPathNode{})
+// Set the impact paths for each issue in the map.
+// With several workers, the subtrees of the roots are walked in parallel. The workers only read the trees and the issues map, and each of them collects the paths of its subtree to a map of its own.
+// The collected paths are then added in the order of the subtrees, so the paths are in the same order as when the trees are walked sequentially.
+func buildImpactPaths(issuesImpactPathsMap map[string][][]services.ImpactPathNode, dependencyTrees []*xrayUtils.GraphNode, workers int) {
+	if workers <= 1 {
+		for _, dependency := range dependencyTrees {
+			setPathsForIssues(dependency, issuesImpactPathsMap, []services.ImpactPathNode{})
+		}
+		return
+	}
+	var subtrees []*impactPathsSubtree
+	for _, root := range dependencyTrees {
+		// The root itself is checked separately, before its subtrees.
+		subtrees = append(subtrees, &impactPathsSubtree{node: root})
+		for _, child := range root.Nodes {
+			subtrees = append(subtrees, &impactPathsSubtree{node: child, pathFromRoot: []services.ImpactPathNode{{ComponentId: root.Id}}, walkChildren: true})
+		}
+	}
+	errGroup := new(errgroup.Group)
+	errGroup.SetLimit(workers)
+	for _, subtree := range subtrees {
+		currentSubtree := subtree
+		errGroup.Go(func() error {
+			currentSubtree.impactPaths = map[string][][]services.ImpactPathNode{}
+			collectImpactPaths(currentSubtree.node, issuesImpactPathsMap, currentSubtree.impactPaths, currentSubtree.pathFromRoot, currentSubtree.walkChildren)
+			return nil
+		})
+	}
+	// The workers never fail.
+	_ = errGroup.Wait()
+	for _, subtree := range subtrees {
+		for dependencyId, impactPaths := range subtree.impactPaths {
+			issuesImpactPathsMap[dependencyId] = append(issuesImpactPathsMap[dependencyId], impactPaths...)
+		}
 	}
 }
 
-func buildVulnerabilitiesImpactPaths(vulnerabilities []services.Vulnerability, dependencyTrees []*xrayUtils.GraphNode) {
+// A subtree of the dependency trees, whose impact paths are collected by one of the workers.
+type impactPathsSubtree struct {
+	node         *xrayUtils.GraphNode
+	pathFromRoot []services.ImpactPathNode
+	// Whether to walk the children of the node, or to check only the node itself.
+	walkChildren bool
+	impactPaths  map[string][][]services.ImpactPathNode
+}
+
+func buildVulnerabilitiesImpactPaths(vulnerabilities []services.Vulnerability, dependencyTrees []*xrayUtils.GraphNode, workers int) {
 	issuesMap := make(map[string][][]services.ImpactPathNode)
 	for _, vulnerability := range vulnerabilities {
 		fillIssuesMapWithEmptyImpactPaths(issuesMap, vulnerability.Components)
 	}
-	buildImpactPaths(issuesMap, dependencyTrees)
+	buildImpactPaths(issuesMap, dependencyTrees, workers)
 	for i := range vulnerabilities {
 		updateComponentsWithImpactPaths(vulnerabilities[i].Components, issuesMap)
 	}
 }
 
-func buildViolationsImpactPaths(violations []services.Violation, dependencyTrees []*xrayUtils.GraphNode) {
+func buildViolationsImpactPaths(violations []services.Violation, dependencyTrees []*xrayUtils.GraphNode, workers int) {
 	issuesMap := make(map[string][][]services.ImpactPathNode)
 	for _, violation := range violations {
 		fillIssuesMapWithEmptyImpactPaths(issuesMap, violation.Components)
 	}
-	buildImpactPaths(issuesMap, dependencyTrees)
+	buildImpactPaths(issuesMap, dependencyTrees, workers)
 	for i := range violations {
 		updateComponentsWithImpactPaths(violations[i].Components, issuesMap)
 	}
 }
 
-func buildLicensesImpactPaths(licenses []services.License, dependencyTrees []*xrayUtils.GraphNode) {
+func buildLicensesImpactPaths(licenses []services.License, dependencyTrees []*xrayUtils.GraphNode, workers int) {
 	issuesMap := make(map[string][][]services.ImpactPathNode)
 	for _, license := range licenses {
 		fillIssuesMapWithEmptyImpactPaths(issuesMap, license.Components)
 	}
-	buildImpactPaths(issuesMap, dependencyTrees)
+	buildImpactPaths(issuesMap, dependencyTrees, workers)
 	for i := range licenses {
 		updateComponentsWithImpactPaths(licenses[i].Components, issuesMap)
 	}
@@ -202,14 +271,228 @@ func updateComponentsWithImpactPaths(components map[string]services.Component, i
 }
 
 func setPathsForIssues(dependency *xrayUtils.GraphNode, issuesImpactPathsMap map[string][][]services.ImpactPathNode, pathFromRoot []services.ImpactPathNode) {
+	collectImpactPaths(dependency, issuesImpactPathsMap, issuesImpactPathsMap, pathFromRoot, true)
+}
+
+// Collects the impact paths of the issues in the tree of the given dependency to the impactPaths map.
+// The issues map is only read, unless it's also the impactPaths map.
+func collectImpactPaths(dependency *xrayUtils.GraphNode, issuesImpactPathsMap, impactPaths map[string][][]services.ImpactPathNode, pathFromRoot []services.ImpactPathNode, walkChildren bool) {
 	pathFromRoot = append(pathFromRoot, services.ImpactPathNode{ComponentId: dependency.Id})
 	if _, exists := issuesImpactPathsMap[dependency.Id]; exists {
 		// Create a copy of pathFromRoot to avoid modifying the original slice
 		pathCopy := make([]services.ImpactPathNode, len(pathFromRoot))
 		copy(pathCopy, pathFromRoot)
-		issuesImpactPathsMap[dependency.Id] = append(issuesImpactPathsMap[dependency.Id], pathCopy)
+		impactPaths[dependency.Id] = append(impactPaths[dependency.Id], pathCopy)
+	}
+	if !walkChildren {
+		return
 	}
 	for _, depChild := range dependency.Nodes {
-		setPathsForIssues(depChild, issuesImpactPathsMap, pathFromRoot)
+		collectImpactPaths(depChild, issuesImpactPathsMap, impactPaths, pathFromRoot, true)
+	}
+}
+
+// Returns the path of the given node in the tree of the given root: the IDs of the nodes from the root to the node, joined by the separator.
+// Since the path starts with the root, nodes with the same ID in different roots of a multi-root project get different paths.
+// If the separator is empty, DefaultNodePathSeparator is used. If the node is not in the root's tree, an empty string is returned.
+func NodePath(root, node *xrayUtils.GraphNode, separator string) string {
+	nodesIds, found := findNodePath(root, node, nil)
+	if !found {
+		return ""
+	}
+	return joinNodePath(nodesIds, separator)
+}
+
+func findNodePath(currNode, node *xrayUtils.GraphNode, pathFromRoot []string) ([]string, bool) {
+	pathFromRoot = append(pathFromRoot, currNode.Id)
+	if currNode == node {
+		return pathFromRoot, true
+	}
+	for _, child := range currNode.Nodes {
+		if path, found := findNodePath(child, node, pathFromRoot); found {
+			return path, true
+		}
+	}
+	return nil, false
+}
+
+// Returns the given impact path in the same format of NodePath, so impact paths can be correlated with the tree nodes.
+func ImpactPathToNodePath(impactPath []services.ImpactPathNode, separator string) string {
+	nodesIds := make([]string, 0, len(impactPath))
+	for _, impactPathNode := range impactPath {
+		nodesIds = append(nodesIds, impactPathNode.ComponentId)
+	}
+	return joinNodePath(nodesIds, separator)
+}
+
+func joinNodePath(nodesIds []string, separator string) string {
+	if separator == "" {
+		separator = DefaultNodePathSeparator
+	}
+	return strings.Join(nodesIds, separator)
+}
+
+// The scan results of a single Xray server.
+type ServerScanResults struct {
+	ServerId string
+	Results  []services.ScanResponse
+}
+
+// MergeServersScanResults merges the scan results of multiple Xray servers into a single scan response.
+// Identical findings that were reported by more than one server are added only once.
+// Returns the merged results and a map of each finding to the servers that reported it.
+func MergeServersScanResults(serversResults []ServerScanResults) ([]services.ScanResponse, map[string][]string) {
+	merged := services.ScanResponse{}
+	issueReporters := map[string][]string{}
+	for _, serverResults := range serversResults {
+		for _, response := range serverResults.Results {
+			if merged.ScanId == "" {
+				merged.ScanId = response.ScanId
+				merged.XrayDataUrl = response.XrayDataUrl
+			}
+			for _, vulnerability := range response.Vulnerabilities {
+				if addIssueReporter(issueReporters, GetVulnerabilityKey(vulnerability), serverResults.ServerId) {
+					merged.Vulnerabilities = append(merged.Vulnerabilities, vulnerability)
+				}
+			}
+			for _, violation := range response.Violations {
+				if addIssueReporter(issueReporters, GetViolationKey(violation), serverResults.ServerId) {
+					merged.Violations = append(merged.Violations, violation)
+				}
+			}
+			for _, license := range response.Licenses {
+				if addIssueReporter(issueReporters, GetLicenseKey(license), serverResults.ServerId) {
+					merged.Licenses = append(merged.Licenses, license)
+				}
+			}
+		}
+	}
+	return []services.ScanResponse{merged}, issueReporters
+}
+
+// Adds the server to the reporters of the issue.
+// Returns true if this is the first time the issue was reported.
+func addIssueReporter(issueReporters map[string][]string, issueKey, serverId string) bool {
+	reporters, exists := issueReporters[issueKey]
+	if !slices.Contains(reporters, serverId) {
+		issueReporters[issueKey] = append(reporters, serverId)
+	}
+	return !exists
+}
+
+func GetVulnerabilityKey(vulnerability services.Vulnerability) string {
+	return strings.Join([]string{"vulnerability", vulnerability.IssueId, getSortedComponentIds(vulnerability.Components)}, ":")
+}
+
+func GetViolationKey(violation services.Violation) string {
+	return strings.Join([]string{"violation", violation.WatchName, violation.IssueId, violation.LicenseKey, getSortedComponentIds(violation.Components)}, ":")
+}
+
+func GetLicenseKey(license services.License) string {
+	return strings.Join([]string{"license", license.Key, getSortedComponentIds(license.Components)}, ":")
+}
+
+func getSortedComponentIds(components map[string]services.Component) string {
+	componentIds := maps.Keys(components)
+	slices.Sort(componentIds)
+	return strings.Join(componentIds, ",")
+}
+
+// Returns the IDs of the components of the given flat tree that no data was returned for by Xray.
+// These components are unknown to Xray (for example, internal packages that were never indexed), so they couldn't be scanned at all.
+// Since Xray returns license data for each of the components it knows, the scan results must include the licenses data.
+func GetUnscannedComponents(flatTree *xrayUtils.GraphNode, scanResults []services.ScanResponse) (unscannedComponents []string) {
+	scannedComponents := map[string]bool{}
+	addComponents := func(components map[string]services.Component) {
+		for componentId := range components {
+			scannedComponents[componentId] = true
+		}
+	}
+	for _, result := range scanResults {
+		for _, vulnerability := range result.Vulnerabilities {
+			addComponents(vulnerability.Components)
+		}
+		for _, violation := range result.Violations {
+			addComponents(violation.Components)
+		}
+		for _, license := range result.Licenses {
+			addComponents(license.Components)
+		}
+	}
+	for _, node := range flatTree.Nodes {
+		if !scannedComponents[node.Id] {
+			unscannedComponents = append(unscannedComponents, node.Id)
+		}
+	}
+	return
+}
+
+// Returns a flat tree of the dependencies that appear in the given flat tree, but not in the base flat tree.
+// The base flat tree may be nil, in which case all the dependencies are considered added.
+func GetAddedDependencies(flatTree, baseFlatTree *xrayUtils.GraphNode) *xrayUtils.GraphNode {
+	baseDependencies := map[string]bool{}
+	if baseFlatTree != nil {
+		for _, node := range baseFlatTree.Nodes {
+			baseDependencies[node.Id] = true
+		}
+	}
+	addedDependencies := &xrayUtils.GraphNode{Id: flatTree.Id, Nodes: []*xrayUtils.GraphNode{}}
+	for _, node := range flatTree.Nodes {
+		if !baseDependencies[node.Id] {
+			addedDependencies.Nodes = append(addedDependencies.Nodes, node)
+		}
+	}
+	return addedDependencies
+}
+
+// Returns whether the version of the given component ID is a version range, such as the npm '^1.2.0' or the Maven '[1.0,2.0)', rather than a concrete version.
+// Only the package types whose builders may return ranges are checked, since other ecosystems may have concrete versions that look like ranges.
+func IsVersionRange(componentId string) bool {
+	packageType, coordinates, found := strings.Cut(componentId, "://")
+	if !found {
+		return false
+	}
+	separatorIndex := strings.LastIndex(coordinates, ":")
+	if separatorIndex < 0 {
+		return false
+	}
+	version := strings.TrimSpace(coordinates[separatorIndex+1:])
+	switch packageType {
+	case "npm":
+		return isNpmVersionRange(version)
+	case "gav":
+		return isMavenVersionRange(version)
+	}
+	return false
+}
+
+func isNpmVersionRange(version string) bool {
+	if version == "" || version == "latest" || strings.ContainsAny(version, "^~<>=* ") || strings.Contains(version, "||") {
+		return true
+	}
+	// Wildcard versions, such as '1.x' or '1.2.X'.
+	for _, part := range strings.Split(version, ".") {
+		if part == "x" || part == "X" {
+			return true
+		}
+	}
+	return false
+}
+
+func isMavenVersionRange(version string) bool {
+	return strings.HasPrefix(version, "[") || strings.HasPrefix(version, "(") || version == "LATEST" || version == "RELEASE"
+}
+
+// Removes the components whose versions are unresolved version ranges, since Xray can scan only concrete versions.
+// The builders are expected to resolve the ranges to the installed or selected versions, so each removed component is warned about.
+func RemoveVersionRanges(uniqueDeps []string) []string {
+	concreteDeps := make([]string, 0, len(uniqueDeps))
+	for _, dependency := range uniqueDeps {
+		if IsVersionRange(dependency) {
+			log.Warn(fmt.Sprintf("Couldn't resolve the version range of '%s' to a concrete version. Skipping it...", dependency))
+			continue
+		}
+		concreteDeps = append(concreteDeps, dependency)
 	}
+	return concreteDeps
 }
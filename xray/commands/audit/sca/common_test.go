@@ -1,6 +1,8 @@
 package sca
 
 import (
+	"fmt"
+
 	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
@@ -223,3 +225,166 @@ func TestBuildXrayDependencyTree(t *testing.T) {
 	assert.ElementsMatch(t, expectedUniqueDeps, uniqueDeps)
 	assert.True(t, tests.CompareTree(tree, rootNode))
 }
+
+func TestMergeServersScanResults(t *testing.T) {
+	sharedVulnerability := services.Vulnerability{IssueId: "XRAY-1", Severity: "High", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}}
+	serversResults := []ServerScanResults{
+		{
+			ServerId: "jpd1",
+			Results: []services.ScanResponse{{
+				ScanId:          "scan1",
+				Vulnerabilities: []services.Vulnerability{sharedVulnerability, {IssueId: "XRAY-2", Components: map[string]services.Component{"npm://minimist:1.2.5": {}}}},
+				Licenses:        []services.License{{Key: "MIT", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}}},
+			}},
+		},
+		{
+			ServerId: "jpd2",
+			Results: []services.ScanResponse{{
+				ScanId:          "scan2",
+				Vulnerabilities: []services.Vulnerability{sharedVulnerability, {IssueId: "XRAY-3", Components: map[string]services.Component{"npm://axios:0.21.0": {}}}},
+				Violations:      []services.Violation{{IssueId: "XRAY-3", WatchName: "watch", Components: map[string]services.Component{"npm://axios:0.21.0": {}}}},
+			}},
+		},
+	}
+
+	merged, issueReporters := MergeServersScanResults(serversResults)
+	assert.Len(t, merged, 1)
+	assert.Equal(t, "scan1", merged[0].ScanId)
+	assert.Len(t, merged[0].Vulnerabilities, 3)
+	assert.Len(t, merged[0].Violations, 1)
+	assert.Len(t, merged[0].Licenses, 1)
+	assert.Equal(t, []string{"jpd1", "jpd2"}, issueReporters[GetVulnerabilityKey(sharedVulnerability)])
+	assert.Equal(t, []string{"jpd1"}, issueReporters[GetVulnerabilityKey(serversResults[0].Results[0].Vulnerabilities[1])])
+	assert.Equal(t, []string{"jpd2"}, issueReporters[GetVulnerabilityKey(serversResults[1].Results[0].Vulnerabilities[1])])
+	assert.Equal(t, []string{"jpd2"}, issueReporters[GetViolationKey(serversResults[1].Results[0].Violations[0])])
+}
+
+func TestNodePath(t *testing.T) {
+	// Two roots, which both depend on the same component through different paths.
+	commonDep1 := &xrayUtils.GraphNode{Id: "npm://common:1.0.0"}
+	commonDep2 := &xrayUtils.GraphNode{Id: "npm://common:1.0.0"}
+	root1 := &xrayUtils.GraphNode{Id: "npm://root1:1.0.0", Nodes: []*xrayUtils.GraphNode{{Id: "npm://a:1.0.0", Nodes: []*xrayUtils.GraphNode{commonDep1}}}}
+	root2 := &xrayUtils.GraphNode{Id: "npm://root2:1.0.0", Nodes: []*xrayUtils.GraphNode{{Id: "npm://b:1.0.0"}, commonDep2}}
+
+	path1 := NodePath(root1, commonDep1, "")
+	path2 := NodePath(root2, commonDep2, "")
+	assert.Equal(t, "npm://root1:1.0.0>npm://a:1.0.0>npm://common:1.0.0", path1)
+	assert.Equal(t, "npm://root2:1.0.0>npm://common:1.0.0", path2)
+	// The paths are stable
+	assert.Equal(t, path1, NodePath(root1, commonDep1, DefaultNodePathSeparator))
+	// Custom separator
+	assert.Equal(t, "npm://root2:1.0.0 | npm://common:1.0.0", NodePath(root2, commonDep2, " | "))
+	// The root itself, and a node from another root
+	assert.Equal(t, "npm://root1:1.0.0", NodePath(root1, root1, ""))
+	assert.Empty(t, NodePath(root1, commonDep2, ""))
+
+	// The impact paths of the common component match the node paths
+	scanResponse := []services.ScanResponse{{Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Components: map[string]services.Component{"npm://common:1.0.0": {}}}}}}
+	scanResponse = BuildImpactPathsForScanResponse(scanResponse, []*xrayUtils.GraphNode{root1, root2})
+	var impactPaths []string
+	for _, impactPath := range scanResponse[0].Vulnerabilities[0].Components["npm://common:1.0.0"].ImpactPaths {
+		impactPaths = append(impactPaths, ImpactPathToNodePath(impactPath, ""))
+	}
+	assert.ElementsMatch(t, []string{path1, path2}, impactPaths)
+}
+
+func TestGetAddedDependencies(t *testing.T) {
+	flatTree := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "npm://lodash:4.17.21"}, {Id: "npm://axios:1.6.0"}, {Id: "npm://minimist:1.2.8"}}}
+	baseFlatTree := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "npm://lodash:4.17.21"}, {Id: "npm://minimist:1.2.5"}}}
+
+	added := GetAddedDependencies(flatTree, baseFlatTree)
+	assert.Equal(t, "root", added.Id)
+	assert.Equal(t, []*xrayUtils.GraphNode{{Id: "npm://axios:1.6.0"}, {Id: "npm://minimist:1.2.8"}}, added.Nodes)
+
+	// Without a base tree, all the dependencies are added
+	assert.Len(t, GetAddedDependencies(flatTree, nil).Nodes, 3)
+}
+
+func TestBuildImpactPathsForScanResponseWithWorkers(t *testing.T) {
+	dependencyTrees := createSyntheticDependencyTrees(3, 6, 40)
+	sequentialResponse := BuildImpactPathsForScanResponse(createSyntheticScanResponse(40), dependencyTrees)
+	for _, workers := range []int{2, 8, 100} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			parallelResponse := BuildImpactPathsForScanResponseWithWorkers(createSyntheticScanResponse(40), dependencyTrees, workers)
+			assert.Equal(t, sequentialResponse, parallelResponse)
+		})
+	}
+	// Sanity check that the synthetic components are found in the trees, in several paths.
+	assert.Greater(t, len(sequentialResponse[0].Vulnerabilities[0].Components["npm://dep-0:1.0.0"].ImpactPaths), 1)
+}
+
+func BenchmarkBuildImpactPathsForScanResponse(b *testing.B) {
+	dependencyTrees := createSyntheticDependencyTrees(4, 8, 200)
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("%d workers", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				scanResponse := createSyntheticScanResponse(200)
+				b.StartTimer()
+				BuildImpactPathsForScanResponseWithWorkers(scanResponse, dependencyTrees, workers)
+			}
+		})
+	}
+}
+
+// Creates dependency trees of the given depth and width, whose nodes are the given number of components, so each component appears in several paths.
+func createSyntheticDependencyTrees(roots, depth, components int) (dependencyTrees []*xrayUtils.GraphNode) {
+	nextComponent := 0
+	var populate func(node *xrayUtils.GraphNode, level int)
+	populate = func(node *xrayUtils.GraphNode, level int) {
+		if level == depth {
+			return
+		}
+		for i := 0; i < 3; i++ {
+			child := &xrayUtils.GraphNode{Id: fmt.Sprintf("npm://dep-%d:1.0.0", nextComponent%components), Parent: node}
+			nextComponent++
+			node.Nodes = append(node.Nodes, child)
+			populate(child, level+1)
+		}
+	}
+	for i := 0; i < roots; i++ {
+		root := &xrayUtils.GraphNode{Id: fmt.Sprintf("npm://root-%d:1.0.0", i)}
+		populate(root, 0)
+		dependencyTrees = append(dependencyTrees, root)
+	}
+	return
+}
+
+// Creates a scan response in which each of the given number of components has a vulnerability, a violation and a license.
+func createSyntheticScanResponse(components int) []services.ScanResponse {
+	response := services.ScanResponse{}
+	for i := 0; i < components; i++ {
+		componentId := fmt.Sprintf("npm://dep-%d:1.0.0", i)
+		response.Vulnerabilities = append(response.Vulnerabilities, services.Vulnerability{IssueId: fmt.Sprintf("XRAY-%d", i), Components: map[string]services.Component{componentId: {FixedVersions: []string{"[2.0.0]"}}}})
+		response.Violations = append(response.Violations, services.Violation{IssueId: fmt.Sprintf("XRAY-%d", i), Components: map[string]services.Component{componentId: {}}})
+		response.Licenses = append(response.Licenses, services.License{Key: "MIT", Components: map[string]services.Component{componentId: {}}})
+	}
+	return []services.ScanResponse{response}
+}
+
+func TestIsVersionRange(t *testing.T) {
+	testCases := []struct {
+		componentId string
+		expected    bool
+	}{
+		{componentId: "npm://lodash:4.17.21", expected: false},
+		{componentId: "npm://@types/node:20.0.0-beta.1", expected: false},
+		{componentId: "npm://lodash:^4.17.0", expected: true},
+		{componentId: "npm://ms:~2.1.0", expected: true},
+		{componentId: "npm://debug:>=4.0.0 <5.0.0", expected: true},
+		{componentId: "npm://react:16 || 17", expected: true},
+		{componentId: "npm://uuid:8.x", expected: true},
+		{componentId: "npm://uuid:*", expected: true},
+		{componentId: "gav://junit:junit:4.13.2", expected: false},
+		{componentId: "gav://com.example:lib:[1.0,2.0)", expected: true},
+		{componentId: "gav://com.example:lib:(,1.0]", expected: true},
+		{componentId: "gav://com.example:lib:LATEST", expected: true},
+		// Only the package types whose builders may return ranges are checked.
+		{componentId: "docker://alpine:latest", expected: false},
+		{componentId: "pypi://requests:>=2.0", expected: false},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, IsVersionRange(testCase.componentId), testCase.componentId)
+	}
+	assert.Equal(t, []string{"npm://lodash:4.17.21", "gav://junit:junit:4.13.2"}, RemoveVersionRanges([]string{"npm://lodash:4.17.21", "npm://ms:~2.1.0", "gav://junit:junit:4.13.2", "gav://com.example:lib:[1.0,2.0)"}))
+}
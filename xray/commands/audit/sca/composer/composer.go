@@ -0,0 +1,126 @@
+package composer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// The Xray package type of Composer, which is also the type of their purls: 'pkg:composer/<vendor>/<name>@<version>'.
+	composerPackageTypeIdentifier = "composer://"
+	composerJsonName              = "composer.json"
+	composerLockName              = "composer.lock"
+)
+
+// The content of a composer.json file that is relevant for building the dependency tree.
+type composerJson struct {
+	Name       string            `json:"name"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// The content of a composer.lock file that is relevant for building the dependency tree.
+// The packages that only the dev requirements need are locked separately from the others.
+type composerLock struct {
+	Packages    []composerLockPackage `json:"packages"`
+	PackagesDev []composerLockPackage `json:"packages-dev"`
+}
+
+type composerLockPackage struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Require map[string]string `json:"require"`
+}
+
+// Builds the dependency tree of the Composer project in the working directory from its composer.lock file.
+// The requirements of the composer.json are the direct dependencies, and the dev requirements are scoped as dev dependencies, or excluded if the test dependencies are excluded.
+// Platform requirements, such as 'php' or 'ext-json', aren't locked and aren't included. The scopes of the dependencies are returned with the tree.
+func BuildDependencyTree(params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *utils.DependencyTreesInfo, err error) {
+	exists, err := fileutils.IsFileExists(filepath.Join(workingDir, composerLockName), false)
+	if err != nil {
+		return
+	}
+	if !exists {
+		err = errorutils.CheckErrorf("the %s file wasn't found in '%s'. Run 'composer update' to create it", composerLockName, workingDir)
+		return
+	}
+	project := &composerJson{}
+	if err = readJsonFile(filepath.Join(workingDir, composerJsonName), project); err != nil {
+		return
+	}
+	lock := &composerLock{}
+	if err = readJsonFile(filepath.Join(workingDir, composerLockName), lock); err != nil {
+		return
+	}
+	lockedPackages := map[string]composerLockPackage{}
+	for _, lockedPackage := range append(lock.Packages, lock.PackagesDev...) {
+		lockedPackages[lockedPackage.Name] = lockedPackage
+	}
+	rootId := project.Name
+	if rootId == "" {
+		rootId = filepath.Base(workingDir)
+	}
+	uniqueDepsSet := datastructures.MakeSet[string]()
+	treesInfo = &utils.DependencyTreesInfo{}
+	rootNode := &xrayUtils.GraphNode{Id: rootId, Nodes: []*xrayUtils.GraphNode{}}
+	addDirectDependencies := func(requirements map[string]string, scope string) {
+		names := maps.Keys(requirements)
+		slices.Sort(names)
+		for _, name := range names {
+			if _, locked := lockedPackages[name]; locked {
+				rootNode.Nodes = append(rootNode.Nodes, buildPackageTree(name, rootNode, scope, lockedPackages, treesInfo, uniqueDepsSet))
+			}
+		}
+	}
+	addDirectDependencies(project.Require, utils.ProdScope)
+	if !params.ExcludeTestDependencies() {
+		addDirectDependencies(project.RequireDev, utils.DevScope)
+	}
+	dependencyTree = []*xrayUtils.GraphNode{rootNode}
+	uniqueDeps = uniqueDepsSet.ToSlice()
+	return
+}
+
+// Creates the node of the given package, and populates it with the package's dependencies. The dependencies get the scope of the direct dependency that brought them.
+func buildPackageTree(name string, parent *xrayUtils.GraphNode, scope string, lockedPackages map[string]composerLockPackage, treesInfo *utils.DependencyTreesInfo, uniqueDepsSet *datastructures.Set[string]) *xrayUtils.GraphNode {
+	lockedPackage := lockedPackages[name]
+	node := &xrayUtils.GraphNode{Id: getComposerComponentId(name, lockedPackage.Version), Nodes: []*xrayUtils.GraphNode{}, Parent: parent}
+	uniqueDepsSet.Add(node.Id)
+	treesInfo.AddDependencyScopes(node.Id, scope)
+	if node.NodeHasLoop() {
+		return node
+	}
+	dependencies := maps.Keys(lockedPackage.Require)
+	slices.Sort(dependencies)
+	for _, dependency := range dependencies {
+		if _, locked := lockedPackages[dependency]; locked {
+			node.Nodes = append(node.Nodes, buildPackageTree(dependency, node, scope, lockedPackages, treesInfo, uniqueDepsSet))
+		}
+	}
+	return node
+}
+
+// Returns the Xray component ID of the given package, whose name includes its vendor, such as 'monolog/monolog'.
+func getComposerComponentId(name, version string) string {
+	return composerPackageTypeIdentifier + name + ":" + version
+}
+
+func readJsonFile(path string, content interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = json.Unmarshal(data, content); err != nil {
+		return errorutils.CheckErrorf("failed to parse '%s': %s", path, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyTree(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "composer-project")
+	defer cleanUp()
+
+	params := &xrayutils.AuditBasicParams{}
+	dependencyTree, uniqueDeps, treesInfo, err := BuildDependencyTree(params, projectDir)
+	assert.NoError(t, err)
+	// The platform requirements aren't included.
+	assert.ElementsMatch(t, []string{
+		"composer://monolog/monolog:2.9.1", "composer://psr/log:3.0.0",
+		"composer://mockery/mockery:1.6.6", "composer://hamcrest/hamcrest-php:v2.0.1",
+	}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		root := dependencyTree[0]
+		assert.Equal(t, "jfrog/composer-project", root.Id)
+		assert.Equal(t, []string{"composer://monolog/monolog:2.9.1", "composer://mockery/mockery:1.6.6"}, getChildrenIds(root))
+		// The transitive dependencies are nested under the packages that require them.
+		assert.Equal(t, []string{"composer://psr/log:3.0.0"}, getChildrenIds(root.Nodes[0]))
+		assert.Equal(t, []string{"composer://hamcrest/hamcrest-php:v2.0.1"}, getChildrenIds(root.Nodes[1]))
+	}
+	scopes := treesInfo.DependenciesScopes
+	assert.Equal(t, []string{xrayutils.ProdScope}, scopes["composer://psr/log:3.0.0"])
+	assert.Equal(t, []string{xrayutils.DevScope}, scopes["composer://mockery/mockery:1.6.6"])
+	assert.Equal(t, []string{xrayutils.DevScope}, scopes["composer://hamcrest/hamcrest-php:v2.0.1"])
+}
+
+func TestBuildDependencyTreeExcludeTestDependencies(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "composer-project")
+	defer cleanUp()
+
+	params := &xrayutils.AuditBasicParams{}
+	dependencyTree, uniqueDeps, _, err := BuildDependencyTree(params.SetExcludeTestDependencies(true), projectDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"composer://monolog/monolog:2.9.1", "composer://psr/log:3.0.0"}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		assert.Equal(t, []string{"composer://monolog/monolog:2.9.1"}, getChildrenIds(dependencyTree[0]))
+	}
+}
+
+func TestBuildDependencyTreeWithoutLockFile(t *testing.T) {
+	tempDirPath, cleanUp := sca.CreateTestWorkspace(t, "composer-project")
+	defer cleanUp()
+	assert.NoError(t, os.Remove(filepath.Join(tempDirPath, composerLockName)))
+
+	_, _, _, err := BuildDependencyTree(&xrayutils.AuditBasicParams{}, tempDirPath)
+	assert.ErrorContains(t, err, "Run 'composer update' to create it")
+}
+
+func getChildrenIds(node *xrayUtils.GraphNode) (ids []string) {
+	for _, child := range node.Nodes {
+		ids = append(ids, child.Id)
+	}
+	return
+}
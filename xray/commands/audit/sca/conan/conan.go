@@ -0,0 +1,245 @@
+package conan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	conanPackageTypeIdentifier = "conan://"
+	conanLockName              = "conan.lock"
+	// The ID of the node of the project itself, in both the lockfiles and the output of 'conan graph info'.
+	rootNodeId = "0"
+	// The reference of the project in the output of 'conan graph info', when its conanfile doesn't declare a name and a version.
+	anonymousProjectRef = "conanfile"
+)
+
+// The graph of the packages of a Conan project, keyed by the IDs of the nodes.
+type conanGraph map[string]*conanNode
+
+type conanNode struct {
+	ref          string
+	dependencies []conanDependency
+}
+
+type conanDependency struct {
+	nodeId string
+	// Whether the dependency is a build or a test requirement, which isn't linked into the project.
+	dev bool
+}
+
+// The content of a Conan 1.x lockfile that is relevant for building the dependency tree.
+type conanLockV1 struct {
+	GraphLock *struct {
+		Nodes map[string]struct {
+			Ref           string   `json:"ref"`
+			Requires      []string `json:"requires"`
+			BuildRequires []string `json:"build_requires"`
+		} `json:"nodes"`
+	} `json:"graph_lock"`
+}
+
+// The content of a Conan 2.x lockfile that is relevant for building the dependency tree.
+// Unlike Conan 1.x, the lockfile lists the locked packages without the graph of their requirements.
+type conanLockV2 struct {
+	Requires      []string `json:"requires"`
+	BuildRequires []string `json:"build_requires"`
+}
+
+// The content of the output of 'conan graph info --format=json' that is relevant for building the dependency tree.
+type conanGraphInfo struct {
+	Graph struct {
+		Nodes map[string]struct {
+			Ref          string `json:"ref"`
+			Dependencies map[string]struct {
+				Direct bool `json:"direct"`
+				Build  bool `json:"build"`
+				Test   bool `json:"test"`
+			} `json:"dependencies"`
+		} `json:"nodes"`
+	} `json:"graph"`
+}
+
+// Builds the dependency tree of the Conan project in the working directory.
+// If the project has a conan.lock file, the tree is built from it. Otherwise, it is built from the output of 'conan graph info', which requires Conan 2.x.
+// Conan 2.x lockfiles don't record which package requires which, so all of their packages are direct dependencies of the project.
+// The build and test requirements are scoped as dev dependencies, or excluded if the test dependencies are excluded. The scopes are returned with the tree.
+func BuildDependencyTree(ctx context.Context, params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *utils.DependencyTreesInfo, err error) {
+	treesInfo = utils.NewDependencyTreesInfo(params.LogCommands())
+	graph, err := getConanGraph(ctx, workingDir, treesInfo)
+	if err != nil {
+		return
+	}
+	root, exists := graph[rootNodeId]
+	if !exists {
+		err = errorutils.CheckErrorf("the dependency graph of the Conan project in '%s' doesn't include the project itself", workingDir)
+		return
+	}
+	rootId := filepath.Base(workingDir)
+	if root.ref != "" && root.ref != anonymousProjectRef {
+		rootId = getConanComponentId(root.ref)
+	}
+	uniqueDepsSet := datastructures.MakeSet[string]()
+	rootNode := &xrayUtils.GraphNode{Id: rootId, Nodes: []*xrayUtils.GraphNode{}}
+	for _, dependency := range root.dependencies {
+		if dependency.dev && params.ExcludeTestDependencies() {
+			continue
+		}
+		scope := utils.ProdScope
+		if dependency.dev {
+			scope = utils.DevScope
+		}
+		if node := buildPackageTree(dependency.nodeId, rootNode, scope, graph, params, treesInfo, uniqueDepsSet); node != nil {
+			rootNode.Nodes = append(rootNode.Nodes, node)
+		}
+	}
+	dependencyTree = []*xrayUtils.GraphNode{rootNode}
+	uniqueDeps = uniqueDepsSet.ToSlice()
+	return
+}
+
+// Creates the node of the given package, and populates it with the package's dependencies. The dependencies get the scope of the direct dependency that brought them.
+// Returns nil if the package isn't in the graph.
+func buildPackageTree(nodeId string, parent *xrayUtils.GraphNode, scope string, graph conanGraph, params utils.AuditParams, treesInfo *utils.DependencyTreesInfo, uniqueDepsSet *datastructures.Set[string]) *xrayUtils.GraphNode {
+	conanPackage, exists := graph[nodeId]
+	if !exists || conanPackage.ref == "" {
+		return nil
+	}
+	node := &xrayUtils.GraphNode{Id: getConanComponentId(conanPackage.ref), Nodes: []*xrayUtils.GraphNode{}, Parent: parent}
+	uniqueDepsSet.Add(node.Id)
+	treesInfo.AddDependencyScopes(node.Id, scope)
+	if node.NodeHasLoop() {
+		return node
+	}
+	for _, dependency := range conanPackage.dependencies {
+		if dependency.dev && params.ExcludeTestDependencies() {
+			continue
+		}
+		if child := buildPackageTree(dependency.nodeId, node, scope, graph, params, treesInfo, uniqueDepsSet); child != nil {
+			node.Nodes = append(node.Nodes, child)
+		}
+	}
+	return node
+}
+
+func getConanGraph(ctx context.Context, workingDir string, treesInfo *utils.DependencyTreesInfo) (conanGraph, error) {
+	lockPath := filepath.Join(workingDir, conanLockName)
+	exists, err := fileutils.IsFileExists(lockPath, false)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		content, err := os.ReadFile(lockPath)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		return parseConanLock(lockPath, content)
+	}
+	args := []string{"graph", "info", ".", "--format=json"}
+	treesInfo.RecordExecutedCommand("conan", args...)
+	//#nosec G204
+	command := sca.CreateCommand(ctx, "conan", args...)
+	command.Dir = workingDir
+	// The graph is printed to the standard output, while the progress is printed to the standard error.
+	output, err := command.Output()
+	if err != nil {
+		var stderr []byte
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = exitErr.Stderr
+		}
+		return nil, errorutils.CheckErrorf("failed running command 'conan %s': %s - %s", strings.Join(args, " "), err.Error(), stderr)
+	}
+	return parseConanGraphInfo(output)
+}
+
+// Parses the graph of a Conan 1.x or a Conan 2.x lockfile.
+func parseConanLock(lockPath string, content []byte) (conanGraph, error) {
+	lockV1 := &conanLockV1{}
+	if err := json.Unmarshal(content, lockV1); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse '%s': %s", lockPath, err.Error())
+	}
+	if lockV1.GraphLock != nil {
+		graph := conanGraph{}
+		for id, lockNode := range lockV1.GraphLock.Nodes {
+			node := &conanNode{ref: lockNode.Ref}
+			for _, requirement := range lockNode.Requires {
+				node.dependencies = append(node.dependencies, conanDependency{nodeId: requirement})
+			}
+			for _, requirement := range lockNode.BuildRequires {
+				node.dependencies = append(node.dependencies, conanDependency{nodeId: requirement, dev: true})
+			}
+			graph[id] = node
+		}
+		return graph, nil
+	}
+	lockV2 := &conanLockV2{}
+	if err := json.Unmarshal(content, lockV2); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse '%s': %s", lockPath, err.Error())
+	}
+	// The locked packages are added to the graph as direct dependencies of the project, which has no reference.
+	root := &conanNode{}
+	graph := conanGraph{rootNodeId: root}
+	addLockedPackages := func(refs []string, dev bool) {
+		for _, ref := range refs {
+			id := fmt.Sprint(len(graph))
+			graph[id] = &conanNode{ref: ref}
+			root.dependencies = append(root.dependencies, conanDependency{nodeId: id, dev: dev})
+		}
+	}
+	addLockedPackages(lockV2.Requires, false)
+	addLockedPackages(lockV2.BuildRequires, true)
+	return graph, nil
+}
+
+// Parses the output of 'conan graph info --format=json'. The dependencies of each node include its transitive dependencies, so only the direct ones are kept.
+func parseConanGraphInfo(output []byte) (conanGraph, error) {
+	graphInfo := &conanGraphInfo{}
+	if err := json.Unmarshal(output, graphInfo); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the output of 'conan graph info': %s", err.Error())
+	}
+	graph := conanGraph{}
+	for id, infoNode := range graphInfo.Graph.Nodes {
+		node := &conanNode{ref: infoNode.Ref}
+		dependencyIds := maps.Keys(infoNode.Dependencies)
+		slices.SortFunc(dependencyIds, compareNodeIds)
+		for _, dependencyId := range dependencyIds {
+			dependency := infoNode.Dependencies[dependencyId]
+			if dependency.Direct {
+				node.dependencies = append(node.dependencies, conanDependency{nodeId: dependencyId, dev: dependency.Build || dependency.Test})
+			}
+		}
+		graph[id] = node
+	}
+	return graph, nil
+}
+
+// The IDs of the nodes are numbers, so they're compared by their length before their content.
+func compareNodeIds(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// Returns the Xray component ID of the given Conan reference, such as 'zlib/1.2.13@user/channel#revision%timestamp'.
+// The user, the channel and the revision aren't part of the component ID.
+func getConanComponentId(ref string) string {
+	ref, _, _ = strings.Cut(ref, "#")
+	ref, _, _ = strings.Cut(ref, "@")
+	name, version, _ := strings.Cut(ref, "/")
+	return conanPackageTypeIdentifier + name + ":" + version
+}
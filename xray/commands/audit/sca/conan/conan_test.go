@@ -0,0 +1,87 @@
+package conan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyTree(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "conan-project")
+	defer cleanUp()
+
+	params := &xrayutils.AuditBasicParams{}
+	dependencyTree, uniqueDeps, treesInfo, err := BuildDependencyTree(context.Background(), params, projectDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"conan://openssl:1.1.1w", "conan://zlib:1.2.13", "conan://fmt:10.1.1", "conan://cmake:3.27.7"}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		root := dependencyTree[0]
+		assert.Equal(t, []string{"conan://openssl:1.1.1w", "conan://fmt:10.1.1", "conan://cmake:3.27.7"}, getChildrenIds(root))
+		// The transitive dependencies are nested under the packages that require them.
+		assert.Equal(t, []string{"conan://zlib:1.2.13"}, getChildrenIds(root.Nodes[0]))
+	}
+	scopes := treesInfo.DependenciesScopes
+	assert.Equal(t, []string{xrayutils.ProdScope}, scopes["conan://zlib:1.2.13"])
+	assert.Equal(t, []string{xrayutils.DevScope}, scopes["conan://cmake:3.27.7"])
+}
+
+func TestBuildDependencyTreeExcludeTestDependencies(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "conan-project")
+	defer cleanUp()
+
+	params := &xrayutils.AuditBasicParams{}
+	dependencyTree, uniqueDeps, _, err := BuildDependencyTree(context.Background(), params.SetExcludeTestDependencies(true), projectDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"conan://openssl:1.1.1w", "conan://zlib:1.2.13", "conan://fmt:10.1.1"}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		assert.Equal(t, []string{"conan://openssl:1.1.1w", "conan://fmt:10.1.1"}, getChildrenIds(dependencyTree[0]))
+	}
+}
+
+func TestParseConanLockV2(t *testing.T) {
+	lock := `{"version": "0.5", "requires": ["openssl/3.1.3#a1b2c3%1694000000.0", "zlib/1.3@acme/stable#d4e5f6%1694000001.0"], "build_requires": ["cmake/3.27.7#aa11%1694000002.0"]}`
+	graph, err := parseConanLock("conan.lock", []byte(lock))
+	assert.NoError(t, err)
+	// The packages are direct dependencies of the project, in the order of the lockfile.
+	root := graph[rootNodeId]
+	if assert.NotNil(t, root) && assert.Len(t, root.dependencies, 3) {
+		assert.Equal(t, "openssl/3.1.3#a1b2c3%1694000000.0", graph[root.dependencies[0].nodeId].ref)
+		assert.False(t, root.dependencies[0].dev)
+		assert.Equal(t, "cmake/3.27.7#aa11%1694000002.0", graph[root.dependencies[2].nodeId].ref)
+		assert.True(t, root.dependencies[2].dev)
+	}
+}
+
+func TestParseConanGraphInfo(t *testing.T) {
+	output := `{"graph": {"nodes": {
+		"0": {"ref": "conanfile", "dependencies": {
+			"1": {"ref": "openssl/3.1.3", "direct": true, "build": false, "test": false},
+			"2": {"ref": "zlib/1.3", "direct": false, "build": false, "test": false},
+			"10": {"ref": "gtest/1.14.0", "direct": true, "build": false, "test": true}}},
+		"1": {"ref": "openssl/3.1.3#a1b2c3", "dependencies": {"2": {"ref": "zlib/1.3", "direct": true, "build": false, "test": false}}},
+		"2": {"ref": "zlib/1.3#d4e5f6", "dependencies": {}},
+		"10": {"ref": "gtest/1.14.0#aa11", "dependencies": {}}}}}`
+	graph, err := parseConanGraphInfo([]byte(output))
+	assert.NoError(t, err)
+	// The transitive dependencies are excluded from the dependencies of the project.
+	assert.Equal(t, []conanDependency{{nodeId: "1"}, {nodeId: "10", dev: true}}, graph[rootNodeId].dependencies)
+	assert.Equal(t, []conanDependency{{nodeId: "2"}}, graph["1"].dependencies)
+}
+
+func TestGetConanComponentId(t *testing.T) {
+	assert.Equal(t, "conan://zlib:1.2.13", getConanComponentId("zlib/1.2.13"))
+	assert.Equal(t, "conan://zlib:1.2.13", getConanComponentId("zlib/1.2.13@acme/stable"))
+	assert.Equal(t, "conan://zlib:1.2.13", getConanComponentId("zlib/1.2.13@acme/stable#d4e5f6%1694000001.0"))
+	assert.Equal(t, "conan://zlib:1.2.13", getConanComponentId("zlib/1.2.13#d4e5f6"))
+}
+
+func getChildrenIds(node *xrayUtils.GraphNode) (ids []string) {
+	for _, child := range node.Nodes {
+		ids = append(ids, child.Id)
+	}
+	return
+}
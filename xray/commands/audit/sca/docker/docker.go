@@ -0,0 +1,222 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+const (
+	dockerPackageTypeIdentifier = "docker://"
+	dockerfileName              = "dockerfile"
+	scratchImage                = "scratch"
+	defaultImageTag             = "latest"
+)
+
+// Builds the dependency trees of the Dockerfiles in the working directory.
+// Each Dockerfile is the root of a tree, whose dependencies are the base images that are referenced by its FROM instructions.
+func BuildDependencyTree(params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
+	dockerfiles, err := getDockerfiles(workingDir)
+	if err != nil {
+		return
+	}
+	uniqueDepsSet := datastructures.MakeSet[string]()
+	for _, dockerfile := range dockerfiles {
+		var images []string
+		if images, err = ExtractBaseImages(filepath.Join(workingDir, dockerfile)); err != nil {
+			return
+		}
+		rootNode := &xrayUtils.GraphNode{Id: dockerfile, Nodes: []*xrayUtils.GraphNode{}}
+		for _, image := range images {
+			imageNode := &xrayUtils.GraphNode{Id: dockerPackageTypeIdentifier + image, Parent: rootNode}
+			rootNode.Nodes = append(rootNode.Nodes, imageNode)
+			uniqueDepsSet.Add(imageNode.Id)
+		}
+		dependencyTree = append(dependencyTree, rootNode)
+	}
+	uniqueDeps = uniqueDepsSet.ToSlice()
+	return
+}
+
+// Returns the names of the Dockerfiles in the given directory, sorted.
+func getDockerfiles(dir string) (dockerfiles []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && IsDockerfile(entry.Name()) {
+			dockerfiles = append(dockerfiles, entry.Name())
+		}
+	}
+	sort.Strings(dockerfiles)
+	return
+}
+
+// Checks whether the given file is a Dockerfile, by the common naming conventions: 'Dockerfile', 'Dockerfile.<suffix>' and '<prefix>.Dockerfile'.
+func IsDockerfile(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	return name == dockerfileName || strings.HasPrefix(name, dockerfileName+".") || strings.HasSuffix(name, "."+dockerfileName)
+}
+
+// Detects the Dockerfiles in the given path, and maps the directories that contain them to their paths.
+func DetectDockerfiles(path string, recursive bool, excludePathPattern string) (map[string][]string, error) {
+	filesList, err := fspatterns.ListFiles(path, recursive, false, true, true, excludePathPattern)
+	if err != nil {
+		return nil, err
+	}
+	workingDirToDockerfiles := map[string][]string{}
+	for _, file := range filesList {
+		if IsDockerfile(file) {
+			workingDirToDockerfiles[filepath.Dir(file)] = append(workingDirToDockerfiles[filepath.Dir(file)], file)
+		}
+	}
+	return workingDirToDockerfiles, nil
+}
+
+// Returns the base images that are referenced by the FROM instructions of the given Dockerfile, in their order and without duplicates.
+// In multi-stage builds, the stages that are based on previous stages are skipped, and so is the 'scratch' image.
+// The build args in the references are expanded with the default values of the ARG instructions that precede the first FROM instruction.
+// Images without a tag get the 'latest' tag, as Docker does.
+func ExtractBaseImages(dockerfilePath string) ([]string, error) {
+	content, err := os.ReadFile(dockerfilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	buildArgs := map[string]string{}
+	stages := datastructures.MakeSet[string]()
+	imagesSet := datastructures.MakeSet[string]()
+	var images []string
+	fromFound := false
+	for _, instruction := range getInstructions(content) {
+		fields := strings.Fields(instruction)
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			// The args that are declared after the first FROM instruction are scoped to their stages, and can't be used in FROM instructions.
+			if !fromFound {
+				addBuildArgs(fields[1:], buildArgs)
+			}
+		case "FROM":
+			fromFound = true
+			image, stage := parseFromInstruction(fields[1:])
+			if stage != "" {
+				stages.Add(strings.ToLower(stage))
+			}
+			if image == "" {
+				continue
+			}
+			expandedImage, resolved := expandBuildArgs(image, buildArgs)
+			if !resolved {
+				log.Warn(fmt.Sprintf("Skipping the base image '%s' in '%s', since the build args it uses have no default values.", image, dockerfilePath))
+				continue
+			}
+			if strings.EqualFold(expandedImage, scratchImage) || stages.Exists(strings.ToLower(expandedImage)) {
+				continue
+			}
+			if !hasTag(expandedImage) {
+				expandedImage += ":" + defaultImageTag
+			}
+			if !imagesSet.Exists(expandedImage) {
+				imagesSet.Add(expandedImage)
+				images = append(images, expandedImage)
+			}
+		}
+	}
+	return images, nil
+}
+
+// Splits the content of a Dockerfile to its instructions, joining the lines that are continued with a backslash and skipping the comments.
+func getInstructions(content []byte) (instructions []string) {
+	var current strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if continued, found := strings.CutSuffix(line, "\\"); found {
+			current.WriteString(continued + " ")
+			continue
+		}
+		current.WriteString(line)
+		instructions = append(instructions, current.String())
+		current.Reset()
+	}
+	if current.Len() > 0 {
+		instructions = append(instructions, current.String())
+	}
+	return
+}
+
+// Adds the default values of the given ARG declarations ('NAME=value') to the build args. Declarations without a default value are skipped.
+func addBuildArgs(declarations []string, buildArgs map[string]string) {
+	for _, declaration := range declarations {
+		if name, value, found := strings.Cut(declaration, "="); found {
+			buildArgs[name] = strings.Trim(value, `"'`)
+		}
+	}
+}
+
+// Returns the image and the stage name of a FROM instruction: 'FROM [--platform=<platform>] <image> [AS <name>]'.
+func parseFromInstruction(args []string) (image, stage string) {
+	var positionalArgs []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			positionalArgs = append(positionalArgs, arg)
+		}
+	}
+	if len(positionalArgs) == 0 {
+		return
+	}
+	image = positionalArgs[0]
+	if len(positionalArgs) >= 3 && strings.EqualFold(positionalArgs[1], "AS") {
+		stage = positionalArgs[2]
+	}
+	return
+}
+
+// Expands the build args in the given value: '$NAME', '${NAME}', '${NAME:-default}' and '${NAME:+alternative}'.
+// Returns false if the value uses a build arg that has no value.
+func expandBuildArgs(value string, buildArgs map[string]string) (string, bool) {
+	resolved := true
+	expanded := os.Expand(value, func(expression string) string {
+		if name, defaultValue, found := strings.Cut(expression, ":-"); found {
+			if argValue := buildArgs[name]; argValue != "" {
+				return argValue
+			}
+			return defaultValue
+		}
+		if name, alternativeValue, found := strings.Cut(expression, ":+"); found {
+			if buildArgs[name] != "" {
+				return alternativeValue
+			}
+			return ""
+		}
+		argValue, exists := buildArgs[expression]
+		if !exists {
+			resolved = false
+		}
+		return argValue
+	})
+	return expanded, resolved
+}
+
+// Checks whether the image reference has a tag or a digest. A colon that is followed by a slash belongs to the registry's port.
+func hasTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+	lastColon := strings.LastIndex(image, ":")
+	return lastColon > strings.LastIndex(image, "/")
+}
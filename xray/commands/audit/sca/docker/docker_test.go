@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyTree(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "dockerfile-project")
+	defer cleanUp()
+
+	dependencyTree, uniqueDeps, err := BuildDependencyTree(&xrayutils.AuditBasicParams{}, projectDir)
+	assert.NoError(t, err)
+	expectedImages := []string{"docker://node:18.19.0-alpine3.19", "docker://docker.io/library/nginx:latest"}
+	assert.ElementsMatch(t, expectedImages, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		rootNode := dependencyTree[0]
+		assert.Equal(t, "Dockerfile", rootNode.Id)
+		if assert.Len(t, rootNode.Nodes, 2) {
+			for i, image := range expectedImages {
+				assert.Equal(t, image, rootNode.Nodes[i].Id)
+			}
+		}
+	}
+}
+
+func TestExtractBaseImages(t *testing.T) {
+	images, err := ExtractBaseImages(filepath.Join("..", "..", "..", "testdata", "dockerfile-project", "deploy", "Dockerfile.prod"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"gcr.io/distroless/nodejs18-debian12@sha256:9a1a5b8e4e2f0c2a6a1f3b8e7a4d5c6b7a8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a",
+		"node:18.19.0-alpine3.19",
+	}, images)
+}
+
+func TestDetectDockerfiles(t *testing.T) {
+	projectDir := filepath.Join("..", "..", "..", "testdata", "dockerfile-project")
+	workingDirToDockerfiles, err := DetectDockerfiles(projectDir, true, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		projectDir:                          {filepath.Join(projectDir, "Dockerfile")},
+		filepath.Join(projectDir, "deploy"): {filepath.Join(projectDir, "deploy", "Dockerfile.prod")},
+	}, workingDirToDockerfiles)
+}
+
+func TestExpandBuildArgs(t *testing.T) {
+	buildArgs := map[string]string{"VERSION": "1.0.0", "EMPTY": ""}
+	tests := []struct {
+		value            string
+		expectedValue    string
+		expectedResolved bool
+	}{
+		{value: "image:$VERSION", expectedValue: "image:1.0.0", expectedResolved: true},
+		{value: "image:${VERSION}-alpine", expectedValue: "image:1.0.0-alpine", expectedResolved: true},
+		{value: "image:${EMPTY:-2.0.0}", expectedValue: "image:2.0.0", expectedResolved: true},
+		{value: "image${VERSION:+:pinned}", expectedValue: "image:pinned", expectedResolved: true},
+		{value: "image:${MISSING}", expectedValue: "image:", expectedResolved: false},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			value, resolved := expandBuildArgs(test.value, buildArgs)
+			assert.Equal(t, test.expectedValue, value)
+			assert.Equal(t, test.expectedResolved, resolved)
+		})
+	}
+}
+
+func TestIsDockerfile(t *testing.T) {
+	assert.True(t, IsDockerfile(filepath.Join("project", "Dockerfile")))
+	assert.True(t, IsDockerfile("Dockerfile.prod"))
+	assert.True(t, IsDockerfile("worker.dockerfile"))
+	assert.False(t, IsDockerfile("Dockerfiles"))
+	assert.False(t, IsDockerfile("docker-compose.yaml"))
+}
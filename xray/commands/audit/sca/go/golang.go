@@ -1,27 +1,50 @@
 package _go
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	biutils "github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
-	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	goutils "github.com/jfrog/jfrog-cli-core/v2/utils/golang"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 const (
 	goPackageTypeIdentifier = "go://"
 	goSourceCodePrefix      = "github.com/golang/go:v"
+	// Marks the version of modules that are replaced by a local directory.
+	goLocalReplacementVersion = "local"
+	// The format of the modules that 'go list' prints.
+	goListModulesFormat = "{{with .Module}}{{.Path}}:{{.Version}}{{end}}"
+	// The latest Go version whose 'go list' modifies go.mod automatically, without the '-mod=mod' flag.
+	goMaxVersionAutomaticallyModifyMod = "go1.15"
 )
 
-func BuildDependencyTree(params utils.AuditParams) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
-	currentDir, err := coreutils.GetWorkingDirectory()
-	if err != nil {
-		return
+// Returns the dependency tree with the git-sourced modules that were found in it.
+// The Go commands are run by build-info-go, so they can't be killed once the given context is done, but the build stops before running the next one.
+func BuildDependencyTree(ctx context.Context, params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *utils.DependencyTreesInfo, err error) {
+	treesInfo = utils.NewDependencyTreesInfo(params.LogCommands())
+	defer func() {
+		if err == nil {
+			treesInfo.Add(getGitSourcedModules(uniqueDeps))
+		}
+	}()
+	if params.ScanVendored() {
+		if dependencyTree, uniqueDeps, err = buildVendoredDependencyTree(workingDir); err != nil || len(dependencyTree) > 0 {
+			return
+		}
+		log.Info("No vendored modules were found. Scanning the modules that go.mod requires...")
 	}
 
 	server, err := params.ServerDetails()
@@ -31,23 +54,55 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTree []*xrayUtils.
 	}
 
 	remoteGoRepo := params.DepsRepo()
-	if remoteGoRepo != "" {
+	if params.GoProxy() != "" {
+		var restoreGoProxy func() error
+		if restoreGoProxy, err = setGoProxyForAudit(server, remoteGoRepo, params.GoProxy()); err != nil {
+			return
+		}
+		defer func() {
+			err = errors.Join(err, restoreGoProxy())
+		}()
+	} else if remoteGoRepo != "" {
 		if err = setGoProxy(server, remoteGoRepo); err != nil {
 			return
 		}
 	}
+	// The Go commands are executed by build-info-go, so the commands it executes are recorded here
+	var goListArgs []string
+	if params.LogCommands() {
+		if goListArgs, err = getGoListArgs(); err != nil {
+			return
+		}
+	}
 	// Calculate go dependencies graph
-	dependenciesGraph, err := goutils.GetDependenciesGraph(currentDir)
+	if err = sca.CheckContext(ctx); err != nil {
+		return
+	}
+	treesInfo.RecordExecutedCommand("go", "mod", "graph")
+	dependenciesGraph, err := goutils.GetDependenciesGraph(workingDir)
 	if err != nil || len(dependenciesGraph) == 0 {
 		return
 	}
 	// Calculate go dependencies list
-	dependenciesList, err := goutils.GetDependenciesList(currentDir)
+	if err = sca.CheckContext(ctx); err != nil {
+		return
+	}
+	treesInfo.RecordExecutedCommand("go", append(goListArgs, "-f", goListModulesFormat, "all")...)
+	dependenciesList, err := goutils.GetDependenciesList(workingDir)
 	if err != nil {
 		return
 	}
 	// Get root module name
-	rootModuleName, err := goutils.GetModuleName(currentDir)
+	if err = sca.CheckContext(ctx); err != nil {
+		return
+	}
+	treesInfo.RecordExecutedCommand("go", append(goListArgs, "-m")...)
+	rootModuleName, err := goutils.GetModuleName(workingDir)
+	if err != nil {
+		return
+	}
+	// Get the replace directives, so the tree reflects the modules that are actually used
+	replaces, err := getReplaceDirectives(filepath.Join(workingDir, "go.mod"))
 	if err != nil {
 		return
 	}
@@ -57,7 +112,7 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTree []*xrayUtils.
 		Nodes: []*xrayUtils.GraphNode{},
 	}
 	uniqueDepsSet := datastructures.MakeSet[string]()
-	populateGoDependencyTree(rootNode, dependenciesGraph, dependenciesList, uniqueDepsSet)
+	populateGoDependencyTree(rootNode, rootModuleName, dependenciesGraph, dependenciesList, replaces, uniqueDepsSet)
 
 	goVersionDependency, err := getGoVersionAsDependency()
 	if err != nil {
@@ -80,12 +135,51 @@ func setGoProxy(server *config.ServerDetails, remoteGoRepo string) error {
 	return os.Setenv("GOPROXY", repoUrl)
 }
 
-func populateGoDependencyTree(currNode *xrayUtils.GraphNode, dependenciesGraph map[string][]string, dependenciesList map[string]bool, uniqueDepsSet *datastructures.Set[string]) {
+// Sets 'GOPROXY' to the given Go module proxy until it is restored, so the proxy is used by the Go commands of the audit only.
+// If there is a resolution repository, the modules are resolved from it first, and from the proxy only if they are not found in it.
+func setGoProxyForAudit(server *config.ServerDetails, remoteGoRepo, goProxy string) (restoreGoProxy func() error, err error) {
+	restoreGoProxy = func() error {
+		return nil
+	}
+	goProxyValue, err := getAuditGoProxyValue(server, remoteGoRepo, goProxy)
+	if err != nil {
+		return
+	}
+	origGoProxyValue, hadGoProxy := os.LookupEnv("GOPROXY")
+	if err = errorutils.CheckError(os.Setenv("GOPROXY", goProxyValue)); err != nil {
+		return
+	}
+	restoreGoProxy = func() error {
+		if hadGoProxy {
+			return errorutils.CheckError(os.Setenv("GOPROXY", origGoProxyValue))
+		}
+		return errorutils.CheckError(os.Unsetenv("GOPROXY"))
+	}
+	return
+}
+
+func getAuditGoProxyValue(server *config.ServerDetails, remoteGoRepo, goProxy string) (string, error) {
+	if remoteGoRepo == "" {
+		return goProxy, nil
+	}
+	repoUrl, err := goutils.GetArtifactoryRemoteRepoUrl(server, remoteGoRepo)
+	if err != nil {
+		return "", err
+	}
+	return repoUrl + "|" + goProxy, nil
+}
+
+// Populate the dependency tree of the given node.
+// The dependencies graph and list refer to the modules by their required name (path:version), and the tree nodes are created with the names of the modules that replace them.
+// Modules that are replaced by a local directory are added to the tree, but not to the unique dependencies, since they are not public modules that Xray knows.
+func populateGoDependencyTree(currNode *xrayUtils.GraphNode, moduleName string, dependenciesGraph map[string][]string, dependenciesList map[string]bool, replaces []*modfile.Replace, uniqueDepsSet *datastructures.Set[string]) {
 	if currNode.NodeHasLoop() {
 		return
 	}
-	uniqueDepsSet.Add(currNode.Id)
-	currDepChildren := dependenciesGraph[strings.TrimPrefix(currNode.Id, goPackageTypeIdentifier)]
+	if !strings.HasSuffix(currNode.Id, ":"+goLocalReplacementVersion) {
+		uniqueDepsSet.Add(currNode.Id)
+	}
+	currDepChildren := dependenciesGraph[moduleName]
 	// Recursively create & append all node's dependencies.
 	for _, childName := range currDepChildren {
 		if !dependenciesList[childName] {
@@ -93,13 +187,51 @@ func populateGoDependencyTree(currNode *xrayUtils.GraphNode, dependenciesGraph m
 			continue
 		}
 		childNode := &xrayUtils.GraphNode{
-			Id:     goPackageTypeIdentifier + childName,
+			Id:     goPackageTypeIdentifier + resolveReplacement(childName, replaces),
 			Nodes:  []*xrayUtils.GraphNode{},
 			Parent: currNode,
 		}
 		currNode.Nodes = append(currNode.Nodes, childNode)
-		populateGoDependencyTree(childNode, dependenciesGraph, dependenciesList, uniqueDepsSet)
+		populateGoDependencyTree(childNode, childName, dependenciesGraph, dependenciesList, replaces, uniqueDepsSet)
+	}
+}
+
+// Read the replace directives from the given go.mod file.
+func getReplaceDirectives(goModPath string) ([]*modfile.Replace, error) {
+	goModFile, err := parseGoModFile(goModPath)
+	if err != nil {
+		return nil, err
 	}
+	return goModFile.Replace, nil
+}
+
+// Returns the name (path:version) of the module that is actually used instead of the given module, according to the replace directives.
+// Modules that are replaced by a local directory get the 'local' version.
+func resolveReplacement(moduleName string, replaces []*modfile.Replace) string {
+	path, version, _ := strings.Cut(moduleName, ":")
+	for _, replace := range replaces {
+		if replace.Old.Path != path || (replace.Old.Version != "" && replace.Old.Version != version) {
+			continue
+		}
+		if modfile.IsDirectoryPath(replace.New.Path) {
+			log.Debug(fmt.Sprintf("The module '%s' is replaced by the local directory '%s'", moduleName, replace.New.Path))
+			return path + ":" + goLocalReplacementVersion
+		}
+		return replace.New.Path + ":" + replace.New.Version
+	}
+	return moduleName
+}
+
+// Returns the arguments of the 'go list' commands that build-info-go executes, which depend on the Go version.
+func getGoListArgs() ([]string, error) {
+	goVersion, err := biutils.GetParsedGoVersion()
+	if err != nil {
+		return nil, err
+	}
+	if !goVersion.AtLeast(goMaxVersionAutomaticallyModifyMod) {
+		return []string{"list"}, nil
+	}
+	return []string{"list", "-mod=mod"}, nil
 }
 
 func getGoVersionAsDependency() (*xrayUtils.GraphNode, error) {
@@ -113,3 +245,19 @@ func getGoVersionAsDependency() (*xrayUtils.GraphNode, error) {
 		Id: goPackageTypeIdentifier + goVersionID,
 	}, nil
 }
+
+// Flags the modules whose versions are pseudo-versions, such as 'v0.0.0-20231010120000-0123456789ab'.
+// Pseudo-versions refer to untagged commits of the modules' git repositories, so the modules are flagged with their paths and commits.
+func getGitSourcedModules(uniqueDeps []string) (treesInfo *utils.DependencyTreesInfo) {
+	treesInfo = &utils.DependencyTreesInfo{}
+	for _, dependency := range uniqueDeps {
+		path, version, found := strings.Cut(strings.TrimPrefix(dependency, goPackageTypeIdentifier), ":")
+		if !found || !module.IsPseudoVersion(version) {
+			continue
+		}
+		if revision, err := module.PseudoVersionRev(version); err == nil {
+			treesInfo.AddGitSourcedDependency(dependency, path+"@"+revision)
+		}
+	}
+	return
+}
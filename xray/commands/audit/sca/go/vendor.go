@@ -0,0 +1,178 @@
+package _go
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	vendorModulesFile = "modules.txt"
+	// Marks the modules in vendor/modules.txt that are required by go.mod.
+	vendorExplicitMarker = "## explicit"
+)
+
+// A module that is vendored in the vendor directory.
+type vendoredModule struct {
+	path    string
+	version string
+	// The name (path:version) of the module that replaces it, if it's replaced.
+	replacement string
+	// Whether the module is required by go.mod.
+	explicit bool
+}
+
+// A difference between the modules that go.mod requires and the modules that are vendored.
+type vendorDiscrepancy struct {
+	Module string
+	// The required version, or empty if the module isn't required by go.mod.
+	GoModVersion string
+	// The vendored version, or empty if the module isn't vendored.
+	VendoredVersion string
+}
+
+func (vd vendorDiscrepancy) String() string {
+	switch {
+	case vd.VendoredVersion == "":
+		return fmt.Sprintf("'%s' is required in version %s by go.mod, but isn't vendored", vd.Module, vd.GoModVersion)
+	case vd.GoModVersion == "":
+		return fmt.Sprintf("'%s' is vendored in version %s, but isn't required by go.mod", vd.Module, vd.VendoredVersion)
+	default:
+		return fmt.Sprintf("'%s' is required in version %s by go.mod, but version %s is vendored", vd.Module, vd.GoModVersion, vd.VendoredVersion)
+	}
+}
+
+// Builds the dependency tree of the Go project in the given directory from the modules that are vendored in its vendor directory.
+// The vendored modules are compared with the modules that go.mod requires, and the discrepancies, which indicate a stale vendor directory, are logged.
+// Returns an empty tree if the project has no vendored modules.
+func buildVendoredDependencyTree(projectDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
+	modulesTxtPath := filepath.Join(projectDir, "vendor", vendorModulesFile)
+	exists, err := fileutils.IsFileExists(modulesTxtPath, false)
+	if err != nil || !exists {
+		return
+	}
+	goModFile, err := parseGoModFile(filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return
+	}
+	vendoredModules, err := readVendoredModules(modulesTxtPath)
+	if err != nil {
+		return
+	}
+	if discrepancies := getVendorDiscrepancies(goModFile, vendoredModules); len(discrepancies) > 0 {
+		var messages []string
+		for _, discrepancy := range discrepancies {
+			messages = append(messages, discrepancy.String())
+		}
+		log.Warn(fmt.Sprintf("The vendor directory of '%s' doesn't match its go.mod file:\n%s", goModFile.Module.Mod.Path, strings.Join(messages, "\n")))
+	}
+	// The vendored modules don't record the dependencies between them, so all of them are added as direct dependencies.
+	rootNode := &xrayUtils.GraphNode{Id: goPackageTypeIdentifier + goModFile.Module.Mod.Path, Nodes: []*xrayUtils.GraphNode{}}
+	uniqueDepsSet := datastructures.MakeSet[string]()
+	uniqueDepsSet.Add(rootNode.Id)
+	for _, module := range vendoredModules {
+		moduleName := module.path + ":" + module.version
+		if module.replacement != "" {
+			moduleName = module.replacement
+		}
+		node := &xrayUtils.GraphNode{Id: goPackageTypeIdentifier + moduleName, Parent: rootNode}
+		rootNode.Nodes = append(rootNode.Nodes, node)
+		if !strings.HasSuffix(moduleName, ":"+goLocalReplacementVersion) {
+			uniqueDepsSet.Add(node.Id)
+		}
+	}
+	goVersionDependency, err := getGoVersionAsDependency()
+	if err != nil {
+		return
+	}
+	rootNode.Nodes = append(rootNode.Nodes, goVersionDependency)
+	uniqueDepsSet.Add(goVersionDependency.Id)
+	dependencyTree = []*xrayUtils.GraphNode{rootNode}
+	uniqueDeps = uniqueDepsSet.ToSlice()
+	return
+}
+
+func parseGoModFile(goModPath string) (*modfile.File, error) {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	goModFile, err := modfile.Parse(goModPath, content, nil)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse '%s': %s", goModPath, err.Error())
+	}
+	return goModFile, nil
+}
+
+// Reads the vendored modules from the vendor/modules.txt file.
+// Each module is listed in a '# path version' line, optionally followed by ' => replacementPath [replacementVersion]'.
+// Lines that record a replacement of all the versions of a module ('# path => replacement') don't list a vendored module, and are skipped.
+func readVendoredModules(modulesTxtPath string) (modules []*vendoredModule, err error) {
+	content, err := os.ReadFile(modulesTxtPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var current *vendoredModule
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, vendorExplicitMarker):
+			if current != nil {
+				current.explicit = true
+			}
+		case strings.HasPrefix(line, "# "):
+			current = nil
+			module, replacement, _ := strings.Cut(strings.TrimPrefix(line, "# "), "=>")
+			fields := strings.Fields(module)
+			if len(fields) != 2 {
+				continue
+			}
+			current = &vendoredModule{path: fields[0], version: fields[1]}
+			if replacementFields := strings.Fields(replacement); len(replacementFields) > 0 {
+				if modfile.IsDirectoryPath(replacementFields[0]) {
+					current.replacement = current.path + ":" + goLocalReplacementVersion
+				} else if len(replacementFields) == 2 {
+					current.replacement = replacementFields[0] + ":" + replacementFields[1]
+				}
+			}
+			modules = append(modules, current)
+		}
+	}
+	return modules, errorutils.CheckError(scanner.Err())
+}
+
+// Returns the modules whose required version differs from the vendored version, or that are required but not vendored.
+// Vendored modules that are marked as required by go.mod but aren't required by it are returned as well.
+func getVendorDiscrepancies(goModFile *modfile.File, vendoredModules []*vendoredModule) (discrepancies []vendorDiscrepancy) {
+	vendoredVersions := map[string]*vendoredModule{}
+	for _, module := range vendoredModules {
+		vendoredVersions[module.path] = module
+	}
+	requiredVersions := map[string]string{}
+	for _, require := range goModFile.Require {
+		requiredVersions[require.Mod.Path] = require.Mod.Version
+		vendored, exists := vendoredVersions[require.Mod.Path]
+		if !exists {
+			discrepancies = append(discrepancies, vendorDiscrepancy{Module: require.Mod.Path, GoModVersion: require.Mod.Version})
+		} else if vendored.version != require.Mod.Version {
+			discrepancies = append(discrepancies, vendorDiscrepancy{Module: require.Mod.Path, GoModVersion: require.Mod.Version, VendoredVersion: vendored.version})
+		}
+	}
+	for _, module := range vendoredModules {
+		if _, exists := requiredVersions[module.path]; module.explicit && !exists {
+			discrepancies = append(discrepancies, vendorDiscrepancy{Module: module.path, VendoredVersion: module.version})
+		}
+	}
+	return
+}
@@ -0,0 +1,222 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	helmPackageTypeIdentifier   = "helm://"
+	dockerPackageTypeIdentifier = "docker://"
+	chartFileName               = "Chart.yaml"
+	chartLockFileName           = "Chart.lock"
+	valuesFileName              = "values.yaml"
+	subChartsDirName            = "charts"
+	defaultImageTag             = "latest"
+)
+
+type chartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+}
+
+// The fields of Chart.yaml that are relevant for building the dependency tree.
+type chartFile struct {
+	Name         string            `yaml:"name"`
+	Version      string            `yaml:"version"`
+	AppVersion   string            `yaml:"appVersion"`
+	Dependencies []chartDependency `yaml:"dependencies"`
+}
+
+// The fields of Chart.lock that are relevant for building the dependency tree.
+type chartLockFile struct {
+	Dependencies []chartDependency `yaml:"dependencies"`
+}
+
+// Builds the dependency tree of the Helm chart in the working directory from its Chart.lock file.
+// Sub-charts that are unpacked in the 'charts' directory are walked recursively.
+// If requested, the container images referenced by the chart's values are returned as additional trees, so they are scanned separately.
+func BuildDependencyTree(params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
+	uniqueDepsSet := datastructures.MakeSet[string]()
+	rootNode, chart, err := buildChartTree(workingDir, nil, uniqueDepsSet)
+	if err != nil {
+		return
+	}
+	dependencyTree = []*xrayUtils.GraphNode{rootNode}
+	if params.ScanHelmImages() {
+		var images []string
+		if images, err = ExtractImageReferences(filepath.Join(workingDir, valuesFileName), chart.AppVersion); err != nil {
+			return
+		}
+		for _, image := range images {
+			imageNode := &xrayUtils.GraphNode{Id: dockerPackageTypeIdentifier + image}
+			dependencyTree = append(dependencyTree, imageNode)
+			uniqueDepsSet.Add(imageNode.Id)
+		}
+	}
+	uniqueDeps = uniqueDepsSet.ToSlice()
+	return
+}
+
+// Creates the node of the chart in the given directory, and populates it with the chart's locked dependencies.
+func buildChartTree(chartDir string, parent *xrayUtils.GraphNode, uniqueDepsSet *datastructures.Set[string]) (node *xrayUtils.GraphNode, chart *chartFile, err error) {
+	chart = &chartFile{}
+	if err = readYamlFile(filepath.Join(chartDir, chartFileName), chart); err != nil {
+		return
+	}
+	node = &xrayUtils.GraphNode{
+		Id:     helmPackageTypeIdentifier + chart.Name + ":" + chart.Version,
+		Nodes:  []*xrayUtils.GraphNode{},
+		Parent: parent,
+	}
+	if node.NodeHasLoop() {
+		return
+	}
+	dependencies, err := getLockedDependencies(chartDir, chart)
+	if err != nil {
+		return
+	}
+	for _, dependency := range dependencies {
+		childId := helmPackageTypeIdentifier + dependency.Name + ":" + dependency.Version
+		uniqueDepsSet.Add(childId)
+		subChartDir := filepath.Join(chartDir, subChartsDirName, dependency.Name)
+		var subChartExists bool
+		if subChartExists, err = fileutils.IsFileExists(filepath.Join(subChartDir, chartFileName), false); err != nil {
+			return
+		}
+		if !subChartExists {
+			node.Nodes = append(node.Nodes, &xrayUtils.GraphNode{Id: childId, Parent: node})
+			continue
+		}
+		var childNode *xrayUtils.GraphNode
+		if childNode, _, err = buildChartTree(subChartDir, node, uniqueDepsSet); err != nil {
+			return
+		}
+		// The locked version is the one that is used, even if the unpacked sub-chart states otherwise.
+		childNode.Id = childId
+		node.Nodes = append(node.Nodes, childNode)
+	}
+	return
+}
+
+// Returns the dependencies of the chart, as they are locked in its Chart.lock file.
+// A chart without dependencies doesn't have to be locked.
+func getLockedDependencies(chartDir string, chart *chartFile) ([]chartDependency, error) {
+	lockPath := filepath.Join(chartDir, chartLockFileName)
+	exists, err := fileutils.IsFileExists(lockPath, false)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if len(chart.Dependencies) > 0 {
+			return nil, errorutils.CheckErrorf("the chart '%s' has dependencies, but %s was not found in '%s'. Run 'helm dependency update' and try again", chart.Name, chartLockFileName, chartDir)
+		}
+		return nil, nil
+	}
+	lock := &chartLockFile{}
+	if err = readYamlFile(lockPath, lock); err != nil {
+		return nil, err
+	}
+	return lock.Dependencies, nil
+}
+
+// Returns the container images referenced by the given values file, sorted and without duplicates.
+// Images are referenced either by a string ('image: nginx:1.25') or by a map with 'registry', 'repository' and 'tag' keys.
+// Images without a tag get the chart's appVersion, as Helm charts commonly do, or 'latest' if it is empty.
+func ExtractImageReferences(valuesPath, appVersion string) ([]string, error) {
+	exists, err := fileutils.IsFileExists(valuesPath, false)
+	if err != nil || !exists {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err = readYamlFile(valuesPath, &values); err != nil {
+		return nil, err
+	}
+	defaultTag := appVersion
+	if defaultTag == "" {
+		defaultTag = defaultImageTag
+	}
+	imagesSet := datastructures.MakeSet[string]()
+	collectImageReferences(values, defaultTag, imagesSet)
+	images := imagesSet.ToSlice()
+	sort.Strings(images)
+	return images, nil
+}
+
+func collectImageReferences(value interface{}, defaultTag string, imagesSet *datastructures.Set[string]) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			if key == "image" {
+				if image := toImageReference(child, defaultTag); image != "" {
+					imagesSet.Add(image)
+					continue
+				}
+			}
+			collectImageReferences(child, defaultTag, imagesSet)
+		}
+	case []interface{}:
+		for _, child := range typedValue {
+			collectImageReferences(child, defaultTag, imagesSet)
+		}
+	}
+}
+
+// Converts the value of an 'image' key to an image reference, or returns an empty string if it doesn't describe an image.
+func toImageReference(value interface{}, defaultTag string) string {
+	switch typedValue := value.(type) {
+	case string:
+		if typedValue == "" {
+			return ""
+		}
+		if !hasTag(typedValue) {
+			return typedValue + ":" + defaultTag
+		}
+		return typedValue
+	case map[string]interface{}:
+		repository, _ := typedValue["repository"].(string)
+		if repository == "" {
+			return ""
+		}
+		if registry, _ := typedValue["registry"].(string); registry != "" {
+			repository = strings.TrimSuffix(registry, "/") + "/" + repository
+		}
+		tag := fmt.Sprint(typedValue["tag"])
+		if typedValue["tag"] == nil || tag == "" {
+			tag = defaultTag
+		}
+		return repository + ":" + tag
+	}
+	return ""
+}
+
+// Checks whether the image reference has a tag or a digest. A colon that is followed by a slash belongs to the registry's port.
+func hasTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+	lastColon := strings.LastIndex(image, ":")
+	return lastColon > strings.LastIndex(image, "/")
+}
+
+func readYamlFile(path string, target interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = yaml.Unmarshal(content, target); err != nil {
+		return errorutils.CheckErrorf("failed to parse '%s': %s", path, err.Error())
+	}
+	return nil
+}
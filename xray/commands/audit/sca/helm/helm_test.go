@@ -0,0 +1,71 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyTree(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "helm-project")
+	defer cleanUp()
+
+	dependencyTree, uniqueDeps, err := BuildDependencyTree(&xrayutils.AuditBasicParams{}, projectDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"helm://postgresql:12.1.2", "helm://redis:17.3.7", "helm://common:2.1.2"}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		rootNode := dependencyTree[0]
+		assert.Equal(t, "helm://my-app:0.1.0", rootNode.Id)
+		if assert.Len(t, rootNode.Nodes, 2) {
+			assert.Equal(t, "helm://postgresql:12.1.2", rootNode.Nodes[0].Id)
+			assert.Empty(t, rootNode.Nodes[0].Nodes)
+			redisNode := rootNode.Nodes[1]
+			assert.Equal(t, "helm://redis:17.3.7", redisNode.Id)
+			if assert.Len(t, redisNode.Nodes, 1) {
+				assert.Equal(t, "helm://common:2.1.2", redisNode.Nodes[0].Id)
+			}
+		}
+	}
+
+	// Scan the images referenced by the chart's values as well
+	dependencyTree, uniqueDeps, err = BuildDependencyTree((&xrayutils.AuditBasicParams{}).SetScanHelmImages(true), projectDir)
+	assert.NoError(t, err)
+	expectedImages := []string{"docker://docker.io/bitnami/nginx-exporter:0.11.0", "docker://envoyproxy/envoy:v1.28.0", "docker://nginx:1.16.0"}
+	assert.Len(t, dependencyTree, 4)
+	for i, image := range expectedImages {
+		assert.Equal(t, image, dependencyTree[i+1].Id)
+	}
+	assert.Subset(t, uniqueDeps, expectedImages)
+}
+
+func TestBuildDependencyTreeWithoutLock(t *testing.T) {
+	tempDirPath, cleanUp := sca.CreateTestWorkspace(t, "helm-project")
+	defer cleanUp()
+
+	assert.NoError(t, os.Remove(filepath.Join(tempDirPath, chartLockFileName)))
+	_, _, err := BuildDependencyTree(&xrayutils.AuditBasicParams{}, tempDirPath)
+	assert.ErrorContains(t, err, "Chart.lock was not found")
+}
+
+func TestToImageReference(t *testing.T) {
+	testCases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{value: "nginx", expected: "nginx:1.0.0"},
+		{value: "nginx:1.25", expected: "nginx:1.25"},
+		{value: "localhost:5000/nginx", expected: "localhost:5000/nginx:1.0.0"},
+		{value: "nginx@sha256:0123", expected: "nginx@sha256:0123"},
+		{value: map[string]interface{}{"repository": "nginx", "tag": 1.25}, expected: "nginx:1.25"},
+		{value: map[string]interface{}{"registry": "docker.io/", "repository": "nginx"}, expected: "docker.io/nginx:1.0.0"},
+		{value: map[string]interface{}{"pullPolicy": "Always"}, expected: ""},
+		{value: "", expected: ""},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, toImageReference(testCase.value, "1.0.0"))
+	}
+}
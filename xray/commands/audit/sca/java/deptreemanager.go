@@ -1,15 +1,20 @@
 package java
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/slices"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -17,17 +22,37 @@ const (
 	GavPackageTypeIdentifier = "gav://"
 )
 
-func BuildDependencyTree(params xrayutils.AuditParams, tech coreutils.Technology) ([]*xrayUtils.GraphNode, []string, error) {
+// Returns the dependency trees with the commands and the scopes that were recorded while building them.
+func BuildDependencyTree(ctx context.Context, params xrayutils.AuditParams, tech coreutils.Technology, workingDir string) ([]*xrayUtils.GraphNode, []string, *xrayutils.DependencyTreesInfo, error) {
+	treesInfo := xrayutils.NewDependencyTreesInfo(params.LogCommands())
+	dependencyTrees, uniqueDeps, err := buildDependencyTree(ctx, params, tech, workingDir, treesInfo)
+	if err != nil || len(params.ScanExclusions()) == 0 {
+		return dependencyTrees, uniqueDeps, treesInfo, err
+	}
+	dependencyTrees, uniqueDeps, err = removeExcludedModules(dependencyTrees, uniqueDeps, tech, workingDir, params.ScanExclusions())
+	return dependencyTrees, uniqueDeps, treesInfo, err
+}
+
+func buildDependencyTree(ctx context.Context, params xrayutils.AuditParams, tech coreutils.Technology, workingDir string, treesInfo *xrayutils.DependencyTreesInfo) ([]*xrayUtils.GraphNode, []string, error) {
 	serverDetails, err := params.ServerDetails()
 	if err != nil {
 		return nil, nil, err
 	}
 	depTreeParams := &DepTreeParams{
-		UseWrapper: params.UseWrapper(),
-		Server:     serverDetails,
-		DepsRepo:   params.DepsRepo(),
+		UseWrapper:                  params.UseWrapper(),
+		Server:                      serverDetails,
+		DepsRepo:                    params.DepsRepo(),
+		RecordCommand:               treesInfo.RecordExecutedCommand,
+		AddDependencyScopes:         treesInfo.AddDependencyScopes,
+		UseGradleDaemon:             params.UseGradleDaemon(),
+		UseGradleConfigurationCache: params.UseGradleConfigurationCache(),
+		WorkingDir:                  workingDir,
+		Context:                     ctx,
 	}
 	if tech == coreutils.Maven {
+		if params.MavenOffline() {
+			return buildMavenDependencyTreeOffline(depTreeParams)
+		}
 		return buildMavenDependencyTree(depTreeParams, params.IsMavenDepTreeInstalled())
 	}
 	return buildGradleDependencyTree(depTreeParams)
@@ -37,16 +62,43 @@ type DepTreeParams struct {
 	UseWrapper bool
 	Server     *config.ServerDetails
 	DepsRepo   string
+	// Optional, called with each of the Maven/Gradle commands before it is executed.
+	RecordCommand func(executable string, args ...string)
+	// Optional, called with the scopes (Maven) or configurations (Gradle) in which each of the dependencies is used.
+	AddDependencyScopes func(dependencyId string, scopes ...string)
+	// Whether to let Gradle use its daemon and its configuration cache. Both are disabled by default, to avoid resolving the dependencies from a stale state.
+	UseGradleDaemon             bool
+	UseGradleConfigurationCache bool
+	// The directory of the project, in which Maven/Gradle run. Defaults to the current working directory.
+	WorkingDir string
+	// Optional, the Maven/Gradle commands are killed once it is done.
+	Context context.Context
 }
 
 type DepTreeManager struct {
-	server     *config.ServerDetails
-	depsRepo   string
-	useWrapper bool
+	server        *config.ServerDetails
+	depsRepo      string
+	useWrapper    bool
+	recordCommand func(executable string, args ...string)
+	addScopes     func(dependencyId string, scopes ...string)
+	workingDir    string
+	ctx           context.Context
 }
 
 func NewDepTreeManager(params *DepTreeParams) DepTreeManager {
-	return DepTreeManager{useWrapper: params.UseWrapper, depsRepo: params.DepsRepo, server: params.Server}
+	return DepTreeManager{useWrapper: params.UseWrapper, depsRepo: params.DepsRepo, server: params.Server, recordCommand: params.RecordCommand, addScopes: params.AddDependencyScopes, workingDir: params.WorkingDir, ctx: params.Context}
+}
+
+func (dtm *DepTreeManager) recordExecutedCommand(executable string, args ...string) {
+	if dtm.recordCommand != nil {
+		dtm.recordCommand(executable, args...)
+	}
+}
+
+func (dtm *DepTreeManager) addDependencyScopes(dependencyId string, scopes ...string) {
+	if dtm.addScopes != nil && len(scopes) > 0 {
+		dtm.addScopes(dependencyId, scopes...)
+	}
 }
 
 // The structure of a dependency tree of a module in a Gradle/Maven project, as created by the gradle-dep-tree and maven-dep-tree plugins.
@@ -57,11 +109,13 @@ type moduleDepTree struct {
 
 type depTreeNode struct {
 	Children []string `json:"children"`
+	// The Maven scopes or the Gradle configurations in which the dependency is used.
+	Configurations []string `json:"configurations"`
 }
 
 // Reads the output files of the gradle-dep-tree and maven-dep-tree plugins and returns them as a slice of GraphNodes.
 // It takes the output of the plugin's run (which is a byte representation of a list of paths of the output files, separated by newlines) as input.
-func getGraphFromDepTree(outputFilePaths string) (depsGraph []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
+func (dtm *DepTreeManager) getGraphFromDepTree(outputFilePaths string) (depsGraph []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
 	modules, err := parseDepTreeFiles(outputFilePaths)
 	if err != nil {
 		return
@@ -70,6 +124,9 @@ func getGraphFromDepTree(outputFilePaths string) (depsGraph []*xrayUtils.GraphNo
 	allModulesUniqueDeps := datastructures.MakeSet[string]()
 	for _, module := range modules {
 		moduleTree, moduleUniqueDeps := getModuleTreeAndDependencies(module)
+		for depName, dependency := range module.Nodes {
+			dtm.addDependencyScopes(GavPackageTypeIdentifier+depName, dependency.Configurations...)
+		}
 		depsGraph = append(depsGraph, moduleTree)
 		for _, depToAdd := range moduleUniqueDeps {
 			allModulesUniqueDeps.Add(depToAdd)
@@ -130,3 +187,71 @@ func getModuleTreeAndDependencies(module *moduleDepTree) (*xrayUtils.GraphNode,
 	}
 	return sca.BuildXrayDependencyTree(moduleTreeMap, GavPackageTypeIdentifier+module.Root)
 }
+
+// Removes the dependency trees of the modules in the paths that match the scan exclusions, and returns the unique dependencies of the remaining trees.
+// Maven modules are identified by the group and artifact IDs in their pom.xml files, and Gradle modules by the names of their directories, which are the default names of Gradle projects.
+func removeExcludedModules(dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string, tech coreutils.Technology, workingDir string, scanExclusions []string) (keptTrees []*xrayUtils.GraphNode, keptDeps []string, err error) {
+	excludedModules, err := getScanExcludedModules(tech, workingDir, scanExclusions)
+	if err != nil || len(excludedModules) == 0 {
+		return dependencyTrees, uniqueDeps, err
+	}
+	keptDepsSet := datastructures.MakeSet[string]()
+	for _, tree := range dependencyTrees {
+		if isExcludedModule(tech, tree.Id, excludedModules) {
+			log.Debug(fmt.Sprintf("Excluding the '%s' module from the scan.", strings.TrimPrefix(tree.Id, GavPackageTypeIdentifier)))
+			continue
+		}
+		keptTrees = append(keptTrees, tree)
+		addTreeDependencies(tree, keptDepsSet)
+	}
+	return keptTrees, keptDepsSet.ToSlice(), nil
+}
+
+// Returns the 'groupId:artifactId' of the excluded Maven modules, or the names of the excluded Gradle modules.
+func getScanExcludedModules(tech coreutils.Technology, workingDir string, scanExclusions []string) (excludedModules []string, err error) {
+	if tech == coreutils.Gradle {
+		excludedDirs, err := sca.GetScanExcludedModulesDirs(workingDir, scanExclusions, "build.gradle", "build.gradle.kts")
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range excludedDirs {
+			excludedModules = append(excludedModules, filepath.Base(dir))
+		}
+		return excludedModules, nil
+	}
+	excludedDirs, err := sca.GetScanExcludedModulesDirs(workingDir, scanExclusions, pomFileName)
+	if err != nil {
+		return
+	}
+	for _, dir := range excludedDirs {
+		project, err := readPomFile(filepath.Join(dir, pomFileName))
+		if err != nil {
+			return nil, err
+		}
+		groupId := project.GroupId
+		if groupId == "" && project.Parent != nil {
+			groupId = project.Parent.GroupId
+		}
+		excludedModules = append(excludedModules, groupId+":"+project.ArtifactId)
+	}
+	return
+}
+
+// The ID of the root of a module's tree is 'gav://groupId:artifactId:version'.
+func isExcludedModule(tech coreutils.Technology, moduleId string, excludedModules []string) bool {
+	coordinates := strings.Split(strings.TrimPrefix(moduleId, GavPackageTypeIdentifier), ":")
+	if len(coordinates) < 2 {
+		return false
+	}
+	if tech == coreutils.Gradle {
+		return slices.Contains(excludedModules, coordinates[1])
+	}
+	return slices.Contains(excludedModules, coordinates[0]+":"+coordinates[1])
+}
+
+func addTreeDependencies(node *xrayUtils.GraphNode, dependencies *datastructures.Set[string]) {
+	dependencies.Add(node.Id)
+	for _, child := range node.Nodes {
+		addTreeDependencies(child, dependencies)
+	}
+}
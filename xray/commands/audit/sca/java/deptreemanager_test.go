@@ -1,7 +1,9 @@
 package java
 
 import (
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"path/filepath"
@@ -49,10 +51,10 @@ func TestGetGradleGraphFromDepTree(t *testing.T) {
 		"org.slf4j:slf4j-api:1.4.2",
 	}
 
-	manager := &gradleDepTreeManager{DepTreeManager{}}
+	manager := &gradleDepTreeManager{DepTreeManager: DepTreeManager{}}
 	outputFileContent, err := manager.runGradleDepTree()
 	assert.NoError(t, err)
-	depTree, uniqueDeps, err := getGraphFromDepTree(outputFileContent)
+	depTree, uniqueDeps, err := manager.getGraphFromDepTree(outputFileContent)
 	assert.NoError(t, err)
 	reflect.DeepEqual(uniqueDeps, expectedUniqueDeps)
 
@@ -63,3 +65,61 @@ func TestGetGradleGraphFromDepTree(t *testing.T) {
 		assert.Equal(t, len(depChild), len(dependency.Nodes))
 	}
 }
+
+func TestRemoveExcludedModules(t *testing.T) {
+	workingDir := t.TempDir()
+	for _, dir := range []string{"app", "vendored"} {
+		assert.NoError(t, os.Mkdir(filepath.Join(workingDir, dir), 0755))
+	}
+	// The group ID of the module is inherited from its parent.
+	vendoredPom := "<project><parent><groupId>org.example</groupId><artifactId>parent</artifactId><version>1.0</version></parent><artifactId>vendored</artifactId></project>"
+	assert.NoError(t, os.WriteFile(filepath.Join(workingDir, "vendored", "pom.xml"), []byte(vendoredPom), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(workingDir, "vendored", "build.gradle"), []byte{}, 0644))
+	newTrees := func() []*xrayUtils.GraphNode {
+		return []*xrayUtils.GraphNode{
+			{Id: "gav://org.example:app:1.0", Nodes: []*xrayUtils.GraphNode{{Id: "gav://junit:junit:4.13.2"}}},
+			{Id: "gav://org.example:vendored:1.0", Nodes: []*xrayUtils.GraphNode{{Id: "gav://commons-io:commons-io:2.11.0"}}},
+		}
+	}
+	uniqueDeps := []string{"gav://org.example:app:1.0", "gav://junit:junit:4.13.2", "gav://org.example:vendored:1.0", "gav://commons-io:commons-io:2.11.0"}
+
+	for _, tech := range []coreutils.Technology{coreutils.Maven, coreutils.Gradle} {
+		t.Run(tech.String(), func(t *testing.T) {
+			trees, deps, err := removeExcludedModules(newTrees(), uniqueDeps, tech, workingDir, []string{"*app-docs*"})
+			assert.NoError(t, err)
+			assert.Len(t, trees, 2)
+			assert.ElementsMatch(t, uniqueDeps, deps)
+
+			trees, deps, err = removeExcludedModules(newTrees(), uniqueDeps, tech, workingDir, []string{"*vendored*"})
+			assert.NoError(t, err)
+			if assert.Len(t, trees, 1) {
+				assert.Equal(t, "gav://org.example:app:1.0", trees[0].Id)
+			}
+			assert.ElementsMatch(t, []string{"gav://org.example:app:1.0", "gav://junit:junit:4.13.2"}, deps)
+		})
+	}
+}
+
+func TestGetGraphFromDepTreeScopes(t *testing.T) {
+	depTreeFile := filepath.Join(t.TempDir(), "deptree.json")
+	assert.NoError(t, os.WriteFile(depTreeFile, []byte(`{
+		"root": "org.example:app:1.0",
+		"nodes": {
+			"org.example:app:1.0": {"children": ["junit:junit:4.13.2", "commons-io:commons-io:2.15.0"]},
+			"junit:junit:4.13.2": {"children": [], "configurations": ["test"]},
+			"commons-io:commons-io:2.15.0": {"children": [], "configurations": ["compile", "runtime"]}
+		}
+	}`), 0644))
+
+	scopes := map[string][]string{}
+	manager := &DepTreeManager{addScopes: func(dependencyId string, dependencyScopes ...string) {
+		scopes[dependencyId] = append(scopes[dependencyId], dependencyScopes...)
+	}}
+	_, uniqueDeps, err := manager.getGraphFromDepTree(depTreeFile)
+	assert.NoError(t, err)
+	assert.Len(t, uniqueDeps, 3)
+	assert.Equal(t, map[string][]string{
+		GavPackageTypeIdentifier + "junit:junit:4.13.2":           {"test"},
+		GavPackageTypeIdentifier + "commons-io:commons-io:2.15.0": {"compile", "runtime"},
+	}, scopes)
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -13,6 +12,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/ioutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
@@ -54,15 +54,17 @@ var gradleDepTreeJar []byte
 
 type gradleDepTreeManager struct {
 	DepTreeManager
+	useDaemon             bool
+	useConfigurationCache bool
 }
 
 func buildGradleDependencyTree(params *DepTreeParams) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
-	manager := &gradleDepTreeManager{DepTreeManager: NewDepTreeManager(params)}
+	manager := &gradleDepTreeManager{DepTreeManager: NewDepTreeManager(params), useDaemon: params.UseGradleDaemon, useConfigurationCache: params.UseGradleConfigurationCache}
 	outputFileContent, err := manager.runGradleDepTree()
 	if err != nil {
 		return
 	}
-	dependencyTree, uniqueDeps, err = getGraphFromDepTree(outputFileContent)
+	dependencyTree, uniqueDeps, err = manager.getGraphFromDepTree(outputFileContent)
 	return
 }
 
@@ -77,7 +79,7 @@ func (gdt *gradleDepTreeManager) runGradleDepTree() (string, error) {
 	}()
 
 	if gdt.useWrapper {
-		gdt.useWrapper, err = isGradleWrapperExist()
+		gdt.useWrapper, err = isGradleWrapperExist(gdt.workingDir)
 		if err != nil {
 			return "", err
 		}
@@ -152,14 +154,13 @@ func (gdt *gradleDepTreeManager) execGradleDepTree(depTreeDir string) (outputFil
 	}
 
 	outputFilePath := filepath.Join(depTreeDir, gradleDepTreeOutputFile)
-	tasks := []string{
-		"clean",
-		"generateDepTrees", "-I", filepath.Join(depTreeDir, gradleDepTreeInitFile),
-		"-q",
-		fmt.Sprintf("-Dcom.jfrog.depsTreeOutputFile=%s", outputFilePath),
-		"-Dcom.jfrog.includeAllBuildFiles=true"}
+	tasks := gdt.getGradleDepTreeArgs(depTreeDir, outputFilePath)
 	log.Info("Running gradle deps tree command:", gradleExecPath, strings.Join(tasks, " "))
-	if output, err := exec.Command(gradleExecPath, tasks...).CombinedOutput(); err != nil {
+	gdt.recordExecutedCommand(gradleExecPath, tasks...)
+	command := sca.CreateCommand(gdt.ctx, gradleExecPath, tasks...)
+	// The path of the Gradle wrapper is relative to the project directory.
+	command.Dir = gdt.workingDir
+	if output, err := command.CombinedOutput(); err != nil {
 		return nil, errorutils.CheckErrorf("error running gradle-dep-tree: %s\n%s", err.Error(), string(output))
 	}
 	defer func() {
@@ -171,6 +172,25 @@ func (gdt *gradleDepTreeManager) execGradleDepTree(depTreeDir string) (outputFil
 	return
 }
 
+// Returns the arguments of the gradle-dep-tree command.
+// Unless requested otherwise, Gradle runs without its daemon and its configuration cache, so the dependencies are resolved from a fresh build configuration.
+// This is slower, especially for repeated audits of large projects, but avoids resolving the dependencies from a stale state of a previous build.
+func (gdt *gradleDepTreeManager) getGradleDepTreeArgs(depTreeDir, outputFilePath string) []string {
+	args := []string{
+		"clean",
+		"generateDepTrees", "-I", filepath.Join(depTreeDir, gradleDepTreeInitFile),
+		"-q",
+		fmt.Sprintf("-Dcom.jfrog.depsTreeOutputFile=%s", outputFilePath),
+		"-Dcom.jfrog.includeAllBuildFiles=true"}
+	if !gdt.useDaemon {
+		args = append(args, "--no-daemon")
+	}
+	if !gdt.useConfigurationCache {
+		args = append(args, "--no-configuration-cache")
+	}
+	return args
+}
+
 func getDepTreeArtifactoryRepository(remoteRepo string, server *config.ServerDetails) (string, error) {
 	if remoteRepo == "" || server.IsEmpty() {
 		return "", nil
@@ -188,12 +208,12 @@ func getDepTreeArtifactoryRepository(remoteRepo string, server *config.ServerDet
 		password), nil
 }
 
-// This function assumes that the Gradle wrapper is in the root directory.
+// This function assumes that the Gradle wrapper is in the root directory of the project.
 // The --project-dir option of Gradle won't work in this case.
-func isGradleWrapperExist() (bool, error) {
+func isGradleWrapperExist(projectDir string) (bool, error) {
 	wrapperName := gradlew
 	if coreutils.IsWindows() {
 		wrapperName += ".bat"
 	}
-	return fileutils.IsFileExists(wrapperName, false)
+	return fileutils.IsFileExists(filepath.Join(projectDir, wrapperName), false)
 }
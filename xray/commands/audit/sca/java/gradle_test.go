@@ -92,14 +92,14 @@ func TestGradleTreesWithConfig(t *testing.T) {
 
 func TestIsGradleWrapperExist(t *testing.T) {
 	// Check Gradle wrapper doesn't exist
-	isWrapperExist, err := isGradleWrapperExist()
+	isWrapperExist, err := isGradleWrapperExist(t.TempDir())
 	assert.False(t, isWrapperExist)
 	assert.NoError(t, err)
 
 	// Check Gradle wrapper exist
-	_, cleanUp := sca.CreateTestWorkspace(t, "gradle-example-ci-server")
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "gradle-example-ci-server")
 	defer cleanUp()
-	isWrapperExist, err = isGradleWrapperExist()
+	isWrapperExist, err = isGradleWrapperExist(projectDir)
 	assert.NoError(t, err)
 	assert.True(t, isWrapperExist)
 }
@@ -228,3 +228,24 @@ func TestConstructReleasesRemoteRepo(t *testing.T) {
 		}()
 	}
 }
+
+func TestGetGradleDepTreeArgs(t *testing.T) {
+	depTreeDir := filepath.Join("tmp", "dep-tree")
+	outputFilePath := filepath.Join(depTreeDir, gradleDepTreeOutputFile)
+	expectedArgs := []string{
+		"clean",
+		"generateDepTrees", "-I", filepath.Join(depTreeDir, gradleDepTreeInitFile),
+		"-q",
+		"-Dcom.jfrog.depsTreeOutputFile=" + outputFilePath,
+		"-Dcom.jfrog.includeAllBuildFiles=true"}
+
+	// By default, Gradle runs without its daemon and its configuration cache
+	manager := &gradleDepTreeManager{}
+	assert.Equal(t, append(expectedArgs, "--no-daemon", "--no-configuration-cache"), manager.getGradleDepTreeArgs(depTreeDir, outputFilePath))
+
+	manager = &gradleDepTreeManager{useDaemon: true}
+	assert.Equal(t, append(expectedArgs, "--no-configuration-cache"), manager.getGradleDepTreeArgs(depTreeDir, outputFilePath))
+
+	manager = &gradleDepTreeManager{useDaemon: true, useConfigurationCache: true}
+	assert.Equal(t, expectedArgs, manager.getGradleDepTreeArgs(depTreeDir, outputFilePath))
+}
@@ -5,13 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/ioutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -48,8 +48,11 @@ type MavenDepTreeManager struct {
 
 func NewMavenDepTreeManager(params *DepTreeParams, cmdName MavenDepTreeCmd, isDepTreeInstalled bool) *MavenDepTreeManager {
 	depTreeManager := NewDepTreeManager(&DepTreeParams{
-		Server:   params.Server,
-		DepsRepo: params.DepsRepo,
+		Server:              params.Server,
+		DepsRepo:            params.DepsRepo,
+		RecordCommand:       params.RecordCommand,
+		AddDependencyScopes: params.AddDependencyScopes,
+		WorkingDir:          params.WorkingDir,
 	})
 	return &MavenDepTreeManager{
 		DepTreeManager: depTreeManager,
@@ -64,7 +67,7 @@ func buildMavenDependencyTree(params *DepTreeParams, isDepTreeInstalled bool) (d
 	if err != nil {
 		return
 	}
-	dependencyTree, uniqueDeps, err = getGraphFromDepTree(outputFilePaths)
+	dependencyTree, uniqueDeps, err = manager.getGraphFromDepTree(outputFilePaths)
 	return
 }
 
@@ -130,7 +133,7 @@ func (mdt *MavenDepTreeManager) run(goals []string, depTreeOutputPath string) (s
 }
 
 func (mdt *MavenDepTreeManager) RunMvnCmd(goals []string) (cmdOutput []byte, err error) {
-	restoreMavenConfig, err := removeMavenConfig()
+	restoreMavenConfig, err := removeMavenConfig(mdt.workingDir)
 	if err != nil {
 		return
 	}
@@ -145,8 +148,11 @@ func (mdt *MavenDepTreeManager) RunMvnCmd(goals []string) (cmdOutput []byte, err
 		goals = append(goals, "-s", mdt.settingsXmlPath)
 	}
 
+	mdt.recordExecutedCommand("mvn", goals...)
 	//#nosec G204
-	cmdOutput, err = exec.Command("mvn", goals...).CombinedOutput()
+	command := sca.CreateCommand(mdt.ctx, "mvn", goals...)
+	command.Dir = mdt.workingDir
+	cmdOutput, err = command.CombinedOutput()
 	if err != nil {
 		if len(cmdOutput) > 0 {
 			log.Info(string(cmdOutput))
@@ -156,7 +162,9 @@ func (mdt *MavenDepTreeManager) RunMvnCmd(goals []string) (cmdOutput []byte, err
 	return
 }
 
-func removeMavenConfig() (func() error, error) {
+// Removes the maven.config file of the project in the given directory, if it exists, and returns a function that restores it.
+func removeMavenConfig(projectDir string) (func() error, error) {
+	mavenConfigPath := filepath.Join(projectDir, mavenConfigPath)
 	mavenConfigExists, err := fileutils.IsFileExists(mavenConfigPath, false)
 	if err != nil {
 		return nil, err
@@ -4,7 +4,6 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
-	"github.com/jfrog/jfrog-client-go/utils/tests"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"path/filepath"
@@ -232,27 +231,24 @@ func TestRunProjectsCmd(t *testing.T) {
 
 func TestRemoveMavenConfig(t *testing.T) {
 	tmpDir := t.TempDir()
-	currentDir, err := os.Getwd()
-	assert.NoError(t, err)
-	restoreDir := tests.ChangeDirWithCallback(t, currentDir, tmpDir)
-	defer restoreDir()
 
 	// No maven.config exists
-	restoreFunc, err := removeMavenConfig()
+	restoreFunc, err := removeMavenConfig(tmpDir)
 	assert.Nil(t, restoreFunc)
 	assert.Nil(t, err)
 
 	// Create maven.config
-	err = fileutils.CreateDirIfNotExist(".mvn")
+	err = fileutils.CreateDirIfNotExist(filepath.Join(tmpDir, ".mvn"))
 	assert.NoError(t, err)
-	file, err := os.Create(mavenConfigPath)
+	projectMavenConfigPath := filepath.Join(tmpDir, mavenConfigPath)
+	file, err := os.Create(projectMavenConfigPath)
 	assert.NoError(t, err)
 	err = file.Close()
 	assert.NoError(t, err)
-	restoreFunc, err = removeMavenConfig()
+	restoreFunc, err = removeMavenConfig(tmpDir)
 	assert.NoError(t, err)
-	assert.NoFileExists(t, mavenConfigPath)
+	assert.NoFileExists(t, projectMavenConfigPath)
 	err = restoreFunc()
 	assert.NoError(t, err)
-	assert.FileExists(t, mavenConfigPath)
+	assert.FileExists(t, projectMavenConfigPath)
 }
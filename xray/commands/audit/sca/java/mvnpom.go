@@ -0,0 +1,359 @@
+package java
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+const (
+	pomFileName       = "pom.xml"
+	defaultParentPath = "../pom.xml"
+	importScope       = "import"
+	defaultScope      = "compile"
+	pomType           = "pom"
+	// Limits the nesting of properties that refer to other properties, to avoid endless loops.
+	maxPropertyInterpolations = 10
+)
+
+var pomPropertyRegexp = regexp.MustCompile(`\$\{([^}]+)}`)
+
+// The parts of a pom.xml file that are needed to resolve the project's dependencies.
+type pomProject struct {
+	Parent               *pomParent      `xml:"parent"`
+	GroupId              string          `xml:"groupId"`
+	ArtifactId           string          `xml:"artifactId"`
+	Version              string          `xml:"version"`
+	Properties           pomProperties   `xml:"properties"`
+	DependencyManagement []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+}
+
+type pomParent struct {
+	GroupId      string  `xml:"groupId"`
+	ArtifactId   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"`
+}
+
+type pomDependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Type       string `xml:"type"`
+}
+
+func (dependency pomDependency) key() string {
+	return dependency.GroupId + ":" + dependency.ArtifactId
+}
+
+func (dependency pomDependency) isBomImport() bool {
+	return dependency.Scope == importScope && dependency.Type == pomType
+}
+
+// The properties of a pom are arbitrary elements, so they are unmarshalled into a map.
+type pomProperties map[string]string
+
+func (properties *pomProperties) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	*properties = pomProperties{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err = decoder.DecodeElement(&value, &element); err != nil {
+				return err
+			}
+			(*properties)[element.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// A pom after applying its parents and its imported BOMs.
+type effectivePom struct {
+	groupId    string
+	artifactId string
+	version    string
+	properties map[string]string
+	// Maps the 'groupId:artifactId' of each managed dependency to its version.
+	managedVersions map[string]string
+	dependencies    []pomDependency
+}
+
+// Resolves the effective poms of a Maven project from the local Maven repository, or from the resolution repository in Artifactory.
+type pomResolver struct {
+	server          *config.ServerDetails
+	depsRepo        string
+	localRepository string
+	serviceManager  artifactory.ArtifactoryServicesManager
+	// The coordinates of the poms that are being resolved, to detect cycles.
+	inProgress map[string]bool
+}
+
+func newPomResolver(params *DepTreeParams) *pomResolver {
+	return &pomResolver{
+		server:          params.Server,
+		depsRepo:        params.DepsRepo,
+		localRepository: filepath.Join(fileutils.GetHomeDir(), ".m2", "repository"),
+		inProgress:      map[string]bool{},
+	}
+}
+
+// Builds the dependency tree of the Maven project in the project directory from its pom.xml, without running Maven.
+// The versions of the dependencies that are managed by the project, its parents or the BOMs they import are resolved from the poms that declare them.
+// Only the direct dependencies of the project are included in the tree.
+func buildMavenDependencyTreeOffline(params *DepTreeParams) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
+	projectDir := params.WorkingDir
+	if projectDir == "" {
+		projectDir = "."
+	}
+	resolver := newPomResolver(params)
+	project, err := readPomFile(filepath.Join(projectDir, pomFileName))
+	if err != nil {
+		return
+	}
+	pom, err := resolver.resolve(project, projectDir)
+	if err != nil {
+		return
+	}
+	rootId := GavPackageTypeIdentifier + strings.Join([]string{pom.groupId, pom.artifactId, pom.version}, ":")
+	var directDependencies []string
+	for _, dependency := range pom.dependencies {
+		version := pom.interpolate(dependency.Version)
+		if version == "" {
+			version = pom.managedVersions[dependency.key()]
+		}
+		if version == "" || strings.Contains(version, "${") {
+			log.Warn(fmt.Sprintf("Couldn't resolve the version of the '%s' dependency. Skipping it...", dependency.key()))
+			continue
+		}
+		if isVersionRange(version) {
+			// Xray scans concrete versions only, so ranges are resolved to the versions that Maven would select from the available ones.
+			versionRange := version
+			if version, err = resolver.resolveVersionRange(dependency.GroupId, dependency.ArtifactId, versionRange); err != nil {
+				return
+			}
+			if version == "" {
+				log.Warn(fmt.Sprintf("Couldn't resolve the version range '%s' of the '%s' dependency to an available version. Skipping it...", versionRange, dependency.key()))
+				continue
+			}
+		}
+		dependencyId := GavPackageTypeIdentifier + dependency.key() + ":" + version
+		directDependencies = append(directDependencies, dependencyId)
+		if params.AddDependencyScopes != nil {
+			scope := dependency.Scope
+			if scope == "" {
+				scope = defaultScope
+			}
+			params.AddDependencyScopes(dependencyId, scope)
+		}
+	}
+	rootNode, uniqueDeps := sca.BuildXrayDependencyTree(map[string][]string{rootId: directDependencies}, rootId)
+	dependencyTree = []*xrayUtils.GraphNode{rootNode}
+	return
+}
+
+// Returns the effective pom of the given project.
+// projectDir is the directory of the project's pom.xml, or empty if it was fetched from a repository.
+func (pr *pomResolver) resolve(project *pomProject, projectDir string) (pom *effectivePom, err error) {
+	pom = &effectivePom{
+		groupId:         project.GroupId,
+		artifactId:      project.ArtifactId,
+		version:         project.Version,
+		properties:      map[string]string{},
+		managedVersions: map[string]string{},
+	}
+	var parent *effectivePom
+	if project.Parent != nil {
+		if parent, err = pr.resolveParent(project.Parent, projectDir); err != nil {
+			return
+		}
+		if pom.groupId == "" {
+			pom.groupId = parent.groupId
+		}
+		if pom.version == "" {
+			pom.version = parent.version
+		}
+		for key, value := range parent.properties {
+			pom.properties[key] = value
+		}
+		pom.properties["project.parent.groupId"] = parent.groupId
+		pom.properties["project.parent.version"] = parent.version
+	}
+	for key, value := range project.Properties {
+		pom.properties[key] = value
+	}
+	pom.properties["project.groupId"] = pom.groupId
+	pom.properties["project.artifactId"] = pom.artifactId
+	pom.properties["project.version"] = pom.version
+	pom.properties["pom.version"] = pom.version
+	// The managed dependencies that are declared in the pom take precedence over the inherited ones, which take precedence over the imported ones.
+	var bomImports []pomDependency
+	for _, dependency := range project.DependencyManagement {
+		if dependency.isBomImport() {
+			bomImports = append(bomImports, dependency)
+			continue
+		}
+		pom.managedVersions[dependency.key()] = pom.interpolate(dependency.Version)
+	}
+	if parent != nil {
+		addMissingManagedVersions(pom.managedVersions, parent.managedVersions)
+	}
+	for _, bomImport := range bomImports {
+		var bom *effectivePom
+		if bom, err = pr.resolveCoordinates(bomImport.GroupId, bomImport.ArtifactId, pom.interpolate(bomImport.Version)); err != nil {
+			return
+		}
+		addMissingManagedVersions(pom.managedVersions, bom.managedVersions)
+	}
+	pom.dependencies = project.Dependencies
+	if parent != nil {
+		pom.dependencies = append(pom.dependencies, parent.dependencies...)
+	}
+	return
+}
+
+// Returns the effective pom of the parent, from the local file system if it's a part of the project, or from a repository otherwise.
+func (pr *pomResolver) resolveParent(parent *pomParent, projectDir string) (*effectivePom, error) {
+	if projectDir != "" {
+		relativePath := defaultParentPath
+		if parent.RelativePath != nil {
+			relativePath = *parent.RelativePath
+		}
+		if relativePath != "" {
+			parentPath := filepath.Join(projectDir, filepath.FromSlash(relativePath))
+			if !strings.HasSuffix(parentPath, pomFileName) {
+				parentPath = filepath.Join(parentPath, pomFileName)
+			}
+			exists, err := fileutils.IsFileExists(parentPath, false)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				localParent, err := readPomFile(parentPath)
+				if err != nil {
+					return nil, err
+				}
+				if localParent.ArtifactId == parent.ArtifactId {
+					return pr.resolve(localParent, filepath.Dir(parentPath))
+				}
+			}
+		}
+	}
+	return pr.resolveCoordinates(parent.GroupId, parent.ArtifactId, parent.Version)
+}
+
+// Returns the effective pom of the given coordinates, fetched from the local Maven repository or from the resolution repository.
+func (pr *pomResolver) resolveCoordinates(groupId, artifactId, version string) (*effectivePom, error) {
+	coordinates := strings.Join([]string{groupId, artifactId, version}, ":")
+	if pr.inProgress[coordinates] {
+		return nil, errorutils.CheckErrorf("the pom of '%s' refers to itself through its parents or imported BOMs", coordinates)
+	}
+	pr.inProgress[coordinates] = true
+	defer delete(pr.inProgress, coordinates)
+	project, err := pr.fetchPom(groupId, artifactId, version)
+	if err != nil {
+		return nil, err
+	}
+	return pr.resolve(project, "")
+}
+
+func (pr *pomResolver) fetchPom(groupId, artifactId, version string) (*pomProject, error) {
+	pomPath := path.Join(strings.ReplaceAll(groupId, ".", "/"), artifactId, version, artifactId+"-"+version+".pom")
+	localPath := filepath.Join(pr.localRepository, filepath.FromSlash(pomPath))
+	exists, err := fileutils.IsFileExists(localPath, false)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		log.Debug(fmt.Sprintf("Reading the pom of '%s:%s:%s' from the local Maven repository", groupId, artifactId, version))
+		return readPomFile(localPath)
+	}
+	if pr.server == nil || pr.depsRepo == "" {
+		return nil, errorutils.CheckErrorf("the pom of '%s:%s:%s' was not found in the local Maven repository, and no resolution repository was configured", groupId, artifactId, version)
+	}
+	log.Debug(fmt.Sprintf("Downloading the pom of '%s:%s:%s' from the resolution repository", groupId, artifactId, version))
+	content, downloadUrl, err := pr.download(pomPath)
+	if err != nil {
+		return nil, err
+	}
+	return parsePom(content, downloadUrl)
+}
+
+// Downloads the file in the given path of the resolution repository. Returns the content of the file and its URL.
+func (pr *pomResolver) download(filePath string) (content []byte, downloadUrl string, err error) {
+	if pr.serviceManager == nil {
+		if pr.serviceManager, err = utils.CreateServiceManager(pr.server, -1, 0, false); err != nil {
+			return
+		}
+	}
+	downloadUrl = strings.TrimSuffix(pr.server.ArtifactoryUrl, "/") + "/" + path.Join(pr.depsRepo, filePath)
+	httpClientDetails := pr.serviceManager.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+	resp, content, _, err := pr.serviceManager.Client().SendGet(downloadUrl, true, &httpClientDetails)
+	if err != nil {
+		return
+	}
+	err = errorutils.CheckResponseStatusWithBody(resp, content, http.StatusOK)
+	return
+}
+
+func readPomFile(pomPath string) (*pomProject, error) {
+	content, err := fileutils.ReadFile(pomPath)
+	if err != nil {
+		return nil, err
+	}
+	return parsePom(content, pomPath)
+}
+
+func parsePom(content []byte, source string) (*pomProject, error) {
+	project := &pomProject{}
+	if err := xml.Unmarshal(content, project); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the pom '%s': %s", source, err.Error())
+	}
+	return project, nil
+}
+
+func addMissingManagedVersions(managedVersions, additionalManagedVersions map[string]string) {
+	for key, version := range additionalManagedVersions {
+		if _, exists := managedVersions[key]; !exists {
+			managedVersions[key] = version
+		}
+	}
+}
+
+// Replaces the references to properties in the given value with the properties values.
+// References to unknown properties are left as is.
+func (pom *effectivePom) interpolate(value string) string {
+	for i := 0; i < maxPropertyInterpolations && strings.Contains(value, "${"); i++ {
+		interpolated := pomPropertyRegexp.ReplaceAllStringFunc(value, func(reference string) string {
+			if propertyValue, exists := pom.properties[reference[2:len(reference)-1]]; exists {
+				return propertyValue
+			}
+			return reference
+		})
+		if interpolated == value {
+			break
+		}
+		value = interpolated
+	}
+	return value
+}
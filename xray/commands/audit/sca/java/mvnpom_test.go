@@ -0,0 +1,99 @@
+package java
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	coretests "github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/stretchr/testify/assert"
+)
+
+const platformBomPath = "com/example/platform-bom/2.0.0/platform-bom-2.0.0.pom"
+
+var expectedBomConsumerDependencies = []string{
+	GavPackageTypeIdentifier + "com.fasterxml.jackson.core:jackson-databind:2.15.2",
+	GavPackageTypeIdentifier + "com.google.guava:guava:32.1.3-jre",
+	GavPackageTypeIdentifier + "commons-io:commons-io:2.15.0",
+	GavPackageTypeIdentifier + "junit:junit:4.13.2",
+}
+
+func TestBuildMavenDependencyTreeOffline(t *testing.T) {
+	tempDirPath, cleanUp := sca.CreateTestWorkspace(t, "maven-bom-project")
+	defer cleanUp()
+	// The BOMs are resolved from the local Maven repository in the workspace
+	t.Setenv("HOME", tempDirPath)
+
+	dependencyTree, uniqueDeps, err := buildMavenDependencyTreeOffline(&DepTreeParams{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, append([]string{GavPackageTypeIdentifier + "com.example:bom-consumer:1.0.0"}, expectedBomConsumerDependencies...), uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		assert.Equal(t, GavPackageTypeIdentifier+"com.example:bom-consumer:1.0.0", dependencyTree[0].Id)
+		var directDependencies []string
+		for _, node := range dependencyTree[0].Nodes {
+			directDependencies = append(directDependencies, node.Id)
+		}
+		assert.ElementsMatch(t, expectedBomConsumerDependencies, directDependencies)
+	}
+}
+
+func TestBuildMavenDependencyTreeOfflineScopes(t *testing.T) {
+	tempDirPath, cleanUp := sca.CreateTestWorkspace(t, "maven-bom-project")
+	defer cleanUp()
+	t.Setenv("HOME", tempDirPath)
+
+	scopes := map[string][]string{}
+	_, _, err := buildMavenDependencyTreeOffline(&DepTreeParams{AddDependencyScopes: func(dependencyId string, dependencyScopes ...string) {
+		scopes[dependencyId] = append(scopes[dependencyId], dependencyScopes...)
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		GavPackageTypeIdentifier + "com.fasterxml.jackson.core:jackson-databind:2.15.2": {"compile"},
+		GavPackageTypeIdentifier + "com.google.guava:guava:32.1.3-jre":                  {"compile"},
+		GavPackageTypeIdentifier + "commons-io:commons-io:2.15.0":                       {"compile"},
+		GavPackageTypeIdentifier + "junit:junit:4.13.2":                                 {"test"},
+	}, scopes)
+}
+
+func TestBuildMavenDependencyTreeOfflineFromResolutionRepo(t *testing.T) {
+	tempDirPath, cleanUp := sca.CreateTestWorkspace(t, "maven-bom-project")
+	defer cleanUp()
+	t.Setenv("HOME", tempDirPath)
+	// The transitively imported BOM is missing from the local Maven repository, so it is downloaded from the resolution repository
+	localPlatformBom := filepath.Join(tempDirPath, ".m2", "repository", filepath.FromSlash(platformBomPath))
+	platformBom, err := os.ReadFile(localPlatformBom)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(localPlatformBom))
+	var downloaded bool
+	testServer := coretests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/artifactory/maven-remote/"+platformBomPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		downloaded = true
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(platformBom)
+		assert.NoError(t, err)
+	})
+	defer testServer.Close()
+
+	params := &DepTreeParams{Server: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/artifactory/"}, DepsRepo: "maven-remote"}
+	_, uniqueDeps, err := buildMavenDependencyTreeOffline(params)
+	assert.NoError(t, err)
+	assert.True(t, downloaded)
+	assert.Subset(t, uniqueDeps, expectedBomConsumerDependencies)
+
+	// Without a resolution repository, the missing BOM fails the resolution
+	_, _, err = buildMavenDependencyTreeOffline(&DepTreeParams{})
+	assert.ErrorContains(t, err, "com.example:platform-bom:2.0.0' was not found")
+}
+
+func TestInterpolatePomProperties(t *testing.T) {
+	pom := &effectivePom{properties: map[string]string{"a": "${b}", "b": "1.0", "loop": "${loop}"}}
+	assert.Equal(t, "1.0", pom.interpolate("${a}"))
+	assert.Equal(t, "1.0-${unknown}", pom.interpolate("${b}-${unknown}"))
+	assert.Equal(t, "${loop}", pom.interpolate("${loop}"))
+}
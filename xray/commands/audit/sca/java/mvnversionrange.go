@@ -0,0 +1,161 @@
+package java
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const mavenMetadataFileName = "maven-metadata.xml"
+
+// A single interval of a Maven version range, such as '[1.0,2.0)'. An empty bound is unbounded.
+type mavenVersionInterval struct {
+	lower          string
+	lowerInclusive bool
+	upper          string
+	upperInclusive bool
+}
+
+// The versions of an artifact, as listed by the maven-metadata.xml file of the artifact in a repository.
+type mavenMetadata struct {
+	Versions []string `xml:"versioning>versions>version"`
+}
+
+// Parses a Maven version range, which is a comma separated list of intervals, such as '(,1.0],[1.2,)'.
+// A version between brackets without a comma, such as '[1.0]', is an exact version.
+func parseMavenVersionRange(versionRange string) (intervals []mavenVersionInterval, err error) {
+	remaining := strings.TrimSpace(versionRange)
+	for remaining != "" {
+		if remaining[0] != '[' && remaining[0] != '(' {
+			return nil, errorutils.CheckErrorf("invalid Maven version range '%s'", versionRange)
+		}
+		end := strings.IndexAny(remaining, "])")
+		if end < 0 {
+			return nil, errorutils.CheckErrorf("invalid Maven version range '%s'", versionRange)
+		}
+		interval := mavenVersionInterval{lowerInclusive: remaining[0] == '[', upperInclusive: remaining[end] == ']'}
+		bounds := remaining[1:end]
+		if lower, upper, isInterval := strings.Cut(bounds, ","); isInterval {
+			interval.lower, interval.upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+		} else {
+			// An exact version must be inclusive on both sides.
+			if !interval.lowerInclusive || !interval.upperInclusive {
+				return nil, errorutils.CheckErrorf("invalid Maven version range '%s'", versionRange)
+			}
+			interval.lower, interval.upper = strings.TrimSpace(bounds), strings.TrimSpace(bounds)
+		}
+		intervals = append(intervals, interval)
+		remaining = strings.TrimPrefix(strings.TrimSpace(remaining[end+1:]), ",")
+		remaining = strings.TrimSpace(remaining)
+	}
+	return
+}
+
+func (interval mavenVersionInterval) contains(candidate string) bool {
+	candidateVersion := version.NewVersion(candidate)
+	if interval.lower != "" {
+		// Compare returns 1 if the given version is greater than the candidate.
+		comparison := candidateVersion.Compare(interval.lower)
+		if comparison > 0 || (comparison == 0 && !interval.lowerInclusive) {
+			return false
+		}
+	}
+	if interval.upper != "" {
+		comparison := candidateVersion.Compare(interval.upper)
+		if comparison < 0 || (comparison == 0 && !interval.upperInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the highest of the given versions that is in the given intervals, or an empty string if none of them is.
+func getHighestVersionInRange(intervals []mavenVersionInterval, versions []string) (highest string) {
+	for _, candidate := range versions {
+		inRange := false
+		for _, interval := range intervals {
+			if interval.contains(candidate) {
+				inRange = true
+				break
+			}
+		}
+		if inRange && (highest == "" || version.NewVersion(highest).Compare(candidate) > 0) {
+			highest = candidate
+		}
+	}
+	return
+}
+
+// Resolves a Maven version range to the highest version in the range that is available in the local Maven repository, or listed by the resolution repository.
+// Returns an empty string if no available version is in the range.
+func (pr *pomResolver) resolveVersionRange(groupId, artifactId, versionRange string) (string, error) {
+	intervals, err := parseMavenVersionRange(versionRange)
+	if err != nil {
+		return "", err
+	}
+	versions, err := pr.getLocalVersions(groupId, artifactId)
+	if err != nil {
+		return "", err
+	}
+	if resolved := getHighestVersionInRange(intervals, versions); resolved != "" {
+		log.Debug(fmt.Sprintf("Resolved the version range '%s' of '%s:%s' to '%s' from the local Maven repository", versionRange, groupId, artifactId, resolved))
+		return resolved, nil
+	}
+	if pr.server == nil || pr.depsRepo == "" {
+		return "", nil
+	}
+	metadataPath := path.Join(strings.ReplaceAll(groupId, ".", "/"), artifactId, mavenMetadataFileName)
+	content, source, err := pr.download(metadataPath)
+	if err != nil {
+		// The artifact may not be in the resolution repository, in which case the range is left unresolved.
+		log.Debug(fmt.Sprintf("Couldn't get the versions of '%s:%s' from the resolution repository: %s", groupId, artifactId, err.Error()))
+		return "", nil
+	}
+	metadata := &mavenMetadata{}
+	if err = xml.Unmarshal(content, metadata); err != nil {
+		return "", errorutils.CheckErrorf("failed to parse the Maven metadata '%s': %s", source, err.Error())
+	}
+	resolved := getHighestVersionInRange(intervals, metadata.Versions)
+	if resolved != "" {
+		log.Debug(fmt.Sprintf("Resolved the version range '%s' of '%s:%s' to '%s' from %s", versionRange, groupId, artifactId, resolved, source))
+	}
+	return resolved, nil
+}
+
+// Returns the versions of the given artifact whose poms are in the local Maven repository.
+func (pr *pomResolver) getLocalVersions(groupId, artifactId string) (versions []string, err error) {
+	artifactDir := filepath.Join(pr.localRepository, filepath.FromSlash(strings.ReplaceAll(groupId, ".", "/")), artifactId)
+	exists, err := fileutils.IsDirExists(artifactDir, false)
+	if err != nil || !exists {
+		return
+	}
+	entries, err := os.ReadDir(artifactDir)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pomExists, err := fileutils.IsFileExists(filepath.Join(artifactDir, entry.Name(), artifactId+"-"+entry.Name()+".pom"), false)
+		if err != nil {
+			return nil, err
+		}
+		if pomExists {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return
+}
+
+func isVersionRange(dependencyVersion string) bool {
+	return strings.HasPrefix(dependencyVersion, "[") || strings.HasPrefix(dependencyVersion, "(")
+}
@@ -0,0 +1,83 @@
+package java
+
+import (
+	"net/http"
+	"testing"
+
+	coretests "github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMavenDependencyTreeOfflineVersionRanges(t *testing.T) {
+	tempDirPath, cleanUp := sca.CreateTestWorkspace(t, "maven-range-project")
+	defer cleanUp()
+	t.Setenv("HOME", tempDirPath)
+
+	_, uniqueDeps, err := buildMavenDependencyTreeOffline(&DepTreeParams{})
+	assert.NoError(t, err)
+	// The ranges are resolved to the highest versions in the local Maven repository that are in range, and the unresolved range of lib-c is skipped.
+	assert.ElementsMatch(t, []string{
+		GavPackageTypeIdentifier + "com.example:range-consumer:1.0.0",
+		GavPackageTypeIdentifier + "com.example:lib-a:1.10.0",
+		GavPackageTypeIdentifier + "com.example:lib-b:2.5.1",
+		GavPackageTypeIdentifier + "commons-io:commons-io:2.15.0",
+	}, uniqueDeps)
+	for _, dependency := range uniqueDeps {
+		assert.False(t, sca.IsVersionRange(dependency), dependency)
+	}
+}
+
+func TestBuildMavenDependencyTreeOfflineVersionRangesFromResolutionRepo(t *testing.T) {
+	tempDirPath, cleanUp := sca.CreateTestWorkspace(t, "maven-range-project")
+	defer cleanUp()
+	t.Setenv("HOME", tempDirPath)
+	testServer := coretests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/artifactory/maven-remote/com/example/lib-c/maven-metadata.xml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`<metadata><groupId>com.example</groupId><artifactId>lib-c</artifactId><versioning><versions><version>4.0.0</version><version>5.2.0</version><version>5.10.1</version></versions></versioning></metadata>`))
+		assert.NoError(t, err)
+	})
+	defer testServer.Close()
+
+	params := &DepTreeParams{Server: &config.ServerDetails{ArtifactoryUrl: testServer.URL + "/artifactory/"}, DepsRepo: "maven-remote"}
+	_, uniqueDeps, err := buildMavenDependencyTreeOffline(params)
+	assert.NoError(t, err)
+	assert.Contains(t, uniqueDeps, GavPackageTypeIdentifier+"com.example:lib-c:5.10.1")
+}
+
+func TestParseMavenVersionRange(t *testing.T) {
+	testCases := []struct {
+		versionRange string
+		expected     []mavenVersionInterval
+	}{
+		{versionRange: "[1.0,2.0)", expected: []mavenVersionInterval{{lower: "1.0", lowerInclusive: true, upper: "2.0"}}},
+		{versionRange: "[1.5]", expected: []mavenVersionInterval{{lower: "1.5", lowerInclusive: true, upper: "1.5", upperInclusive: true}}},
+		{versionRange: "(,1.0], [1.2,)", expected: []mavenVersionInterval{{upper: "1.0", upperInclusive: true}, {lower: "1.2", lowerInclusive: true}}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.versionRange, func(t *testing.T) {
+			intervals, err := parseMavenVersionRange(testCase.versionRange)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, intervals)
+		})
+	}
+	for _, invalidRange := range []string{"1.0", "[1.0,2.0", "(1.0)"} {
+		_, err := parseMavenVersionRange(invalidRange)
+		assert.Error(t, err, invalidRange)
+	}
+}
+
+func TestGetHighestVersionInRange(t *testing.T) {
+	versions := []string{"0.9", "1.0", "1.2", "1.10", "2.0"}
+	intervals, err := parseMavenVersionRange("(,1.0], [1.2,1.10)")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2", getHighestVersionInRange(intervals, versions))
+	intervals, err = parseMavenVersionRange("(1.0,1.2)")
+	assert.NoError(t, err)
+	assert.Empty(t, getHighestVersionInRange(intervals, versions))
+}
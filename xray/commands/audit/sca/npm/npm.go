@@ -1,14 +1,23 @@
 package npm
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
 	biutils "github.com/jfrog/build-info-go/build/utils"
 	buildinfo "github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/npm"
-	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"golang.org/x/exp/slices"
@@ -18,23 +27,21 @@ const (
 	ignoreScriptsFlag = "--ignore-scripts"
 )
 
-func BuildDependencyTree(params utils.AuditParams) (dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
-	currentDir, err := coreutils.GetWorkingDirectory()
-	if err != nil {
-		return
-	}
+// Returns the dependency trees with the scopes and the git-sourced dependencies that were recorded while building them.
+// The npm commands are run by build-info-go, so they can't be killed once the given context is done, but the build stops before running them.
+func BuildDependencyTree(ctx context.Context, params utils.AuditParams, workingDir string) (dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *utils.DependencyTreesInfo, err error) {
 	npmVersion, npmExecutablePath, err := biutils.GetNpmVersionAndExecPath(log.Logger)
 	if err != nil {
 		return
 	}
-	packageInfo, err := biutils.ReadPackageInfoFromPackageJsonIfExists(currentDir, npmVersion)
+	packageInfo, err := biutils.ReadPackageInfoFromPackageJsonIfExists(workingDir, npmVersion)
 	if err != nil {
 		return
 	}
 
 	treeDepsParam := createTreeDepsParam(params)
 
-	restoreNpmrcFunc, err := configNpmResolutionServerIfNeeded(params)
+	restoreNpmrcFunc, err := configNpmResolutionServerIfNeeded(params, workingDir)
 	if err != nil {
 		err = fmt.Errorf("failed while configuring a resolution server: %s", err.Error())
 		return
@@ -46,23 +53,139 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTrees []*xrayUtils
 	}()
 
 	// Calculate npm dependencies
-	dependenciesMap, err := biutils.CalculateDependenciesMap(npmExecutablePath, currentDir, packageInfo.BuildInfoModuleId(), treeDepsParam, log.Logger)
+	if err = sca.CheckContext(ctx); err != nil {
+		return
+	}
+	treesInfo = utils.NewDependencyTreesInfo(params != nil && params.LogCommands())
+	if err = recordNpmTreeCommands(treesInfo, npmExecutablePath, workingDir, treeDepsParam); err != nil {
+		return
+	}
+	dependenciesMap, err := biutils.CalculateDependenciesMap(npmExecutablePath, workingDir, packageInfo.BuildInfoModuleId(), treeDepsParam, log.Logger)
 	if err != nil {
 		log.Info("Used npm version:", npmVersion.GetVersion())
 		return
 	}
+	var excludedPackages []string
+	if params != nil {
+		if excludedPackages, err = GetScanExcludedPackages(workingDir, params.ScanExclusions()); err != nil {
+			return
+		}
+	}
+	// npm ls doesn't report whether a package is a peer dependency, it's taken from the package-lock.json file.
+	packageLockEntries, err := readPackageLockEntries(workingDir)
+	if err != nil {
+		return
+	}
+	excludeOptional, excludePeer := getNpmDependencyTypesExclusions(params)
+	excludeDev := params != nil && params.ExcludeDevDependencies()
 	var dependenciesList []buildinfo.Dependency
 	for _, dependency := range dependenciesMap {
+		packageLockEntry := packageLockEntries[dependency.Id]
+		optional := dependency.Optional || packageLockEntry.Optional
+		if (excludeOptional && optional) || (excludePeer && packageLockEntry.Peer) {
+			log.Debug(fmt.Sprintf("Excluding '%s' from the dependency tree, it's installed only as an optional or a peer dependency.", dependency.Id))
+			continue
+		}
+		if excludeDev && isDevOnlyDependency(dependency.Scopes) {
+			log.Debug(fmt.Sprintf("Excluding '%s' from the dependency tree, it's installed only as a dev dependency.", dependency.Id))
+			continue
+		}
 		dependenciesList = append(dependenciesList, dependency.Dependency)
+		treesInfo.AddDependencyScopes(utils.NpmPackageTypeIdentifier+dependency.Id, getNpmDependencyScopes(dependency.Scopes, optional, packageLockEntry.Peer)...)
+		if isGitResolved(packageLockEntry.Resolved) {
+			treesInfo.AddGitSourcedDependency(utils.NpmPackageTypeIdentifier+dependency.Id, packageLockEntry.Resolved)
+		}
 	}
 	// Parse the dependencies into Xray dependency tree format
-	dependencyTree, uniqueDeps := parseNpmDependenciesList(dependenciesList, packageInfo)
+	dependencyTree, uniqueDeps := parseNpmDependenciesList(removeExcludedPackages(dependenciesList, excludedPackages), packageInfo)
 	dependencyTrees = []*xrayUtils.GraphNode{dependencyTree}
 	return
 }
 
+// The npm commands are executed by build-info-go, so the commands it's going to execute are recorded according to the same conditions it checks.
+// If the node_modules directory is used, only 'npm ls' is executed. Otherwise, the package-lock.json file is installed first if it's missing or outdated.
+func recordNpmTreeCommands(treesInfo *utils.DependencyTreesInfo, npmExecutablePath, workingDir string, treeDepsParam biutils.NpmTreeDepListParam) error {
+	lsArgs := append([]string{"ls"}, treeDepsParam.Args...)
+	lsArgs = append(lsArgs, "--json", "--all", "--long")
+	nodeModulesExist, err := fileutils.IsDirExists(filepath.Join(workingDir, "node_modules"), false)
+	if err != nil {
+		return err
+	}
+	if nodeModulesExist && !treeDepsParam.IgnoreNodeModules {
+		treesInfo.RecordExecutedCommand(npmExecutablePath, lsArgs...)
+		return nil
+	}
+	installRequired, err := isPackageLockInstallRequired(workingDir, treeDepsParam.OverwritePackageLock)
+	if err != nil {
+		return err
+	}
+	if installRequired {
+		argsWithoutInstallArgs := append(slices.Clone(treeDepsParam.Args), "--package-lock-only")
+		installArgs := slices.Clone(argsWithoutInstallArgs)
+		for _, arg := range treeDepsParam.InstallCommandArgs {
+			// The 'install' command and the arguments that are already included are omitted.
+			if arg != "install" && !slices.Contains(argsWithoutInstallArgs, arg) {
+				installArgs = append(installArgs, arg)
+			}
+		}
+		treesInfo.RecordExecutedCommand(npmExecutablePath, append([]string{"install"}, installArgs...)...)
+	}
+	treesInfo.RecordExecutedCommand(npmExecutablePath, append(lsArgs, "--package-lock-only")...)
+	return nil
+}
+
+// Returns whether the package-lock.json file is installed before the dependencies are listed, because it's missing, or because it should be overwritten and package.json was modified after it.
+func isPackageLockInstallRequired(workingDir string, overwritePackageLock bool) (bool, error) {
+	packageLockInfo, err := os.Stat(filepath.Join(workingDir, "package-lock.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	if !overwritePackageLock {
+		return false, nil
+	}
+	packageJsonInfo, err := os.Stat(filepath.Join(workingDir, "package.json"))
+	if err != nil {
+		// build-info-go doesn't install the package-lock.json file in this case.
+		return false, nil
+	}
+	return packageJsonInfo.ModTime().After(packageLockInfo.ModTime()), nil
+}
+
+// Returns the scopes of an npm dependency, from the scopes that npm reported for it.
+// Besides the prod/dev scopes, npm reports the scopes of scoped packages ('@scope'), which are not dependency scopes and are dropped.
+func getNpmDependencyScopes(npmScopes []string, optional, peer bool) (scopes []string) {
+	for _, scope := range npmScopes {
+		if scope == utils.ProdScope || scope == utils.DevScope {
+			scopes = append(scopes, scope)
+		}
+	}
+	if optional {
+		scopes = append(scopes, utils.OptionalScope)
+	}
+	if peer {
+		scopes = append(scopes, utils.PeerScope)
+	}
+	return
+}
+
+// Returns whether the dependency is required only by the dev dependencies, according to the scopes that npm reported for it.
+func isDevOnlyDependency(npmScopes []string) bool {
+	return slices.Contains(npmScopes, utils.DevScope) && !slices.Contains(npmScopes, utils.ProdScope)
+}
+
+// Returns whether the optional and the peer dependencies should be excluded from the dependency tree.
+func getNpmDependencyTypesExclusions(params utils.AuditParams) (excludeOptional, excludePeer bool) {
+	if npmParams, ok := params.(utils.AuditNpmParams); ok {
+		return npmParams.NpmExcludeOptionalDependencies(), npmParams.NpmExcludePeerDependencies()
+	}
+	return
+}
+
 // Generates a .npmrc file to configure an Artifactory server as the resolver server.
-func configNpmResolutionServerIfNeeded(params utils.AuditParams) (restoreNpmrcFunc func() error, err error) {
+func configNpmResolutionServerIfNeeded(params utils.AuditParams, workingDir string) (restoreNpmrcFunc func() error, err error) {
 	if params == nil {
 		err = fmt.Errorf("got empty params upon configuring resolution server")
 		return
@@ -76,7 +199,7 @@ func configNpmResolutionServerIfNeeded(params utils.AuditParams) (restoreNpmrcFu
 		return
 	}
 
-	npmCmd := npm.NewNpmCommand("install", false).SetServerDetails(serverDetails)
+	npmCmd := npm.NewNpmCommand("install", false).SetServerDetails(serverDetails).SetWorkingDirectory(workingDir)
 	if err = npmCmd.PreparePrerequisites(depsRepo); err != nil {
 		return
 	}
@@ -138,3 +261,84 @@ func appendUniqueChild(children []string, candidateDependency string) []string {
 	}
 	return append(children, candidateDependency)
 }
+
+// The sections of package.json in which the direct dependencies are declared.
+var packageJsonDependenciesSections = []string{"dependencies", "devDependencies", "optionalDependencies", "peerDependencies"}
+
+// Returns the locations of the declarations of the given direct dependencies in the package.json file in the given directory.
+// Dependencies that are not declared in the file are omitted.
+func GetDirectDependenciesLocations(workingDir string, directDependencies []string) (map[string]utils.DescriptorLocation, error) {
+	packageJsonPath := filepath.Join(workingDir, "package.json")
+	content, err := os.ReadFile(packageJsonPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	declarationsLines, err := getPackageJsonDeclarationsLines(content)
+	if err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse '%s': %s", packageJsonPath, err.Error())
+	}
+	locations := map[string]utils.DescriptorLocation{}
+	for _, dependencyId := range directDependencies {
+		// The ID format is 'npm://name:version', where the name may start with a scope ('@scope/name').
+		name := strings.TrimPrefix(dependencyId, utils.NpmPackageTypeIdentifier)
+		if separator := strings.LastIndex(name, ":"); separator > 0 {
+			name = name[:separator]
+		}
+		if line, exists := declarationsLines[name]; exists {
+			locations[dependencyId] = utils.DescriptorLocation{File: packageJsonPath, Line: line}
+		}
+	}
+	return locations, nil
+}
+
+// Maps the names of the dependencies declared in the given package.json content to the numbers of their declaring lines.
+// If a dependency is declared in several sections, the first declaration is used.
+func getPackageJsonDeclarationsLines(content []byte) (map[string]int, error) {
+	type jsonContainer struct {
+		isObject   bool
+		expectsKey bool
+		currentKey string
+	}
+	declarationsLines := map[string]int{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	var containers []*jsonContainer
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return declarationsLines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var parent *jsonContainer
+		if len(containers) > 0 {
+			parent = containers[len(containers)-1]
+		}
+		if key, isString := token.(string); isString && parent != nil && parent.isObject && parent.expectsKey {
+			parent.currentKey, parent.expectsKey = key, false
+			// A key of the second level object, under one of the dependencies sections of the root object.
+			if len(containers) == 2 && slices.Contains(packageJsonDependenciesSections, containers[0].currentKey) {
+				if _, exists := declarationsLines[key]; !exists {
+					declarationsLines[key] = bytes.Count(content[:decoder.InputOffset()], []byte("\n")) + 1
+				}
+			}
+			continue
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			containers = append(containers, &jsonContainer{isObject: token == json.Delim('{'), expectsKey: true})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			containers = containers[:len(containers)-1]
+			if len(containers) > 0 {
+				parent = containers[len(containers)-1]
+			} else {
+				parent = nil
+			}
+		}
+		// A value was read, so the next token of the parent object is a key.
+		if parent != nil && parent.isObject {
+			parent.expectsKey = true
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package npm
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"os"
+	"path/filepath"
 	"testing"
 
 	biutils "github.com/jfrog/build-info-go/build/utils"
@@ -109,14 +111,207 @@ func TestParseNpmDependenciesList(t *testing.T) {
 
 }
 
+func TestGetNpmDependencyScopes(t *testing.T) {
+	testCases := []struct {
+		npmScopes      []string
+		optional       bool
+		peer           bool
+		expectedScopes []string
+	}{
+		{npmScopes: []string{"prod"}, expectedScopes: []string{utils.ProdScope}},
+		{npmScopes: []string{"dev", "@types"}, expectedScopes: []string{utils.DevScope}},
+		{npmScopes: []string{"prod", "dev"}, optional: true, expectedScopes: []string{utils.ProdScope, utils.DevScope, utils.OptionalScope}},
+		{npmScopes: []string{"prod"}, peer: true, expectedScopes: []string{utils.ProdScope, utils.PeerScope}},
+		{npmScopes: []string{"@next"}, expectedScopes: nil},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedScopes, getNpmDependencyScopes(testCase.npmScopes, testCase.optional, testCase.peer))
+	}
+}
+
+func TestBuildDependencyTreeOptionalAndPeerDependencies(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "npm-optional-peer-project")
+	defer cleanUp()
+	allDependencies := []string{
+		"npm://npm-optional-peer-project:1.0.0",
+		"npm://lodash:4.17.21",
+		"npm://styled-jsx:5.1.1",
+		"npm://client-only:0.0.1",
+		// Optional dependency
+		"npm://fsevents:2.3.3",
+		// Peer dependency of styled-jsx and its dependencies
+		"npm://react:18.2.0",
+		"npm://loose-envify:1.4.0",
+		"npm://js-tokens:4.0.0",
+	}
+
+	t.Run("Included by default", func(t *testing.T) {
+		params := &utils.AuditBasicParams{}
+		_, uniqueDeps, treesInfo, err := BuildDependencyTree(context.Background(), params, projectDir)
+		assert.NoError(t, err)
+		// The optional peer dependencies of styled-jsx that aren't installed are not included.
+		assert.ElementsMatch(t, allDependencies, uniqueDeps)
+		scopes := treesInfo.DependenciesScopes
+		assert.ElementsMatch(t, []string{utils.ProdScope, utils.OptionalScope}, scopes["npm://fsevents:2.3.3"])
+		assert.ElementsMatch(t, []string{utils.ProdScope, utils.PeerScope}, scopes["npm://react:18.2.0"])
+		assert.ElementsMatch(t, []string{utils.ProdScope, utils.PeerScope}, scopes["npm://js-tokens:4.0.0"])
+		assert.Equal(t, []string{utils.ProdScope}, scopes["npm://styled-jsx:5.1.1"])
+	})
+
+	t.Run("Excluded", func(t *testing.T) {
+		params := utils.AuditNpmParams{AuditParams: &utils.AuditBasicParams{}}.
+			SetNpmExcludeOptionalDependencies(true).
+			SetNpmExcludePeerDependencies(true)
+		_, uniqueDeps, _, err := BuildDependencyTree(context.Background(), params, projectDir)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, allDependencies[:4], uniqueDeps)
+	})
+}
+
+func TestBuildDependencyTreeDevDependencies(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "npm-dev-project")
+	defer cleanUp()
+	allDependencies := []string{
+		"npm://npm-dev-project:1.0.0",
+		"npm://lodash:4.17.21",
+		// Dev dependency and its dependencies
+		"npm://is-odd:3.0.1",
+		"npm://is-number:6.0.0",
+	}
+
+	t.Run("Included by default", func(t *testing.T) {
+		_, uniqueDeps, _, err := BuildDependencyTree(context.Background(), &utils.AuditBasicParams{}, projectDir)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, allDependencies, uniqueDeps)
+	})
+
+	t.Run("Excluded", func(t *testing.T) {
+		dependencyTree, uniqueDeps, _, err := BuildDependencyTree(context.Background(), (&utils.AuditBasicParams{}).SetExcludeDevDependencies(true), projectDir)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, allDependencies[:2], uniqueDeps)
+		if assert.Len(t, dependencyTree, 1) {
+			assert.Len(t, dependencyTree[0].Nodes, 1)
+			sca.GetAndAssertNode(t, dependencyTree[0].Nodes, "lodash:4.17.21")
+		}
+	})
+}
+
+func TestBuildDependencyTreeVersionRanges(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "npm-range-project")
+	defer cleanUp()
+
+	dependencyTree, uniqueDeps, _, err := BuildDependencyTree(context.Background(), &utils.AuditBasicParams{}, projectDir)
+	assert.NoError(t, err)
+	// The ranges of the package.json are resolved to the versions that are locked in the package-lock.json.
+	assert.ElementsMatch(t, []string{
+		"npm://npm-range-project:1.0.0",
+		"npm://debug:4.3.4",
+		"npm://ms:2.1.2",
+		"npm://lodash:4.17.21",
+		"npm://ms:2.1.3",
+	}, uniqueDeps)
+	for _, dependency := range uniqueDeps {
+		assert.False(t, sca.IsVersionRange(dependency), dependency)
+	}
+	if assert.Len(t, dependencyTree, 1) {
+		debug := sca.GetAndAssertNode(t, dependencyTree[0].Nodes, "debug:4.3.4")
+		sca.GetAndAssertNode(t, debug.Nodes, "ms:2.1.2")
+	}
+}
+
+func TestBuildDependencyTreeGitSourcedDependencies(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "npm-git-project")
+	defer cleanUp()
+
+	params := &utils.AuditBasicParams{}
+	dependencyTree, uniqueDeps, treesInfo, err := BuildDependencyTree(context.Background(), params, projectDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"npm://npm-git-project:1.0.0", "npm://is-number:7.0.0", "npm://lodash:4.17.21"}, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		sca.GetAndAssertNode(t, dependencyTree[0].Nodes, "is-number:7.0.0")
+	}
+	// The dependency that was fetched from a git repository is flagged with its git URL.
+	assert.Equal(t, map[string]string{
+		"npm://is-number:7.0.0": "git+ssh://git@github.com/jonschlinkert/is-number.git#98e8ff1da1a89f93d1397a24d7413ed15421c139",
+	}, treesInfo.GitSourcedDependencies)
+}
+
 func TestIgnoreScripts(t *testing.T) {
 	// Create and change directory to test workspace
-	_, cleanUp := sca.CreateTestWorkspace(t, "npm-scripts")
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "npm-scripts")
 	defer cleanUp()
 
 	// The package.json file contain a postinstall script running an "exit 1" command.
 	// Without the "--ignore-scripts" flag, the test will fail.
 	params := &utils.AuditBasicParams{}
-	_, _, err := BuildDependencyTree(params)
+	_, _, _, err := BuildDependencyTree(context.Background(), params, projectDir)
+	assert.NoError(t, err)
+}
+
+func TestRecordNpmTreeCommands(t *testing.T) {
+	tempDirPath, createTempDirCallback := tests.CreateTempDirWithCallbackAndAssert(t)
+	defer createTempDirCallback()
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDirPath, "package.json"), []byte("{}"), 0644))
+	treeDepsParam := biutils.NpmTreeDepListParam{Args: []string{"--ignore-scripts"}, InstallCommandArgs: []string{"install", "--ignore-scripts", "--legacy-peer-deps"}}
+	recordCommands := func() []string {
+		treesInfo := utils.NewDependencyTreesInfo(true)
+		assert.NoError(t, recordNpmTreeCommands(treesInfo, "npm", tempDirPath, treeDepsParam))
+		return treesInfo.ExecutedCommands
+	}
+
+	// The package-lock.json file is missing, so it's installed before the dependencies are listed.
+	assert.Equal(t, []string{
+		"npm install --ignore-scripts --package-lock-only --legacy-peer-deps",
+		"npm ls --ignore-scripts --json --all --long --package-lock-only",
+	}, recordCommands())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tempDirPath, "package-lock.json"), []byte("{}"), 0644))
+	assert.Equal(t, []string{"npm ls --ignore-scripts --json --all --long --package-lock-only"}, recordCommands())
+
+	assert.NoError(t, os.Mkdir(filepath.Join(tempDirPath, "node_modules"), 0755))
+	assert.Equal(t, []string{"npm ls --ignore-scripts --json --all --long"}, recordCommands())
+}
+
+func TestRemoveExcludedPackages(t *testing.T) {
+	dependencies := []buildinfo.Dependency{
+		{Id: "app:1.0.0", RequestedBy: [][]string{{"root:1.0.0"}}},
+		{Id: "vendored-lib:1.0.0", RequestedBy: [][]string{{"root:1.0.0"}}},
+		// Required only through the excluded package
+		{Id: "lodash:4.17.21", RequestedBy: [][]string{{"vendored-lib:1.0.0", "root:1.0.0"}}},
+		// Required through both packages
+		{Id: "minimist:1.2.8", RequestedBy: [][]string{{"app:1.0.0", "root:1.0.0"}, {"vendored-lib:1.0.0", "root:1.0.0"}}},
+	}
+	assert.Equal(t, dependencies, removeExcludedPackages(dependencies, nil))
+	assert.Equal(t, []buildinfo.Dependency{
+		{Id: "app:1.0.0", RequestedBy: [][]string{{"root:1.0.0"}}},
+		{Id: "minimist:1.2.8", RequestedBy: [][]string{{"app:1.0.0", "root:1.0.0"}}},
+	}, removeExcludedPackages(dependencies, []string{"vendored-lib"}))
+}
+
+func TestGetDirectDependenciesLocations(t *testing.T) {
+	tempDirPath, cleanUp := sca.CreateTestWorkspace(t, "npm-project")
+	defer cleanUp()
+	packageJsonPath := filepath.Join(tempDirPath, "package.json")
+	locations, err := GetDirectDependenciesLocations(tempDirPath, []string{"npm://underscore:1.13.6", "npm://lightweight:0.1.0", "npm://undeclared:1.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]utils.DescriptorLocation{
+		"npm://lightweight:0.1.0": {File: packageJsonPath, Line: 12},
+		"npm://underscore:1.13.6": {File: packageJsonPath, Line: 13},
+	}, locations)
+}
+
+func TestGetPackageJsonDeclarationsLines(t *testing.T) {
+	packageJson := `{
+  "name": "dependencies",
+  "config": {"dependencies": {"nested": "1.0.0"}},
+  "keywords": ["dependencies", {"a": 1}],
+  "dependencies": {
+    "@scope/name": "^2.0.0",
+    "lodash": "4.17.21"
+  },
+  "devDependencies": {"jest": "^29.0.0", "lodash": "4.17.20"}
+}`
+	lines, err := getPackageJsonDeclarationsLines([]byte(packageJson))
 	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"@scope/name": 6, "lodash": 7, "jest": 9}, lines)
 }
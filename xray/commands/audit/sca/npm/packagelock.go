@@ -0,0 +1,66 @@
+package npm
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	packageLockFileName = "package-lock.json"
+	nodeModulesPrefix   = "node_modules/"
+)
+
+// The properties of an installed package, as recorded in the 'packages' section of package-lock.json (lockfileVersion 2 and above).
+type packageLockEntry struct {
+	Version string `json:"version"`
+	// The URL the package was fetched from. Packages that are fetched from git repositories have their git URLs, with the commit.
+	Resolved string `json:"resolved"`
+	// The package is installed only because it is an optional dependency, directly or transitively.
+	Optional bool `json:"optional"`
+	// The package is installed only because it is a peer dependency, directly or transitively.
+	Peer bool `json:"peer"`
+}
+
+// Returns the entries of the installed packages in the package-lock.json file in the given directory, by their IDs (name:version).
+// A package that is installed in several locations is optional or peer only if all its installations are.
+// Returns an empty map if the file doesn't exist, or was created by npm 6 and has no 'packages' section.
+func readPackageLockEntries(workingDir string) (map[string]packageLockEntry, error) {
+	entries := map[string]packageLockEntry{}
+	content, err := os.ReadFile(filepath.Join(workingDir, packageLockFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entries, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	var packageLock struct {
+		Packages map[string]packageLockEntry `json:"packages"`
+	}
+	if err = json.Unmarshal(content, &packageLock); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse %s: %s", packageLockFileName, err.Error())
+	}
+	for path, entry := range packageLock.Packages {
+		// The root project and the workspaces are not in node_modules.
+		nameIndex := strings.LastIndex(path, nodeModulesPrefix)
+		if nameIndex < 0 || entry.Version == "" {
+			continue
+		}
+		id := path[nameIndex+len(nodeModulesPrefix):] + ":" + entry.Version
+		if existingEntry, exists := entries[id]; exists {
+			entry.Optional = entry.Optional && existingEntry.Optional
+			entry.Peer = entry.Peer && existingEntry.Peer
+		}
+		entries[id] = entry
+	}
+	return entries, nil
+}
+
+// Returns whether the given resolved URL of a package is a git URL, such as 'git+ssh://git@github.com/owner/repo.git#<commit>'.
+func isGitResolved(resolved string) bool {
+	return strings.HasPrefix(resolved, "git+") || strings.HasPrefix(resolved, "git://")
+}
@@ -0,0 +1,81 @@
+package npm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const packageJsonFileName = "package.json"
+
+// Returns the names of the packages in the working directory, such as workspaces or vendored packages, whose directories match the scan exclusions.
+func GetScanExcludedPackages(workingDir string, scanExclusions []string) (excludedPackages []string, err error) {
+	excludedDirs, err := sca.GetScanExcludedModulesDirs(workingDir, scanExclusions, packageJsonFileName)
+	if err != nil {
+		return
+	}
+	for _, dir := range excludedDirs {
+		packageJsonPath := filepath.Join(dir, packageJsonFileName)
+		content, err := os.ReadFile(packageJsonPath)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		var packageJson struct {
+			Name string `json:"name"`
+		}
+		if err = json.Unmarshal(content, &packageJson); err != nil {
+			return nil, errorutils.CheckErrorf("failed to parse '%s': %s", packageJsonPath, err.Error())
+		}
+		if packageJson.Name != "" {
+			log.Debug(fmt.Sprintf("Excluding the '%s' package at '%s' from the scan.", packageJson.Name, dir))
+			excludedPackages = append(excludedPackages, packageJson.Name)
+		}
+	}
+	return
+}
+
+// Removes the direct dependencies with the given names, with the dependencies that are required only through them.
+// The paths in which each dependency is requested start with the requesting dependency, and end with the root project.
+func removeExcludedPackages(dependencies []buildinfo.Dependency, excludedPackages []string) (keptDependencies []buildinfo.Dependency) {
+	if len(excludedPackages) == 0 {
+		return dependencies
+	}
+	excluded := datastructures.MakeSet[string]()
+	for _, excludedPackage := range excludedPackages {
+		excluded.Add(excludedPackage)
+	}
+	for _, dependency := range dependencies {
+		var requestedBy [][]string
+		for _, path := range dependency.RequestedBy {
+			// The direct dependency of the path is the dependency itself if it's requested by the root project, or the requesting dependency before the root project.
+			directDependency := dependency.Id
+			if len(path) > 1 {
+				directDependency = path[len(path)-2]
+			}
+			if !excluded.Exists(getPackageName(directDependency)) {
+				requestedBy = append(requestedBy, path)
+			}
+		}
+		if len(requestedBy) > 0 {
+			dependency.RequestedBy = requestedBy
+			keptDependencies = append(keptDependencies, dependency)
+		}
+	}
+	return
+}
+
+// Returns the name of the package with the given ID (name:version).
+func getPackageName(packageId string) string {
+	if separator := strings.LastIndex(packageId, ":"); separator > 0 {
+		return packageId[:separator]
+	}
+	return packageId
+}
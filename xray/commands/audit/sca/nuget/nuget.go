@@ -1,10 +1,12 @@
 package nuget
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	bidotnet "github.com/jfrog/build-info-go/build/utils/dotnet"
 	"github.com/jfrog/build-info-go/build/utils/dotnet/solution"
+	"github.com/jfrog/build-info-go/build/utils/dotnet/solution/project"
 	"github.com/jfrog/build-info-go/entities"
 	biutils "github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/gofrog/datastructures"
@@ -12,14 +14,16 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -34,12 +38,10 @@ const (
 	globalPackagesNotFoundErrorMessage = "could not find global packages path at:"
 )
 
-func BuildDependencyTree(params utils.AuditParams) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
-	wd, err := os.Getwd()
-	if err != nil {
-		return
-	}
-	sol, err := solution.Load(wd, "", log.Logger)
+// Returns the dependency tree with the restore command, if it had to be executed.
+func BuildDependencyTree(ctx context.Context, params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *utils.DependencyTreesInfo, err error) {
+	treesInfo = utils.NewDependencyTreesInfo(params.LogCommands())
+	sol, err := solution.Load(workingDir, "", log.Logger)
 	if err != nil && !strings.Contains(err.Error(), globalPackagesNotFoundErrorMessage) {
 		// In older NuGet projects that utilize NuGet Cli and package.config, if the project is not installed, the solution.Load function raises an error because it cannot find global package paths.
 		// This issue is resolved by executing the 'nuget restore' command followed by running solution.Load again. Therefore, in this scenario, we need to proceed with this process.
@@ -48,7 +50,7 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTree []*xrayUtils.
 
 	if isInstallRequired(params, sol) {
 		log.Info("Dependencies sources were not detected nor 'install' command provided. Running 'restore' command")
-		sol, err = runDotnetRestoreAndLoadSolution(params, wd)
+		sol, err = runDotnetRestoreAndLoadSolution(ctx, params, workingDir, treesInfo)
 		if err != nil {
 			return
 		}
@@ -58,6 +60,9 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTree []*xrayUtils.
 	if err != nil {
 		return
 	}
+	if err = removeExcludedProjects(buildInfo, sol.GetProjects(), workingDir, params.ScanExclusions()); err != nil {
+		return
+	}
 	dependencyTree, uniqueDeps = parseNugetDependencyTree(buildInfo)
 	return
 }
@@ -73,7 +78,7 @@ func isInstallRequired(params utils.AuditParams, sol solution.Solution) bool {
 
 // Generates a temporary duplicate of the project to execute the 'install' command without impacting the original directory and establishing the JFrog configuration file for Artifactory resolution
 // Additionally, re-loads the project's Solution so the dependencies sources will be identified
-func runDotnetRestoreAndLoadSolution(params utils.AuditParams, originalWd string) (sol solution.Solution, err error) {
+func runDotnetRestoreAndLoadSolution(ctx context.Context, params utils.AuditParams, originalWd string, treesInfo *utils.DependencyTreesInfo) (sol solution.Solution, err error) {
 	// Creating a temporary copy of the project in order to run 'install' command without effecting the original directory + creating the jfrog config for artifactory resolution
 	tmpWd, err := fileutils.CreateTempDir()
 	if err != nil {
@@ -124,7 +129,7 @@ func runDotnetRestoreAndLoadSolution(params utils.AuditParams, originalWd string
 		installCommandArgs = append(installCommandArgs, toolType.GetTypeFlagPrefix()+"configfile", configFile.Name())
 	}
 
-	err = runDotnetRestore(tmpWd, params, toolType, installCommandArgs)
+	err = runDotnetRestore(ctx, tmpWd, params, toolType, installCommandArgs, treesInfo)
 	if err != nil {
 		return
 	}
@@ -191,7 +196,7 @@ func getProjectConfigurationFilesPaths(wd string) (projectConfigFilesPaths []str
 	return
 }
 
-func runDotnetRestore(wd string, params utils.AuditParams, toolType bidotnet.ToolchainType, commandExtraArgs []string) (err error) {
+func runDotnetRestore(ctx context.Context, wd string, params utils.AuditParams, toolType bidotnet.ToolchainType, commandExtraArgs []string, treesInfo *utils.DependencyTreesInfo) (err error) {
 	var completeCommandArgs []string
 	if len(params.InstallCommandArgs()) > 0 {
 		// If the user has specified an 'install' command, we execute the command that has been provided.
@@ -203,7 +208,8 @@ func runDotnetRestore(wd string, params utils.AuditParams, toolType bidotnet.Too
 
 	// We include the flag that allows resolution from an Artifactory server, if it exists.
 	completeCommandArgs = append(completeCommandArgs, commandExtraArgs...)
-	command := exec.Command(completeCommandArgs[0], completeCommandArgs[1:]...)
+	treesInfo.RecordExecutedCommand(completeCommandArgs[0], completeCommandArgs[1:]...)
+	command := sca.CreateCommand(ctx, completeCommandArgs[0], completeCommandArgs[1:]...)
 	command.Dir = wd
 	output, err := command.CombinedOutput()
 	if err != nil {
@@ -212,6 +218,33 @@ func runDotnetRestore(wd string, params utils.AuditParams, toolType bidotnet.Too
 	return
 }
 
+// Remove the modules of the projects that are located in paths matching the scan exclusions, so their dependencies are not scanned.
+func removeExcludedProjects(buildInfo *entities.BuildInfo, projects []project.Project, wd string, scanExclusions []string) error {
+	if len(scanExclusions) == 0 {
+		return nil
+	}
+	excludePattern := fspatterns.PrepareExcludePathPattern(scanExclusions, clientutils.WildCardPattern, true)
+	excludedModules := datastructures.MakeSet[string]()
+	for _, currProject := range projects {
+		excluded, err := regexp.MatchString(excludePattern, strings.TrimPrefix(currProject.RootPath(), wd))
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		if excluded {
+			log.Debug(fmt.Sprintf("Excluding the '%s' project at '%s' from the scan.", currProject.Name(), currProject.RootPath()))
+			excludedModules.Add(currProject.Name())
+		}
+	}
+	var modules []entities.Module
+	for _, module := range buildInfo.Modules {
+		if !excludedModules.Exists(module.Id) {
+			modules = append(modules, module)
+		}
+	}
+	buildInfo.Modules = modules
+	return nil
+}
+
 func parseNugetDependencyTree(buildInfo *entities.BuildInfo) (nodes []*xrayUtils.GraphNode, allUniqueDeps []string) {
 	uniqueDepsSet := datastructures.MakeSet[string]()
 	for _, module := range buildInfo.Modules {
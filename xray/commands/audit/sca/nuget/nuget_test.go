@@ -1,8 +1,10 @@
 package nuget
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/jfrog/build-info-go/build/utils/dotnet/solution"
+	"github.com/jfrog/build-info-go/build/utils/dotnet/solution/project"
 	"github.com/jfrog/build-info-go/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
 	xrayUtils2 "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
@@ -138,9 +140,26 @@ func TestRunDotnetRestoreAndLoadSolution(t *testing.T) {
 		assert.Empty(t, sol.GetDependenciesSources())
 
 		params := &xrayUtils2.AuditBasicParams{}
-		sol, err = runDotnetRestoreAndLoadSolution(params, tempDirPath)
+		sol, err = runDotnetRestoreAndLoadSolution(context.Background(), params, tempDirPath, xrayUtils2.NewDependencyTreesInfo(false))
 		assert.NoError(t, err)
 		assert.NotEmpty(t, sol.GetProjects())
 		assert.NotEmpty(t, sol.GetDependenciesSources())
 	}
 }
+
+func TestRemoveExcludedProjects(t *testing.T) {
+	wd := filepath.Join("root", "solution")
+	projects := []project.Project{
+		project.CreateProject("app", filepath.Join(wd, "app")),
+		project.CreateProject("vendored-lib", filepath.Join(wd, "vendor", "lib")),
+	}
+	buildInfo := &entities.BuildInfo{Modules: []entities.Module{{Id: "app"}, {Id: "vendored-lib"}}}
+
+	// No scan exclusions, all the projects are kept
+	assert.NoError(t, removeExcludedProjects(buildInfo, projects, wd, nil))
+	assert.Len(t, buildInfo.Modules, 2)
+
+	// The vendored project is excluded from the scan
+	assert.NoError(t, removeExcludedProjects(buildInfo, projects, wd, []string{"*vendor*"}))
+	assert.Equal(t, []entities.Module{{Id: "app"}}, buildInfo.Modules)
+}
@@ -0,0 +1,61 @@
+package sca
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	// Semantic versioning pre-release, such as 1.0.0-beta.1 or 2.0.0-rc1.
+	semverPrereleasePattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+-[0-9A-Za-z.-]+`)
+	// PEP 440 pre-release and development release, such as 1.0a1, 1.0.0rc2 or 1.0.dev3.
+	pep440PrereleasePattern = regexp.MustCompile(`(?i)^v?\d+(\.\d+)*[-_.]?(a|alpha|b|beta|c|rc|pre|preview|dev)\d*`)
+)
+
+// Returns true if the given version is clearly a pre-release version of the technology's ecosystem:
+//   - Maven, Gradle: versions with the '-SNAPSHOT' suffix, such as 1.0.0-SNAPSHOT.
+//   - npm, Yarn, NuGet: semantic versions with a pre-release part, such as 1.0.0-beta.1.
+//   - Go: semantic versions with a pre-release part, such as v1.0.0-rc.1. Pseudo-versions are not pre-releases, since they are commonly used for untagged modules.
+//   - pip, Pipenv, Poetry: PEP 440 pre-releases and development releases, such as 1.0a1, 1.0rc1 or 1.0.dev1.
+func IsPrereleaseVersion(tech coreutils.Technology, version string) bool {
+	switch tech {
+	case coreutils.Maven, coreutils.Gradle:
+		return strings.HasSuffix(strings.ToUpper(version), "-SNAPSHOT")
+	case coreutils.Npm, coreutils.Yarn, coreutils.Nuget, coreutils.Dotnet:
+		return semverPrereleasePattern.MatchString(version)
+	case coreutils.Go:
+		return semver.Prerelease(version) != "" && !module.IsPseudoVersion(version)
+	case coreutils.Pip, coreutils.Pipenv, coreutils.Poetry:
+		return pep440PrereleasePattern.MatchString(version)
+	default:
+		return false
+	}
+}
+
+// Remove the pre-release components from the given flat dependency tree.
+// Returns the filtered tree and the IDs of the removed components.
+func FilterPrereleaseDependencies(tech coreutils.Technology, flatTree *xrayUtils.GraphNode) (filteredTree *xrayUtils.GraphNode, prereleaseDeps []string) {
+	filteredTree = &xrayUtils.GraphNode{Id: flatTree.Id}
+	for _, node := range flatTree.Nodes {
+		if IsPrereleaseVersion(tech, getComponentVersion(node.Id)) {
+			prereleaseDeps = append(prereleaseDeps, node.Id)
+			continue
+		}
+		filteredTree.Nodes = append(filteredTree.Nodes, node)
+	}
+	return
+}
+
+// Returns the version of the given component ID, such as 1.0.0 for 'npm://@scope/name:1.0.0'.
+func getComponentVersion(componentId string) string {
+	_, componentId, _ = strings.Cut(componentId, "://")
+	if index := strings.LastIndex(componentId, ":"); index >= 0 {
+		return componentId[index+1:]
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package sca
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPrereleaseVersion(t *testing.T) {
+	testCases := []struct {
+		tech       coreutils.Technology
+		version    string
+		prerelease bool
+	}{
+		{coreutils.Maven, "1.0.0-SNAPSHOT", true},
+		{coreutils.Gradle, "2.1-snapshot", true},
+		{coreutils.Maven, "1.0.0", false},
+		{coreutils.Maven, "5.3.0.RELEASE", false},
+		{coreutils.Npm, "1.0.0-beta.1", true},
+		{coreutils.Yarn, "2.0.0-rc1", true},
+		{coreutils.Npm, "1.2.3", false},
+		{coreutils.Nuget, "6.0.0-preview.7.21377.19", true},
+		{coreutils.Nuget, "13.0.1", false},
+		{coreutils.Go, "v1.0.0-rc.1", true},
+		{coreutils.Go, "v0.0.0-20210220032951-036812b2e83c", false},
+		{coreutils.Go, "v1.5.2", false},
+		{coreutils.Pip, "1.0a1", true},
+		{coreutils.Pipenv, "2.0.0rc2", true},
+		{coreutils.Poetry, "1.0.dev3", true},
+		{coreutils.Pip, "1.0.post1", false},
+		{coreutils.Pip, "2.31.0", false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.tech.String()+":"+testCase.version, func(t *testing.T) {
+			assert.Equal(t, testCase.prerelease, IsPrereleaseVersion(testCase.tech, testCase.version))
+		})
+	}
+}
+
+func TestFilterPrereleaseDependencies(t *testing.T) {
+	flatTree := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{
+		{Id: "npm://@jfrog/lib:1.0.0-beta.2"},
+		{Id: "npm://lodash:4.17.21"},
+		{Id: "npm://express:5.0.0-alpha.8"},
+	}}
+	filteredTree, prereleaseDeps := FilterPrereleaseDependencies(coreutils.Npm, flatTree)
+	assert.Equal(t, "root", filteredTree.Id)
+	assert.Len(t, filteredTree.Nodes, 1)
+	assert.Equal(t, "npm://lodash:4.17.21", filteredTree.Nodes[0].Id)
+	assert.ElementsMatch(t, []string{"npm://@jfrog/lib:1.0.0-beta.2", "npm://express:5.0.0-alpha.8"}, prereleaseDeps)
+	// The original tree is kept as is
+	assert.Len(t, flatTree.Nodes, 3)
+}
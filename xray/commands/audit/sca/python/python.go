@@ -1,6 +1,7 @@
 package python
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	biutils "github.com/jfrog/build-info-go/utils"
@@ -10,12 +11,12 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	utils "github.com/jfrog/jfrog-cli-core/v2/utils/python"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -30,9 +31,23 @@ type AuditPython struct {
 	Tool                pythonutils.PythonTool
 	RemotePypiRepo      string
 	PipRequirementsFile string
+	// Optional, the virtual environment, or the Python interpreter inside it, whose installed packages are the dependencies of the project.
+	// The virtual environment is only read. If empty, the dependencies are installed and resolved in a copy of the project, using the environment of the tool.
+	VenvPath string
+	// The directory of the project. Defaults to the current working directory.
+	WorkingDir string
+	// Whether to record the commands that are executed while building the dependency tree.
+	LogCommands bool
+	// Optional, the install commands are killed once it is done.
+	Context context.Context
+	// The information that is recorded while building the dependency tree.
+	treesInfo *xrayutils.DependencyTreesInfo
 }
 
-func BuildDependencyTree(auditPython *AuditPython) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
+// Returns the dependency tree with the commands that were recorded while building it.
+func BuildDependencyTree(auditPython *AuditPython) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *xrayutils.DependencyTreesInfo, err error) {
+	auditPython.treesInfo = xrayutils.NewDependencyTreesInfo(auditPython.LogCommands)
+	treesInfo = auditPython.treesInfo
 	dependenciesGraph, directDependenciesList, err := getDependencies(auditPython)
 	if err != nil {
 		return
@@ -57,9 +72,14 @@ func BuildDependencyTree(auditPython *AuditPython) (dependencyTree []*xrayUtils.
 }
 
 func getDependencies(auditPython *AuditPython) (dependenciesGraph map[string][]string, directDependencies []string, err error) {
-	wd, err := os.Getwd()
-	if errorutils.CheckError(err) != nil {
-		return
+	if auditPython.VenvPath != "" {
+		return getVirtualEnvDependencies(auditPython)
+	}
+	wd := auditPython.WorkingDir
+	if wd == "" {
+		if wd, err = os.Getwd(); errorutils.CheckError(err) != nil {
+			return
+		}
 	}
 
 	// Create temp dir to run all work outside users working directory
@@ -68,17 +88,8 @@ func getDependencies(auditPython *AuditPython) (dependenciesGraph map[string][]s
 		return
 	}
 
-	err = os.Chdir(tempDirPath)
-	if errorutils.CheckError(err) != nil {
-		return
-	}
-
 	defer func() {
-		err = errors.Join(
-			err,
-			errorutils.CheckError(os.Chdir(wd)),
-			fileutils.RemoveTempDir(tempDirPath),
-		)
+		err = errors.Join(err, fileutils.RemoveTempDir(tempDirPath))
 	}()
 
 	err = biutils.CopyDir(wd, tempDirPath, true, nil)
@@ -86,7 +97,7 @@ func getDependencies(auditPython *AuditPython) (dependenciesGraph map[string][]s
 		return
 	}
 
-	restoreEnv, err := runPythonInstall(auditPython)
+	restoreEnv, err := runPythonInstall(auditPython, tempDirPath)
 	defer func() {
 		err = errors.Join(err, restoreEnv())
 	}()
@@ -106,19 +117,27 @@ func getDependencies(auditPython *AuditPython) (dependenciesGraph map[string][]s
 	return
 }
 
-func runPythonInstall(auditPython *AuditPython) (restoreEnv func() error, err error) {
+// Installs the dependencies of the project that was copied to the given directory.
+func runPythonInstall(auditPython *AuditPython, projectDir string) (restoreEnv func() error, err error) {
 	switch auditPython.Tool {
 	case pythonutils.Pip:
-		return installPipDeps(auditPython)
+		return installPipDeps(auditPython, projectDir)
 	case pythonutils.Pipenv:
-		return installPipenvDeps(auditPython)
+		return installPipenvDeps(auditPython, projectDir)
 	case pythonutils.Poetry:
-		return installPoetryDeps(auditPython)
+		return installPoetryDeps(auditPython, projectDir)
 	}
 	return
 }
 
-func installPoetryDeps(auditPython *AuditPython) (restoreEnv func() error, err error) {
+func getVirtualEnvBinPath(venvPath string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(venvPath, "Scripts")
+	}
+	return filepath.Join(venvPath, "bin")
+}
+
+func installPoetryDeps(auditPython *AuditPython, projectDir string) (restoreEnv func() error, err error) {
 	restoreEnv = func() error {
 		return nil
 	}
@@ -128,19 +147,19 @@ func installPoetryDeps(auditPython *AuditPython) (restoreEnv func() error, err e
 			return restoreEnv, err
 		}
 		if password != "" {
-			err = utils.ConfigPoetryRepo(rtUrl.Scheme+"://"+rtUrl.Host+rtUrl.Path, username, password, auditPython.RemotePypiRepo)
+			err = utils.ConfigPoetryProjectRepo(projectDir, rtUrl.Scheme+"://"+rtUrl.Host+rtUrl.Path, username, password, auditPython.RemotePypiRepo)
 			if err != nil {
 				return restoreEnv, err
 			}
 		}
 	}
 	// Run 'poetry install'
-	return restoreEnv, executeCommand("poetry", "install")
+	return restoreEnv, auditPython.executeCommand(projectDir, "poetry", "install")
 }
 
-func installPipenvDeps(auditPython *AuditPython) (restoreEnv func() error, err error) {
+func installPipenvDeps(auditPython *AuditPython, projectDir string) (restoreEnv func() error, err error) {
 	// Set virtualenv path to venv dir
-	err = os.Setenv("WORKON_HOME", ".jfrog")
+	err = os.Setenv("WORKON_HOME", filepath.Join(projectDir, ".jfrog"))
 	if err != nil {
 		return
 	}
@@ -148,18 +167,23 @@ func installPipenvDeps(auditPython *AuditPython) (restoreEnv func() error, err e
 		return os.Unsetenv("WORKON_HOME")
 	}
 	if auditPython.RemotePypiRepo != "" {
-		return restoreEnv, runPipenvInstallFromRemoteRegistry(auditPython.Server, auditPython.RemotePypiRepo)
+		return restoreEnv, runPipenvInstallFromRemoteRegistry(auditPython, projectDir)
 	}
 	// Run 'pipenv install -d'
-	return restoreEnv, executeCommand("pipenv", "install", "-d")
+	return restoreEnv, auditPython.executeCommand(projectDir, "pipenv", "install", "-d")
 }
 
-func installPipDeps(auditPython *AuditPython) (restoreEnv func() error, err error) {
-	restoreEnv, err = SetPipVirtualEnvPath()
+func installPipDeps(auditPython *AuditPython, projectDir string) (restoreEnv func() error, err error) {
+	restoreEnv, err = SetPipVirtualEnvPath(projectDir)
 	if err != nil {
 		return
 	}
+	err = runPipInstall(auditPython, projectDir)
+	return
+}
 
+// Runs 'pip install' with the Python interpreter that is found first in 'PATH'.
+func runPipInstall(auditPython *AuditPython, projectDir string) (err error) {
 	remoteUrl := ""
 	if auditPython.RemotePypiRepo != "" {
 		remoteUrl, err = utils.GetPypiRepoUrl(auditPython.Server, auditPython.RemotePypiRepo)
@@ -168,10 +192,10 @@ func installPipDeps(auditPython *AuditPython) (restoreEnv func() error, err erro
 		}
 	}
 	pipInstallArgs := getPipInstallArgs(auditPython.PipRequirementsFile, remoteUrl)
-	err = executeCommand("python", pipInstallArgs...)
+	err = auditPython.executeCommand(projectDir, "python", pipInstallArgs...)
 	if err != nil && auditPython.PipRequirementsFile == "" {
 		pipInstallArgs = getPipInstallArgs("requirements.txt", remoteUrl)
-		reqErr := executeCommand("python", pipInstallArgs...)
+		reqErr := auditPython.executeCommand(projectDir, "python", pipInstallArgs...)
 		if reqErr != nil {
 			// Return Pip install error and log the requirements fallback error.
 			log.Debug(reqErr.Error())
@@ -182,8 +206,17 @@ func installPipDeps(auditPython *AuditPython) (restoreEnv func() error, err erro
 	return
 }
 
-func executeCommand(executable string, args ...string) error {
-	installCmd := exec.Command(executable, args...)
+func (auditPython *AuditPython) executeCommand(dir, executable string, args ...string) error {
+	if auditPython.treesInfo != nil {
+		auditPython.treesInfo.RecordExecutedCommand(executable, args...)
+	}
+	return executeCommand(auditPython.Context, dir, executable, args...)
+}
+
+// Executes the command in the given directory. The command is killed once the given context is done.
+func executeCommand(ctx context.Context, dir, executable string, args ...string) error {
+	installCmd := sca.CreateCommand(ctx, executable, args...)
+	installCmd.Dir = dir
 	maskedCmdString := coreutils.GetMaskedCommandString(installCmd)
 	log.Debug("Running", maskedCmdString)
 	output, err := installCmd.CombinedOutput()
@@ -209,17 +242,17 @@ func getPipInstallArgs(requirementsFile, remoteUrl string) []string {
 	return args
 }
 
-func runPipenvInstallFromRemoteRegistry(server *config.ServerDetails, depsRepoName string) (err error) {
-	rtUrl, err := utils.GetPypiRepoUrl(server, depsRepoName)
+func runPipenvInstallFromRemoteRegistry(auditPython *AuditPython, projectDir string) (err error) {
+	rtUrl, err := utils.GetPypiRepoUrl(auditPython.Server, auditPython.RemotePypiRepo)
 	if err != nil {
 		return err
 	}
 	args := []string{"install", "-d", utils.GetPypiRemoteRegistryFlag(pythonutils.Pipenv), rtUrl}
-	return executeCommand("pipenv", args...)
+	return auditPython.executeCommand(projectDir, "pipenv", args...)
 }
 
-// Execute virtualenv command: "virtualenv venvdir" / "python3 -m venv venvdir" and set path
-func SetPipVirtualEnvPath() (restoreEnv func() error, err error) {
+// Execute virtualenv command: "virtualenv venvdir" / "python3 -m venv venvdir" in the given directory and set path
+func SetPipVirtualEnvPath(dir string) (restoreEnv func() error, err error) {
 	restoreEnv = func() error {
 		return nil
 	}
@@ -231,11 +264,11 @@ func SetPipVirtualEnvPath() (restoreEnv func() error, err error) {
 		cmdArgs = append(cmdArgs, windowsPyArg)
 	}
 	cmdArgs = append(cmdArgs, "-m", "venv", venvdirName)
-	err = executeCommand(pythonPath, cmdArgs...)
+	err = executeCommand(context.Background(), dir, pythonPath, cmdArgs...)
 	if err != nil {
 		// Failed running 'python -m venv', trying to run 'virtualenv'
 		log.Debug("Failed running python venv:", err.Error())
-		err = executeCommand("virtualenv", "-p", pythonPath, venvdirName)
+		err = executeCommand(context.Background(), dir, "virtualenv", "-p", pythonPath, venvdirName)
 		if err != nil {
 			return
 		}
@@ -243,16 +276,11 @@ func SetPipVirtualEnvPath() (restoreEnv func() error, err error) {
 
 	// Keep original value of 'PATH'.
 	origPathValue := os.Getenv("PATH")
-	venvPath, err := filepath.Abs(venvdirName)
+	venvPath, err := filepath.Abs(filepath.Join(dir, venvdirName))
 	if err != nil {
 		return
 	}
-	var venvBinPath string
-	if runtime.GOOS == "windows" {
-		venvBinPath = filepath.Join(venvPath, "Scripts")
-	} else {
-		venvBinPath = filepath.Join(venvPath, "bin")
-	}
+	venvBinPath := getVirtualEnvBinPath(venvPath)
 	err = os.Setenv("PATH", fmt.Sprintf("%s%c%s", venvBinPath, os.PathListSeparator, origPathValue))
 	if err != nil {
 		return
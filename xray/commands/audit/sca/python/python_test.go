@@ -1,7 +1,10 @@
 package python
 
 import (
+	"encoding/json"
+	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
@@ -15,7 +18,7 @@ func TestBuildPipDependencyListSetuppy(t *testing.T) {
 	_, cleanUp := sca.CreateTestWorkspace(t, filepath.Join("pip-project", "setuppyproject"))
 	defer cleanUp()
 	// Run getModulesDependencyTrees
-	rootNode, uniqueDeps, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Pip})
+	rootNode, uniqueDeps, _, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Pip})
 	assert.NoError(t, err)
 	assert.Contains(t, uniqueDeps, pythonPackageTypeIdentifier+"pexpect:4.8.0")
 	assert.Contains(t, uniqueDeps, pythonPackageTypeIdentifier+"ptyprocess:0.7.0")
@@ -39,7 +42,7 @@ func TestPipDependencyListRequirementsFallback(t *testing.T) {
 	_, cleanUp := sca.CreateTestWorkspace(t, filepath.Join("pip-project", "requirementsproject"))
 	defer cleanUp()
 	// No requirements file field specified, expect the command to use the fallback 'pip install -r requirements.txt' command
-	rootNode, uniqueDeps, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Pip})
+	rootNode, uniqueDeps, _, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Pip})
 	assert.NoError(t, err)
 	assert.Contains(t, uniqueDeps, pythonPackageTypeIdentifier+"pexpect:4.7.0")
 	assert.Contains(t, uniqueDeps, pythonPackageTypeIdentifier+"ptyprocess:0.7.0")
@@ -58,7 +61,7 @@ func TestBuildPipDependencyListRequirements(t *testing.T) {
 	_, cleanUp := sca.CreateTestWorkspace(t, filepath.Join("pip-project", "requirementsproject"))
 	defer cleanUp()
 	// Run getModulesDependencyTrees
-	rootNode, uniqueDeps, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Pip, PipRequirementsFile: "requirements.txt"})
+	rootNode, uniqueDeps, _, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Pip, PipRequirementsFile: "requirements.txt"})
 	assert.NoError(t, err)
 	assert.Contains(t, uniqueDeps, pythonPackageTypeIdentifier+"pexpect:4.7.0")
 	assert.Contains(t, uniqueDeps, pythonPackageTypeIdentifier+"ptyprocess:0.7.0")
@@ -84,7 +87,7 @@ func TestBuildPipenvDependencyList(t *testing.T) {
 		pythonPackageTypeIdentifier + "ptyprocess:0.7.0",
 	}
 	// Run getModulesDependencyTrees
-	rootNode, uniqueDeps, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Pipenv})
+	rootNode, uniqueDeps, _, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Pipenv})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -119,7 +122,7 @@ func TestBuildPoetryDependencyList(t *testing.T) {
 		pythonPackageTypeIdentifier + "pytest:5.4.3",
 	}
 	// Run getModulesDependencyTrees
-	rootNode, uniqueDeps, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Poetry})
+	rootNode, uniqueDeps, _, err := BuildDependencyTree(&AuditPython{Tool: pythonutils.Poetry})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -143,3 +146,42 @@ func TestGetPipInstallArgs(t *testing.T) {
 	assert.Equal(t, []string{"-m", "pip", "install", ".", "-i", "https://user@pass:remote.url/repo"}, getPipInstallArgs("", "https://user@pass:remote.url/repo"))
 	assert.Equal(t, []string{"-m", "pip", "install", "-r", "requirements.txt", "-i", "https://user@pass:remote.url/repo"}, getPipInstallArgs("requirements.txt", "https://user@pass:remote.url/repo"))
 }
+
+func TestGetVirtualEnvInterpreter(t *testing.T) {
+	venvPath := t.TempDir()
+	venvBinPath := getVirtualEnvBinPath(venvPath)
+	assert.NoError(t, os.MkdirAll(venvBinPath, 0755))
+	interpreterPath := filepath.Join(venvBinPath, "python")
+	if runtime.GOOS == "windows" {
+		interpreterPath += ".exe"
+	}
+	assert.NoError(t, os.WriteFile(interpreterPath, []byte{}, 0755))
+
+	// Get the interpreter by the virtual environment directory and by the interpreter itself.
+	for _, path := range []string{venvPath, interpreterPath} {
+		actualVenvPath, actualInterpreterPath, err := getVirtualEnvInterpreter(path)
+		assert.NoError(t, err)
+		assert.Equal(t, venvPath, actualVenvPath)
+		assert.Equal(t, interpreterPath, actualInterpreterPath)
+	}
+
+	// A directory without an interpreter isn't a virtual environment.
+	_, _, err := getVirtualEnvInterpreter(t.TempDir())
+	assert.ErrorContains(t, err, "is not a Python virtual environment")
+}
+
+func TestParsePipInspectReport(t *testing.T) {
+	var report pipInspectReport
+	assert.NoError(t, json.Unmarshal([]byte(`{"installed": [
+		{"metadata": {"name": "pip", "version": "23.3"}},
+		{"metadata": {"name": "setuptools", "version": "69.0.2"}},
+		{"metadata": {"name": "Requests", "version": "2.31.0", "requires_dist": ["charset-normalizer (<4,>=2)", "urllib3<3,>=1.21.1", "PySocks!=1.5.7,>=1.5.6; extra == \"socks\""]}},
+		{"metadata": {"name": "charset_normalizer", "version": "3.3.2"}},
+		{"metadata": {"name": "urllib3", "version": "2.1.0"}},
+		{"metadata": {"name": "PySocks", "version": "1.7.1"}}
+	]}`), &report))
+	dependenciesGraph, directDependencies := parsePipInspectReport(report)
+	assert.ElementsMatch(t, []string{"requests:2.31.0", "pysocks:1.7.1"}, directDependencies)
+	assert.ElementsMatch(t, []string{"charset_normalizer:3.3.2", "urllib3:2.1.0"}, dependenciesGraph["requests:2.31.0"])
+	assert.Empty(t, dependenciesGraph["urllib3:2.1.0"])
+}
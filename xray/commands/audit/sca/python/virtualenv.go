@@ -0,0 +1,142 @@
+package python
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+var (
+	// The packages that are installed with the virtual environment itself, rather than by the project.
+	virtualEnvPackagingTools = []string{"pip", "setuptools", "wheel", "distribute"}
+	requirementNameRegexp    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+	packageNameSeparators    = regexp.MustCompile(`[-_.]+`)
+)
+
+// The output of 'pip inspect'.
+type pipInspectReport struct {
+	Installed []struct {
+		Metadata struct {
+			Name         string   `json:"name"`
+			Version      string   `json:"version"`
+			RequiresDist []string `json:"requires_dist,omitempty"`
+		} `json:"metadata"`
+	} `json:"installed"`
+}
+
+// Returns the dependencies graph of the packages that are installed in the virtual environment of the audit, and the packages that no other package requires.
+// The packages are listed by the interpreter of the virtual environment, which is only read, so nothing is installed into it and the environment of the process isn't changed.
+func getVirtualEnvDependencies(auditPython *AuditPython) (dependenciesGraph map[string][]string, directDependencies []string, err error) {
+	venvPath, interpreterPath, err := getVirtualEnvInterpreter(auditPython.VenvPath)
+	if err != nil {
+		return
+	}
+	log.Debug("Reading the packages of the Python virtual environment", venvPath)
+	args := []string{"-m", "pip", "inspect", "--local"}
+	if auditPython.treesInfo != nil {
+		auditPython.treesInfo.RecordExecutedCommand(interpreterPath, args...)
+	}
+	inspectCmd := sca.CreateCommand(auditPython.Context, interpreterPath, args...)
+	inspectCmd.Env = append(os.Environ(), "VIRTUAL_ENV="+venvPath, fmt.Sprintf("PATH=%s%c%s", getVirtualEnvBinPath(venvPath), os.PathListSeparator, os.Getenv("PATH")))
+	maskedCmdString := coreutils.GetMaskedCommandString(inspectCmd)
+	log.Debug("Running", maskedCmdString)
+	output, err := inspectCmd.Output()
+	if err != nil {
+		var stderr []byte
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = exitErr.Stderr
+		}
+		sca.LogExecutableVersion(interpreterPath)
+		err = errorutils.CheckErrorf("%q command failed: %s - %s", maskedCmdString, err.Error(), stderr)
+		return
+	}
+	var report pipInspectReport
+	if err = json.Unmarshal(output, &report); err != nil {
+		err = errorutils.CheckErrorf("couldn't parse the packages of the Python virtual environment %q: %s", venvPath, err.Error())
+		return
+	}
+	dependenciesGraph, directDependencies = parsePipInspectReport(report)
+	if len(directDependencies) == 0 {
+		err = errorutils.CheckErrorf("no packages are installed in the Python virtual environment %q. Install the dependencies of the project into it before the audit", venvPath)
+	}
+	return
+}
+
+// Returns the virtual environment of the given path, which is either the virtual environment or the Python interpreter inside it, with the interpreter of the virtual environment.
+func getVirtualEnvInterpreter(path string) (venvPath, interpreterPath string, err error) {
+	if path, err = filepath.Abs(path); errorutils.CheckError(err) != nil {
+		return
+	}
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		err = errorutils.CheckErrorf("the Python virtual environment %q could not be read: %s", path, err.Error())
+		return
+	}
+	if !pathInfo.IsDir() {
+		// The path of an interpreter, such as 'venv/bin/python'.
+		return filepath.Dir(filepath.Dir(path)), path, nil
+	}
+	venvPath = path
+	interpreterName := "python"
+	if runtime.GOOS == "windows" {
+		interpreterName += ".exe"
+	}
+	interpreterPath = filepath.Join(getVirtualEnvBinPath(venvPath), interpreterName)
+	exists, err := fileutils.IsFileExists(interpreterPath, false)
+	if err != nil {
+		return
+	}
+	if !exists {
+		err = errorutils.CheckErrorf("%q is not a Python virtual environment, since it has no %q interpreter", venvPath, interpreterPath)
+	}
+	return
+}
+
+// Returns the dependencies graph of the installed packages, by their 'name:version' keys, and the packages that no other package requires.
+// A requirement of a package is in the graph only if it's installed and isn't of an extra of the package.
+func parsePipInspectReport(report pipInspectReport) (dependenciesGraph map[string][]string, directDependencies []string) {
+	keys := map[string]string{}
+	for _, installed := range report.Installed {
+		keys[normalizePackageName(installed.Metadata.Name)] = strings.ToLower(installed.Metadata.Name) + ":" + installed.Metadata.Version
+	}
+	dependenciesGraph = map[string][]string{}
+	required := map[string]bool{}
+	for _, installed := range report.Installed {
+		var requirements []string
+		for _, requirement := range installed.Metadata.RequiresDist {
+			if _, marker, found := strings.Cut(requirement, ";"); found && strings.Contains(marker, "extra") {
+				continue
+			}
+			if key, ok := keys[normalizePackageName(requirementNameRegexp.FindString(requirement))]; ok {
+				requirements = append(requirements, key)
+				required[key] = true
+			}
+		}
+		dependenciesGraph[keys[normalizePackageName(installed.Metadata.Name)]] = requirements
+	}
+	for _, tool := range virtualEnvPackagingTools {
+		required[keys[tool]] = true
+	}
+	for _, installed := range report.Installed {
+		if key := keys[normalizePackageName(installed.Metadata.Name)]; !required[key] {
+			directDependencies = append(directDependencies, key)
+		}
+	}
+	return
+}
+
+// Normalizes the name of a package, as pip compares them.
+func normalizePackageName(name string) string {
+	return packageNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
@@ -0,0 +1,57 @@
+package sca
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+// The directories that hold installed packages or version control data, rather than modules of the project.
+var nonModuleDirs = []string{"node_modules", ".git"}
+
+// Returns the directories of the modules of the project in the working directory that are excluded from the scan.
+// A module directory includes one of the given descriptors, and is excluded if its path, relative to the working directory, matches the scan exclusions.
+// The working directory itself is never excluded.
+func GetScanExcludedModulesDirs(workingDir string, scanExclusions []string, descriptors ...string) (excludedDirs []string, err error) {
+	if len(scanExclusions) == 0 {
+		return
+	}
+	excludeRegexp, err := regexp.Compile(fspatterns.PrepareExcludePathPattern(scanExclusions, clientutils.WildCardPattern, true))
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	err = filepath.WalkDir(workingDir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if !entry.IsDir() || path == workingDir {
+			return nil
+		}
+		for _, nonModuleDir := range nonModuleDirs {
+			if entry.Name() == nonModuleDir {
+				return filepath.SkipDir
+			}
+		}
+		if !excludeRegexp.MatchString(strings.TrimPrefix(path, workingDir)) {
+			return nil
+		}
+		for _, descriptor := range descriptors {
+			exists, e := fileutils.IsFileExists(filepath.Join(path, descriptor), false)
+			if e != nil {
+				return e
+			}
+			if exists {
+				excludedDirs = append(excludedDirs, path)
+				break
+			}
+		}
+		return nil
+	})
+	return excludedDirs, errorutils.CheckError(err)
+}
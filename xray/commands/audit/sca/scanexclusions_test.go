@@ -0,0 +1,29 @@
+package sca
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScanExcludedModulesDirs(t *testing.T) {
+	workingDir := t.TempDir()
+	for _, dir := range []string{"app", filepath.Join("vendor", "lib"), filepath.Join("vendor", "docs"), filepath.Join("node_modules", "vendor-pkg")} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(workingDir, dir), 0755))
+	}
+	for _, descriptor := range []string{"package.json", filepath.Join("app", "package.json"), filepath.Join("vendor", "lib", "package.json"), filepath.Join("node_modules", "vendor-pkg", "package.json")} {
+		assert.NoError(t, os.WriteFile(filepath.Join(workingDir, descriptor), []byte("{}"), 0644))
+	}
+
+	// No scan exclusions
+	excludedDirs, err := GetScanExcludedModulesDirs(workingDir, nil, "package.json")
+	assert.NoError(t, err)
+	assert.Empty(t, excludedDirs)
+
+	// Only the matching directories with descriptors are excluded, and the installed packages aren't walked.
+	excludedDirs, err = GetScanExcludedModulesDirs(workingDir, []string{"*vendor*"}, "package.json")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(workingDir, "vendor", "lib")}, excludedDirs)
+}
@@ -0,0 +1,127 @@
+package swift
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/gofrog/datastructures"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+)
+
+const (
+	swiftPackageTypeIdentifier = "swift://"
+	packageSwiftName           = "Package.swift"
+	packageResolvedName        = "Package.resolved"
+)
+
+// Matches the name of the package in its Package.swift manifest, such as 'Package(name: "MyApp", ...)'.
+var packageNameRegex = regexp.MustCompile(`Package\(\s*name:\s*"([^"]+)"`)
+
+// The content of a Package.resolved file that is relevant for building the dependency tree.
+// Version 1 of the file nests the pins under 'object', and names their repositories 'repositoryURL', while the later versions name them 'location'.
+type packageResolved struct {
+	Pins   []resolvedPin `json:"pins"`
+	Object *struct {
+		Pins []resolvedPin `json:"pins"`
+	} `json:"object"`
+}
+
+type resolvedPin struct {
+	Location      string `json:"location"`
+	RepositoryURL string `json:"repositoryURL"`
+	State         struct {
+		Branch   string `json:"branch"`
+		Revision string `json:"revision"`
+		Version  string `json:"version"`
+	} `json:"state"`
+}
+
+// Builds the dependency tree of the Swift package in the working directory from its Package.resolved file.
+// The file doesn't record which package requires which, so all the resolved packages are direct dependencies of the project.
+// The packages are identified by their repositories, such as 'github.com/apple/swift-nio'. Packages that are pinned to a branch or a revision
+// rather than to a version are identified by their revision, and are returned as fetched from git with the tree.
+func BuildDependencyTree(params utils.AuditParams, workingDir string) (dependencyTree []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *utils.DependencyTreesInfo, err error) {
+	resolvedPath := filepath.Join(workingDir, packageResolvedName)
+	exists, err := fileutils.IsFileExists(resolvedPath, false)
+	if err != nil {
+		return
+	}
+	if !exists {
+		err = errorutils.CheckErrorf("the %s file wasn't found in '%s'. Run 'swift package resolve' to create it", packageResolvedName, workingDir)
+		return
+	}
+	content, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		err = errorutils.CheckError(err)
+		return
+	}
+	resolved := &packageResolved{}
+	if err = json.Unmarshal(content, resolved); err != nil {
+		err = errorutils.CheckErrorf("failed to parse '%s': %s", resolvedPath, err.Error())
+		return
+	}
+	pins := resolved.Pins
+	if resolved.Object != nil {
+		pins = resolved.Object.Pins
+	}
+	rootNode := &xrayUtils.GraphNode{Id: getPackageName(workingDir), Nodes: []*xrayUtils.GraphNode{}}
+	uniqueDepsSet := datastructures.MakeSet[string]()
+	treesInfo = &utils.DependencyTreesInfo{}
+	for _, pin := range pins {
+		location := pin.Location
+		if location == "" {
+			location = pin.RepositoryURL
+		}
+		version := pin.State.Version
+		if version == "" {
+			version = pin.State.Revision
+		}
+		id := getSwiftComponentId(location, version)
+		if uniqueDepsSet.Exists(id) {
+			continue
+		}
+		uniqueDepsSet.Add(id)
+		rootNode.Nodes = append(rootNode.Nodes, &xrayUtils.GraphNode{Id: id, Nodes: []*xrayUtils.GraphNode{}, Parent: rootNode})
+		if pin.State.Version == "" {
+			gitReference := pin.State.Branch
+			if gitReference == "" {
+				gitReference = pin.State.Revision
+			}
+			treesInfo.AddGitSourcedDependency(id, gitReference)
+		}
+	}
+	dependencyTree = []*xrayUtils.GraphNode{rootNode}
+	uniqueDeps = uniqueDepsSet.ToSlice()
+	return
+}
+
+// Returns the name of the package from its Package.swift manifest, or the name of its directory if the name can't be found.
+func getPackageName(workingDir string) string {
+	content, err := os.ReadFile(filepath.Join(workingDir, packageSwiftName))
+	if err == nil {
+		if match := packageNameRegex.FindSubmatch(content); match != nil {
+			return string(match[1])
+		}
+	}
+	return filepath.Base(workingDir)
+}
+
+// Returns the Xray component ID of the package in the given repository, which is identified by its URL without the scheme and the '.git' suffix.
+// For example, 'https://github.com/apple/swift-nio.git' is identified as 'github.com/apple/swift-nio'.
+func getSwiftComponentId(location, version string) string {
+	name := location
+	if _, withoutScheme, found := strings.Cut(name, "://"); found {
+		name = withoutScheme
+	} else if _, withoutUser, found := strings.Cut(name, "@"); found {
+		// An SSH location, such as 'git@github.com:apple/swift-nio.git'.
+		name = strings.Replace(withoutUser, ":", "/", 1)
+	}
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "/"), ".git")
+	return swiftPackageTypeIdentifier + name + ":" + version
+}
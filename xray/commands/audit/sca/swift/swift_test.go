@@ -0,0 +1,66 @@
+package swift
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDependencyTree(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "swift-project")
+	defer cleanUp()
+
+	params := &xrayutils.AuditBasicParams{}
+	dependencyTree, uniqueDeps, treesInfo, err := BuildDependencyTree(params, projectDir)
+	assert.NoError(t, err)
+	expectedDeps := []string{
+		"swift://github.com/acme/acme-utils:3f5b0c9a2d1e4b7c8a6f0e9d2c1b4a7e5f3d2c1b",
+		"swift://github.com/Alamofire/Alamofire:5.8.1",
+		"swift://github.com/apple/swift-log:1.5.3",
+	}
+	assert.ElementsMatch(t, expectedDeps, uniqueDeps)
+	if assert.Len(t, dependencyTree, 1) {
+		root := dependencyTree[0]
+		assert.Equal(t, "AcmeApp", root.Id)
+		var childrenIds []string
+		for _, child := range root.Nodes {
+			childrenIds = append(childrenIds, child.Id)
+		}
+		assert.Equal(t, expectedDeps, childrenIds)
+	}
+	// The package that is pinned to a branch is reported as fetched from git.
+	assert.Equal(t, map[string]string{"swift://github.com/acme/acme-utils:3f5b0c9a2d1e4b7c8a6f0e9d2c1b4a7e5f3d2c1b": "main"}, treesInfo.GitSourcedDependencies)
+}
+
+func TestBuildDependencyTreeResolvedV1(t *testing.T) {
+	projectDir := t.TempDir()
+	resolved := `{"object": {"pins": [{"package": "Alamofire", "repositoryURL": "https://github.com/Alamofire/Alamofire.git", "state": {"branch": null, "revision": "3dc6a42c7727c49bf26508e29b0a0b35f9c7e1ad", "version": "5.8.1"}}]}, "version": 1}`
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, packageResolvedName), []byte(resolved), 0644))
+
+	dependencyTree, uniqueDeps, _, err := BuildDependencyTree(&xrayutils.AuditBasicParams{}, projectDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"swift://github.com/Alamofire/Alamofire:5.8.1"}, uniqueDeps)
+	// Without a Package.swift, the project is named after its directory.
+	if assert.Len(t, dependencyTree, 1) {
+		assert.Equal(t, filepath.Base(projectDir), dependencyTree[0].Id)
+	}
+}
+
+func TestBuildDependencyTreeWithoutResolvedFile(t *testing.T) {
+	projectDir, cleanUp := sca.CreateTestWorkspace(t, "swift-project")
+	defer cleanUp()
+	assert.NoError(t, os.Remove(filepath.Join(projectDir, packageResolvedName)))
+
+	_, _, _, err := BuildDependencyTree(&xrayutils.AuditBasicParams{}, projectDir)
+	assert.ErrorContains(t, err, "Run 'swift package resolve' to create it")
+}
+
+func TestGetSwiftComponentId(t *testing.T) {
+	assert.Equal(t, "swift://github.com/apple/swift-nio:2.50.0", getSwiftComponentId("https://github.com/apple/swift-nio.git", "2.50.0"))
+	assert.Equal(t, "swift://github.com/apple/swift-nio:2.50.0", getSwiftComponentId("https://github.com/apple/swift-nio", "2.50.0"))
+	assert.Equal(t, "swift://github.com/apple/swift-nio:2.50.0", getSwiftComponentId("git@github.com:apple/swift-nio.git", "2.50.0"))
+}
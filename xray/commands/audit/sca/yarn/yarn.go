@@ -1,21 +1,24 @@
 package yarn
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"github.com/jfrog/build-info-go/build"
 	biutils "github.com/jfrog/build-info-go/build/utils"
 	"github.com/jfrog/gofrog/version"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/yarn"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
-	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/ioutils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/npm"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/slices"
+	"os"
+	"os/exec"
 	"path/filepath"
 )
 
@@ -38,28 +41,27 @@ const (
 	nodeModulesRepoName = "node_modules"
 )
 
-func BuildDependencyTree(params utils.AuditParams) (dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string, err error) {
-	currentDir, err := coreutils.GetWorkingDirectory()
-	if err != nil {
-		return
-	}
+// Returns the dependency trees with the commands that were executed while building them.
+// The install command is killed once the given context is done. The command that lists the dependencies is run by build-info-go, so it can't be killed, but the build stops before running it.
+func BuildDependencyTree(ctx context.Context, params utils.AuditParams, workingDir string) (dependencyTrees []*xrayUtils.GraphNode, uniqueDeps []string, treesInfo *utils.DependencyTreesInfo, err error) {
 	executablePath, err := biutils.GetYarnExecutable()
 	if errorutils.CheckError(err) != nil {
 		return
 	}
 
-	packageInfo, err := biutils.ReadPackageInfoFromPackageJsonIfExists(currentDir, nil)
+	packageInfo, err := biutils.ReadPackageInfoFromPackageJsonIfExists(workingDir, nil)
 	if errorutils.CheckError(err) != nil {
 		return
 	}
 
-	installRequired, err := isInstallRequired(currentDir, params.InstallCommandArgs())
+	treesInfo = utils.NewDependencyTreesInfo(params.LogCommands())
+	installRequired, err := isInstallRequired(workingDir, params.InstallCommandArgs())
 	if err != nil {
 		return
 	}
 
 	if installRequired {
-		err = configureYarnResolutionServerAndRunInstall(params, currentDir, executablePath)
+		err = configureYarnResolutionServerAndRunInstall(ctx, params, workingDir, executablePath, treesInfo)
 		if err != nil {
 			err = fmt.Errorf("failed to configure an Artifactory resolution server or running and install command: %s", err.Error())
 			return
@@ -67,23 +69,41 @@ func BuildDependencyTree(params utils.AuditParams) (dependencyTrees []*xrayUtils
 	}
 
 	// Calculate Yarn dependencies
-	dependenciesMap, root, err := biutils.GetYarnDependencies(executablePath, currentDir, packageInfo, log.Logger)
+	if err = sca.CheckContext(ctx); err != nil {
+		return
+	}
+	if params.LogCommands() {
+		if err = recordYarnDependenciesCommand(treesInfo, executablePath, workingDir); err != nil {
+			return
+		}
+	}
+	dependenciesMap, root, err := biutils.GetYarnDependencies(executablePath, workingDir, packageInfo, log.Logger)
 	if err != nil {
 		return
 	}
+	var excludedDirectDependencies []string
+	if params.ExcludeDevDependencies() {
+		excludedDirectDependencies = getDevOnlyDirectDependencies(packageInfo)
+	}
+	// The workspaces and the local packages in paths that are excluded from the scan are excluded as well.
+	excludedPackages, err := npm.GetScanExcludedPackages(workingDir, params.ScanExclusions())
+	if err != nil {
+		return
+	}
+	excludedDirectDependencies = append(excludedDirectDependencies, excludedPackages...)
 	// Parse the dependencies into Xray dependency tree format
-	dependencyTree, uniqueDeps := parseYarnDependenciesMap(dependenciesMap, getXrayDependencyId(root))
+	dependencyTree, uniqueDeps := parseYarnDependenciesMap(dependenciesMap, getXrayDependencyId(root), excludedDirectDependencies...)
 	dependencyTrees = []*xrayUtils.GraphNode{dependencyTree}
 	return
 }
 
 // Sets up Artifactory server configurations for dependency resolution, if such were provided by the user.
 // Executes the user's 'install' command or a default 'install' command if none was specified.
-func configureYarnResolutionServerAndRunInstall(params utils.AuditParams, curWd, yarnExecPath string) (err error) {
+func configureYarnResolutionServerAndRunInstall(ctx context.Context, params utils.AuditParams, curWd, yarnExecPath string, treesInfo *utils.DependencyTreesInfo) (err error) {
 	depsRepo := params.DepsRepo()
 	if depsRepo == "" {
 		// Run install without configuring an Artifactory server
-		return runYarnInstallAccordingToVersion(curWd, yarnExecPath, params.InstallCommandArgs())
+		return runYarnInstallAccordingToVersion(ctx, curWd, yarnExecPath, params.InstallCommandArgs(), treesInfo)
 	}
 
 	executableYarnVersion, err := biutils.GetVersion(yarnExecPath, curWd)
@@ -116,7 +136,7 @@ func configureYarnResolutionServerAndRunInstall(params utils.AuditParams, curWd,
 		return
 	}
 
-	backupEnvMap, err := yarn.ModifyYarnConfigurations(yarnExecPath, registry, repoAuthIdent)
+	backupEnvMap, err := yarn.ModifyYarnConfigurations(yarnExecPath, curWd, registry, repoAuthIdent)
 	if err != nil {
 		if len(backupEnvMap) > 0 {
 			err = errors.Join(err, yarn.RestoreConfigurationsFromBackup(backupEnvMap, restoreYarnrcFunc))
@@ -130,7 +150,7 @@ func configureYarnResolutionServerAndRunInstall(params utils.AuditParams, curWd,
 	}()
 
 	log.Info(fmt.Sprintf("Resolving dependencies from '%s' from repo '%s'", serverDetails.Url, depsRepo))
-	return runYarnInstallAccordingToVersion(curWd, yarnExecPath, params.InstallCommandArgs())
+	return runYarnInstallAccordingToVersion(ctx, curWd, yarnExecPath, params.InstallCommandArgs(), treesInfo)
 }
 
 func isInstallRequired(currentDir string, installCommandArgs []string) (installRequired bool, err error) {
@@ -149,13 +169,13 @@ func isInstallRequired(currentDir string, installCommandArgs []string) (installR
 }
 
 // Executes the user-defined 'install' command; if absent, defaults to running an 'install' command with specific flags suited to the current yarn version.
-func runYarnInstallAccordingToVersion(curWd, yarnExecPath string, installCommandArgs []string) (err error) {
+func runYarnInstallAccordingToVersion(ctx context.Context, curWd, yarnExecPath string, installCommandArgs []string, treesInfo *utils.DependencyTreesInfo) (err error) {
 	// If the installCommandArgs in the params is not empty, it signifies that the user has provided it, and 'install' is already included as one of the arguments
 	installCommandProvidedFromUser := len(installCommandArgs) != 0
 
 	// Upon receiving a user-provided 'install' command, we execute the command exactly as provided
 	if installCommandProvidedFromUser {
-		return build.RunYarnCommand(yarnExecPath, curWd, installCommandArgs...)
+		return runYarnCommand(ctx, yarnExecPath, curWd, installCommandArgs, treesInfo)
 	}
 
 	installCommandArgs = []string{"install"}
@@ -192,18 +212,65 @@ func runYarnInstallAccordingToVersion(curWd, yarnExecPath string, installCommand
 			installCommandArgs = append(installCommandArgs, v3UpdateLockfileFlag, v3SkipBuildFlag)
 		}
 	}
-	err = build.RunYarnCommand(yarnExecPath, curWd, installCommandArgs...)
+	err = runYarnCommand(ctx, yarnExecPath, curWd, installCommandArgs, treesInfo)
+	return
+}
+
+// Runs the Yarn command in the given directory, printing its output to the stderr, like build-info-go does. The command is killed once the given context is done.
+func runYarnCommand(ctx context.Context, yarnExecPath, curWd string, args []string, treesInfo *utils.DependencyTreesInfo) error {
+	treesInfo.RecordExecutedCommand(yarnExecPath, args...)
+	command := sca.CreateCommand(ctx, yarnExecPath, args...)
+	command.Dir = curWd
+	command.Stdout = os.Stderr
+	command.Stderr = os.Stderr
+	err := command.Run()
+	// urfave/cli (aka codegangsta) exits when an ExitError is returned, so it's converted to a regular error.
+	if _, ok := err.(*exec.ExitError); ok {
+		err = errors.New(err.Error())
+	}
+	return errorutils.CheckError(err)
+}
+
+// The command that lists the Yarn dependencies is executed by build-info-go, so it's recorded here according to the Yarn version.
+func recordYarnDependenciesCommand(treesInfo *utils.DependencyTreesInfo, yarnExecPath, curWd string) error {
+	executableVersionStr, err := biutils.GetVersion(yarnExecPath, curWd)
+	if err != nil {
+		return err
+	}
+	if version.NewVersion(executableVersionStr).Compare(yarnV2Version) <= 0 {
+		treesInfo.RecordExecutedCommand(yarnExecPath, "info", "--all", "--recursive", "--json")
+	} else {
+		treesInfo.RecordExecutedCommand(yarnExecPath, "list", "--json", "--flat", "--no-progress")
+	}
+	return nil
+}
+
+// Returns the names of the direct dependencies that are declared only as dev dependencies in the package.json.
+func getDevOnlyDirectDependencies(packageInfo *biutils.PackageInfo) (devOnlyDependencies []string) {
+	for name := range packageInfo.DevDependencies {
+		_, prod := packageInfo.Dependencies[name]
+		_, optional := packageInfo.OptionalDependencies[name]
+		_, peer := packageInfo.PeerDependencies[name]
+		if !prod && !optional && !peer {
+			devOnlyDependencies = append(devOnlyDependencies, name)
+		}
+	}
 	return
 }
 
-// Parse the dependencies into a Xray dependency tree format
-func parseYarnDependenciesMap(dependencies map[string]*biutils.YarnDependency, rootXrayId string) (*xrayUtils.GraphNode, []string) {
+// Parse the dependencies into a Xray dependency tree format.
+// The given direct dependencies of the root are excluded, with the dependencies that are required only by them.
+func parseYarnDependenciesMap(dependencies map[string]*biutils.YarnDependency, rootXrayId string, excludedDirectDependencies ...string) (*xrayUtils.GraphNode, []string) {
 	treeMap := make(map[string][]string)
 	for _, dependency := range dependencies {
 		xrayDepId := getXrayDependencyId(dependency)
 		var subDeps []string
 		for _, subDepPtr := range dependency.Details.Dependencies {
-			subDeps = append(subDeps, getXrayDependencyId(dependencies[biutils.GetYarnDependencyKeyFromLocator(subDepPtr.Locator)]))
+			subDep := dependencies[biutils.GetYarnDependencyKeyFromLocator(subDepPtr.Locator)]
+			if xrayDepId == rootXrayId && slices.Contains(excludedDirectDependencies, subDep.Name()) {
+				continue
+			}
+			subDeps = append(subDeps, getXrayDependencyId(subDep))
 		}
 		if len(subDeps) > 0 {
 			treeMap[xrayDepId] = subDeps
@@ -1,6 +1,7 @@
 package yarn
 
 import (
+	"context"
 	"github.com/jfrog/build-info-go/build"
 	biutils "github.com/jfrog/build-info-go/build/utils"
 	utils2 "github.com/jfrog/build-info-go/utils"
@@ -50,6 +51,25 @@ func TestParseYarnDependenciesList(t *testing.T) {
 	xrayDependenciesTree, uniqueDeps := parseYarnDependenciesMap(yarnDependencies, rootXrayId)
 	assert.ElementsMatch(t, uniqueDeps, expectedUniqueDeps, "First is actual, Second is Expected")
 	assert.True(t, tests.CompareTree(expectedTree, xrayDependenciesTree), "expected:", expectedTree.Nodes, "got:", xrayDependenciesTree.Nodes)
+
+	// Excluding a direct dependency also excludes the dependencies that are required only by it
+	expectedTree.Nodes = expectedTree.Nodes[:1]
+	expectedUniqueDeps = []string{
+		utils.NpmPackageTypeIdentifier + "pack1:1.0.0",
+		utils.NpmPackageTypeIdentifier + "pack4:4.0.0",
+		utils.NpmPackageTypeIdentifier + "@jfrog/pack3:3.0.0",
+	}
+	xrayDependenciesTree, uniqueDeps = parseYarnDependenciesMap(yarnDependencies, rootXrayId, "pack2")
+	assert.ElementsMatch(t, uniqueDeps, expectedUniqueDeps, "First is actual, Second is Expected")
+	assert.True(t, tests.CompareTree(expectedTree, xrayDependenciesTree), "expected:", expectedTree.Nodes, "got:", xrayDependenciesTree.Nodes)
+}
+
+func TestGetDevOnlyDirectDependencies(t *testing.T) {
+	packageInfo := &biutils.PackageInfo{
+		Dependencies:    map[string]string{"lodash": "^4.17.21", "is-number": "^6.0.0"},
+		DevDependencies: map[string]string{"is-odd": "^3.0.1", "is-number": "^6.0.0"},
+	}
+	assert.Equal(t, []string{"is-odd"}, getDevOnlyDirectDependencies(packageInfo))
 }
 
 func TestIsInstallRequired(t *testing.T) {
@@ -91,8 +111,13 @@ func executeRunYarnInstallAccordingToVersionAndVerifyInstallation(t *testing.T,
 	executablePath, err := biutils.GetYarnExecutable()
 	assert.NoError(t, err)
 
-	err = runYarnInstallAccordingToVersion(tempDirPath, executablePath, params)
+	treesInfo := utils.NewDependencyTreesInfo(true)
+	err = runYarnInstallAccordingToVersion(context.Background(), tempDirPath, executablePath, params, treesInfo)
 	assert.NoError(t, err)
+	// The install command is recorded
+	if assert.Len(t, treesInfo.ExecutedCommands, 1) {
+		assert.Contains(t, treesInfo.ExecutedCommands[0], "install")
+	}
 
 	// Checking the installation worked - we expect to get a 'false' answer when checking whether the project is installed
 	installRequired, err := isInstallRequired(tempDirPath, []string{})
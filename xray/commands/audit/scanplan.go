@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"golang.org/x/exp/slices"
+)
+
+// The content of a scan plan file, which lists the SCA scans to perform.
+// The plan that the audit computes can be written to a file, edited, and given back to the audit to perform exactly the listed scans.
+type scaScanPlan struct {
+	Scans []scaScanPlanEntry `json:"Scans"`
+}
+
+type scaScanPlanEntry struct {
+	Technology coreutils.Technology `json:"Technology"`
+	// Relative paths are relative to the working directory of the audit.
+	WorkingDirectory string   `json:"WorkingDirectory"`
+	Descriptors      []string `json:"Descriptors,omitempty"`
+}
+
+// Writes the given scans to a scan plan file in the given path.
+func writeScaScanPlan(planPath string, scans []*xrayutils.ScaScanResult) error {
+	plan := scaScanPlan{Scans: []scaScanPlanEntry{}}
+	for _, scan := range scans {
+		plan.Scans = append(plan.Scans, scaScanPlanEntry{Technology: scan.Technology, WorkingDirectory: scan.WorkingDirectory, Descriptors: scan.Descriptors})
+	}
+	// The plan is meant to be edited manually, so it is indented.
+	content, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(planPath, content, 0644))
+}
+
+// Reads the scans of the scan plan file in the given path.
+// Each scan must be of a known technology, or of a technology with a registered dependency tree builder, in an existing directory, and its descriptors, if listed, must exist.
+func readScaScanPlan(planPath, currentWorkingDir string) (scans []*xrayutils.ScaScanResult, err error) {
+	content, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	plan := &scaScanPlan{}
+	if err = json.Unmarshal(content, plan); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the scan plan file '%s': %s", planPath, err.Error())
+	}
+	technologies := coreutils.GetAllTechnologiesList()
+	var validationErrors error
+	for i, entry := range plan.Scans {
+		if _, registered := getDependencyTreeBuilder(entry.Technology); !registered && !slices.Contains(technologies, entry.Technology) {
+			validationErrors = errors.Join(validationErrors, fmt.Errorf("scan %d: unsupported technology '%s'", i+1, entry.Technology))
+			continue
+		}
+		if entry.WorkingDirectory == "" {
+			validationErrors = errors.Join(validationErrors, fmt.Errorf("scan %d: the working directory is missing", i+1))
+			continue
+		}
+		scan := &xrayutils.ScaScanResult{Technology: entry.Technology, WorkingDirectory: getPlannedPath(entry.WorkingDirectory, currentWorkingDir)}
+		exists, e := fileutils.IsDirExists(scan.WorkingDirectory, false)
+		if e != nil {
+			return nil, e
+		}
+		if !exists {
+			validationErrors = errors.Join(validationErrors, fmt.Errorf("scan %d: the working directory '%s' doesn't exist", i+1, entry.WorkingDirectory))
+			continue
+		}
+		for _, descriptor := range entry.Descriptors {
+			descriptorPath := getPlannedPath(descriptor, currentWorkingDir)
+			if exists, e = fileutils.IsFileExists(descriptorPath, false); e != nil {
+				return nil, e
+			}
+			if !exists {
+				validationErrors = errors.Join(validationErrors, fmt.Errorf("scan %d: the descriptor '%s' doesn't exist", i+1, descriptor))
+				continue
+			}
+			scan.Descriptors = append(scan.Descriptors, descriptorPath)
+		}
+		scans = append(scans, scan)
+	}
+	if validationErrors != nil {
+		return nil, errorutils.CheckErrorf("invalid scan plan file '%s':\n%s", planPath, validationErrors.Error())
+	}
+	return
+}
+
+func getPlannedPath(plannedPath, currentWorkingDir string) string {
+	if filepath.IsAbs(plannedPath) {
+		return filepath.Clean(plannedPath)
+	}
+	return filepath.Join(currentWorkingDir, plannedPath)
+}
@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	biutils "github.com/jfrog/build-info-go/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaScanPlanRoundTrip(t *testing.T) {
+	skipIfNpmIsMissing(t)
+	tmpDir := t.TempDir()
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "npm-identical-modules"), tmpDir, true, nil))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.Chdir(wd))
+	}()
+	assert.NoError(t, os.Chdir(tmpDir))
+
+	var scanRequests int
+	testServer := createCustomXrayScanGraphMockServer(t, func(*xrayUtils.GraphNode) (string, int) {
+		scanRequests++
+		return "plan-scan", 0
+	}, func(string) any {
+		return services.ScanResponse{ScanId: "plan-scan"}
+	}, nil)
+	defer testServer.Close()
+	newParams := func() *AuditParams {
+		params := NewAuditParams()
+		params.SetServerDetails(&config.ServerDetails{XrayUrl: testServer.URL + "/xray/"})
+		params.xrayVersion = "3.80.0"
+		return params
+	}
+
+	// Generate the plan of the detected modules.
+	planPath := filepath.Join(t.TempDir(), "scan-plan.json")
+	assert.NoError(t, runScaScan(newParams().SetScanPlanOutputFile(planPath), xrayutils.NewAuditResults()))
+	assert.Equal(t, 2, scanRequests)
+	content, err := os.ReadFile(planPath)
+	assert.NoError(t, err)
+	plan := &scaScanPlan{}
+	assert.NoError(t, json.Unmarshal(content, plan))
+	assert.Equal(t, []scaScanPlanEntry{
+		{Technology: coreutils.Npm, WorkingDirectory: filepath.Join(tmpDir, "module1"), Descriptors: []string{filepath.Join(tmpDir, "module1", "package.json")}},
+		{Technology: coreutils.Npm, WorkingDirectory: filepath.Join(tmpDir, "module2"), Descriptors: []string{filepath.Join(tmpDir, "module2", "package.json")}},
+	}, plan.Scans)
+
+	// Edit the plan to scan only the second module, with a path relative to the working directory.
+	plan.Scans = []scaScanPlanEntry{{Technology: coreutils.Npm, WorkingDirectory: "module2"}}
+	content, err = json.Marshal(plan)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(planPath, content, 0644))
+
+	// Scan with the edited plan.
+	scanRequests = 0
+	results := xrayutils.NewAuditResults()
+	assert.NoError(t, runScaScan(newParams().SetScanPlanFile(planPath), results))
+	assert.Equal(t, 1, scanRequests)
+	if assert.Len(t, results.ScaResults, 1) {
+		assert.Equal(t, coreutils.Npm, results.ScaResults[0].Technology)
+		assert.Equal(t, filepath.Join(tmpDir, "module2"), results.ScaResults[0].WorkingDirectory)
+	}
+}
+
+func TestReadScaScanPlanValidation(t *testing.T) {
+	tmpDir := t.TempDir()
+	planPath := filepath.Join(tmpDir, "scan-plan.json")
+	assert.NoError(t, os.WriteFile(planPath, []byte(`{"Scans": [
+  {"Technology": "npm", "WorkingDirectory": "."},
+  {"Technology": "cobol", "WorkingDirectory": "."},
+  {"Technology": "go", "WorkingDirectory": "missing"},
+  {"Technology": "maven", "WorkingDirectory": ".", "Descriptors": ["pom.xml"]},
+  {"Technology": "pip"}
+]}`), 0644))
+
+	_, err := readScaScanPlan(planPath, tmpDir)
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "scan 1:")
+		assert.Contains(t, err.Error(), "scan 2: unsupported technology 'cobol'")
+		assert.Contains(t, err.Error(), "scan 3: the working directory 'missing' doesn't exist")
+		assert.Contains(t, err.Error(), "scan 4: the descriptor 'pom.xml' doesn't exist")
+		assert.Contains(t, err.Error(), "scan 5: the working directory is missing")
+	}
+
+	_, err = readScaScanPlan(filepath.Join(tmpDir, "missing.json"), tmpDir)
+	assert.Error(t, err)
+}
+
+func TestRunAuditDryRun(t *testing.T) {
+	acme := coreutils.Technology("acme")
+	builder := &fakeDependencyTreeBuilder{}
+	RegisterDependencyTreeBuilder(acme, builder)
+	defer RegisterDependencyTreeBuilder(acme, nil)
+	projectDir := t.TempDir()
+	planPath := filepath.Join(t.TempDir(), "scan-plan.json")
+	assert.NoError(t, writeScaScanPlan(planPath, []*xrayutils.ScaScanResult{{Technology: acme, WorkingDirectory: projectDir}}))
+	_, logBuffer, previousLog := tests.RedirectLogOutputToBuffer()
+	defer log.SetLogger(previousLog)
+
+	// No server details are configured, so contacting Xray would fail the audit.
+	results, err := RunAudit(NewAuditParams().SetScanPlanFile(planPath).SetDryRun(true))
+	assert.NoError(t, err)
+	assert.NoError(t, results.ScaError)
+	// The plan is logged, but the dependency trees aren't built.
+	assert.Contains(t, logBuffer.String(), "Preforming 1 SCA scans")
+	assert.Contains(t, logBuffer.String(), projectDir)
+	assert.Empty(t, builder.workingDirs)
+	assert.Empty(t, results.ScaResults)
+}
@@ -1,10 +1,17 @@
 package audit
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/jfrog/build-info-go/utils/pythonutils"
@@ -13,23 +20,89 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/bazel"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/bundler"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/composer"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/conan"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/docker"
 	_go "github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/go"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/helm"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/java"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/npm"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/nuget"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/python"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/swift"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca/yarn"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/scangraph"
 	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	"github.com/jfrog/jfrog-client-go/artifactory/services/fspatterns"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	xrayCmdUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 )
 
-var DefaultExcludePatterns = []string{"*.git*", "*node_modules*", "*target*", "*venv*", "*test*"}
+// The maximum number of requested directories in which the technologies are detected concurrently.
+const maxParallelDetections = 5
+
+// The default wait before the first retry of a graph scan that failed with a transient error.
+const defaultScanRetryBaseDelay = time.Second
+
+// Replaces the characters that are not allowed in file names.
+var trafficDumpNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_")
+
+// Matches the responses of Xray to requests that failed because of a server error.
+var transientScanErrorPattern = regexp.MustCompile(`server response: 5\d\d`)
+
+// The test directories are matched by their whole names, so directories such as 'latest' or 'contest' aren't excluded.
+var DefaultExcludePatterns = []string{"*.git*", "*node_modules*", "*target*", "*venv*", "*/test/*", "*/tests/*", "*/__tests__/*", "*/testdata/*"}
+
+// How the requested exclusions are combined with the default exclusions.
+type ExcludePatternsMode string
+
+const (
+	// The requested exclusions replace the default exclusions, which is the default.
+	ReplaceExcludePatterns ExcludePatternsMode = "replace"
+	// The requested exclusions are added to the default exclusions.
+	AppendExcludePatterns ExcludePatternsMode = "append"
+)
+
+func GetExcludePatternsMode(mode string) (ExcludePatternsMode, error) {
+	switch ExcludePatternsMode(mode) {
+	case "", ReplaceExcludePatterns:
+		return ReplaceExcludePatterns, nil
+	case AppendExcludePatterns:
+		return AppendExcludePatterns, nil
+	}
+	return "", errorutils.CheckErrorf("unsupported exclude patterns mode '%s', the supported modes are '%s' and '%s'", mode, ReplaceExcludePatterns, AppendExcludePatterns)
+}
+
+// How to handle a requested technology whose descriptors weren't detected in a requested directory.
+type TechnologyMismatchPolicy string
+
+const (
+	// Skip the scan of the technology in the directory, with a warning, which is the default.
+	WarnOnTechnologyMismatch TechnologyMismatchPolicy = "warn"
+	// Fail the audit.
+	FailOnTechnologyMismatch TechnologyMismatchPolicy = "fail"
+	// Scan the directory with the technology anyway.
+	ScanOnTechnologyMismatch TechnologyMismatchPolicy = "scan"
+)
+
+func GetTechnologyMismatchPolicy(policy string) (TechnologyMismatchPolicy, error) {
+	switch TechnologyMismatchPolicy(policy) {
+	case "", WarnOnTechnologyMismatch:
+		return WarnOnTechnologyMismatch, nil
+	case FailOnTechnologyMismatch, ScanOnTechnologyMismatch:
+		return TechnologyMismatchPolicy(policy), nil
+	}
+	return "", errorutils.CheckErrorf("unsupported technology mismatch policy '%s', the supported policies are '%s', '%s' and '%s'", policy, WarnOnTechnologyMismatch, FailOnTechnologyMismatch, ScanOnTechnologyMismatch)
+}
 
 func runScaScan(params *AuditParams, results *xrayutils.Results) (err error) {
 	// Prepare
@@ -42,64 +115,381 @@ func runScaScan(params *AuditParams, results *xrayutils.Results) (err error) {
 		return
 	}
 
-	scans := getScaScansToPreform(currentWorkingDir, params)
+	if params.buildName != "" {
+		return runBuildInfoScaScan(currentWorkingDir, serverDetails, params, results)
+	}
+	// Record the exclusions that the technologies detection applies, so the results document what was excluded.
+	results.EffectiveExclusions = params.EffectiveExclusions()
+	var scans []*xrayutils.ScaScanResult
+	if params.scanPlanFile != "" {
+		// The scans of the plan are performed as is, without detecting the technologies.
+		if scans, err = readScaScanPlan(params.scanPlanFile, currentWorkingDir); err != nil {
+			return
+		}
+	} else {
+		if scans, err = getScaScansToPreform(currentWorkingDir, params); err != nil {
+			return
+		}
+	}
+	if params.scanPlanOutputFile != "" {
+		if err = writeScaScanPlan(params.scanPlanOutputFile, scans); err != nil {
+			return
+		}
+		log.Info(fmt.Sprintf("The plan of the SCA scans was written to '%s'", params.scanPlanOutputFile))
+	}
 	if len(scans) == 0 {
+		if params.failOnNoTechnologies {
+			return errorutils.CheckErrorf("couldn't determine a package manager or build tool used by the project in '%s'", currentWorkingDir)
+		}
 		log.Info("Couldn't determine a package manager or build tool used by this project. Skipping the SCA scan...")
 		return
 	}
-	scanInfo, err := coreutils.GetJsonIndent(scans)
-	if err != nil {
+	if err = logScaScansPlanned(params, scans); err != nil {
+		return
+	}
+	if params.dryRun {
+		log.Info(fmt.Sprintf("Dry run: skipping the execution of the %d SCA scans.", len(scans)))
 		return
 	}
-	log.Info(fmt.Sprintf("Preforming %d SCA scans:\n%s", len(scans), scanInfo))
 
-	defer func() {
-		// Make sure to return to the original working directory, executeScaScan may change it
-		err = errors.Join(err, os.Chdir(currentWorkingDir))
-	}()
-	for _, scan := range scans {
-		// Run the scan
-		log.Info("Running SCA scan for", scan.Technology, "vulnerable dependencies in", scan.WorkingDirectory, "directory...")
-		if wdScanErr := executeScaScan(serverDetails, params, scan); wdScanErr != nil {
-			err = errors.Join(err, fmt.Errorf("audit command in '%s' failed:\n%s", scan.WorkingDirectory, wdScanErr.Error()))
-			continue
+	var checkpoint *scaScanCheckpoint
+	if params.resumeFrom != "" {
+		if checkpoint, err = readScaScanCheckpoint(params.resumeFrom); err != nil {
+			return
+		}
+	}
+	// The results of each scan, by the index of the scan. A scan of an identical module is shared by the index of its scan.
+	scansResults := make([]*xrayutils.ScaScanResult, len(scans))
+	sharedScans := map[int]int{}
+	var scansToExecute []int
+	// Maps the descriptors hash of each scanned module to the index of its scan, when identical modules are deduplicated.
+	scannedModules := map[string]int{}
+	for i, scan := range scans {
+		var modulesHash string
+		if params.deduplicateIdenticalModules {
+			if modulesHash, err = getDescriptorsHash(scan); err != nil {
+				return
+			}
+			if sharedScanIndex, exists := scannedModules[modulesHash]; exists {
+				sharedScans[i] = sharedScanIndex
+				continue
+			}
+		}
+		if completedScan := checkpoint.completedScan(scan); completedScan != nil {
+			logScaScanRestored(params, completedScan)
+			scansResults[i] = completedScan
+		} else {
+			scansToExecute = append(scansToExecute, i)
+		}
+		if modulesHash != "" {
+			scannedModules[modulesHash] = i
+		}
+	}
+	scansErrors := executeScaScans(serverDetails, params, scans, scansToExecute, scansResults, checkpoint)
+	if params.dependencyTreeOutputFile != "" {
+		// The trees of the scans that were built are written even if other scans failed.
+		if writeErr := writeDependencyTrees(params.dependencyTreeOutputFile, params, scans); writeErr != nil {
+			err = errors.Join(err, writeErr)
+		} else {
+			log.Info(fmt.Sprintf("The dependency trees were written to '%s'", params.dependencyTreeOutputFile))
+		}
+	}
+
+	// Add the results and the outcomes in the order of the scans
+	for i, scan := range scans {
+		outcome := xrayutils.ScaScanOutcome{Technology: scan.Technology, WorkingDirectory: scan.WorkingDirectory}
+		if sharedScanIndex, isShared := sharedScans[i]; isShared {
+			if sharedScan := scansResults[sharedScanIndex]; sharedScan == nil {
+				outcome.Err = fmt.Errorf("audit command in '%s' failed:\nthe scan of the identical module in '%s' failed", scan.WorkingDirectory, scans[sharedScanIndex].WorkingDirectory)
+			} else {
+				logScaScanShared(params, scan, sharedScan)
+				results.ScaResults = append(results.ScaResults, shareScanResults(sharedScan, scan))
+			}
+		} else if scansErrors[i] != nil {
+			outcome.Err = scansErrors[i]
+		} else {
+			results.ScaResults = append(results.ScaResults, *scansResults[i])
+		}
+		results.ScaScansOutcomes = append(results.ScaScansOutcomes, outcome)
+		err = errors.Join(err, outcome.Err)
+	}
+	if params.sbomOutputPath != "" {
+		// The SBOM covers the scans that succeeded, even if other scans failed.
+		if sbomErr := writeSbom(params, results.ScaResults); sbomErr != nil {
+			err = errors.Join(err, sbomErr)
+		} else {
+			log.Info(fmt.Sprintf("The SBOM of the scanned projects was written to '%s'", params.sbomOutputPath))
 		}
-		// Add the scan to the results
-		results.ScaResults = append(results.ScaResults, *scan)
 	}
 	return
 }
 
+// Executes the scans of the given indexes, with up to the maximum number of parallel scans, and writes the result of each scan to its index.
+// Returns the errors of the scans by their indexes.
+func executeScaScans(serverDetails *config.ServerDetails, params *AuditParams, scans []*xrayutils.ScaScanResult, scansToExecute []int, scansResults []*xrayutils.ScaScanResult, checkpoint *scaScanCheckpoint) []error {
+	scansErrors := make([]error, len(scans))
+	var checkpointMutex sync.Mutex
+	errGroup := new(errgroup.Group)
+	errGroup.SetLimit(params.MaxParallelScans())
+	for _, i := range scansToExecute {
+		index := i
+		errGroup.Go(func() error {
+			// Each routine writes only to its own index, so no locking is needed.
+			scan := scans[index]
+			logScaScanStarted(params, scan)
+			scanErr := executeScaScanWithTimeout(serverDetails, params, scan)
+			logScaScanDone(params, scan, scanErr)
+			if scanErr != nil {
+				scansErrors[index] = fmt.Errorf("audit command in '%s' failed:\n%s", scan.WorkingDirectory, scanErr.Error())
+				return nil
+			}
+			scansResults[index] = scan
+			if checkpoint != nil {
+				checkpointMutex.Lock()
+				defer checkpointMutex.Unlock()
+				// Failing to update the checkpoint shouldn't fail the audit, only a resume of it would repeat the scan.
+				if checkpointErr := checkpoint.addCompletedScan(params.resumeFrom, scan); checkpointErr != nil {
+					log.Warn(fmt.Sprintf("Couldn't record the %s scan in '%s' in the checkpoint: %s", scan.Technology.ToFormal(), scan.WorkingDirectory, checkpointErr.Error()))
+				}
+			}
+			return nil
+		})
+	}
+	// The errors are kept per scan, so Wait never fails.
+	_ = errGroup.Wait()
+	return scansErrors
+}
+
+// Returns a hash of the technology and the content of the descriptors of the scanned module, so identical modules get the same hash.
+// The descriptors are identified by their paths relative to the module's working directory.
+// Returns an empty string for modules without descriptors, which are never considered identical.
+func getDescriptorsHash(scan *xrayutils.ScaScanResult) (string, error) {
+	if len(scan.Descriptors) == 0 {
+		return "", nil
+	}
+	descriptors := slices.Clone(scan.Descriptors)
+	slices.Sort(descriptors)
+	hash := sha256.New()
+	hash.Write([]byte(scan.Technology.String()))
+	for _, descriptor := range descriptors {
+		relativePath, err := filepath.Rel(scan.WorkingDirectory, descriptor)
+		if err != nil {
+			return "", errorutils.CheckError(err)
+		}
+		content, err := os.ReadFile(descriptor)
+		if err != nil {
+			return "", errorutils.CheckError(err)
+		}
+		// Separate the path from the content, so different files can't produce the same input.
+		hash.Write([]byte(filepath.ToSlash(relativePath) + "\x00"))
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Returns the results of the given module, sharing the results of the scan of an identical module.
+func shareScanResults(sharedScan, scan *xrayutils.ScaScanResult) xrayutils.ScaScanResult {
+	result := *scan
+	result.XrayResults = sharedScan.XrayResults
+	result.IsMultipleRootProject = sharedScan.IsMultipleRootProject
+	result.IssueReporters = sharedScan.IssueReporters
+	result.UnscannedComponents = sharedScan.UnscannedComponents
+	result.SharedScanWorkingDirectory = sharedScan.WorkingDirectory
+	result.DirectDependencies = sharedScan.DirectDependencies
+	if sharedScan.Coverage != nil {
+		// The metrics of the identical module apply, but the descriptors are of this module.
+		coverage := *sharedScan.Coverage
+		coverage.Descriptors = scan.Descriptors
+		result.Coverage = &coverage
+	}
+	return result
+}
+
+// Returns the SCA scans that an audit of the given working directory, or of the working directories of the params if set, would preform.
+// The technologies are detected exactly as they are detected by the audit, but the dependency trees aren't built nor scanned.
+// This allows tools, such as IDE integrations, to show the detected projects and their package managers.
+func DetectScaScans(workingDir string, params *AuditParams) ([]*xrayutils.ScaScanResult, error) {
+	return getScaScansToPreform(workingDir, params)
+}
+
 // Calculate the scans to preform
-func getScaScansToPreform(currentWorkingDir string, params *AuditParams) (scansToPreform []*xrayutils.ScaScanResult) {
+func getScaScansToPreform(currentWorkingDir string, params *AuditParams) (scansToPreform []*xrayutils.ScaScanResult, err error) {
 	requestedDirectories, isRecursive := getRequestedDirectoriesToScan(currentWorkingDir, params)
+	// Directories may be reachable via different paths when symlinks are involved.
+	// Track the real paths of the visited directories to avoid scanning the same physical directory more than once.
+	visitedDirectories := datastructures.MakeSet[string]()
+	var directoriesToDetect []string
 	for _, requestedDirectory := range requestedDirectories {
-		// Detect descriptors and technologies in the requested directory.
-		techToWorkingDirs, err := coreutils.DetectTechnologiesDescriptors(requestedDirectory, isRecursive, params.Technologies(), getRequestedDescriptors(params), getExcludePattern(params, isRecursive))
-		if err != nil {
-			log.Warn("Couldn't detect technologies in", requestedDirectory, "directory.", err.Error())
+		realPath := getWorkingDirKey(getRealPath(requestedDirectory))
+		if visitedDirectories.Exists(realPath) {
+			log.Debug(fmt.Sprintf("Skipping '%s' directory, its real path '%s' was already requested.", requestedDirectory, realPath))
+			continue
+		}
+		visitedDirectories.Add(realPath)
+		directoriesToDetect = append(directoriesToDetect, requestedDirectory)
+	}
+	// Detect descriptors and technologies in the requested directories.
+	detections := detectTechnologiesInDirectories(directoriesToDetect, isRecursive, params)
+	includePattern := getIncludePattern(params)
+	plannedScans := datastructures.MakeSet[string]()
+	for i, requestedDirectory := range directoriesToDetect {
+		if detections[i].err != nil {
+			log.Warn("Couldn't detect technologies in", requestedDirectory, "directory.", detections[i].err.Error())
 			continue
 		}
-		// Create scans to preform
-		for tech, workingDirs := range techToWorkingDirs {
+		techToWorkingDirs := detections[i].techToWorkingDirs
+		// Create scans to preform, in a deterministic order
+		techs := maps.Keys(techToWorkingDirs)
+		slices.Sort(techs)
+		for _, tech := range techs {
 			if tech == coreutils.Dotnet {
 				// We detect Dotnet and Nuget the same way, if one detected so does the other.
 				// We don't need to scan for both and get duplicate results.
 				continue
 			}
+			workingDirs := techToWorkingDirs[tech]
 			if len(workingDirs) == 0 {
-				// Requested technology (from params) descriptors/indicators was not found, scan only requested directory for this technology.
-				scansToPreform = append(scansToPreform, &xrayutils.ScaScanResult{WorkingDirectory: requestedDirectory, Technology: tech})
+				// Requested technology (from params) descriptors/indicators was not found.
+				switch params.OnTechnologyMismatch() {
+				case FailOnTechnologyMismatch:
+					return nil, errorutils.CheckErrorf("%s was requested, but none of its descriptors were detected in '%s'", tech.ToFormal(), requestedDirectory)
+				case ScanOnTechnologyMismatch:
+					// Scan only the requested directory for this technology.
+					scansToPreform = append(scansToPreform, &xrayutils.ScaScanResult{WorkingDirectory: requestedDirectory, Technology: tech})
+				default:
+					log.Warn(fmt.Sprintf("%s was requested, but none of its descriptors were detected in '%s'. Skipping its scan in this directory...", tech.ToFormal(), requestedDirectory))
+				}
 			}
-			for workingDir, descriptors := range workingDirs {
+			sortedWorkingDirs := maps.Keys(workingDirs)
+			slices.Sort(sortedWorkingDirs)
+			for _, workingDir := range sortedWorkingDirs {
+				if !isIncludedWorkingDir(currentWorkingDir, workingDir, includePattern) {
+					log.Debug(fmt.Sprintf("Skipping %s scan in '%s' directory, it doesn't match any of the include patterns.", tech.ToFormal(), workingDir))
+					continue
+				}
+				scanKey := tech.String() + ":" + getWorkingDirKey(getRealPath(workingDir))
+				if plannedScans.Exists(scanKey) {
+					log.Debug(fmt.Sprintf("Skipping %s scan in '%s' directory, the same physical directory is already planned to be scanned.", tech.ToFormal(), workingDir))
+					continue
+				}
+				plannedScans.Add(scanKey)
 				// Add scan for each detected working directory.
-				scansToPreform = append(scansToPreform, &xrayutils.ScaScanResult{WorkingDirectory: workingDir, Technology: tech, Descriptors: descriptors})
+				scansToPreform = append(scansToPreform, &xrayutils.ScaScanResult{WorkingDirectory: workingDir, Technology: tech, Descriptors: workingDirs[workingDir]})
 			}
 		}
 	}
+	scansToPreform = coalesceDuplicateScans(scansToPreform)
+	if params.changedSince != "" {
+		scansToPreform = filterScansOfChangedModules(currentWorkingDir, params.changedSince, scansToPreform)
+	}
+	return
+}
+
+// Coalesces the scans of different technologies that have the same working directory and descriptors, since they would scan the same project twice.
+// The scan of the technology that is preferred over the others is kept, or the first of the scans if none is preferred.
+// Scans without descriptors are scans of explicitly requested technologies, so they are always kept.
+func coalesceDuplicateScans(scans []*xrayutils.ScaScanResult) (coalescedScans []*xrayutils.ScaScanResult) {
+	scansIndexes := map[string]int{}
+	for _, scan := range scans {
+		if len(scan.Descriptors) == 0 {
+			coalescedScans = append(coalescedScans, scan)
+			continue
+		}
+		scanKey := getDescriptorsScanKey(scan)
+		index, exists := scansIndexes[scanKey]
+		if !exists {
+			scansIndexes[scanKey] = len(coalescedScans)
+			coalescedScans = append(coalescedScans, scan)
+			continue
+		}
+		keptScan, droppedScan := coalescedScans[index], scan
+		if droppedScan.Technology.IsPreferredOver(keptScan.Technology) {
+			keptScan, droppedScan = droppedScan, keptScan
+			coalescedScans[index] = keptScan
+		}
+		log.Info(fmt.Sprintf("The %s and %s projects in '%s' have the same descriptors. Scanning it as a %s project only...", keptScan.Technology.ToFormal(), droppedScan.Technology.ToFormal(), keptScan.WorkingDirectory, keptScan.Technology.ToFormal()))
+	}
 	return
 }
 
+// Returns a key of the scan's physical working directory and its descriptors, relative to the working directory.
+func getDescriptorsScanKey(scan *xrayutils.ScaScanResult) string {
+	descriptors := make([]string, 0, len(scan.Descriptors))
+	for _, descriptor := range scan.Descriptors {
+		if relativePath, err := filepath.Rel(scan.WorkingDirectory, descriptor); err == nil {
+			descriptor = relativePath
+		}
+		descriptors = append(descriptors, filepath.ToSlash(descriptor))
+	}
+	slices.Sort(descriptors)
+	return getWorkingDirKey(getRealPath(scan.WorkingDirectory)) + "\x00" + strings.Join(descriptors, "\x00")
+}
+
+type technologiesDetection struct {
+	techToWorkingDirs map[coreutils.Technology]map[string][]string
+	err               error
+}
+
+// Detect the technologies in each of the given directories, in parallel.
+// The detection of each directory is returned at the same index as the directory.
+func detectTechnologiesInDirectories(directories []string, isRecursive bool, params *AuditParams) []technologiesDetection {
+	detections := make([]technologiesDetection, len(directories))
+	requestedDescriptors := getRequestedDescriptors(params)
+	excludePattern := getExcludePattern(params, isRecursive)
+	errGroup := new(errgroup.Group)
+	errGroup.SetLimit(maxParallelDetections)
+	for i, directory := range directories {
+		index, dir := i, directory
+		errGroup.Go(func() error {
+			// Each routine writes only to its own index, so no locking is needed.
+			techToWorkingDirs, err := coreutils.DetectTechnologiesDescriptors(dir, isRecursive, params.Technologies(), requestedDescriptors, params.DescriptorPrecedence(), excludePattern)
+			if err == nil && params.ScanDockerfileBaseImages() {
+				techToWorkingDirs, err = addDetectedDockerfiles(techToWorkingDirs, dir, isRecursive, excludePattern)
+			}
+			detections[index].techToWorkingDirs, detections[index].err = techToWorkingDirs, err
+			return nil
+		})
+	}
+	// The errors are kept per directory, so Wait never fails.
+	_ = errGroup.Wait()
+	return detections
+}
+
+// Adds the directories with Dockerfiles to the detected technologies, so the base images of the Dockerfiles are scanned alongside the projects.
+func addDetectedDockerfiles(techToWorkingDirs map[coreutils.Technology]map[string][]string, dir string, isRecursive bool, excludePattern string) (map[coreutils.Technology]map[string][]string, error) {
+	workingDirToDockerfiles, err := docker.DetectDockerfiles(dir, isRecursive, excludePattern)
+	if err != nil || len(workingDirToDockerfiles) == 0 {
+		return techToWorkingDirs, err
+	}
+	if techToWorkingDirs == nil {
+		techToWorkingDirs = map[coreutils.Technology]map[string][]string{}
+	}
+	techToWorkingDirs[coreutils.Docker] = workingDirToDockerfiles
+	return techToWorkingDirs, nil
+}
+
+// Returns the real path of the given directory, after resolving all the symlinks in it.
+// If the path cannot be resolved, it is returned as is.
+func getRealPath(path string) string {
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Couldn't resolve the real path of '%s': %s", path, err.Error()))
+		return path
+	}
+	return realPath
+}
+
+// Returns a key that identifies the given directory, so different spellings of the same directory get the same key.
+// The path is cleaned, and on Windows, where the paths are case-insensitive, the key is also lowercased.
+// Note that filepath.Clean also replaces the slashes with the separator of the OS on Windows.
+func getWorkingDirKey(path string) string {
+	key := filepath.Clean(path)
+	if coreutils.IsWindows() {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
 func getRequestedDescriptors(params *AuditParams) map[coreutils.Technology][]string {
 	requestedDescriptors := map[coreutils.Technology][]string{}
 	if params.PipRequirementsFile() != "" {
@@ -108,68 +498,442 @@ func getRequestedDescriptors(params *AuditParams) map[coreutils.Technology][]str
 	return requestedDescriptors
 }
 
+// Returns a single regular expression that matches the paths excluded by any of the effective exclusions.
+// fspatterns.PrepareExcludePathPattern converts each wildcard pattern to a regular expression, and joins them as alternatives, such as '(^.*node_modules.*$)|(^.*dist.*$)'.
+// The expression is matched against the paths relative to the scanned directory.
 func getExcludePattern(params *AuditParams, recursive bool) string {
-	exclusions := params.Exclusions()
-	if len(exclusions) == 0 {
-		exclusions = append(exclusions, DefaultExcludePatterns...)
+	return fspatterns.PrepareExcludePathPattern(params.EffectiveExclusions(), clientutils.WildCardPattern, recursive)
+}
+
+// Returns a regular expression of the include patterns, or an empty string if all the detected directories are included.
+// Unlike the exclusions, the include patterns aren't applied while walking the directories, so they only filter the directories where projects were detected.
+func getIncludePattern(params *AuditParams) string {
+	return fspatterns.PrepareExcludePathPattern(params.IncludePatterns(), clientutils.WildCardPattern, true)
+}
+
+// Returns true if there is no include pattern, or the path of the working directory, relative to the current working directory, matches it.
+// Working directories outside the current working directory are matched by their absolute paths.
+func isIncludedWorkingDir(currentWorkingDir, workingDir, includePattern string) bool {
+	if includePattern == "" {
+		return true
+	}
+	path := workingDir
+	if relativePath, err := filepath.Rel(currentWorkingDir, workingDir); err == nil && !strings.HasPrefix(relativePath, "..") {
+		path = relativePath
+	}
+	included, err := regexp.MatchString(includePattern, path)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Couldn't match '%s' with the include patterns: %s", workingDir, err.Error()))
+		return false
 	}
-	return fspatterns.PrepareExcludePathPattern(exclusions, clientutils.WildCardPattern, recursive)
+	return included
 }
 
 // Get the directories to scan base on the given parameters.
 // If no working directories were specified, the current working directory will be returned with recursive mode.
+// In this case, if requested, the projects that are declared by the monorepo orchestrator of the current working directory are returned instead, without recursive mode.
 // If working directories were specified, the recursive mode will be false.
 func getRequestedDirectoriesToScan(currentWorkingDir string, params *AuditParams) ([]string, bool) {
-	workingDirs := datastructures.MakeSet[string]()
-	for _, wd := range params.workingDirs {
-		workingDirs.Add(wd)
-	}
 	if len(params.workingDirs) == 0 {
+		if params.useMonorepoConfig {
+			projects, err := getMonorepoProjects(currentWorkingDir)
+			if err != nil {
+				log.Warn("Couldn't read the projects of the monorepo, detecting them recursively instead.", err.Error())
+			} else if len(projects) > 0 {
+				return projects, false
+			}
+		}
 		return []string{currentWorkingDir}, true
 	}
-	return workingDirs.ToSlice(), false
+	// Keep the requested order, so the scans are planned deterministically.
+	// Different spellings of the same directory are requested once, by their first spelling.
+	uniqueWorkingDirs := datastructures.MakeSet[string]()
+	var workingDirs []string
+	for _, wd := range params.workingDirs {
+		if key := getWorkingDirKey(wd); !uniqueWorkingDirs.Exists(key) {
+			uniqueWorkingDirs.Add(key)
+			workingDirs = append(workingDirs, filepath.Clean(wd))
+		}
+	}
+	return workingDirs, false
 }
 
+// Some package managers are configured through environment variables of the process, and the resolution repository is set in the params shared by the scans.
+// This is why the dependency trees of one scan are built at a time, even when the scans run in parallel.
+// The lock is a channel, so a scan that times out while waiting for it stops waiting.
+var dependencyTreesLock = make(chan struct{}, 1)
+
+// The technologies whose built-in dependency tree builders run their package manager commands through build-info-go, which can't kill them once the scan times out.
+var uncancellableTreeBuilders = []coreutils.Technology{coreutils.Npm, coreutils.Yarn, coreutils.Go}
+
 // Preform the SCA scan for the given scan information.
-// This method will change the working directory to the scan's working directory.
-func executeScaScan(serverDetails *config.ServerDetails, params *AuditParams, scan *xrayutils.ScaScanResult) (err error) {
-	// Get the dependency tree for the technology in the working directory.
-	if err = os.Chdir(scan.WorkingDirectory); err != nil {
-		return errorutils.CheckError(err)
+// The dependency trees are built one scan at a time, and are then scanned with Xray in parallel to the other scans.
+// Executes the scan, failing it if it doesn't complete within the scan timeout.
+// Once the scan times out, the package manager commands that build its dependency trees are killed, so the scans that wait for the dependency trees lock can proceed.
+// A dependency tree builder that doesn't run its package manager with the given context can't be interrupted, and keeps running in the background until it completes. Its results are discarded, and the next scans don't wait for it.
+func executeScaScanWithTimeout(serverDetails *config.ServerDetails, params *AuditParams, scan *xrayutils.ScaScanResult) error {
+	if params.ScanTimeout() <= 0 {
+		return executeScaScan(context.Background(), serverDetails, params, scan)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), params.ScanTimeout())
+	defer cancel()
+	// The scan is executed on a copy, so a scan that timed out can't modify the results.
+	executedScan := *scan
+	done := make(chan error, 1)
+	go func() {
+		done <- executeScaScan(ctx, serverDetails, params, &executedScan)
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			*scan = executedScan
+		}
+		return err
+	case <-ctx.Done():
+		return errorutils.CheckErrorf("the %s scan timed out after %s. A package manager may be waiting for an unreachable server", scan.Technology.ToFormal(), params.ScanTimeout())
+	}
+}
+
+func executeScaScan(ctx context.Context, serverDetails *config.ServerDetails, params *AuditParams, scan *xrayutils.ScaScanResult) (err error) {
+	flattenTree, fullDependencyTrees, err := buildScanDependencyTrees(ctx, params, scan)
+	if err != nil || flattenTree == nil {
+		return
+	}
+	if params.offlineTreeOnly {
+		log.Info(fmt.Sprintf("Skipping the Xray scan of the %d %s dependencies in '%s', since the audit runs offline.", len(flattenTree.Nodes), scan.Technology.ToFormal(), scan.WorkingDirectory))
+		scan.XrayResults = []services.ScanResponse{}
+		return
+	}
+	return scanFlatTree(ctx, serverDetails, params, scan, flattenTree, fullDependencyTrees)
+}
+
+// Builds the dependency trees of the scan in its working directory and prepares the flat tree to scan with Xray. Returns a nil flat tree if there's nothing to scan.
+// The package manager commands are killed once the given context is done.
+// If the builder can't kill its commands, the dependency trees lock is released once the context is done, so the next scans don't wait for the commands to complete.
+func buildScanDependencyTrees(ctx context.Context, params *AuditParams, scan *xrayutils.ScaScanResult) (flattenTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode, err error) {
+	_, registered := getDependencyTreeBuilder(scan.Technology)
+	releaseLock, err := lockDependencyTrees(ctx, params.AuditBasicParams, !registered && slices.Contains(uncancellableTreeBuilders, scan.Technology))
+	if err != nil {
+		return
+	}
+	defer releaseLock()
+	flattenTree, fullDependencyTrees, treesInfo, techErr := getTechDependencyTreeWithCache(ctx, params, scan)
+	scan.ExecutedCommands = treesInfo.ExecutedCommands
+	scan.DependenciesScopes = treesInfo.DependenciesScopes
+	scan.GitSourcedDependencies = treesInfo.GitSourcedDependencies
+	if len(scan.GitSourcedDependencies) > 0 {
+		gitSourcedDependencies := maps.Keys(scan.GitSourcedDependencies)
+		slices.Sort(gitSourcedDependencies)
+		log.Warn(fmt.Sprintf("The following dependencies were fetched from git repositories, so Xray may not be able to scan them:\n%s", strings.Join(gitSourcedDependencies, "\n")))
 	}
-	flattenTree, fullDependencyTrees, techErr := GetTechDependencyTree(params.AuditBasicParams, scan.Technology)
 	if techErr != nil {
-		return fmt.Errorf("failed while building '%s' dependency tree:\n%s", scan.Technology, techErr.Error())
+		err = fmt.Errorf("failed while building '%s' dependency tree:\n%s", scan.Technology, techErr.Error())
+		return
+	}
+	// The scan timed out while its dependency trees were built, so it must not modify the params that are shared with the other scans.
+	if err = errorutils.CheckError(ctx.Err()); err != nil {
+		return
+	}
+	recordDependencyTrees(params, scan, flattenTree, fullDependencyTrees)
+	flattenTree, err = prepareDependencyTreesScan(ctx, params, scan, flattenTree, fullDependencyTrees)
+	return
+}
+
+// Acquires the dependency trees lock, unless the given context is done first, and returns the function that releases it.
+// The resolution repository of each scan is taken from the configuration nearest to its working directory, so the one of the params is restored before the lock is released.
+// If releaseOnDone is set, the lock is released once the context is done as well, without waiting for the release function to be called.
+func lockDependencyTrees(ctx context.Context, params xrayutils.AuditParams, releaseOnDone bool) (release func(), err error) {
+	select {
+	case dependencyTreesLock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, errorutils.CheckError(ctx.Err())
+	}
+	restoreResolutionRepo := keepResolutionRepo(params)
+	released := make(chan struct{})
+	var releaseOnce sync.Once
+	release = func() {
+		releaseOnce.Do(func() {
+			restoreResolutionRepo()
+			<-dependencyTreesLock
+			close(released)
+		})
+	}
+	if releaseOnDone {
+		go func() {
+			select {
+			case <-ctx.Done():
+				release()
+			case <-released:
+			}
+		}()
 	}
+	return release, nil
+}
+
+// Scan the dependency trees of the given scan with Xray, and add the results to the scan.
+func scanDependencyTrees(serverDetails *config.ServerDetails, params *AuditParams, scan *xrayutils.ScaScanResult, flattenTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode) (err error) {
+	if flattenTree, err = prepareDependencyTreesScan(context.Background(), params, scan, flattenTree, fullDependencyTrees); err != nil || flattenTree == nil {
+		return
+	}
+	return scanFlatTree(context.Background(), serverDetails, params, scan, flattenTree, fullDependencyTrees)
+}
+
+// Adds the dependencies information to the scan, and returns the flat tree of the dependencies to scan with Xray, or nil if there are none.
+func prepareDependencyTreesScan(ctx context.Context, params *AuditParams, scan *xrayutils.ScaScanResult, flattenTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode) (*xrayCmdUtils.GraphNode, error) {
 	if flattenTree == nil || len(flattenTree.Nodes) == 0 {
-		return errorutils.CheckErrorf("no dependencies were found. Please try to build your project and re-run the audit command")
+		return nil, errorutils.CheckErrorf("no dependencies were found. Please try to build your project and re-run the audit command")
+	}
+	// The params are shared with the other scans, so they aren't modified by a scan that timed out.
+	if err := errorutils.CheckError(ctx.Err()); err != nil {
+		return nil, err
+	}
+	scan.IsMultipleRootProject = clientutils.Pointer(len(fullDependencyTrees) > 1)
+	scan.DirectDependenciesLocations = getDirectDependenciesLocations(scan.Technology, scan.WorkingDirectory, fullDependencyTrees)
+	scan.DirectDependencies = getDirectDependenciesFromTree(fullDependencyTrees)
+	slices.Sort(scan.DirectDependencies)
+	scan.Coverage = xrayutils.NewScaScanCoverage(scan.Descriptors, scan.DirectDependencies)
+	if !params.licenseInventoryOnly {
+		addThirdPartyDependenciesToParams(params, scan.Technology, flattenTree, fullDependencyTrees)
+	}
+	// Pre-release components are kept in the dependency trees, but are not sent to Xray.
+	if params.skipPrereleaseScan {
+		var prereleaseDeps []string
+		if flattenTree, prereleaseDeps = sca.FilterPrereleaseDependencies(scan.Technology, flattenTree); len(prereleaseDeps) > 0 {
+			log.Info(fmt.Sprintf("Skipping the scan of %d pre-release %s dependencies", len(prereleaseDeps), scan.Technology.ToFormal()))
+			log.Debug("Skipped pre-release dependencies:\n" + strings.Join(prereleaseDeps, "\n"))
+		}
+		if len(flattenTree.Nodes) == 0 {
+			return nil, nil
+		}
+	}
+	// Only the dependencies that were added since the base ref are sent to Xray.
+	if params.diffBaseRef != "" {
+		var err error
+		if flattenTree, err = getDependenciesAddedSinceRef(ctx, params, scan.Technology, scan.WorkingDirectory, flattenTree); err != nil || len(flattenTree.Nodes) == 0 {
+			return nil, err
+		}
 	}
-	// Scan the dependency tree.
-	scanResults, xrayErr := runScaWithTech(scan.Technology, params, serverDetails, flattenTree, fullDependencyTrees)
+	scan.Coverage.SubmittedComponents = len(flattenTree.Nodes)
+	return flattenTree, nil
+}
+
+// Scan the flat tree of the dependencies of the given scan with Xray, and add the results to the scan.
+// The scan isn't retried once the given context is done.
+func scanFlatTree(ctx context.Context, serverDetails *config.ServerDetails, params *AuditParams, scan *xrayutils.ScaScanResult, flattenTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode) (err error) {
+	// The publication dates of the CVEs are read from the Xray results only if the reported findings are narrowed by them.
+	var cvesPublicationDates *scangraph.CvesPublicationDates
+	if !params.cvesPublishedSince.IsZero() {
+		cvesPublicationDates = scangraph.NewCvesPublicationDates()
+	}
+	scanResults, issueReporters, xrayErr := runScaWithTech(ctx, scan.Technology, scan.WorkingDirectory, params, serverDetails, flattenTree, fullDependencyTrees, cvesPublicationDates)
 	if xrayErr != nil {
 		return fmt.Errorf("'%s' Xray dependency tree scan request failed:\n%s", scan.Technology, xrayErr.Error())
 	}
-	scan.IsMultipleRootProject = clientutils.Pointer(len(fullDependencyTrees) > 1)
-	addThirdPartyDependenciesToParams(params, scan.Technology, flattenTree, fullDependencyTrees)
 	scan.XrayResults = append(scan.XrayResults, scanResults...)
+	scan.IssueReporters = issueReporters
+	if cvesPublicationDates != nil {
+		scan.CvesPublicationDates = cvesPublicationDates.Dates()
+	}
+	if params.xrayGraphScanParams.IncludeLicenses {
+		scan.UnscannedComponents = sca.GetUnscannedComponents(flattenTree, scanResults)
+		scan.Coverage.ComponentsWithData = clientutils.Pointer(len(flattenTree.Nodes) - len(scan.UnscannedComponents))
+		if len(scan.UnscannedComponents) > 0 {
+			log.Warn(fmt.Sprintf("Xray has no data for %d of the %s dependencies, so they couldn't be scanned:\n%s", len(scan.UnscannedComponents), scan.Technology.ToFormal(), strings.Join(scan.UnscannedComponents, "\n")))
+		}
+	}
+	return
+}
+
+// Returns the locations of the declarations of the direct dependencies in the descriptors, for the technologies that support it.
+// The locations are informative, so failing to compute them doesn't fail the scan.
+func getDirectDependenciesLocations(tech coreutils.Technology, workingDir string, fullDependencyTrees []*xrayCmdUtils.GraphNode) map[string]xrayutils.DescriptorLocation {
+	if tech != coreutils.Npm && tech != coreutils.Yarn {
+		return nil
+	}
+	var directDependencies []string
+	for _, tree := range fullDependencyTrees {
+		for _, node := range tree.Nodes {
+			directDependencies = append(directDependencies, node.Id)
+		}
+	}
+	locations, err := npm.GetDirectDependenciesLocations(workingDir, directDependencies)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Couldn't locate the direct %s dependencies in the descriptor: %s", tech.ToFormal(), err.Error()))
+		return nil
+	}
+	return locations
+}
+
+// Scan the dependency tree with Xray.
+// If additional Xray servers were provided, the tree is scanned by each of them as well, and the results are merged.
+// In this case, issueReporters maps each of the merged findings to the servers that reported it.
+// If cvesPublicationDates is set, the publication dates of the CVEs that the servers found are collected into it.
+// The scan runs in parallel to the scans of other working directories, so the working directory of the scan is given explicitly.
+func runScaWithTech(ctx context.Context, tech coreutils.Technology, workingDir string, params *AuditParams, serverDetails *config.ServerDetails, flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode, cvesPublicationDates *scangraph.CvesPublicationDates) (techResults []services.ScanResponse, issueReporters map[string][]string, err error) {
+	techResults, err = runScaWithServer(ctx, tech, workingDir, params, serverDetails, params.xrayVersion, copyXrayGraphScanParams(params.xrayGraphScanParams), flatTree, cvesPublicationDates)
+	if err != nil {
+		return
+	}
+	if len(params.additionalXrayServers) > 0 {
+		if techResults, issueReporters, err = runScaWithAdditionalServers(ctx, tech, workingDir, params, serverDetails, techResults, flatTree, cvesPublicationDates); err != nil {
+			return
+		}
+	}
+	techResults = sca.BuildImpactPathsForScanResponseWithWorkers(techResults, fullDependencyTrees, params.impactPathWorkers)
+	return
+}
+
+func runScaWithServer(ctx context.Context, tech coreutils.Technology, workingDir string, params *AuditParams, serverDetails *config.ServerDetails, xrayVersion string, xrayGraphScanParams *services.XrayGraphScanParams, flatTree *xrayCmdUtils.GraphNode, cvesPublicationDates *scangraph.CvesPublicationDates) ([]services.ScanResponse, error) {
+	scanGraphParams, err := createScanGraphParams(ctx, tech, workingDir, params, serverDetails, xrayVersion, xrayGraphScanParams)
+	if err != nil {
+		return nil, err
+	}
+	scanGraphParams.SetCvesPublicationDates(cvesPublicationDates)
+	batches := []*xrayCmdUtils.GraphNode{flatTree}
+	if maxTreeNodes := params.MaxTreeNodes(); maxTreeNodes > 0 && len(flatTree.Nodes) > maxTreeNodes && params.TreeNodesLimitPolicy() == xrayutils.BatchOnTreeNodesLimit {
+		batches = splitFlatTree(flatTree, maxTreeNodes)
+	}
+	if len(batches) == 1 {
+		return runScanGraphWithRetries(ctx, tech, params, scanGraphParams, flatTree)
+	}
+	return runScaBatches(ctx, tech, params, scanGraphParams, batches)
+}
+
+// Scan the batches of a split flat tree, with at most the maximum number of parallel graph scans of the scan graph params.
+// The results are returned in the order of the batches, regardless of the order in which the scans are completed.
+func runScaBatches(ctx context.Context, tech coreutils.Technology, params *AuditParams, scanGraphParams *scangraph.ScanGraphParams, batches []*xrayCmdUtils.GraphNode) ([]services.ScanResponse, error) {
+	batchesResults := make([][]services.ScanResponse, len(batches))
+	errGroup := new(errgroup.Group)
+	errGroup.SetLimit(scanGraphParams.MaxParallelGraphScans())
+	for i, batch := range batches {
+		batchIndex, currentBatch := i, batch
+		errGroup.Go(func() (err error) {
+			log.Info(fmt.Sprintf("Scanning batch %d of %d of the %s dependencies...", batchIndex+1, len(batches), tech.ToFormal()))
+			// Each batch sets its own dependency graph in the graph scan params, and dumps its traffic to files of its own.
+			batchScanGraphParams := scanGraphParams.Copy()
+			if scanGraphParams.TrafficDumpPathPrefix() != "" {
+				batchScanGraphParams.SetTrafficDumpPathPrefix(fmt.Sprintf("%s-batch-%d", scanGraphParams.TrafficDumpPathPrefix(), batchIndex+1))
+			}
+			batchesResults[batchIndex], err = runScanGraphWithRetries(ctx, tech, params, batchScanGraphParams, currentBatch)
+			return
+		})
+	}
+	if err := errGroup.Wait(); err != nil {
+		return nil, err
+	}
+	var results []services.ScanResponse
+	for _, batchResults := range batchesResults {
+		results = append(results, batchResults...)
+	}
+	return results, nil
+}
+
+// Scan the flat tree with Xray, and retry the scan with an increasing wait if it fails with a transient error, up to the number of scan retries of the params.
+// The scan isn't retried once the given context is done.
+func runScanGraphWithRetries(ctx context.Context, tech coreutils.Technology, params *AuditParams, scanGraphParams *scangraph.ScanGraphParams, flatTree *xrayCmdUtils.GraphNode) (results []services.ScanResponse, err error) {
+	delay := params.ScanRetryBaseDelay()
+	for attempt := 0; ; attempt++ {
+		if results, err = sca.RunXrayDependenciesTreeScanGraph(flatTree, params.Progress(), tech, scanGraphParams); err == nil || attempt == params.ScanRetries() || !isTransientScanError(err) {
+			return
+		}
+		log.Warn(fmt.Sprintf("The %s scan failed with a transient error, retrying in %s (retry %d of %d):\n%s", tech.ToFormal(), delay, attempt+1, params.ScanRetries(), err.Error()))
+		if waitErr := xrayutils.WaitBeforeRetry(ctx, delay); waitErr != nil {
+			return nil, errors.Join(err, waitErr)
+		}
+		delay *= 2
+	}
+}
+
+// Returns whether the scan failed because of a timeout or a server error, so the scan may succeed if it's retried.
+// Requests that Xray rejected because of its rate limit are already retried by the graph scan, so they aren't retried again.
+// Other errors, such as an authentication error or a bad request, fail the scan immediately.
+func isTransientScanError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	if strings.Contains(message, "timeout") || strings.Contains(message, "timed out") || strings.Contains(message, "connection reset") {
+		return true
+	}
+	return transientScanErrorPattern.MatchString(message)
+}
+
+// Splits the given flat tree into flat trees of at most the given number of nodes, so each of them can be scanned separately.
+func splitFlatTree(flatTree *xrayCmdUtils.GraphNode, batchSize int) (batches []*xrayCmdUtils.GraphNode) {
+	for start := 0; start < len(flatTree.Nodes); start += batchSize {
+		end := start + batchSize
+		if end > len(flatTree.Nodes) {
+			end = len(flatTree.Nodes)
+		}
+		batches = append(batches, &xrayCmdUtils.GraphNode{Id: flatTree.Id, Nodes: flatTree.Nodes[start:end]})
+	}
 	return
 }
 
-func runScaWithTech(tech coreutils.Technology, params *AuditParams, serverDetails *config.ServerDetails, flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode) (techResults []services.ScanResponse, err error) {
+func createScanGraphParams(ctx context.Context, tech coreutils.Technology, workingDir string, params *AuditParams, serverDetails *config.ServerDetails, xrayVersion string, xrayGraphScanParams *services.XrayGraphScanParams) (*scangraph.ScanGraphParams, error) {
 	scanGraphParams := scangraph.NewScanGraphParams().
 		SetServerDetails(serverDetails).
-		SetXrayGraphScanParams(params.xrayGraphScanParams).
-		SetXrayVersion(params.xrayVersion).
+		SetXrayGraphScanParams(xrayGraphScanParams).
+		SetXrayVersion(xrayVersion).
 		SetFixableOnly(params.fixableOnly).
-		SetSeverityLevel(params.minSeverityFilter)
-	techResults, err = sca.RunXrayDependenciesTreeScanGraph(flatTree, params.Progress(), tech, scanGraphParams)
-	if err != nil {
-		return
+		SetSeverityLevel(params.minSeverityFilterOfTech(tech)).
+		SetMaxParallelGraphScans(params.maxParallelGraphScans).
+		SetContext(ctx)
+	if params.dumpXrayTrafficDir != "" {
+		trafficDumpPathPrefix := getTrafficDumpPathPrefix(params, tech, workingDir, serverDetails)
+		scanGraphParams.SetTrafficDumpPathPrefix(trafficDumpPathPrefix)
+	}
+	return scanGraphParams, nil
+}
+
+// The traffic dump files of each scan are named by the technology and the working directory of the scan.
+// When scanning with additional Xray servers, the server ID is added to the name as well.
+func getTrafficDumpPathPrefix(params *AuditParams, tech coreutils.Technology, workingDir string, serverDetails *config.ServerDetails) string {
+	name := tech.String() + "-" + trafficDumpNameReplacer.Replace(strings.Trim(workingDir, string(filepath.Separator)))
+	if len(params.additionalXrayServers) > 0 {
+		name += "-" + trafficDumpNameReplacer.Replace(getXrayServerId(serverDetails))
+	}
+	return filepath.Join(params.dumpXrayTrafficDir, name)
+}
+
+// Scan the dependency tree with each of the additional Xray servers and merge the results with the results of the main server.
+func runScaWithAdditionalServers(ctx context.Context, tech coreutils.Technology, workingDir string, params *AuditParams, serverDetails *config.ServerDetails, mainResults []services.ScanResponse, flatTree *xrayCmdUtils.GraphNode, cvesPublicationDates *scangraph.CvesPublicationDates) (mergedResults []services.ScanResponse, issueReporters map[string][]string, err error) {
+	serversResults := []sca.ServerScanResults{{ServerId: getXrayServerId(serverDetails), Results: mainResults}}
+	for _, additionalServer := range params.additionalXrayServers {
+		serverId := getXrayServerId(additionalServer)
+		log.Info(fmt.Sprintf("Scanning %s dependencies with the additional Xray server '%s'...", tech.ToFormal(), serverId))
+		_, xrayVersion, e := xrayutils.CreateXrayServiceManagerAndGetVersion(additionalServer)
+		if e != nil {
+			return nil, nil, fmt.Errorf("failed to get the version of Xray server '%s':\n%s", serverId, e.Error())
+		}
+		// Each server gets its own copy of the graph scan params, since the scan modifies them according to the server's version.
+		serverResults, e := runScaWithServer(ctx, tech, workingDir, params, additionalServer, xrayVersion, copyXrayGraphScanParams(params.xrayGraphScanParams), flatTree, cvesPublicationDates)
+		if e != nil {
+			return nil, nil, fmt.Errorf("scan with Xray server '%s' failed:\n%s", serverId, e.Error())
+		}
+		serversResults = append(serversResults, sca.ServerScanResults{ServerId: serverId, Results: serverResults})
 	}
-	techResults = sca.BuildImpactPathsForScanResponse(techResults, fullDependencyTrees)
+	mergedResults, issueReporters = sca.MergeServersScanResults(serversResults)
 	return
 }
 
+// Each scan gets its own copy of the graph scan params, since the scan sets its dependency graph and technology in them.
+func copyXrayGraphScanParams(xrayGraphScanParams *services.XrayGraphScanParams) *services.XrayGraphScanParams {
+	paramsCopy := *xrayGraphScanParams
+	if xrayGraphScanParams.XscGitInfoContext != nil {
+		xscGitInfoContext := *xrayGraphScanParams.XscGitInfoContext
+		paramsCopy.XscGitInfoContext = &xscGitInfoContext
+	}
+	return &paramsCopy
+}
+
+func getXrayServerId(serverDetails *config.ServerDetails) string {
+	if serverDetails.ServerId != "" {
+		return serverDetails.ServerId
+	}
+	return serverDetails.XrayUrl
+}
+
 func addThirdPartyDependenciesToParams(params *AuditParams, tech coreutils.Technology, flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode) {
 	var dependenciesForApplicabilityScan []string
 	if shouldUseAllDependencies(params.thirdPartyApplicabilityScan, tech) {
@@ -199,7 +963,19 @@ func getDirectDependenciesFromTree(dependencyTrees []*xrayCmdUtils.GraphNode) []
 	return directDependencies.ToSlice()
 }
 
-func GetTechDependencyTree(params xrayutils.AuditParams, tech coreutils.Technology) (flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode, err error) {
+// The technologies whose dependency tree builders apply the scan exclusions, by excluding the modules, workspaces or projects in the matching paths.
+var scanExclusionsTechnologies = []coreutils.Technology{coreutils.Npm, coreutils.Yarn, coreutils.Maven, coreutils.Gradle, coreutils.Nuget}
+
+// Builds the dependency tree of the given technology's project in the given working directory.
+func GetTechDependencyTree(params xrayutils.AuditParams, tech coreutils.Technology, workingDir string) (flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode, err error) {
+	flatTree, fullDependencyTrees, _, err = getTechDependencyTree(context.Background(), params, tech, workingDir)
+	return
+}
+
+// Builds the dependency tree of the given technology's project in the given working directory, and returns it with the information that was recorded while building it.
+// The package manager commands are killed once the given context is done.
+func getTechDependencyTree(ctx context.Context, params xrayutils.AuditParams, tech coreutils.Technology, workingDir string) (flatTree *xrayCmdUtils.GraphNode, fullDependencyTrees []*xrayCmdUtils.GraphNode, treesInfo *xrayutils.DependencyTreesInfo, err error) {
+	treesInfo = xrayutils.NewDependencyTreesInfo(params.LogCommands())
 	logMessage := fmt.Sprintf("Calculating %s dependencies", tech.ToFormal())
 	log.Info(logMessage + "...")
 	if params.Progress() != nil {
@@ -209,81 +985,202 @@ func GetTechDependencyTree(params xrayutils.AuditParams, tech coreutils.Technolo
 	if err != nil {
 		return
 	}
-	err = SetResolutionRepoIfExists(params, tech)
+	err = SetResolutionRepoIfExists(params, tech, workingDir)
 	if err != nil {
 		return
 	}
+	if _, registered := getDependencyTreeBuilder(tech); !registered && len(params.ScanExclusions()) > 0 && !slices.Contains(scanExclusionsTechnologies, tech) {
+		log.Warn(fmt.Sprintf("The scan exclusions aren't supported for %s projects, so all the dependencies of '%s' are scanned. Use the exclusions to skip the project.", tech.ToFormal(), workingDir))
+	}
 	var uniqueDeps []string
 	startTime := time.Now()
+	fullDependencyTrees, uniqueDeps, err = buildDependencyTreeWithResolutionFallback(ctx, params, tech, func() ([]*xrayCmdUtils.GraphNode, []string, error) {
+		trees, deps, recordedInfo, buildErr := buildTechDependencyTree(ctx, params, serverDetails, tech, workingDir)
+		// The information of a build that failed and was retried with the fallback resolver is kept, so its commands are recorded as well.
+		treesInfo.Add(recordedInfo)
+		return trees, deps, buildErr
+	})
+	if err != nil || len(uniqueDeps) == 0 {
+		return
+	}
+	log.Debug(fmt.Sprintf("Created '%s' dependency tree with %d nodes. Elapsed time: %.1f seconds.", tech.ToFormal(), len(uniqueDeps), time.Since(startTime).Seconds()))
+	if maxTreeDepth := params.MaxTreeDepth(); maxTreeDepth > 0 {
+		if fullDependencyTrees, uniqueDeps = truncateDependencyTrees(tech, fullDependencyTrees, uniqueDeps, maxTreeDepth); len(uniqueDeps) == 0 {
+			return
+		}
+	}
+	// Non-concrete coordinates must not be submitted to Xray.
+	if uniqueDeps = sca.RemoveVersionRanges(uniqueDeps); len(uniqueDeps) == 0 {
+		return
+	}
+	if err = checkTreeNodesLimit(params, tech, len(uniqueDeps)); err != nil {
+		return
+	}
+	flatTree, err = createFlatTree(uniqueDeps)
+	return
+}
+
+// Returns copies of the dependency trees without the dependencies that are deeper than the maximum depth, and the unique dependencies that are left in them.
+// A dependency that is deeper than the maximum depth in one path, but not in another, is kept.
+func truncateDependencyTrees(tech coreutils.Technology, fullDependencyTrees []*xrayCmdUtils.GraphNode, uniqueDeps []string, maxTreeDepth int) (truncatedTrees []*xrayCmdUtils.GraphNode, truncatedDeps []string) {
+	keptDeps := datastructures.MakeSet[string]()
+	for _, tree := range fullDependencyTrees {
+		truncatedTrees = append(truncatedTrees, truncateDependencyTree(tree, nil, 0, maxTreeDepth, keptDeps))
+	}
+	truncatedDeps = make([]string, 0, len(uniqueDeps))
+	for _, dependency := range uniqueDeps {
+		if keptDeps.Exists(dependency) {
+			truncatedDeps = append(truncatedDeps, dependency)
+		}
+	}
+	if droppedDeps := len(uniqueDeps) - len(truncatedDeps); droppedDeps > 0 {
+		log.Warn(fmt.Sprintf("The %s dependency tree is deeper than the maximum depth of %d. %d dependencies that appear only beyond it were dropped from the scan.", tech.ToFormal(), maxTreeDepth, droppedDeps))
+	}
+	return
+}
+
+// The nodes are copied, since the builders may share the same node between several paths of different depths.
+func truncateDependencyTree(node, parent *xrayCmdUtils.GraphNode, depth, maxTreeDepth int, keptDeps *datastructures.Set[string]) *xrayCmdUtils.GraphNode {
+	keptDeps.Add(node.Id)
+	truncatedNode := &xrayCmdUtils.GraphNode{Id: node.Id, Types: node.Types, Parent: parent}
+	if depth == maxTreeDepth {
+		return truncatedNode
+	}
+	for _, child := range node.Nodes {
+		truncatedNode.Nodes = append(truncatedNode.Nodes, truncateDependencyTree(child, truncatedNode, depth+1, maxTreeDepth, keptDeps))
+	}
+	return truncatedNode
+}
+
+// Applies the tree nodes limit policy to a dependency tree with the given number of nodes.
+// By default, a tree that exceeds the maximum number of nodes fails, unless it is to be scanned in batches.
+func checkTreeNodesLimit(params xrayutils.AuditParams, tech coreutils.Technology, treeNodes int) error {
+	maxTreeNodes := params.MaxTreeNodes()
+	if maxTreeNodes <= 0 || treeNodes <= maxTreeNodes {
+		return nil
+	}
+	if params.TreeNodesLimitPolicy() == xrayutils.BatchOnTreeNodesLimit {
+		log.Info(fmt.Sprintf("The %s dependency tree has %d nodes, which exceeds the maximum of %d. Scanning it in batches...", tech.ToFormal(), treeNodes, maxTreeNodes))
+		return nil
+	}
+	return errorutils.CheckErrorf("the %s dependency tree has %d nodes, which exceeds the maximum of %d. Scan smaller modules, or scan the tree in batches", tech.ToFormal(), treeNodes, maxTreeNodes)
+}
+
+// Returns the dependency trees with the information that the builder recorded while building them, or nil if it doesn't record any.
+func buildTechDependencyTree(ctx context.Context, params xrayutils.AuditParams, serverDetails *config.ServerDetails, tech coreutils.Technology, workingDir string) (fullDependencyTrees []*xrayCmdUtils.GraphNode, uniqueDeps []string, treesInfo *xrayutils.DependencyTreesInfo, err error) {
+	// A registered dependency tree builder takes precedence over the built-in one.
+	if builder, exists := getDependencyTreeBuilder(tech); exists {
+		fullDependencyTrees, uniqueDeps, err = builder.BuildTree(ctx, workingDir, params)
+		return
+	}
 	switch tech {
 	case coreutils.Maven, coreutils.Gradle:
-		fullDependencyTrees, uniqueDeps, err = java.BuildDependencyTree(params, tech)
+		fullDependencyTrees, uniqueDeps, treesInfo, err = java.BuildDependencyTree(ctx, params, tech, workingDir)
 	case coreutils.Npm:
-		fullDependencyTrees, uniqueDeps, err = npm.BuildDependencyTree(params)
+		fullDependencyTrees, uniqueDeps, treesInfo, err = npm.BuildDependencyTree(ctx, params, workingDir)
 	case coreutils.Yarn:
-		fullDependencyTrees, uniqueDeps, err = yarn.BuildDependencyTree(params)
+		fullDependencyTrees, uniqueDeps, treesInfo, err = yarn.BuildDependencyTree(ctx, params, workingDir)
 	case coreutils.Go:
-		fullDependencyTrees, uniqueDeps, err = _go.BuildDependencyTree(params)
+		fullDependencyTrees, uniqueDeps, treesInfo, err = _go.BuildDependencyTree(ctx, params, workingDir)
 	case coreutils.Pipenv, coreutils.Pip, coreutils.Poetry:
-		fullDependencyTrees, uniqueDeps, err = python.BuildDependencyTree(&python.AuditPython{
+		fullDependencyTrees, uniqueDeps, treesInfo, err = python.BuildDependencyTree(&python.AuditPython{
 			Server:              serverDetails,
 			Tool:                pythonutils.PythonTool(tech),
 			RemotePypiRepo:      params.DepsRepo(),
-			PipRequirementsFile: params.PipRequirementsFile()})
+			PipRequirementsFile: params.PipRequirementsFile(),
+			VenvPath:            params.PythonVenvPath(),
+			WorkingDir:          workingDir,
+			LogCommands:         params.LogCommands(),
+			Context:             ctx})
 	case coreutils.Nuget:
-		fullDependencyTrees, uniqueDeps, err = nuget.BuildDependencyTree(params)
+		fullDependencyTrees, uniqueDeps, treesInfo, err = nuget.BuildDependencyTree(ctx, params, workingDir)
+	case coreutils.Helm:
+		fullDependencyTrees, uniqueDeps, err = helm.BuildDependencyTree(params, workingDir)
+	case coreutils.Docker:
+		fullDependencyTrees, uniqueDeps, err = docker.BuildDependencyTree(params, workingDir)
+	case coreutils.Bundler:
+		fullDependencyTrees, uniqueDeps, treesInfo, err = bundler.BuildDependencyTree(params, workingDir)
+	case coreutils.Composer:
+		fullDependencyTrees, uniqueDeps, treesInfo, err = composer.BuildDependencyTree(params, workingDir)
+	case coreutils.Conan:
+		fullDependencyTrees, uniqueDeps, treesInfo, err = conan.BuildDependencyTree(ctx, params, workingDir)
+	case coreutils.Swift:
+		fullDependencyTrees, uniqueDeps, treesInfo, err = swift.BuildDependencyTree(params, workingDir)
+	case coreutils.Bazel:
+		fullDependencyTrees, uniqueDeps, err = bazel.BuildDependencyTree(params, workingDir)
 	default:
 		err = errorutils.CheckErrorf("%s is currently not supported", string(tech))
 	}
-	if err != nil || len(uniqueDeps) == 0 {
+	return
+}
+
+// Builds the dependency tree with the given function, which resolves the dependencies from the resolution repository of the params.
+// If it fails and a fallback resolver is configured, the dependency tree is built again, resolving the dependencies from the fallback resolver.
+// The build isn't retried once the given context is done, since the fallback resolver is set in the params that are shared with the other scans.
+func buildDependencyTreeWithResolutionFallback(ctx context.Context, params xrayutils.AuditParams, tech coreutils.Technology, buildTree func() ([]*xrayCmdUtils.GraphNode, []string, error)) (fullDependencyTrees []*xrayCmdUtils.GraphNode, uniqueDeps []string, err error) {
+	primaryRepo := params.DepsRepo()
+	if fullDependencyTrees, uniqueDeps, err = buildTree(); err == nil {
+		log.Debug(fmt.Sprintf("The %s dependencies were resolved from %s.", tech.ToFormal(), getResolverName(primaryRepo)))
 		return
 	}
-	log.Debug(fmt.Sprintf("Created '%s' dependency tree with %d nodes. Elapsed time: %.1f seconds.", tech.ToFormal(), len(uniqueDeps), time.Since(startTime).Seconds()))
-	flatTree, err = createFlatTree(uniqueDeps)
+	fallbackRepo, hasFallback := getResolutionFallbackRepo(params)
+	if !hasFallback || fallbackRepo == primaryRepo || ctx.Err() != nil {
+		return
+	}
+	log.Warn(fmt.Sprintf("Failed to resolve the %s dependencies from %s:\n%s\nTrying to resolve them from the fallback resolver, %s...", tech.ToFormal(), getResolverName(primaryRepo), err.Error(), getResolverName(fallbackRepo)))
+	params.SetDepsRepo(fallbackRepo)
+	defer params.SetDepsRepo(primaryRepo)
+	if fullDependencyTrees, uniqueDeps, err = buildTree(); err != nil {
+		return
+	}
+	log.Info(fmt.Sprintf("The %s dependencies were resolved from the fallback resolver, %s.", tech.ToFormal(), getResolverName(fallbackRepo)))
 	return
 }
 
-// Associates a technology with another of a different type in the structure.
+// Returns the repository of the fallback resolver, which is empty for the public registry, and whether a fallback resolver is configured.
+func getResolutionFallbackRepo(params xrayutils.AuditParams) (string, bool) {
+	switch params.ResolutionFallback() {
+	case "":
+		return "", false
+	case xrayutils.PublicRegistryResolutionFallback:
+		return "", true
+	default:
+		return params.ResolutionFallback(), true
+	}
+}
+
+func getResolverName(repo string) string {
+	if repo == "" {
+		return "the public registry"
+	}
+	return fmt.Sprintf("the '%s' repository", repo)
+}
+
+// Associates a technology with the project types of the configuration files that may hold its resolution repository, by order of precedence.
 // Docker is not present, as there is no docker-config command and, consequently, no docker.yaml file we need to operate on.
-var techType = map[coreutils.Technology]project.ProjectType{
-	coreutils.Maven: project.Maven, coreutils.Gradle: project.Gradle, coreutils.Npm: project.Npm, coreutils.Yarn: project.Yarn, coreutils.Go: project.Go, coreutils.Pip: project.Pip,
-	coreutils.Pipenv: project.Pipenv, coreutils.Poetry: project.Poetry, coreutils.Nuget: project.Nuget, coreutils.Dotnet: project.Dotnet,
+// Nuget and Dotnet are identified similarly in the detection process, so each of them falls back to the configuration of the other.
+// Yarn projects are npm projects as well, so they fall back to the npm configuration.
+var techType = map[coreutils.Technology][]project.ProjectType{
+	coreutils.Maven: {project.Maven}, coreutils.Gradle: {project.Gradle}, coreutils.Npm: {project.Npm}, coreutils.Yarn: {project.Yarn, project.Npm},
+	coreutils.Go: {project.Go}, coreutils.Pip: {project.Pip}, coreutils.Pipenv: {project.Pipenv}, coreutils.Poetry: {project.Poetry},
+	coreutils.Nuget: {project.Nuget, project.Dotnet}, coreutils.Dotnet: {project.Dotnet, project.Nuget},
 }
 
 // Verifies the existence of depsRepo. If it doesn't exist, it searches for a configuration file based on the technology type. If found, it assigns depsRepo in the AuditParams.
-func SetResolutionRepoIfExists(params xrayutils.AuditParams, tech coreutils.Technology) (err error) {
+func SetResolutionRepoIfExists(params xrayutils.AuditParams, tech coreutils.Technology, workingDir string) (err error) {
 	if params.DepsRepo() != "" || params.IgnoreConfigFile() {
 		return
 	}
 
-	configFilePath, exists, err := project.GetProjectConfFilePath(techType[tech])
-	if err != nil {
-		err = fmt.Errorf("failed while searching for %s.yaml config file: %s", tech.String(), err.Error())
+	configFilePath, err := getResolutionConfigFilePath(tech, workingDir)
+	if err != nil || configFilePath == "" {
 		return
 	}
-	if !exists {
-		// Nuget and Dotnet are identified similarly in the detection process. To prevent redundancy, Dotnet is filtered out earlier in the process, focusing solely on detecting Nuget.
-		// Consequently, it becomes necessary to verify the presence of dotnet.yaml when Nuget detection occurs.
-		if tech == coreutils.Nuget {
-			configFilePath, exists, err = project.GetProjectConfFilePath(techType[coreutils.Dotnet])
-			if err != nil {
-				err = fmt.Errorf("failed while searching for %s.yaml config file: %s", tech.String(), err.Error())
-				return
-			}
-			if !exists {
-				log.Debug(fmt.Sprintf("No %s.yaml nor %s.yaml configuration file was found. Resolving dependencies from %s default registry", coreutils.Nuget.String(), coreutils.Dotnet.String(), tech.String()))
-				return
-			}
-		} else {
-			log.Debug(fmt.Sprintf("No %s.yaml configuration file was found. Resolving dependencies from %s default registry", tech.String(), tech.String()))
-			return
-		}
-	}
 
 	log.Debug("Using resolver config from", configFilePath)
 	repoConfig, err := project.ReadResolutionOnlyConfiguration(configFilePath)
 	if err != nil {
-		err = fmt.Errorf("failed while reading %s.yaml config file: %s", tech.String(), err.Error())
+		err = fmt.Errorf("failed while reading %s config file: %s", configFilePath, err.Error())
 		return
 	}
 	details, err := repoConfig.ServerDetails()
@@ -296,6 +1193,67 @@ func SetResolutionRepoIfExists(params xrayutils.AuditParams, tech coreutils.Tech
 	return
 }
 
+// Saves the resolution repository and server details of the given params, and returns a function that restores them.
+// Configuration files only set the resolution repository if it isn't set, so it is restored after each scan to let the next scan use its own configuration.
+func keepResolutionRepo(params xrayutils.AuditParams) (restore func()) {
+	depsRepo := params.DepsRepo()
+	serverDetails, _ := params.ServerDetails()
+	return func() {
+		params.SetDepsRepo(depsRepo)
+		params.SetServerDetails(serverDetails)
+	}
+}
+
+// Returns the path of the configuration file that holds the resolution repository of the given technology.
+// The configuration file is searched in the '.jfrog' directories of the given working directory and its parents, nearest first, so each module of a monorepo can have its own configuration, and then in the JFrog home directory.
+// Returns an empty path if the technology has no configuration file type, or if none of its configuration files exist.
+func getResolutionConfigFilePath(tech coreutils.Technology, workingDir string) (configFilePath string, err error) {
+	projectTypes, ok := techType[tech]
+	if !ok {
+		log.Debug(fmt.Sprintf("%s has no configuration file type. Resolving dependencies from %s default registry", tech.ToFormal(), tech.String()))
+		return
+	}
+	currentDir := workingDir
+	for {
+		if configFilePath, err = findResolutionConfigFile(filepath.Join(currentDir, ".jfrog", "projects"), projectTypes); err != nil || configFilePath != "" {
+			return
+		}
+		parentDir := filepath.Dir(currentDir)
+		if parentDir == currentDir {
+			break
+		}
+		currentDir = parentDir
+	}
+	jfrogHomeDir, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return
+	}
+	if configFilePath, err = findResolutionConfigFile(filepath.Join(jfrogHomeDir, "projects"), projectTypes); err != nil || configFilePath != "" {
+		return
+	}
+	var configFileNames []string
+	for _, projectType := range projectTypes {
+		configFileNames = append(configFileNames, projectType.String()+".yaml")
+	}
+	log.Debug(fmt.Sprintf("No %s configuration file was found. Resolving dependencies from %s default registry", strings.Join(configFileNames, " nor "), tech.String()))
+	return "", nil
+}
+
+// Returns the path of the first configuration file of the given project types in the given projects directory, or an empty path if none of them exist.
+func findResolutionConfigFile(projectsDir string, projectTypes []project.ProjectType) (string, error) {
+	for _, projectType := range projectTypes {
+		configFilePath := filepath.Join(projectsDir, projectType.String()+".yaml")
+		exists, err := fileutils.IsFileExists(configFilePath, false)
+		if err != nil {
+			return "", fmt.Errorf("failed while searching for %s.yaml config file: %s", projectType.String(), err.Error())
+		}
+		if exists {
+			return configFilePath, nil
+		}
+	}
+	return "", nil
+}
+
 func createFlatTree(uniqueDeps []string) (*xrayCmdUtils.GraphNode, error) {
 	if log.GetLogger().GetLogLevel() == log.DEBUG {
 		// Avoid printing and marshaling if not on DEBUG mode.
@@ -305,8 +1263,11 @@ func createFlatTree(uniqueDeps []string) (*xrayCmdUtils.GraphNode, error) {
 		}
 		log.Debug("Unique dependencies list:\n" + clientutils.IndentJsonArray(jsonList))
 	}
+	// The unique dependencies are usually collected in a set, sort them so the flat tree and the output derived from it are reproducible.
+	sortedDeps := slices.Clone(uniqueDeps)
+	slices.Sort(sortedDeps)
 	uniqueNodes := []*xrayCmdUtils.GraphNode{}
-	for _, uniqueDep := range uniqueDeps {
+	for _, uniqueDep := range sortedDeps {
 		uniqueNodes = append(uniqueNodes, &xrayCmdUtils.GraphNode{Id: uniqueDep})
 	}
 	return &xrayCmdUtils.GraphNode{Id: "root", Nodes: uniqueNodes}, nil
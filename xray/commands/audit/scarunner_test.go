@@ -1,17 +1,38 @@
 package audit
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	biutils "github.com/jfrog/build-info-go/utils"
+	coretests "github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/commands/audit/sca"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/scangraph"
 	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
 
 	xrayUtils "github.com/jfrog/jfrog-client-go/xray/services/utils"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 )
 
 func TestGetDirectDependenciesList(t *testing.T) {
@@ -138,7 +159,7 @@ func TestGetExcludePattern(t *testing.T) {
 			name:      "Test no exclude pattern recursive",
 			params:    NewAuditParams,
 			recursive: true,
-			expected:  "(^.*\\.git.*$)|(^.*node_modules.*$)|(^.*target.*$)|(^.*venv.*$)|(^.*test.*$)",
+			expected:  "(^.*\\.git.*$)|(^.*node_modules.*$)|(^.*target.*$)|(^.*venv.*$)|(^.*/test/.*$)|(^.*/tests/.*$)|(^.*/__tests__/.*$)|(^.*/testdata/.*$)",
 		},
 		{
 			name: "Test exclude pattern not recursive",
@@ -154,7 +175,7 @@ func TestGetExcludePattern(t *testing.T) {
 			name:      "Test no exclude pattern",
 			params:    NewAuditParams,
 			recursive: false,
-			expected:  "(^.*\\.git.*$)|(^.*node_modules.*$)|(^.*target.*$)|(^.*venv.*$)|(^.*test.*$)",
+			expected:  "(^.*\\.git.*$)|(^.*node_modules.*$)|(^.*target.*$)|(^.*venv.*$)|(^.*/test/.*$)|(^.*/tests/.*$)|(^.*/__tests__/.*$)|(^.*/testdata/.*$)",
 		},
 	}
 
@@ -293,7 +314,8 @@ func TestGetScaScansToPreform(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			result := getScaScansToPreform(test.wd, test.params())
+			result, err := getScaScansToPreform(test.wd, test.params())
+			assert.NoError(t, err)
 			for i := range result {
 				sort.Strings(result[i].Descriptors)
 				sort.Strings(test.expected[i].Descriptors)
@@ -304,3 +326,1201 @@ func TestGetScaScansToPreform(t *testing.T) {
 
 	cleanUp()
 }
+
+func TestRunScaWithTechAdditionalXrayServers(t *testing.T) {
+	sharedVulnerability := services.Vulnerability{IssueId: "XRAY-1", Severity: "High", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}}
+	mainVulnerability := services.Vulnerability{IssueId: "XRAY-2", Severity: "Low", Components: map[string]services.Component{"npm://minimist:1.2.5": {}}}
+	additionalVulnerability := services.Vulnerability{IssueId: "XRAY-3", Severity: "Medium", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}}
+	mainServer, mainServerDetails := createXrayScanGraphMockServer(t, "jpd1", services.ScanResponse{Vulnerabilities: []services.Vulnerability{sharedVulnerability, mainVulnerability}})
+	defer mainServer.Close()
+	additionalServer, additionalServerDetails := createXrayScanGraphMockServer(t, "jpd2", services.ScanResponse{Vulnerabilities: []services.Vulnerability{sharedVulnerability, additionalVulnerability}})
+	defer additionalServer.Close()
+
+	params := NewAuditParams().SetAdditionalXrayServers([]*config.ServerDetails{additionalServerDetails})
+	params.xrayVersion = "3.80.0"
+	flatTree := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "npm://lodash:4.17.0"}, {Id: "npm://minimist:1.2.5"}}}
+	fullTree := []*xrayUtils.GraphNode{{Id: "npm://project:1.0.0", Nodes: flatTree.Nodes}}
+
+	results, issueReporters, err := runScaWithTech(context.Background(), coreutils.Npm, "", params, mainServerDetails, flatTree, fullTree, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	var issueIds []string
+	for _, vulnerability := range results[0].Vulnerabilities {
+		issueIds = append(issueIds, vulnerability.IssueId)
+		assert.Equal(t, coreutils.Npm.String(), vulnerability.Technology)
+	}
+	assert.ElementsMatch(t, []string{"XRAY-1", "XRAY-2", "XRAY-3"}, issueIds)
+	assert.Equal(t, []string{"jpd1", "jpd2"}, issueReporters[sca.GetVulnerabilityKey(sharedVulnerability)])
+	assert.Equal(t, []string{"jpd1"}, issueReporters[sca.GetVulnerabilityKey(mainVulnerability)])
+	assert.Equal(t, []string{"jpd2"}, issueReporters[sca.GetVulnerabilityKey(additionalVulnerability)])
+}
+
+func TestRunScaBatches(t *testing.T) {
+	// The mock server names each scan after the first dependency of the scanned batch, and counts the scans that run in parallel.
+	var mutex sync.Mutex
+	var runningScans, maxRunningScans int
+	testServer := createCustomXrayScanGraphMockServer(t, func(graph *xrayUtils.GraphNode) (string, int) {
+		mutex.Lock()
+		if runningScans++; runningScans > maxRunningScans {
+			maxRunningScans = runningScans
+		}
+		mutex.Unlock()
+		return graph.Nodes[0].Id, 0
+	}, func(scanId string) any {
+		// Let the scans of the first batches complete last.
+		time.Sleep(time.Duration(10-len(scanId)) * 20 * time.Millisecond)
+		mutex.Lock()
+		runningScans--
+		mutex.Unlock()
+		return services.ScanResponse{ScanId: scanId}
+	}, nil)
+	defer testServer.Close()
+
+	flatTree := &xrayUtils.GraphNode{Id: "root"}
+	for _, id := range []string{"a", "bb", "ccc", "dddd", "eeeee"} {
+		flatTree.Nodes = append(flatTree.Nodes, &xrayUtils.GraphNode{Id: id})
+	}
+	scanGraphParams := scangraph.NewScanGraphParams().
+		SetServerDetails(&config.ServerDetails{XrayUrl: testServer.URL + "/xray/"}).
+		SetXrayGraphScanParams(&services.XrayGraphScanParams{}).
+		SetXrayVersion("3.80.0").
+		SetMaxParallelGraphScans(2)
+	results, err := runScaBatches(context.Background(), coreutils.Npm, NewAuditParams(), scanGraphParams, splitFlatTree(flatTree, 1))
+	assert.NoError(t, err)
+	var scanIds []string
+	for _, result := range results {
+		scanIds = append(scanIds, result.ScanId)
+	}
+	assert.Equal(t, []string{"a", "bb", "ccc", "dddd", "eeeee"}, scanIds)
+	assert.LessOrEqual(t, maxRunningScans, 2)
+	// The scans don't share the graph scan params.
+	assert.Nil(t, scanGraphParams.XrayGraphScanParams().DependenciesGraph)
+}
+
+// Create a flaky mock Xray server, whose graph scan requests fail with the given status until the given number of requests failed.
+func createFlakyXrayScanGraphMockServer(t *testing.T, failureStatus, failures int) (testServer *httptest.Server, serverDetails *config.ServerDetails, scanRequests *int) {
+	scanRequests = new(int)
+	testServer = createCustomXrayScanGraphMockServer(t, func(*xrayUtils.GraphNode) (string, int) {
+		if *scanRequests++; *scanRequests <= failures {
+			return "", failureStatus
+		}
+		return "flaky-scan", 0
+	}, func(string) any {
+		return services.ScanResponse{ScanId: "flaky-scan"}
+	}, nil)
+	return testServer, &config.ServerDetails{XrayUrl: testServer.URL + "/xray/"}, scanRequests
+}
+
+func TestRunScanGraphWithRetries(t *testing.T) {
+	// The HTTP client sends each request up to 4 times when the server fails, before the scan fails.
+	const clientAttempts = 4
+	flatTree := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "npm://lodash:4.17.0"}}}
+	tests := []struct {
+		name                 string
+		failureStatus        int
+		failures             int
+		scanRetries          int
+		expectedError        bool
+		expectedScanRequests int
+	}{
+		{name: "Transient error, no retries", failureStatus: http.StatusServiceUnavailable, failures: clientAttempts, expectedError: true, expectedScanRequests: clientAttempts},
+		{name: "Transient error, retried", failureStatus: http.StatusServiceUnavailable, failures: clientAttempts + 1, scanRetries: 1, expectedScanRequests: clientAttempts + 2},
+		{name: "Transient error, retries exhausted", failureStatus: http.StatusBadGateway, failures: 3 * clientAttempts, scanRetries: 2, expectedError: true, expectedScanRequests: 3 * clientAttempts},
+		{name: "Unauthorized, fails fast", failureStatus: http.StatusUnauthorized, failures: 1, scanRetries: 2, expectedError: true, expectedScanRequests: 1},
+		{name: "Bad request, fails fast", failureStatus: http.StatusBadRequest, failures: 1, scanRetries: 2, expectedError: true, expectedScanRequests: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testServer, serverDetails, scanRequests := createFlakyXrayScanGraphMockServer(t, test.failureStatus, test.failures)
+			defer testServer.Close()
+			params := NewAuditParams().SetScanRetries(test.scanRetries).SetScanRetryBaseDelay(time.Millisecond)
+			scanGraphParams := scangraph.NewScanGraphParams().
+				SetServerDetails(serverDetails).
+				SetXrayGraphScanParams(&services.XrayGraphScanParams{}).
+				SetXrayVersion("3.80.0")
+			results, err := runScanGraphWithRetries(context.Background(), coreutils.Npm, params, scanGraphParams, flatTree)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) && assert.Len(t, results, 1) {
+				assert.Equal(t, "flaky-scan", results[0].ScanId)
+			}
+			assert.Equal(t, test.expectedScanRequests, *scanRequests)
+		})
+	}
+
+	t.Run("Context done while waiting", func(t *testing.T) {
+		testServer, serverDetails, scanRequests := createFlakyXrayScanGraphMockServer(t, http.StatusServiceUnavailable, 2*clientAttempts)
+		defer testServer.Close()
+		params := NewAuditParams().SetScanRetries(1).SetScanRetryBaseDelay(time.Hour)
+		scanGraphParams := scangraph.NewScanGraphParams().
+			SetServerDetails(serverDetails).
+			SetXrayGraphScanParams(&services.XrayGraphScanParams{}).
+			SetXrayVersion("3.80.0")
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		// The scan isn't retried once the context is done, instead of waiting for the retry.
+		_, err := runScanGraphWithRetries(ctx, coreutils.Npm, params, scanGraphParams, flatTree)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, clientAttempts, *scanRequests)
+	})
+}
+
+func TestIsTransientScanError(t *testing.T) {
+	assert.True(t, isTransientScanError(errors.New("scanning npm dependencies failed with error: server response: 503 Service Unavailable")))
+	assert.True(t, isTransientScanError(errors.New("Get \"https://xray/api/v1/scan/graph\": net/http: TLS handshake timeout")))
+	assert.True(t, isTransientScanError(fmt.Errorf("scan failed: %w", context.DeadlineExceeded)))
+	// The rate limit retries of the graph scan aren't multiplied by the scan retries.
+	assert.False(t, isTransientScanError(errors.New("scanning npm dependencies failed with error: server response: 429 Too Many Requests")))
+	assert.False(t, isTransientScanError(errors.New("scanning npm dependencies failed with error: server response: 401 Unauthorized")))
+	assert.False(t, isTransientScanError(errors.New("scanning npm dependencies failed with error: server response: 400 Bad Request")))
+}
+
+// Create a mock Xray server that responds to the graph scan requests with the given scan response.
+func createXrayScanGraphMockServer(t *testing.T, serverId string, scanResponse services.ScanResponse) (*httptest.Server, *config.ServerDetails) {
+	scanResponse.ScanId = serverId + "-scan"
+	testServer := createCustomXrayScanGraphMockServer(t, func(*xrayUtils.GraphNode) (string, int) {
+		return scanResponse.ScanId, 0
+	}, func(string) any {
+		return scanResponse
+	}, nil)
+	return testServer, &config.ServerDetails{ServerId: serverId, XrayUrl: testServer.URL + "/xray/"}
+}
+
+// Create a mock Xray server that handles the graph scan requests with the given functions.
+// scan gets the dependency graph of each graph scan request, and returns the ID of the scan, or a status to fail the request with.
+// results returns the response to the results request of the scan with the given ID.
+// otherResponses are the responses to the rest of the requests that the server handles, by the suffixes of their paths.
+func createCustomXrayScanGraphMockServer(t *testing.T, scan func(graph *xrayUtils.GraphNode) (scanId string, failureStatus int), results func(scanId string) any, otherResponses map[string]any) *httptest.Server {
+	return coretests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		var response any
+		switch {
+		case strings.HasSuffix(r.URL.Path, "api/v1/system/version"):
+			response = map[string]string{"xray_version": "3.80.0"}
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "api/v1/scan/graph"):
+			var graph xrayUtils.GraphNode
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&graph))
+			scanId, failureStatus := scan(&graph)
+			if failureStatus != 0 {
+				w.WriteHeader(failureStatus)
+				return
+			}
+			response = map[string]string{"scan_id": scanId}
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "api/v1/scan/graph/"):
+			response = results(path.Base(r.URL.Path))
+		default:
+			for pathSuffix, otherResponse := range otherResponses {
+				if strings.HasSuffix(r.URL.Path, pathSuffix) {
+					response = otherResponse
+				}
+			}
+			if response == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+		content, err := json.Marshal(response)
+		assert.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(content)
+		assert.NoError(t, err)
+	})
+}
+
+// The tests that audit the npm projects of the test data build their dependency trees with npm, so they can only run where it's installed.
+func skipIfNpmIsMissing(t *testing.T) {
+	if _, err := exec.LookPath("npm"); err != nil {
+		t.Skip("Skipping " + t.Name() + " test, since npm isn't installed...")
+	}
+}
+
+func TestGetScaScansToPreformWithSymlinks(t *testing.T) {
+	dir, cleanUp := createTestDir(t)
+	defer cleanUp()
+	npmDir := filepath.Join(dir, "dir", "npm")
+	// Create a symlink cycle back to the root directory and a symlink to the npm project.
+	assert.NoError(t, os.Symlink(dir, filepath.Join(dir, "dir", "loop")))
+	npmLink := filepath.Join(dir, "npm-link")
+	assert.NoError(t, os.Symlink(npmDir, npmLink))
+
+	t.Run("Recursive detection with symlink cycle", func(t *testing.T) {
+		params := NewAuditParams()
+		params.SetTechnologies([]string{"npm"})
+		result, err := getScaScansToPreform(dir, params)
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, npmDir, result[0].WorkingDirectory)
+	})
+
+	t.Run("Same physical directory requested via symlink", func(t *testing.T) {
+		params := NewAuditParams().SetWorkingDirs([]string{npmDir, npmLink})
+		params.SetTechnologies([]string{"npm"})
+		result, err := getScaScansToPreform(dir, params)
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+	})
+}
+
+func TestGetScaScansToPreformTechnologyMismatch(t *testing.T) {
+	dir, cleanUp := createTestDir(t)
+	defer cleanUp()
+	goDir := filepath.Join(dir, "dir", "go")
+	newParams := func(policy TechnologyMismatchPolicy) *AuditParams {
+		// The Go directory has no npm descriptors.
+		params := NewAuditParams().SetWorkingDirs([]string{goDir}).SetOnTechnologyMismatch(policy)
+		params.SetTechnologies([]string{"go", "npm"})
+		return params
+	}
+	goScan := &xrayutils.ScaScanResult{Technology: coreutils.Go, WorkingDirectory: goDir, Descriptors: []string{filepath.Join(goDir, "go.mod")}}
+
+	t.Run("Warn and skip by default", func(t *testing.T) {
+		_, logBuffer, previousLog := tests.RedirectLogOutputToBuffer()
+		defer log.SetLogger(previousLog)
+		scans, err := getScaScansToPreform(dir, newParams(""))
+		assert.NoError(t, err)
+		assert.Equal(t, []*xrayutils.ScaScanResult{goScan}, scans)
+		assert.Contains(t, logBuffer.String(), fmt.Sprintf("npm was requested, but none of its descriptors were detected in '%s'", goDir))
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		_, err := getScaScansToPreform(dir, newParams(FailOnTechnologyMismatch))
+		assert.EqualError(t, err, fmt.Sprintf("npm was requested, but none of its descriptors were detected in '%s'", goDir))
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		scans, err := getScaScansToPreform(dir, newParams(ScanOnTechnologyMismatch))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []*xrayutils.ScaScanResult{goScan, {Technology: coreutils.Npm, WorkingDirectory: goDir}}, scans)
+	})
+
+	_, err := GetTechnologyMismatchPolicy("ignore")
+	assert.ErrorContains(t, err, "unsupported technology mismatch policy 'ignore'")
+}
+
+func TestGetScaScansToPreformParallelDetection(t *testing.T) {
+	dir, cleanUp := createTestDir(t)
+	defer cleanUp()
+	requestedDirs := []string{
+		filepath.Join(dir, "dir", "maven"),
+		filepath.Join(dir, "dir", "npm"),
+		filepath.Join(dir, "dir", "go"),
+		filepath.Join(dir, "yarn"),
+		filepath.Join(dir, "yarn", "Pip"),
+		filepath.Join(dir, "yarn", "Pipenv"),
+		filepath.Join(dir, "Nuget"),
+		filepath.Join(dir, "dir", "maven", "maven-sub"),
+	}
+	// Detect the technologies in each directory sequentially.
+	var expected []*xrayutils.ScaScanResult
+	for _, requestedDir := range requestedDirs {
+		scans, err := getScaScansToPreform(dir, NewAuditParams().SetWorkingDirs([]string{requestedDir}))
+		assert.NoError(t, err)
+		expected = append(expected, scans...)
+	}
+	assert.NotEmpty(t, expected)
+	// The parallel detection should produce the same scans, in the same order, on every run.
+	for i := 0; i < 3; i++ {
+		scans, err := getScaScansToPreform(dir, NewAuditParams().SetWorkingDirs(requestedDirs))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, scans)
+	}
+}
+
+func TestGetScaScansToPreformDetectionAndScanExclusions(t *testing.T) {
+	dir, cleanUp := createTestDir(t)
+	defer cleanUp()
+	npmScan := &xrayutils.ScaScanResult{
+		Technology:       coreutils.Npm,
+		WorkingDirectory: filepath.Join(dir, "dir", "npm"),
+		Descriptors:      []string{filepath.Join(dir, "dir", "npm", "package.json")},
+	}
+
+	t.Run("Excluded from detection", func(t *testing.T) {
+		result, err := getScaScansToPreform(dir, NewAuditParams().SetExclusions([]string{"*npm*"}))
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result)
+		assert.NotContains(t, result, npmScan)
+	})
+
+	t.Run("Excluded from scanning only", func(t *testing.T) {
+		params := NewAuditParams().SetScanExclusions([]string{"*npm*"})
+		result, err := getScaScansToPreform(dir, params)
+		assert.NoError(t, err)
+		// The project is still detected, the scan exclusions are applied by the dependency tree builders.
+		assert.Contains(t, result, npmScan)
+		assert.Equal(t, []string{"*npm*"}, params.ScanExclusions())
+	})
+}
+
+func TestGetUnscannedComponents(t *testing.T) {
+	xrayServer, serverDetails := createXrayScanGraphMockServer(t, "jpd1", services.ScanResponse{
+		Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Severity: "High", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}}},
+		Licenses: []services.License{
+			{Key: "MIT", Components: map[string]services.Component{"npm://lodash:4.17.0": {}, "npm://minimist:1.2.5": {}}},
+		},
+	})
+	defer xrayServer.Close()
+
+	params := NewAuditParams()
+	params.xrayVersion = "3.80.0"
+	params.xrayGraphScanParams.IncludeLicenses = true
+	// The internal package is submitted, but Xray returns no data for it.
+	flatTree := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "npm://lodash:4.17.0"}, {Id: "npm://minimist:1.2.5"}, {Id: "npm://internal-package:1.0.0"}}}
+	fullTree := []*xrayUtils.GraphNode{{Id: "npm://project:1.0.0", Nodes: flatTree.Nodes}}
+
+	results, _, err := runScaWithTech(context.Background(), coreutils.Npm, "", params, serverDetails, flatTree, fullTree, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"npm://internal-package:1.0.0"}, sca.GetUnscannedComponents(flatTree, results))
+}
+
+func TestRunScaWithTechCvesPublicationDates(t *testing.T) {
+	// The scan results of Xray include the publication dates, which the scan response of the client doesn't expose.
+	scanResults := `{"scan_id":"scan-1","vulnerabilities":[
+		{"issue_id":"XRAY-1","severity":"High","published":"2023-05-01T00:00:00Z","cves":[{"cve":"CVE-2023-1"},{"cve":"CVE-2023-2","published":"2023-06-01"}],"components":{"npm://lodash:4.17.0":{}}},
+		{"issue_id":"XRAY-2","severity":"Low","cves":[{"cve":"CVE-2019-1"}],"components":{"npm://lodash:4.17.0":{}}}]}`
+	testServer := createCustomXrayScanGraphMockServer(t, func(*xrayUtils.GraphNode) (string, int) {
+		return "scan-1", 0
+	}, func(string) any {
+		return json.RawMessage(scanResults)
+	}, nil)
+	defer testServer.Close()
+
+	params := NewAuditParams()
+	params.xrayVersion = "3.80.0"
+	flatTree := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "npm://lodash:4.17.0"}}}
+	cvesPublicationDates := scangraph.NewCvesPublicationDates()
+	results, _, err := runScaWithTech(context.Background(), coreutils.Npm, "", params, &config.ServerDetails{XrayUrl: testServer.URL + "/xray/"}, flatTree, []*xrayUtils.GraphNode{flatTree}, cvesPublicationDates)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	// A CVE without a publication date of its own gets the publication date of its issue, and a CVE without any remains undated.
+	assert.Equal(t, map[string]time.Time{
+		"CVE-2023-1": time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+		"CVE-2023-2": time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	}, cvesPublicationDates.Dates())
+}
+
+func TestGetResolutionConfigFilePath(t *testing.T) {
+	projectDir := t.TempDir()
+	// Use an empty home directory, so the global configuration files are not found.
+	t.Setenv(coreutils.HomeDir, t.TempDir())
+	projectsDir := createEmptyDir(t, filepath.Join(projectDir, ".jfrog", "projects"))
+	createEmptyFile(t, filepath.Join(projectsDir, "npm.yaml"))
+	createEmptyFile(t, filepath.Join(projectsDir, "dotnet.yaml"))
+	createEmptyFile(t, filepath.Join(projectsDir, "maven.yaml"))
+
+	testCases := []struct {
+		tech             coreutils.Technology
+		expectedConfFile string
+	}{
+		{tech: coreutils.Npm, expectedConfFile: "npm.yaml"},
+		// Yarn falls back to the npm configuration
+		{tech: coreutils.Yarn, expectedConfFile: "npm.yaml"},
+		// Nuget falls back to the dotnet configuration
+		{tech: coreutils.Nuget, expectedConfFile: "dotnet.yaml"},
+		{tech: coreutils.Maven, expectedConfFile: "maven.yaml"},
+		{tech: coreutils.Gradle},
+		// Docker has no configuration file type
+		{tech: coreutils.Docker},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.tech.String(), func(t *testing.T) {
+			configFilePath, err := getResolutionConfigFilePath(testCase.tech, projectDir)
+			assert.NoError(t, err)
+			if testCase.expectedConfFile == "" {
+				assert.Empty(t, configFilePath)
+				return
+			}
+			assert.Equal(t, filepath.Join(projectsDir, testCase.expectedConfFile), configFilePath)
+		})
+	}
+
+	t.Run("Yarn configuration takes precedence", func(t *testing.T) {
+		createEmptyFile(t, filepath.Join(projectsDir, "yarn.yaml"))
+		configFilePath, err := getResolutionConfigFilePath(coreutils.Yarn, projectDir)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(projectsDir, "yarn.yaml"), configFilePath)
+	})
+}
+
+func TestSetResolutionRepoPerModule(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "npm-monorepo-resolution"), tmpDir, true, nil))
+	t.Setenv(coreutils.HomeDir, t.TempDir())
+	assert.NoError(t, config.SaveServersConf([]*config.ServerDetails{{ServerId: "monorepo-server", ArtifactoryUrl: "https://domain.com/artifactory/"}}))
+
+	xrayServerDetails := &config.ServerDetails{XrayUrl: "https://domain.com/xray/"}
+	params := (&xrayutils.AuditBasicParams{}).SetServerDetails(xrayServerDetails)
+	testCases := []struct {
+		module       string
+		expectedRepo string
+	}{
+		{module: "module1", expectedRepo: "npm-module1"},
+		{module: "module2", expectedRepo: "npm-module2"},
+		// module3 only has a Maven configuration file, so it uses the npm configuration of the root directory.
+		{module: "module3", expectedRepo: "npm-root"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.module, func(t *testing.T) {
+			restore := keepResolutionRepo(params)
+			assert.NoError(t, SetResolutionRepoIfExists(params, coreutils.Npm, filepath.Join(tmpDir, testCase.module)))
+			assert.Equal(t, testCase.expectedRepo, params.DepsRepo())
+			serverDetails, err := params.ServerDetails()
+			assert.NoError(t, err)
+			assert.Equal(t, "monorepo-server", serverDetails.ServerId)
+			restore()
+			assert.Empty(t, params.DepsRepo())
+			serverDetails, err = params.ServerDetails()
+			assert.NoError(t, err)
+			assert.Equal(t, xrayServerDetails, serverDetails)
+		})
+	}
+}
+
+func TestDumpXrayTraffic(t *testing.T) {
+	vulnerability := services.Vulnerability{IssueId: "XRAY-1", Severity: "High", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}}
+	xrayServer, serverDetails := createXrayScanGraphMockServer(t, "jpd1", services.ScanResponse{Vulnerabilities: []services.Vulnerability{vulnerability}})
+	defer xrayServer.Close()
+	serverDetails.User = "admin"
+	serverDetails.Password = "password"
+
+	dumpDir := t.TempDir()
+	params := NewAuditParams().SetDumpXrayTrafficDir(dumpDir)
+	params.xrayVersion = "3.80.0"
+	flatTree := &xrayUtils.GraphNode{Id: "root", Nodes: []*xrayUtils.GraphNode{{Id: "npm://lodash:4.17.0"}}}
+	workingDir := t.TempDir()
+	_, _, err := runScaWithTech(context.Background(), coreutils.Npm, workingDir, params, serverDetails, flatTree, []*xrayUtils.GraphNode{flatTree}, nil)
+	assert.NoError(t, err)
+
+	// One request/response pair is written for the scan, named by the technology and the working directory.
+	trafficDumpPathPrefix := getTrafficDumpPathPrefix(params, coreutils.Npm, workingDir, serverDetails)
+	assert.True(t, strings.HasPrefix(filepath.Base(trafficDumpPathPrefix), "npm-"))
+	files, err := os.ReadDir(dumpDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	requestContent, err := os.ReadFile(trafficDumpPathPrefix + "-request.json")
+	assert.NoError(t, err)
+	assert.Contains(t, string(requestContent), `"scan_id": "jpd1-scan"`)
+	assert.Contains(t, string(requestContent), `"npm://lodash:4.17.0"`)
+	assert.NotContains(t, string(requestContent), "password")
+
+	responseContent, err := os.ReadFile(trafficDumpPathPrefix + "-response.json")
+	assert.NoError(t, err)
+	var response services.ScanResponse
+	assert.NoError(t, json.Unmarshal(responseContent, &response))
+	assert.Equal(t, "jpd1-scan", response.ScanId)
+	assert.Len(t, response.Vulnerabilities, 1)
+	assert.Equal(t, "XRAY-1", response.Vulnerabilities[0].IssueId)
+}
+
+func TestGetScaScansToPreformDescriptorPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"package.json", "package-lock.json", "yarn.lock"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte("{}"), 0644))
+	}
+
+	t.Run("Default precedence", func(t *testing.T) {
+		result, err := getScaScansToPreform(dir, NewAuditParams())
+		assert.NoError(t, err)
+		if assert.Len(t, result, 1) {
+			assert.Equal(t, coreutils.Yarn, result[0].Technology)
+		}
+	})
+
+	t.Run("Configured precedence", func(t *testing.T) {
+		params := NewAuditParams().SetDescriptorPrecedence(map[coreutils.Technology][]string{coreutils.Npm: {"package-lock.json"}})
+		result, err := getScaScansToPreform(dir, params)
+		assert.NoError(t, err)
+		if assert.Len(t, result, 1) {
+			assert.Equal(t, coreutils.Npm, result[0].Technology)
+		}
+	})
+}
+
+func TestRunScaScanWithNoTechnologies(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.Chdir(wd))
+	}()
+	assert.NoError(t, os.Chdir(t.TempDir()))
+
+	t.Run("Lenient by default", func(t *testing.T) {
+		results := xrayutils.NewAuditResults()
+		assert.NoError(t, runScaScan(NewAuditParams(), results))
+		assert.Empty(t, results.ScaResults)
+	})
+
+	t.Run("Fail on no technologies", func(t *testing.T) {
+		results := xrayutils.NewAuditResults()
+		err := runScaScan(NewAuditParams().SetFailOnNoTechnologies(true), results)
+		assert.ErrorContains(t, err, "couldn't determine a package manager or build tool")
+		assert.Empty(t, results.ScaResults)
+	})
+}
+
+func TestEffectiveExclusions(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.Chdir(wd))
+	}()
+	assert.NoError(t, os.Chdir(t.TempDir()))
+
+	t.Run("Default exclusions", func(t *testing.T) {
+		defaultExcludePatterns := DefaultExcludePatterns
+		defer func() {
+			DefaultExcludePatterns = defaultExcludePatterns
+		}()
+		params := NewAuditParams()
+		results := xrayutils.NewAuditResults()
+		assert.NoError(t, runScaScan(params, results))
+		assert.Equal(t, []string{"*.git*", "*node_modules*", "*target*", "*venv*", "*/test/*", "*/tests/*", "*/__tests__/*", "*/testdata/*"}, results.EffectiveExclusions)
+		// Modifying the defaults doesn't affect the exclusions that were already applied.
+		DefaultExcludePatterns = []string{"*other*"}
+		assert.Equal(t, results.EffectiveExclusions, params.EffectiveExclusions())
+		assert.Equal(t, "(^.*\\.git.*$)|(^.*node_modules.*$)|(^.*target.*$)|(^.*venv.*$)|(^.*/test/.*$)|(^.*/tests/.*$)|(^.*/__tests__/.*$)|(^.*/testdata/.*$)", getExcludePattern(params, false))
+	})
+
+	t.Run("Requested exclusions", func(t *testing.T) {
+		params := NewAuditParams().SetExclusions([]string{"*dist*"})
+		results := xrayutils.NewAuditResults()
+		assert.NoError(t, runScaScan(params, results))
+		assert.Equal(t, []string{"*dist*"}, results.EffectiveExclusions)
+		assert.Equal(t, "(^.*dist.*$)", getExcludePattern(params, false))
+	})
+
+	t.Run("Appended exclusions", func(t *testing.T) {
+		params := NewAuditParams().SetExclusions([]string{"*dist*", "*node_modules*"}).SetExcludePatternsMode(AppendExcludePatterns)
+		results := xrayutils.NewAuditResults()
+		assert.NoError(t, runScaScan(params, results))
+		// The requested exclusions follow the defaults, without repeating them.
+		assert.Equal(t, []string{"*.git*", "*node_modules*", "*target*", "*venv*", "*/test/*", "*/tests/*", "*/__tests__/*", "*/testdata/*", "*dist*"}, results.EffectiveExclusions)
+		assert.Equal(t, "(^.*\\.git.*$)|(^.*node_modules.*$)|(^.*target.*$)|(^.*venv.*$)|(^.*/test/.*$)|(^.*/tests/.*$)|(^.*/__tests__/.*$)|(^.*/testdata/.*$)|(^.*dist.*$)", getExcludePattern(params, false))
+	})
+}
+
+func TestDisabledDefaultExcludePatterns(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, dir := range []string{filepath.Join("src", "latest"), filepath.Join("src", "test")} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(projectDir, dir), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(projectDir, dir, "go.mod"), []byte("module acme\n\ngo 1.20\n"), 0644))
+	}
+	getDetectedDirs := func(params *AuditParams) (detectedDirs []string) {
+		params.SetTechnologies([]string{"go"})
+		scans, err := getScaScansToPreform(projectDir, params)
+		assert.NoError(t, err)
+		for _, scan := range scans {
+			relativePath, err := filepath.Rel(projectDir, scan.WorkingDirectory)
+			assert.NoError(t, err)
+			detectedDirs = append(detectedDirs, relativePath)
+		}
+		return
+	}
+
+	// Only the test directory is excluded by default, not directories whose names contain 'test'.
+	assert.Equal(t, []string{filepath.Join("src", "latest")}, getDetectedDirs(NewAuditParams()))
+
+	params := NewAuditParams().SetDisabledDefaultExcludePatterns([]string{"*/test/*"})
+	assert.NotContains(t, params.EffectiveExclusions(), "*/test/*")
+	assert.Contains(t, params.EffectiveExclusions(), "*node_modules*")
+	assert.ElementsMatch(t, []string{filepath.Join("src", "latest"), filepath.Join("src", "test")}, getDetectedDirs(params))
+
+	// The disabled patterns are removed from the defaults that the requested exclusions are appended to as well.
+	params = NewAuditParams().SetExclusions([]string{"*dist*"}).SetExcludePatternsMode(AppendExcludePatterns).SetDisabledDefaultExcludePatterns([]string{"*target*"})
+	assert.Equal(t, []string{"*.git*", "*node_modules*", "*venv*", "*/test/*", "*/tests/*", "*/__tests__/*", "*/testdata/*", "*dist*"}, params.EffectiveExclusions())
+}
+
+func TestGetExcludePatternsMode(t *testing.T) {
+	mode, err := GetExcludePatternsMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, ReplaceExcludePatterns, mode)
+	mode, err = GetExcludePatternsMode("append")
+	assert.NoError(t, err)
+	assert.Equal(t, AppendExcludePatterns, mode)
+	_, err = GetExcludePatternsMode("merge")
+	assert.ErrorContains(t, err, "unsupported exclude patterns mode 'merge'")
+}
+
+func TestRunScaScanDeduplicateIdenticalModules(t *testing.T) {
+	skipIfNpmIsMissing(t)
+	tmpDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fileutils.RemoveTempDir(tmpDir))
+	}()
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "npm-identical-modules"), tmpDir, true, nil))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.Chdir(wd))
+	}()
+	assert.NoError(t, os.Chdir(tmpDir))
+
+	var scanRequests int
+	testServer := createCustomXrayScanGraphMockServer(t, func(*xrayUtils.GraphNode) (string, int) {
+		scanRequests++
+		return "shared-scan", 0
+	}, func(string) any {
+		return services.ScanResponse{ScanId: "shared-scan", Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Severity: "High", Components: map[string]services.Component{"npm://underscore:1.13.6": {}}}}}
+	}, nil)
+	defer testServer.Close()
+
+	params := NewAuditParams().SetDeduplicateIdenticalModules(true)
+	params.SetServerDetails(&config.ServerDetails{XrayUrl: testServer.URL + "/xray/"})
+	params.xrayVersion = "3.80.0"
+	results := xrayutils.NewAuditResults()
+	assert.NoError(t, runScaScan(params, results))
+
+	assert.Equal(t, 1, scanRequests)
+	if assert.Len(t, results.ScaResults, 2) {
+		firstModule, secondModule := results.ScaResults[0], results.ScaResults[1]
+		assert.Equal(t, filepath.Join(tmpDir, "module1"), firstModule.WorkingDirectory)
+		assert.Empty(t, firstModule.SharedScanWorkingDirectory)
+		assert.Equal(t, filepath.Join(tmpDir, "module2"), secondModule.WorkingDirectory)
+		assert.Equal(t, firstModule.WorkingDirectory, secondModule.SharedScanWorkingDirectory)
+		assert.Equal(t, firstModule.XrayResults, secondModule.XrayResults)
+		assert.NotEmpty(t, secondModule.XrayResults)
+	}
+}
+
+func TestCreateScanGraphParamsSeverityFilterByTech(t *testing.T) {
+	params := NewAuditParams().
+		SetMinSeverityFilter("Medium").
+		SetSeverityFilterByTech(map[coreutils.Technology]string{coreutils.Npm: "High", coreutils.Go: "Critical"})
+	serverDetails := &config.ServerDetails{XrayUrl: "http://localhost/xray/"}
+	testCases := []struct {
+		tech             coreutils.Technology
+		expectedSeverity string
+	}{
+		{tech: coreutils.Npm, expectedSeverity: "High"},
+		{tech: coreutils.Go, expectedSeverity: "Critical"},
+		// Technologies without a filter of their own fall back to the global filter.
+		{tech: coreutils.Maven, expectedSeverity: "Medium"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.tech.String(), func(t *testing.T) {
+			scanGraphParams, err := createScanGraphParams(context.Background(), testCase.tech, "", params, serverDetails, "3.80.0", params.xrayGraphScanParams)
+			assert.NoError(t, err)
+			expectedParams := scangraph.NewScanGraphParams().
+				SetServerDetails(serverDetails).
+				SetXrayGraphScanParams(params.xrayGraphScanParams).
+				SetXrayVersion("3.80.0").
+				SetSeverityLevel(testCase.expectedSeverity).
+				SetContext(context.Background())
+			assert.Equal(t, expectedParams, scanGraphParams)
+		})
+	}
+}
+
+func TestCreateFlatTreeIsSorted(t *testing.T) {
+	uniqueDeps := []string{"npm://zod:3.22.4", "npm://axios:1.6.2", "npm://lodash:4.17.21", "npm://@types/node:20.10.0"}
+	firstTree, err := createFlatTree(uniqueDeps)
+	assert.NoError(t, err)
+	// The same dependencies in a different order, as collected from a set.
+	secondTree, err := createFlatTree([]string{uniqueDeps[2], uniqueDeps[0], uniqueDeps[3], uniqueDeps[1]})
+	assert.NoError(t, err)
+	assert.Equal(t, firstTree, secondTree)
+	var nodeIds []string
+	for _, node := range firstTree.Nodes {
+		nodeIds = append(nodeIds, node.Id)
+	}
+	assert.Equal(t, []string{"npm://@types/node:20.10.0", "npm://axios:1.6.2", "npm://lodash:4.17.21", "npm://zod:3.22.4"}, nodeIds)
+	// The input isn't modified.
+	assert.Equal(t, "npm://zod:3.22.4", uniqueDeps[0])
+}
+
+func TestGetWorkingDirKey(t *testing.T) {
+	assert.Equal(t, getWorkingDirKey(filepath.Join("repo", "module")), getWorkingDirKey(filepath.Join("repo", ".", "module")+string(filepath.Separator)))
+	assert.Equal(t, getWorkingDirKey(filepath.Join("repo", "module")), getWorkingDirKey(filepath.Join("repo", "other", "..", "module")))
+	if !coreutils.IsWindows() {
+		// The paths are case-sensitive on other operating systems.
+		assert.NotEqual(t, getWorkingDirKey("/repo"), getWorkingDirKey("/Repo"))
+	}
+}
+
+func TestGetScaScansToPreformWindowsPaths(t *testing.T) {
+	if !coreutils.IsWindows() {
+		t.Skip("Skipping TestGetScaScansToPreformWindowsPaths test on non-Windows...")
+	}
+	t.Run("Different spellings of the requested directory", func(t *testing.T) {
+		dirs, recursive := getRequestedDirectoriesToScan("tmp", NewAuditParams().SetWorkingDirs([]string{`C:\Repo`, `c:\repo\.`, "C:/REPO/"}))
+		assert.False(t, recursive)
+		assert.Equal(t, []string{`C:\Repo`}, dirs)
+	})
+
+	t.Run("Different spellings of a project directory", func(t *testing.T) {
+		dir, cleanUp := createTestDir(t)
+		defer cleanUp()
+		npmDir := filepath.Join(dir, "dir", "npm")
+		params := NewAuditParams().SetWorkingDirs([]string{npmDir, strings.ToUpper(npmDir) + `\.`, filepath.ToSlash(strings.ToLower(npmDir))})
+		params.SetTechnologies([]string{"npm"})
+		result, err := getScaScansToPreform(dir, params)
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("Checkpoint of a different spelling of the working directory", func(t *testing.T) {
+		checkpoint := &scaScanCheckpoint{CompletedScans: []xrayutils.ScaScanResult{{Technology: coreutils.Npm, WorkingDirectory: `C:\Repo`}}}
+		assert.NotNil(t, checkpoint.completedScan(&xrayutils.ScaScanResult{Technology: coreutils.Npm, WorkingDirectory: `c:\repo\.`}))
+	})
+}
+
+func TestGetScaScansToPreformDockerfileBaseImages(t *testing.T) {
+	tmpDir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fileutils.RemoveTempDir(tmpDir))
+	}()
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "dockerfile-project"), tmpDir, true, nil))
+
+	// The Dockerfiles are scanned only if requested.
+	scans, err := getScaScansToPreform(tmpDir, NewAuditParams())
+	assert.NoError(t, err)
+	assert.Empty(t, scans)
+
+	params := NewAuditParams().SetScanDockerfileBaseImages(true)
+	scans, err = getScaScansToPreform(tmpDir, params)
+	assert.NoError(t, err)
+	if assert.Len(t, scans, 2) {
+		assert.Equal(t, coreutils.Docker, scans[0].Technology)
+		assert.Equal(t, tmpDir, scans[0].WorkingDirectory)
+		assert.Equal(t, []string{filepath.Join(tmpDir, "Dockerfile")}, scans[0].Descriptors)
+		assert.Equal(t, coreutils.Docker, scans[1].Technology)
+		assert.Equal(t, filepath.Join(tmpDir, "deploy"), scans[1].WorkingDirectory)
+		assert.Equal(t, []string{filepath.Join(tmpDir, "deploy", "Dockerfile.prod")}, scans[1].Descriptors)
+	}
+}
+
+func TestBuildDependencyTreeWithResolutionFallback(t *testing.T) {
+	expectedTree := []*xrayUtils.GraphNode{{Id: "npm://root:1.0.0", Nodes: []*xrayUtils.GraphNode{{Id: "npm://dep:1.0.0"}}}}
+	tests := []struct {
+		name               string
+		resolutionFallback string
+		failingRepos       []string
+		expectedRepos      []string
+		expectedErr        bool
+	}{
+		{name: "Primary resolver succeeds", resolutionFallback: "fallback-repo", expectedRepos: []string{"primary-repo"}},
+		{name: "Fallback repository succeeds", resolutionFallback: "fallback-repo", failingRepos: []string{"primary-repo"}, expectedRepos: []string{"primary-repo", "fallback-repo"}},
+		{name: "Fallback to the public registry succeeds", resolutionFallback: xrayutils.PublicRegistryResolutionFallback, failingRepos: []string{"primary-repo"}, expectedRepos: []string{"primary-repo", ""}},
+		{name: "Fallback resolver fails", resolutionFallback: "fallback-repo", failingRepos: []string{"primary-repo", "fallback-repo"}, expectedRepos: []string{"primary-repo", "fallback-repo"}, expectedErr: true},
+		{name: "No fallback resolver", failingRepos: []string{"primary-repo"}, expectedRepos: []string{"primary-repo"}, expectedErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := NewAuditParams().SetDepsRepo("primary-repo").SetResolutionFallback(test.resolutionFallback)
+			var usedRepos []string
+			buildTree := func() ([]*xrayUtils.GraphNode, []string, error) {
+				usedRepos = append(usedRepos, params.DepsRepo())
+				if slices.Contains(test.failingRepos, params.DepsRepo()) {
+					return nil, nil, errors.New("failed to resolve the dependencies")
+				}
+				return expectedTree, []string{"npm://dep:1.0.0"}, nil
+			}
+			tree, uniqueDeps, err := buildDependencyTreeWithResolutionFallback(context.Background(), params.AuditBasicParams, coreutils.Npm, buildTree)
+			assert.Equal(t, test.expectedRepos, usedRepos)
+			// The resolution repository is restored after the fallback
+			assert.Equal(t, "primary-repo", params.DepsRepo())
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, expectedTree, tree)
+			assert.Equal(t, []string{"npm://dep:1.0.0"}, uniqueDeps)
+		})
+	}
+}
+
+func TestTreeNodesLimit(t *testing.T) {
+	flatTree := &xrayUtils.GraphNode{Id: "root"}
+	for i := 0; i < 25; i++ {
+		flatTree.Nodes = append(flatTree.Nodes, &xrayUtils.GraphNode{Id: fmt.Sprintf("npm://package-%d:1.0.0", i)})
+	}
+
+	t.Run("Within the limit", func(t *testing.T) {
+		params := (&xrayutils.AuditBasicParams{}).SetMaxTreeNodes(25)
+		assert.NoError(t, checkTreeNodesLimit(params, coreutils.Npm, len(flatTree.Nodes)))
+		// Unlimited by default.
+		assert.NoError(t, checkTreeNodesLimit(&xrayutils.AuditBasicParams{}, coreutils.Npm, len(flatTree.Nodes)))
+	})
+
+	t.Run("Fail policy", func(t *testing.T) {
+		params := (&xrayutils.AuditBasicParams{}).SetMaxTreeNodes(10)
+		assert.ErrorContains(t, checkTreeNodesLimit(params, coreutils.Npm, len(flatTree.Nodes)), "the npm dependency tree has 25 nodes, which exceeds the maximum of 10")
+	})
+
+	t.Run("Batch policy", func(t *testing.T) {
+		var batchSizes []int
+		testServer := createCustomXrayScanGraphMockServer(t, func(batch *xrayUtils.GraphNode) (string, int) {
+			batchSizes = append(batchSizes, len(batch.Nodes))
+			return fmt.Sprintf("batch-%d", len(batchSizes)), 0
+		}, func(scanId string) any {
+			return services.ScanResponse{ScanId: scanId}
+		}, nil)
+		defer testServer.Close()
+
+		params := NewAuditParams()
+		params.SetMaxTreeNodes(10).SetTreeNodesLimitPolicy(xrayutils.BatchOnTreeNodesLimit)
+		assert.NoError(t, checkTreeNodesLimit(params.AuditBasicParams, coreutils.Npm, len(flatTree.Nodes)))
+		params.xrayVersion = "3.80.0"
+		results, err := runScaWithServer(context.Background(), coreutils.Npm, "", params, &config.ServerDetails{XrayUrl: testServer.URL + "/xray/"}, params.xrayVersion, params.xrayGraphScanParams, flatTree, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10, 10, 5}, batchSizes)
+		if assert.Len(t, results, 3) {
+			assert.Equal(t, "batch-3", results[2].ScanId)
+		}
+	})
+}
+
+type failingDependencyTreeBuilder struct {
+	fakeDependencyTreeBuilder
+	failingDir string
+}
+
+func (builder *failingDependencyTreeBuilder) BuildTree(ctx context.Context, workingDir string, params xrayutils.AuditParams) ([]*xrayUtils.GraphNode, []string, error) {
+	if filepath.Base(workingDir) == builder.failingDir {
+		return nil, nil, errors.New("failed to resolve the dependencies")
+	}
+	return builder.fakeDependencyTreeBuilder.BuildTree(ctx, workingDir, params)
+}
+
+func TestRunScaScanInParallel(t *testing.T) {
+	tmpDir := t.TempDir()
+	modules := []string{"module1", "module2", "broken", "module3"}
+	for _, module := range modules {
+		assert.NoError(t, os.Mkdir(filepath.Join(tmpDir, module), 0755))
+	}
+	acme := coreutils.Technology("acme")
+	builder := &failingDependencyTreeBuilder{failingDir: "broken"}
+	RegisterDependencyTreeBuilder(acme, builder)
+	defer RegisterDependencyTreeBuilder(acme, nil)
+
+	plan := &scaScanPlan{}
+	for _, module := range modules {
+		plan.Scans = append(plan.Scans, scaScanPlanEntry{Technology: acme, WorkingDirectory: filepath.Join(tmpDir, module)})
+	}
+	content, err := json.Marshal(plan)
+	assert.NoError(t, err)
+	planPath := filepath.Join(t.TempDir(), "scan-plan.json")
+	assert.NoError(t, os.WriteFile(planPath, content, 0644))
+
+	vulnerability := services.Vulnerability{IssueId: "XRAY-1", Severity: "High", Components: map[string]services.Component{"generic://acme-lib:1.0.0": {}}}
+	xrayServer, serverDetails := createXrayScanGraphMockServer(t, "jpd1", services.ScanResponse{Vulnerabilities: []services.Vulnerability{vulnerability}})
+	defer xrayServer.Close()
+	params := NewAuditParams().SetScanPlanFile(planPath).SetMaxParallelScans(3)
+	params.SetServerDetails(serverDetails)
+	params.xrayVersion = "3.80.0"
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	results := xrayutils.NewAuditResults()
+	err = runScaScan(params, results)
+	// The failure of one scan doesn't stop the others, and the working directory isn't changed.
+	assert.ErrorContains(t, err, fmt.Sprintf("audit command in '%s' failed", filepath.Join(tmpDir, "broken")))
+	currentWd, wdErr := os.Getwd()
+	assert.NoError(t, wdErr)
+	assert.Equal(t, wd, currentWd)
+	assert.ElementsMatch(t, []string{filepath.Join(tmpDir, "module1"), filepath.Join(tmpDir, "module2"), filepath.Join(tmpDir, "module3")}, builder.workingDirs)
+
+	// The results are in the order of the scans.
+	if assert.Len(t, results.ScaResults, 3) {
+		for i, module := range []string{"module1", "module2", "module3"} {
+			assert.Equal(t, filepath.Join(tmpDir, module), results.ScaResults[i].WorkingDirectory)
+			if assert.Len(t, results.ScaResults[i].XrayResults, 1) {
+				assert.Equal(t, "jpd1-scan", results.ScaResults[i].XrayResults[0].ScanId)
+			}
+		}
+	}
+}
+
+type slowDependencyTreeBuilder struct {
+	fakeDependencyTreeBuilder
+	slowDir string
+	release chan struct{}
+}
+
+func (builder *slowDependencyTreeBuilder) BuildTree(ctx context.Context, workingDir string, params xrayutils.AuditParams) ([]*xrayUtils.GraphNode, []string, error) {
+	if filepath.Base(workingDir) == builder.slowDir {
+		select {
+		case <-builder.release:
+			return nil, nil, errors.New("released")
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	return builder.fakeDependencyTreeBuilder.BuildTree(ctx, workingDir, params)
+}
+
+func TestRunScaScanWithTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+	modules := []string{"module1", "hanging"}
+	for _, module := range modules {
+		assert.NoError(t, os.Mkdir(filepath.Join(tmpDir, module), 0755))
+	}
+	acme := coreutils.Technology("acme")
+	builder := &slowDependencyTreeBuilder{slowDir: "hanging", release: make(chan struct{})}
+	RegisterDependencyTreeBuilder(acme, builder)
+	defer RegisterDependencyTreeBuilder(acme, nil)
+
+	plan := &scaScanPlan{}
+	for _, module := range modules {
+		plan.Scans = append(plan.Scans, scaScanPlanEntry{Technology: acme, WorkingDirectory: filepath.Join(tmpDir, module)})
+	}
+	content, err := json.Marshal(plan)
+	assert.NoError(t, err)
+	planPath := filepath.Join(t.TempDir(), "scan-plan.json")
+	assert.NoError(t, os.WriteFile(planPath, content, 0644))
+
+	xrayServer, serverDetails := createXrayScanGraphMockServer(t, "jpd1", services.ScanResponse{})
+	defer xrayServer.Close()
+	// The scans are executed one at a time, so the hanging scan doesn't hold the dependency trees of the other one.
+	params := NewAuditParams().SetScanPlanFile(planPath).SetMaxParallelScans(1).SetScanTimeout(100 * time.Millisecond)
+	params.SetServerDetails(serverDetails)
+	params.xrayVersion = "3.80.0"
+
+	results := xrayutils.NewAuditResults()
+	err = runScaScan(params, results)
+	// The hanging scan is interrupted once it times out, so it doesn't hold the dependency trees lock.
+	select {
+	case dependencyTreesLock <- struct{}{}:
+		<-dependencyTreesLock
+	case <-time.After(time.Second):
+		assert.Fail(t, "the dependency trees lock wasn't released by the scan that timed out")
+	}
+	close(builder.release)
+	// The hanging scan fails without stalling the audit, and the other scan completes.
+	assert.ErrorContains(t, err, fmt.Sprintf("audit command in '%s' failed", filepath.Join(tmpDir, "hanging")))
+	assert.ErrorContains(t, err, "the Acme scan timed out after 100ms")
+	if assert.Len(t, results.ScaResults, 1) {
+		assert.Equal(t, filepath.Join(tmpDir, "module1"), results.ScaResults[0].WorkingDirectory)
+	}
+	// The outcomes of both scans are recorded in the order of the scans, so the failed one can be reported by its working directory.
+	if assert.Len(t, results.ScaScansOutcomes, 2) {
+		assert.Equal(t, filepath.Join(tmpDir, "module1"), results.ScaScansOutcomes[0].WorkingDirectory)
+		assert.True(t, results.ScaScansOutcomes[0].Succeeded())
+		assert.Equal(t, filepath.Join(tmpDir, "hanging"), results.ScaScansOutcomes[1].WorkingDirectory)
+		assert.Equal(t, acme, results.ScaScansOutcomes[1].Technology)
+		assert.ErrorContains(t, results.ScaScansOutcomes[1].Err, "the Acme scan timed out after 100ms")
+	}
+	if failedScans := results.GetFailedScaScans(); assert.Len(t, failedScans, 1) {
+		assert.Equal(t, filepath.Join(tmpDir, "hanging"), failedScans[0].WorkingDirectory)
+	}
+}
+
+func TestLockDependencyTrees(t *testing.T) {
+	isLockReleased := func() bool {
+		select {
+		case dependencyTreesLock <- struct{}{}:
+			<-dependencyTreesLock
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}
+	params := (&xrayutils.AuditBasicParams{}).SetDepsRepo("primary-repo")
+
+	t.Run("Released by the builder", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		release, err := lockDependencyTrees(ctx, params, false)
+		assert.NoError(t, err)
+		params.SetDepsRepo("configured-repo")
+		cancel()
+		// The builder can be interrupted, so the lock is released only when it completes.
+		assert.False(t, isLockReleased())
+		release()
+		assert.True(t, isLockReleased())
+		assert.Equal(t, "primary-repo", params.DepsRepo())
+	})
+
+	t.Run("Released once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		release, err := lockDependencyTrees(ctx, params, true)
+		assert.NoError(t, err)
+		params.SetDepsRepo("configured-repo")
+		assert.False(t, isLockReleased())
+		cancel()
+		// The builder can't be interrupted, so the next scans don't wait for it.
+		assert.True(t, isLockReleased())
+		assert.Equal(t, "primary-repo", params.DepsRepo())
+		// The builder that completes later doesn't release the lock again.
+		dependencyTreesLock <- struct{}{}
+		release()
+		assert.False(t, isLockReleased())
+		<-dependencyTreesLock
+	})
+
+	t.Run("Context done while waiting", func(t *testing.T) {
+		dependencyTreesLock <- struct{}{}
+		defer func() {
+			<-dependencyTreesLock
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := lockDependencyTrees(ctx, params, true)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestGetTechDependencyTreeInDifferentWorkingDirs(t *testing.T) {
+	helmDir, composerDir := t.TempDir(), t.TempDir()
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "helm-project"), helmDir, true, nil))
+	assert.NoError(t, biutils.CopyDir(filepath.Join("..", "testdata", "composer-project"), composerDir, true, nil))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	// The dependency trees of the two projects are built at the same time, neither of them in the current working directory.
+	var helmTree, composerTree *xrayUtils.GraphNode
+	errGroup := new(errgroup.Group)
+	errGroup.Go(func() (e error) {
+		helmTree, _, e = GetTechDependencyTree(&xrayutils.AuditBasicParams{}, coreutils.Helm, helmDir)
+		return
+	})
+	errGroup.Go(func() (e error) {
+		composerTree, _, e = GetTechDependencyTree(&xrayutils.AuditBasicParams{}, coreutils.Composer, composerDir)
+		return
+	})
+	assert.NoError(t, errGroup.Wait())
+	if assert.NotNil(t, helmTree) {
+		assert.ElementsMatch(t, []*xrayUtils.GraphNode{{Id: "helm://common:2.1.2"}, {Id: "helm://postgresql:12.1.2"}, {Id: "helm://redis:17.3.7"}}, helmTree.Nodes)
+	}
+	if assert.NotNil(t, composerTree) {
+		for _, node := range composerTree.Nodes {
+			assert.True(t, strings.HasPrefix(node.Id, "composer://"), node.Id)
+		}
+	}
+	currentWd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, wd, currentWd)
+}
+
+type deepDependencyTreeBuilder struct{}
+
+// Builds the tree: project -> [a -> [b -> [c -> [d]]], d].
+func (deepDependencyTreeBuilder) BuildTree(context.Context, string, xrayutils.AuditParams) ([]*xrayUtils.GraphNode, []string, error) {
+	d := &xrayUtils.GraphNode{Id: "generic://d:1.0.0"}
+	c := &xrayUtils.GraphNode{Id: "generic://c:1.0.0", Nodes: []*xrayUtils.GraphNode{d}}
+	b := &xrayUtils.GraphNode{Id: "generic://b:1.0.0", Nodes: []*xrayUtils.GraphNode{c}}
+	a := &xrayUtils.GraphNode{Id: "generic://a:1.0.0", Nodes: []*xrayUtils.GraphNode{b}}
+	project := &xrayUtils.GraphNode{Id: "deep-project", Nodes: []*xrayUtils.GraphNode{a, d}}
+	return []*xrayUtils.GraphNode{project}, []string{a.Id, b.Id, c.Id, d.Id}, nil
+}
+
+func TestMaxTreeDepth(t *testing.T) {
+	deep := coreutils.Technology("deep")
+	RegisterDependencyTreeBuilder(deep, deepDependencyTreeBuilder{})
+	defer RegisterDependencyTreeBuilder(deep, nil)
+
+	getFlatTreeIds := func(flatTree *xrayUtils.GraphNode) (ids []string) {
+		for _, node := range flatTree.Nodes {
+			ids = append(ids, node.Id)
+		}
+		return
+	}
+
+	// Unlimited by default.
+	flatTree, _, err := GetTechDependencyTree(&xrayutils.AuditBasicParams{}, deep, t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"generic://a:1.0.0", "generic://b:1.0.0", "generic://c:1.0.0", "generic://d:1.0.0"}, getFlatTreeIds(flatTree))
+
+	// The dependencies beyond depth 2 are dropped, unless they appear above it in another path, like 'd'.
+	flatTree, fullDependencyTrees, err := GetTechDependencyTree((&xrayutils.AuditBasicParams{}).SetMaxTreeDepth(2), deep, t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"generic://a:1.0.0", "generic://b:1.0.0", "generic://d:1.0.0"}, getFlatTreeIds(flatTree))
+	if assert.Len(t, fullDependencyTrees, 1) && assert.Len(t, fullDependencyTrees[0].Nodes, 2) {
+		a := sca.GetAndAssertNode(t, fullDependencyTrees[0].Nodes, "a:1.0.0")
+		b := sca.GetAndAssertNode(t, a.Nodes, "b:1.0.0")
+		assert.Empty(t, b.Nodes)
+		assert.Equal(t, a, b.Parent)
+		sca.GetAndAssertNode(t, fullDependencyTrees[0].Nodes, "d:1.0.0")
+	}
+}
+
+func TestGetScaScansToPreformCoalescesDuplicateScans(t *testing.T) {
+	dir := t.TempDir()
+	// Both npm and Yarn are detected, since '.yarnrc' isn't excluded by npm, and both have the same descriptor.
+	for _, file := range []string{"package.json", ".yarnrc"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte("{}"), 0644))
+	}
+	result, err := getScaScansToPreform(dir, NewAuditParams())
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, coreutils.Yarn, result[0].Technology)
+		assert.Equal(t, []string{filepath.Join(dir, "package.json")}, result[0].Descriptors)
+	}
+}
+
+func TestCoalesceDuplicateScans(t *testing.T) {
+	dir := t.TempDir()
+	scans := []*xrayutils.ScaScanResult{
+		{Technology: coreutils.Pip, WorkingDirectory: dir, Descriptors: []string{filepath.Join(dir, "setup.py"), filepath.Join(dir, "requirements.txt")}},
+		{Technology: coreutils.Go, WorkingDirectory: dir, Descriptors: []string{filepath.Join(dir, "go.mod")}},
+		// Same descriptors in a different order, and Poetry is preferred over pip.
+		{Technology: coreutils.Poetry, WorkingDirectory: dir, Descriptors: []string{filepath.Join(dir, "requirements.txt"), filepath.Join(dir, "setup.py")}},
+		// Neither technology is preferred, so the first scan is kept.
+		{Technology: coreutils.Bazel, WorkingDirectory: dir, Descriptors: []string{filepath.Join(dir, "go.mod")}},
+		// Scans without descriptors are always kept.
+		{Technology: coreutils.Npm, WorkingDirectory: dir},
+		{Technology: coreutils.Yarn, WorkingDirectory: dir},
+	}
+	var technologies []coreutils.Technology
+	for _, scan := range coalesceDuplicateScans(scans) {
+		technologies = append(technologies, scan.Technology)
+	}
+	assert.Equal(t, []coreutils.Technology{coreutils.Poetry, coreutils.Go, coreutils.Npm, coreutils.Yarn}, technologies)
+}
+
+func TestDetectScaScans(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/project"), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "web"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "web", "package.json"), []byte("{}"), 0644))
+
+	scans, err := DetectScaScans(dir, NewAuditParams())
+	assert.NoError(t, err)
+	expectedScans, err := getScaScansToPreform(dir, NewAuditParams())
+	assert.NoError(t, err)
+	assert.Equal(t, expectedScans, scans)
+	if assert.Len(t, scans, 2) {
+		assert.Equal(t, coreutils.Go, scans[0].Technology)
+		assert.Equal(t, dir, scans[0].WorkingDirectory)
+		assert.Equal(t, coreutils.Npm, scans[1].Technology)
+		assert.Equal(t, filepath.Join(dir, "web"), scans[1].WorkingDirectory)
+		// Nothing is scanned.
+		assert.Nil(t, scans[1].XrayResults)
+	}
+}
+
+func TestGetScaScansToPreformIncludePatterns(t *testing.T) {
+	dir := t.TempDir()
+	for _, descriptor := range []string{
+		filepath.Join("services", "api", "go.mod"),
+		filepath.Join("services", "web", "package.json"),
+		filepath.Join("services", "legacy", "requirements.txt"),
+		filepath.Join("tools", "cli", "go.mod"),
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(descriptor)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, descriptor), []byte("{}"), 0644))
+	}
+	getScannedDirs := func(params *AuditParams) (scannedDirs []string) {
+		scans, err := getScaScansToPreform(dir, params)
+		assert.NoError(t, err)
+		for _, scan := range scans {
+			relativePath, err := filepath.Rel(dir, scan.WorkingDirectory)
+			assert.NoError(t, err)
+			scannedDirs = append(scannedDirs, filepath.ToSlash(relativePath))
+		}
+		sort.Strings(scannedDirs)
+		return
+	}
+
+	// All the detected directories are scanned by default.
+	assert.Equal(t, []string{"services/api", "services/legacy", "services/web", "tools/cli"}, getScannedDirs(NewAuditParams()))
+	// Only the directories that match the include patterns are scanned.
+	assert.Equal(t, []string{"services/api", "services/legacy", "services/web"}, getScannedDirs(NewAuditParams().SetIncludePatterns([]string{"services/*"})))
+	assert.Equal(t, []string{"services/api", "tools/cli"}, getScannedDirs(NewAuditParams().SetIncludePatterns([]string{"*/api", "tools/*"})))
+	// The exclusions take precedence over the include patterns.
+	params := NewAuditParams().SetIncludePatterns([]string{"services/*"})
+	params.SetExclusions([]string{"*legacy*"}).SetExcludePatternsMode(AppendExcludePatterns)
+	assert.Equal(t, []string{"services/api", "services/web"}, getScannedDirs(params))
+}
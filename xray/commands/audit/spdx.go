@@ -0,0 +1,173 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	spdxVersion           = "SPDX-2.3"
+	spdxDataLicense       = "CC0-1.0"
+	spdxDocumentId        = "SPDXRef-DOCUMENT"
+	spdxNoAssertion       = "NOASSERTION"
+	spdxDefaultName       = "jfrog-audit"
+	spdxNamespacePrefix   = "https://jfrog.com/spdxdocs/"
+	spdxDescribesRelation = "DESCRIBES"
+	spdxDependsOnRelation = "DEPENDS_ON"
+)
+
+var (
+	// The characters that aren't allowed in SPDX identifiers.
+	spdxIdInvalidCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+	// Matches the license keys that can be used as SPDX license expressions as is.
+	spdxLicenseIdRegex = regexp.MustCompile(`^[a-zA-Z0-9.+-]+$`)
+)
+
+// An SPDX 2.3 document, with the fields that describe the packages of the scanned projects and the dependencies between them.
+type spdxDocument struct {
+	SpdxVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SpdxId            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SpdxId           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SpdxElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// Converts the given CycloneDX SBOM to an SPDX 2.3 document, with the same packages and dependencies.
+// The projects are described by the document, and each dependency edge is a DEPENDS_ON relationship.
+// SPDX doesn't describe vulnerabilities, so they aren't included.
+// The packages and the relationships are sorted, and the document namespace is derived from the packages, so the same scan produces the same document.
+func createSpdxDocument(bom *cdx.BOM) *spdxDocument {
+	var components []cdx.Component
+	if bom.Components != nil {
+		components = slices.Clone(*bom.Components)
+	}
+	slices.SortFunc(components, func(a, b cdx.Component) int {
+		return strings.Compare(a.BOMRef, b.BOMRef)
+	})
+	document := &spdxDocument{
+		SpdxVersion:   spdxVersion,
+		DataLicense:   spdxDataLicense,
+		SpdxId:        spdxDocumentId,
+		Name:          spdxDefaultName,
+		CreationInfo:  spdxCreationInfo{Created: bom.Metadata.Timestamp, Creators: []string{"Tool: jfrog-cli-core"}},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+	// The SPDX identifiers of the packages, by the BOM references of their components.
+	spdxIds := map[string]string{}
+	usedSpdxIds := map[string]bool{}
+	namespaceHash := sha256.New()
+	var projects []cdx.Component
+	for _, component := range components {
+		spdxId := getSpdxId(component.BOMRef, usedSpdxIds)
+		spdxIds[component.BOMRef] = spdxId
+		namespaceHash.Write([]byte(component.BOMRef + "\x00"))
+		spdxPackage := spdxPackage{
+			Name:             component.Name,
+			SpdxId:           spdxId,
+			VersionInfo:      component.Version,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  getSpdxLicenseExpression(component.Licenses),
+		}
+		if component.Group != "" {
+			spdxPackage.Name = component.Group + "/" + component.Name
+		}
+		if component.PackageURL != "" {
+			spdxPackage.ExternalRefs = []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: component.PackageURL}}
+		}
+		document.Packages = append(document.Packages, spdxPackage)
+		if component.Type == cdx.ComponentTypeApplication {
+			projects = append(projects, component)
+			document.Relationships = append(document.Relationships, spdxRelationship{SpdxElementId: spdxDocumentId, RelationshipType: spdxDescribesRelation, RelatedSpdxElement: spdxId})
+		}
+	}
+	if len(projects) == 1 {
+		document.Name = projects[0].Name
+	}
+	document.DocumentNamespace = fmt.Sprintf("%s%s-%s", spdxNamespacePrefix, document.Name, hex.EncodeToString(namespaceHash.Sum(nil)))
+	var dependsOnRelationships []spdxRelationship
+	if bom.Dependencies != nil {
+		for _, dependency := range *bom.Dependencies {
+			if dependency.Dependencies == nil {
+				continue
+			}
+			for _, dependsOn := range *dependency.Dependencies {
+				dependsOnRelationships = append(dependsOnRelationships, spdxRelationship{SpdxElementId: spdxIds[dependency.Ref], RelationshipType: spdxDependsOnRelation, RelatedSpdxElement: spdxIds[dependsOn]})
+			}
+		}
+	}
+	slices.SortFunc(dependsOnRelationships, func(a, b spdxRelationship) int {
+		if a.SpdxElementId != b.SpdxElementId {
+			return strings.Compare(a.SpdxElementId, b.SpdxElementId)
+		}
+		return strings.Compare(a.RelatedSpdxElement, b.RelatedSpdxElement)
+	})
+	document.Relationships = append(document.Relationships, dependsOnRelationships...)
+	return document
+}
+
+// Returns a unique SPDX identifier for the component of the given BOM reference, replacing the characters that aren't allowed in SPDX identifiers.
+// If the identifier is already used by another component, a counter is appended to it.
+func getSpdxId(bomRef string, usedSpdxIds map[string]bool) string {
+	baseId := "SPDXRef-Package-" + strings.Trim(spdxIdInvalidCharsRegex.ReplaceAllString(bomRef, "-"), "-")
+	spdxId := baseId
+	for i := 2; usedSpdxIds[spdxId]; i++ {
+		spdxId = fmt.Sprintf("%s-%d", baseId, i)
+	}
+	usedSpdxIds[spdxId] = true
+	return spdxId
+}
+
+// Returns the SPDX license expression of the given licenses, or NOASSERTION if any of them can't be expressed by its name.
+func getSpdxLicenseExpression(licenses *cdx.Licenses) string {
+	if licenses == nil || len(*licenses) == 0 {
+		return spdxNoAssertion
+	}
+	var licenseIds []string
+	for _, license := range *licenses {
+		if license.License == nil || !spdxLicenseIdRegex.MatchString(license.License.Name) || strings.EqualFold(license.License.Name, "unknown") {
+			return spdxNoAssertion
+		}
+		licenseIds = append(licenseIds, license.License.Name)
+	}
+	slices.Sort(licenseIds)
+	return strings.Join(licenseIds, " AND ")
+}
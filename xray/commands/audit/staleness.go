@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/http/httpclient"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+)
+
+// The release metadata of a package: its latest version, and the release times of its versions.
+type packageMetadata struct {
+	latestVersion string
+	releaseTimes  map[string]time.Time
+}
+
+type packageMetadataFetcher func(client *httpclient.HttpClient, registry packageMetadataRegistry, packageName string) (*packageMetadata, error)
+
+type packageMetadataRegistry struct {
+	url   string
+	fetch packageMetadataFetcher
+	// The details of the requests to the registry, with the credentials of the server if the registry is an Artifactory repository.
+	clientDetails httputils.HttpClientDetails
+}
+
+// The public registries that provide the release metadata of the packages, by the package types of their component IDs.
+var packageMetadataRegistries = map[string]packageMetadataRegistry{
+	"npm":  {url: "https://registry.npmjs.org", fetch: fetchNpmPackageMetadata},
+	"pypi": {url: "https://pypi.org/pypi", fetch: fetchPypiPackageMetadata},
+}
+
+// Returns the registries to read the release metadata of the packages from, with the registries of the params replacing the public ones.
+// A registry under the Artifactory URL of the server, such as 'https://acme.jfrog.io/artifactory/api/npm/npm-remote', is accessed with the credentials of the server.
+func getPackageMetadataRegistries(params *AuditParams) map[string]packageMetadataRegistry {
+	registries := map[string]packageMetadataRegistry{}
+	for packageType, registry := range packageMetadataRegistries {
+		registryUrl, configured := params.stalenessRegistries[packageType]
+		if !configured {
+			registries[packageType] = registry
+			continue
+		}
+		registry.url = strings.TrimSuffix(registryUrl, "/")
+		registry.clientDetails = getRegistryClientDetails(params, registryUrl)
+		registries[packageType] = registry
+	}
+	for packageType := range params.stalenessRegistries {
+		if _, supported := packageMetadataRegistries[packageType]; !supported {
+			log.Warn(fmt.Sprintf("The staleness of the '%s' packages can't be checked, so their registry is ignored.", packageType))
+		}
+	}
+	return registries
+}
+
+// Returns the details of the requests to the given registry, which include the credentials of the server only if the registry is in its Artifactory.
+func getRegistryClientDetails(params *AuditParams, registryUrl string) httputils.HttpClientDetails {
+	serverDetails, err := params.ServerDetails()
+	if err != nil || serverDetails == nil || serverDetails.ArtifactoryUrl == "" || !strings.HasPrefix(registryUrl, serverDetails.ArtifactoryUrl) {
+		return httputils.HttpClientDetails{}
+	}
+	artAuth, err := serverDetails.CreateArtAuthConfig()
+	if err != nil {
+		log.Debug(fmt.Sprintf("Couldn't get the credentials of the registry '%s': %s", registryUrl, err.Error()))
+		return httputils.HttpClientDetails{}
+	}
+	return artAuth.CreateHttpClientDetails()
+}
+
+// Annotates the direct dependencies of the SCA scans with how far behind their latest versions they are, according to the release metadata of their registries.
+// The staleness is an enrichment of the results, so failing to get the metadata of a package is logged and doesn't fail the audit.
+func addDependenciesStaleness(results *xrayutils.Results, registries map[string]packageMetadataRegistry, now time.Time) {
+	log.Info("Checking the staleness of the direct dependencies...")
+	client, err := httpclient.ClientBuilder().SetRetries(3).Build()
+	if err != nil {
+		log.Warn("Couldn't check the staleness of the dependencies:", err.Error())
+		return
+	}
+	// Packages may be used by several scans, so their metadata is fetched once.
+	metadataCache := map[string]*packageMetadata{}
+	var skippedTechnologies []string
+	for i := range results.ScaResults {
+		scan := &results.ScaResults[i]
+		for _, dependency := range scan.DirectDependencies {
+			packageType, _, _ := strings.Cut(dependency, "://")
+			registry, supported := registries[packageType]
+			if !supported {
+				if !slices.Contains(skippedTechnologies, scan.Technology.ToFormal()) {
+					skippedTechnologies = append(skippedTechnologies, scan.Technology.ToFormal())
+				}
+				continue
+			}
+			name, version, _ := xrayutils.SplitComponentId(dependency)
+			if version == "" {
+				continue
+			}
+			packageKey := packageType + "://" + name
+			metadata, fetched := metadataCache[packageKey]
+			if !fetched {
+				if metadata, err = registry.fetch(client, registry, name); err != nil {
+					log.Debug(fmt.Sprintf("Couldn't get the release metadata of '%s': %s", packageKey, err.Error()))
+				}
+				metadataCache[packageKey] = metadata
+			}
+			if metadata == nil {
+				continue
+			}
+			if scan.DependenciesStaleness == nil {
+				scan.DependenciesStaleness = map[string]xrayutils.DependencyStaleness{}
+			}
+			scan.DependenciesStaleness[dependency] = getDependencyStaleness(metadata, version, now)
+		}
+	}
+	if len(skippedTechnologies) > 0 {
+		log.Info(fmt.Sprintf("The staleness of the %s dependencies wasn't checked, since it's supported only for npm and PyPI packages.", strings.Join(skippedTechnologies, ", ")))
+	}
+}
+
+func getDependencyStaleness(metadata *packageMetadata, version string, now time.Time) xrayutils.DependencyStaleness {
+	staleness := xrayutils.DependencyStaleness{LatestVersion: metadata.latestVersion}
+	releaseTime, found := metadata.releaseTimes[version]
+	if !found {
+		return staleness
+	}
+	staleness.ReleaseTime = &releaseTime
+	staleness.ReleaseAgeDays = daysBetween(releaseTime, now)
+	if version == metadata.latestVersion {
+		return staleness
+	}
+	latestReleaseTime, latestFound := metadata.releaseTimes[metadata.latestVersion]
+	if latestFound && latestReleaseTime.After(releaseTime) {
+		staleness.DaysBehindLatest = daysBetween(releaseTime, latestReleaseTime)
+	}
+	for _, otherReleaseTime := range metadata.releaseTimes {
+		// Versions that were released after the latest version, like prereleases, aren't counted.
+		if otherReleaseTime.After(releaseTime) && (!latestFound || !otherReleaseTime.After(latestReleaseTime)) {
+			staleness.VersionsBehind++
+		}
+	}
+	return staleness
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func getJson(client *httpclient.HttpClient, url string, clientDetails httputils.HttpClientDetails, result interface{}) error {
+	resp, body, _, err := client.SendGet(url, true, clientDetails, "")
+	if err != nil {
+		return err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return err
+	}
+	return errorutils.CheckError(json.Unmarshal(body, result))
+}
+
+// The packument of an npm package, which maps its dist-tags to their versions, and its versions to their release times.
+type npmPackument struct {
+	DistTags map[string]string `json:"dist-tags"`
+	Time     map[string]string `json:"time"`
+}
+
+func fetchNpmPackageMetadata(client *httpclient.HttpClient, registry packageMetadataRegistry, packageName string) (*packageMetadata, error) {
+	var packument npmPackument
+	// The slash of scoped packages is escaped.
+	if err := getJson(client, registry.url+"/"+url.PathEscape(packageName), registry.clientDetails, &packument); err != nil {
+		return nil, err
+	}
+	metadata := &packageMetadata{latestVersion: packument.DistTags["latest"], releaseTimes: map[string]time.Time{}}
+	for version, releaseTime := range packument.Time {
+		// The packument also records the creation and modification times of the package.
+		if version == "created" || version == "modified" {
+			continue
+		}
+		if parsedTime, err := time.Parse(time.RFC3339, releaseTime); err == nil {
+			metadata.releaseTimes[version] = parsedTime
+		}
+	}
+	return metadata, nil
+}
+
+// The JSON API metadata of a PyPI project, which lists the files that were uploaded for each of its releases.
+type pypiProject struct {
+	Info struct {
+		Version string `json:"version"`
+	} `json:"info"`
+	Releases map[string][]struct {
+		UploadTime string `json:"upload_time_iso_8601"`
+	} `json:"releases"`
+}
+
+func fetchPypiPackageMetadata(client *httpclient.HttpClient, registry packageMetadataRegistry, packageName string) (*packageMetadata, error) {
+	var project pypiProject
+	if err := getJson(client, registry.url+"/"+url.PathEscape(packageName)+"/json", registry.clientDetails, &project); err != nil {
+		return nil, err
+	}
+	metadata := &packageMetadata{latestVersion: project.Info.Version, releaseTimes: map[string]time.Time{}}
+	for version, files := range project.Releases {
+		// A release is released when its first file is uploaded.
+		for _, file := range files {
+			uploadTime, err := time.Parse(time.RFC3339, file.UploadTime)
+			if err != nil {
+				continue
+			}
+			if releaseTime, found := metadata.releaseTimes[version]; !found || uploadTime.Before(releaseTime) {
+				metadata.releaseTimes[version] = uploadTime
+			}
+		}
+	}
+	return metadata, nil
+}
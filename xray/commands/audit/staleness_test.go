@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	coretests "github.com/jfrog/jfrog-cli-core/v2/common/tests"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddDependenciesStaleness(t *testing.T) {
+	testServer := coretests.CreateRestsMockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/npm/lodash":
+			_, _ = w.Write([]byte(`{"dist-tags":{"latest":"4.17.21"},"time":{"created":"2012-04-23T16:37:11.912Z","modified":"2024-01-01T00:00:00.000Z","4.17.20":"2020-08-13T16:53:54.152Z","4.17.21":"2021-02-20T15:42:16.891Z","5.0.0-beta":"2023-01-01T00:00:00.000Z"}}`))
+		case "/npm/@types%2Fnode":
+			_, _ = w.Write([]byte(`{"dist-tags":{"latest":"20.0.0"},"time":{"20.0.0":"2023-04-21T00:00:00.000Z"}}`))
+		case "/pypi/requests/json":
+			_, _ = w.Write([]byte(`{"info":{"version":"2.31.0"},"releases":{"2.28.0":[{"upload_time_iso_8601":"2022-06-09T14:44:25.000Z"},{"upload_time_iso_8601":"2022-06-09T14:44:20.000Z"}],"2.30.0":[{"upload_time_iso_8601":"2023-05-03T15:30:00.000Z"}],"2.31.0":[{"upload_time_iso_8601":"2023-05-22T15:12:00.000Z"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer testServer.Close()
+	registries := map[string]packageMetadataRegistry{
+		"npm":  {url: testServer.URL + "/npm", fetch: fetchNpmPackageMetadata},
+		"pypi": {url: testServer.URL + "/pypi", fetch: fetchPypiPackageMetadata},
+	}
+
+	results := xrayutils.NewAuditResults()
+	results.ScaResults = []xrayutils.ScaScanResult{
+		{Technology: coreutils.Npm, DirectDependencies: []string{"npm://lodash:4.17.20", "npm://@types/node:20.0.0", "npm://unknown:1.0.0"}},
+		{Technology: coreutils.Pip, DirectDependencies: []string{"pypi://requests:2.28.0"}},
+		{Technology: coreutils.Go, DirectDependencies: []string{"go://github.com/jfrog/gofrog:v1.3.0"}},
+	}
+	now := time.Date(2024, 2, 20, 15, 42, 16, 891000000, time.UTC)
+	addDependenciesStaleness(results, registries, now)
+
+	lodashRelease := time.Date(2020, 8, 13, 16, 53, 54, 152000000, time.UTC)
+	typesNodeRelease := time.Date(2023, 4, 21, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, map[string]xrayutils.DependencyStaleness{
+		// The beta version, which was released after the latest version, isn't counted.
+		"npm://lodash:4.17.20":     {LatestVersion: "4.17.21", ReleaseTime: &lodashRelease, ReleaseAgeDays: 1285, VersionsBehind: 1, DaysBehindLatest: 190},
+		"npm://@types/node:20.0.0": {LatestVersion: "20.0.0", ReleaseTime: &typesNodeRelease, ReleaseAgeDays: 305},
+	}, results.ScaResults[0].DependenciesStaleness)
+	// The release time of a PyPI release is the upload time of its first file.
+	requestsRelease := time.Date(2022, 6, 9, 14, 44, 20, 0, time.UTC)
+	assert.Equal(t, map[string]xrayutils.DependencyStaleness{
+		"pypi://requests:2.28.0": {LatestVersion: "2.31.0", ReleaseTime: &requestsRelease, ReleaseAgeDays: 621, VersionsBehind: 2, DaysBehindLatest: 347},
+	}, results.ScaResults[1].DependenciesStaleness)
+	// Packages of unsupported registries aren't annotated.
+	assert.Nil(t, results.ScaResults[2].DependenciesStaleness)
+}
+
+func TestGetPackageMetadataRegistries(t *testing.T) {
+	params := NewAuditParams()
+	params.SetServerDetails(&config.ServerDetails{ArtifactoryUrl: "https://acme.jfrog.io/artifactory/", AccessToken: "token"})
+
+	// The public registries are used by default.
+	registries := getPackageMetadataRegistries(params)
+	assert.Equal(t, "https://registry.npmjs.org", registries["npm"].url)
+	assert.Equal(t, "https://pypi.org/pypi", registries["pypi"].url)
+
+	// A registry in Artifactory is accessed with the credentials of the server, unlike other registries.
+	params.SetStalenessRegistries(map[string]string{"npm": "https://acme.jfrog.io/artifactory/api/npm/npm-remote/", "pypi": "https://pypi.acme.com/pypi"})
+	registries = getPackageMetadataRegistries(params)
+	assert.Equal(t, "https://acme.jfrog.io/artifactory/api/npm/npm-remote", registries["npm"].url)
+	assert.Equal(t, "token", registries["npm"].clientDetails.AccessToken)
+	assert.Equal(t, "https://pypi.acme.com/pypi", registries["pypi"].url)
+	assert.Empty(t, registries["pypi"].clientDetails.AccessToken)
+}
@@ -207,7 +207,12 @@ func (ca *CurationAuditCommand) getAuditParamsByTech(tech coreutils.Technology)
 }
 
 func (ca *CurationAuditCommand) auditTree(tech coreutils.Technology, results map[string][]*PackageStatus) error {
-	flattenGraph, fullDependenciesTree, err := audit.GetTechDependencyTree(ca.getAuditParamsByTech(tech), tech)
+	// The curation audit runs in the directory of each of the projects.
+	workingDir, err := coreutils.GetWorkingDirectory()
+	if err != nil {
+		return err
+	}
+	flattenGraph, fullDependenciesTree, err := audit.GetTechDependencyTree(ca.getAuditParamsByTech(tech), tech, workingDir)
 	if err != nil {
 		return err
 	}
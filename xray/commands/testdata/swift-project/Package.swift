@@ -0,0 +1,21 @@
+// swift-tools-version:5.7
+import PackageDescription
+
+let package = Package(
+    name: "AcmeApp",
+    platforms: [.macOS(.v12)],
+    dependencies: [
+        .package(url: "https://github.com/Alamofire/Alamofire.git", from: "5.6.0"),
+        .package(url: "https://github.com/apple/swift-log.git", from: "1.5.0"),
+        .package(url: "https://github.com/acme/acme-utils.git", branch: "main"),
+    ],
+    targets: [
+        .executableTarget(
+            name: "AcmeApp",
+            dependencies: [
+                "Alamofire",
+                .product(name: "Logging", package: "swift-log"),
+                .product(name: "AcmeUtils", package: "acme-utils"),
+            ]),
+    ]
+)
@@ -1,20 +1,41 @@
 package scangraph
 
 import (
+	"context"
+	"time"
+
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 )
 
+const (
+	// The default number of times a graph scan request that Xray rejected because of its rate limit is retried.
+	DefaultRateLimitRetries = 5
+	// The default wait before the first retry of a request that Xray rejected because of its rate limit.
+	DefaultRateLimitRetryWait = 2 * time.Second
+)
+
 type ScanGraphParams struct {
 	serverDetails       *config.ServerDetails
 	xrayGraphScanParams *services.XrayGraphScanParams
 	fixableOnly         bool
 	xrayVersion         string
 	severityLevel       int
+	// If set, the submitted graph scan request and the Xray response are written to files with this path prefix.
+	trafficDumpPathPrefix string
+	// The maximum number of graph scan requests that are submitted to Xray in parallel. The requests are submitted one at a time by default.
+	maxParallelGraphScans int
+	// If set, the publication dates of the CVEs that the scan found are collected into it.
+	cvesPublicationDates *CvesPublicationDates
+	// The number of times a request that Xray rejected because of its rate limit is retried, and the wait before the first retry, which is doubled before each of the following retries.
+	rateLimitRetries   int
+	rateLimitRetryWait time.Duration
+	// The requests aren't retried once the context is done.
+	ctx context.Context
 }
 
 func NewScanGraphParams() *ScanGraphParams {
-	return &ScanGraphParams{}
+	return &ScanGraphParams{rateLimitRetries: DefaultRateLimitRetries, rateLimitRetryWait: DefaultRateLimitRetryWait}
 }
 
 func (sgp *ScanGraphParams) SetServerDetails(serverDetails *config.ServerDetails) *ScanGraphParams {
@@ -57,3 +78,77 @@ func (sgp *ScanGraphParams) SetFixableOnly(fixable bool) *ScanGraphParams {
 	sgp.fixableOnly = fixable
 	return sgp
 }
+
+func (sgp *ScanGraphParams) TrafficDumpPathPrefix() string {
+	return sgp.trafficDumpPathPrefix
+}
+
+func (sgp *ScanGraphParams) SetTrafficDumpPathPrefix(trafficDumpPathPrefix string) *ScanGraphParams {
+	sgp.trafficDumpPathPrefix = trafficDumpPathPrefix
+	return sgp
+}
+
+func (sgp *ScanGraphParams) MaxParallelGraphScans() int {
+	if sgp.maxParallelGraphScans <= 0 {
+		return 1
+	}
+	return sgp.maxParallelGraphScans
+}
+
+func (sgp *ScanGraphParams) SetMaxParallelGraphScans(maxParallelGraphScans int) *ScanGraphParams {
+	sgp.maxParallelGraphScans = maxParallelGraphScans
+	return sgp
+}
+
+func (sgp *ScanGraphParams) CvesPublicationDates() *CvesPublicationDates {
+	return sgp.cvesPublicationDates
+}
+
+func (sgp *ScanGraphParams) SetCvesPublicationDates(cvesPublicationDates *CvesPublicationDates) *ScanGraphParams {
+	sgp.cvesPublicationDates = cvesPublicationDates
+	return sgp
+}
+
+func (sgp *ScanGraphParams) RateLimitRetries() int {
+	return sgp.rateLimitRetries
+}
+
+func (sgp *ScanGraphParams) SetRateLimitRetries(rateLimitRetries int) *ScanGraphParams {
+	sgp.rateLimitRetries = rateLimitRetries
+	return sgp
+}
+
+func (sgp *ScanGraphParams) RateLimitRetryWait() time.Duration {
+	return sgp.rateLimitRetryWait
+}
+
+func (sgp *ScanGraphParams) SetRateLimitRetryWait(rateLimitRetryWait time.Duration) *ScanGraphParams {
+	sgp.rateLimitRetryWait = rateLimitRetryWait
+	return sgp
+}
+
+func (sgp *ScanGraphParams) Context() context.Context {
+	if sgp.ctx == nil {
+		return context.Background()
+	}
+	return sgp.ctx
+}
+
+func (sgp *ScanGraphParams) SetContext(ctx context.Context) *ScanGraphParams {
+	sgp.ctx = ctx
+	return sgp
+}
+
+// Returns a copy of the params, with a copy of the graph scan params, so the copy can be used by a scan that runs in parallel to other scans.
+func (sgp *ScanGraphParams) Copy() *ScanGraphParams {
+	paramsCopy := *sgp
+	if sgp.xrayGraphScanParams != nil {
+		xrayGraphScanParams := *sgp.xrayGraphScanParams
+		if sgp.xrayGraphScanParams.XscGitInfoContext != nil {
+			xscGitInfoContext := *sgp.xrayGraphScanParams.XscGitInfoContext
+			xrayGraphScanParams.XscGitInfoContext = &xscGitInfoContext
+		}
+		paramsCopy.xrayGraphScanParams = &xrayGraphScanParams
+	}
+	return &paramsCopy
+}
@@ -0,0 +1,128 @@
+package scangraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const scanGraphApi = "api/v1/scan/graph"
+
+// Collects the publication dates of the CVEs that the graph scans found, by the CVE IDs.
+// The collector is shared by the copies of the scan graph params, so the dates that the batches of a split tree found are collected together.
+type CvesPublicationDates struct {
+	mutex sync.Mutex
+	dates map[string]time.Time
+}
+
+func NewCvesPublicationDates() *CvesPublicationDates {
+	return &CvesPublicationDates{dates: map[string]time.Time{}}
+}
+
+func (cpd *CvesPublicationDates) add(dates map[string]time.Time) {
+	cpd.mutex.Lock()
+	defer cpd.mutex.Unlock()
+	for cveId, publicationDate := range dates {
+		cpd.dates[cveId] = publicationDate
+	}
+}
+
+// Returns a copy of the collected publication dates, or nil if none were collected.
+func (cpd *CvesPublicationDates) Dates() map[string]time.Time {
+	cpd.mutex.Lock()
+	defer cpd.mutex.Unlock()
+	if len(cpd.dates) == 0 {
+		return nil
+	}
+	dates := make(map[string]time.Time, len(cpd.dates))
+	for cveId, publicationDate := range cpd.dates {
+		dates[cveId] = publicationDate
+	}
+	return dates
+}
+
+// The publication dates of the issues of the graph scan results. Xray reports them in the results, but the scan response of the client doesn't expose them.
+type scanIssuesPublicationDates struct {
+	Vulnerabilities []issuePublicationDates `json:"vulnerabilities,omitempty"`
+	Violations      []issuePublicationDates `json:"violations,omitempty"`
+}
+
+type issuePublicationDates struct {
+	Published string `json:"published,omitempty"`
+	Cves      []struct {
+		Id        string `json:"cve,omitempty"`
+		Published string `json:"published,omitempty"`
+	} `json:"cves,omitempty"`
+}
+
+// Reads the results of the completed graph scan from Xray, and collects the publication dates of their CVEs.
+// A CVE without a publication date of its own gets the publication date of its issue, and a CVE without any remains undated.
+func collectCvesPublicationDates(xrayManager *xray.XrayServicesManager, scanId string, params *ScanGraphParams, xscEnabled bool) error {
+	body, err := getScanGraphResultsBody(xrayManager, scanId, params.xrayGraphScanParams.IncludeVulnerabilities, xscEnabled)
+	if err != nil {
+		return err
+	}
+	var issues scanIssuesPublicationDates
+	if err = json.Unmarshal(body, &issues); err != nil {
+		return errorutils.CheckErrorf("couldn't parse the publication dates of the Xray scan results: %s", err.Error())
+	}
+	dates := map[string]time.Time{}
+	for _, issue := range append(issues.Vulnerabilities, issues.Violations...) {
+		for _, cve := range issue.Cves {
+			published := cve.Published
+			if published == "" {
+				published = issue.Published
+			}
+			if cve.Id == "" || published == "" {
+				continue
+			}
+			publicationDate, err := parsePublicationDate(published)
+			if err != nil {
+				log.Debug(fmt.Sprintf("Ignoring the publication date of '%s': %s", cve.Id, err.Error()))
+				continue
+			}
+			dates[cve.Id] = publicationDate
+		}
+	}
+	params.cvesPublicationDates.add(dates)
+	return nil
+}
+
+// The scan is already completed, so its results are returned immediately, without polling.
+func getScanGraphResultsBody(xrayManager *xray.XrayServicesManager, scanId string, includeVulnerabilities, xscEnabled bool) ([]byte, error) {
+	serviceDetails := xrayManager.Config().GetServiceDetails()
+	endpoint := serviceDetails.GetUrl() + scanGraphApi
+	if xscEnabled {
+		endpoint = strings.Replace(serviceDetails.GetUrl(), services.XraySuffix, services.XscSuffix, 1) + services.XscGraphAPI
+	}
+	endpoint += "/" + scanId
+	if includeVulnerabilities {
+		endpoint += "?include_vulnerabilities=true"
+	}
+	httpClientDetails := serviceDetails.CreateHttpClientDetails()
+	resp, body, _, err := xrayManager.Client().SendGet(endpoint, true, &httpClientDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func parsePublicationDate(published string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if publicationDate, err := time.Parse(layout, published); err == nil {
+			return publicationDate, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid publication date '%s'", published)
+}
@@ -1,8 +1,20 @@
 package scangraph
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -31,19 +43,102 @@ func RunScanGraphAndGetResults(params *ScanGraphParams) (*services.ScanResponse,
 		}
 	}
 
-	scanId, err := xrayManager.ScanGraph(*params.xrayGraphScanParams)
+	var scanId string
+	err = runWithRateLimitRetries(params, "Submitting the graph scan", func() (e error) {
+		scanId, e = xrayManager.ScanGraph(*params.xrayGraphScanParams)
+		return
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	xscEnabled := params.xrayGraphScanParams.XscVersion != ""
-	scanResult, err := xrayManager.GetScanGraphResults(scanId, params.XrayGraphScanParams().IncludeVulnerabilities, params.XrayGraphScanParams().IncludeLicenses, xscEnabled)
+	var scanResult *services.ScanResponse
+	err = runWithRateLimitRetries(params, "Getting the graph scan results", func() (e error) {
+		scanResult, e = xrayManager.GetScanGraphResults(scanId, params.XrayGraphScanParams().IncludeVulnerabilities, params.XrayGraphScanParams().IncludeLicenses, xscEnabled)
+		return
+	})
 	if err != nil {
 		return nil, err
 	}
+	if params.cvesPublicationDates != nil {
+		// The publication dates only narrow the reported findings, so the CVEs are left undated if they can't be read.
+		if e := collectCvesPublicationDates(xrayManager, scanId, params, xscEnabled); e != nil {
+			log.Warn("Couldn't read the publication dates of the CVEs from Xray:", e.Error())
+		}
+	}
+	if params.trafficDumpPathPrefix != "" {
+		if err = dumpScanTraffic(params, scanId, scanResult); err != nil {
+			return nil, err
+		}
+	}
 	return filterResultIfNeeded(scanResult, params), nil
 }
 
+// Runs the given request, and retries it with an increasing wait as long as Xray rejects it because of its rate limit, up to the rate limit retries of the params.
+// The HTTP client already retries such requests, but without waiting between the retries, which isn't enough when several scans are submitted in parallel.
+// The request isn't retried once the context of the params is done.
+func runWithRateLimitRetries(params *ScanGraphParams, description string, request func() error) (err error) {
+	wait := params.RateLimitRetryWait()
+	for i := 0; ; i++ {
+		if err = request(); err == nil || i >= params.RateLimitRetries() || !isRateLimitError(err) {
+			return
+		}
+		log.Warn(fmt.Sprintf("%s was rejected by Xray because of its rate limit. Retrying in %s (attempt %d of %d)...", description, wait, i+1, params.RateLimitRetries()))
+		if waitErr := utils.WaitBeforeRetry(params.Context(), wait); waitErr != nil {
+			return errors.Join(err, waitErr)
+		}
+		wait *= 2
+	}
+}
+
+// Returns whether the error is the response of Xray to a request that exceeded its rate limit.
+func isRateLimitError(err error) bool {
+	return strings.Contains(err.Error(), "server response: "+strconv.Itoa(http.StatusTooManyRequests))
+}
+
+// The submitted graph scan request, as written to the traffic dump file.
+type scanRequestDump struct {
+	XrayUrl     string                        `json:"xray_url"`
+	XrayVersion string                        `json:"xray_version"`
+	ScanId      string                        `json:"scan_id"`
+	Params      *services.XrayGraphScanParams `json:"params"`
+}
+
+// Write the submitted graph scan request and the Xray response (before any filtering) to files, for debugging.
+// No credentials are written, the user info is removed from the Xray URL.
+func dumpScanTraffic(params *ScanGraphParams, scanId string, scanResult *services.ScanResponse) error {
+	if err := os.MkdirAll(filepath.Dir(params.trafficDumpPathPrefix), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	request := scanRequestDump{XrayUrl: redactUrl(params.serverDetails.XrayUrl), XrayVersion: params.xrayVersion, ScanId: scanId, Params: params.xrayGraphScanParams}
+	if err := writeJsonFile(params.trafficDumpPathPrefix+"-request.json", request); err != nil {
+		return err
+	}
+	if err := writeJsonFile(params.trafficDumpPathPrefix+"-response.json", scanResult); err != nil {
+		return err
+	}
+	log.Debug("The Xray scan request and response were written to", params.trafficDumpPathPrefix+"-*.json")
+	return nil
+}
+
+func redactUrl(rawUrl string) string {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	parsedUrl.User = nil
+	return parsedUrl.String()
+}
+
+func writeJsonFile(path string, content any) error {
+	bytes, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(path, bytes, 0644))
+}
+
 func filterResultIfNeeded(scanResult *services.ScanResponse, params *ScanGraphParams) *services.ScanResponse {
 	if !shouldFilterResults(params) {
 		return scanResult
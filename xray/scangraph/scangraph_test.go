@@ -1,10 +1,13 @@
 package scangraph
 
 import (
+	"context"
+	"errors"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestFilterResultIfNeeded(t *testing.T) {
@@ -159,3 +162,63 @@ func TestGetFixableComponents(t *testing.T) {
 		})
 	}
 }
+
+func TestRunWithRateLimitRetries(t *testing.T) {
+	params := NewScanGraphParams().SetRateLimitRetryWait(0)
+	rateLimitErr := errors.New("server response: 429 Too Many Requests")
+
+	t.Run("Succeeds after the rate limit is lifted", func(t *testing.T) {
+		attempts := 0
+		err := runWithRateLimitRetries(params, "Scanning", func() error {
+			if attempts++; attempts < 3 {
+				return rateLimitErr
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("Gives up after the maximum retries", func(t *testing.T) {
+		attempts := 0
+		err := runWithRateLimitRetries(params, "Scanning", func() error {
+			attempts++
+			return rateLimitErr
+		})
+		assert.ErrorIs(t, err, rateLimitErr)
+		assert.Equal(t, DefaultRateLimitRetries+1, attempts)
+	})
+
+	t.Run("Configured retries", func(t *testing.T) {
+		attempts := 0
+		err := runWithRateLimitRetries(params.Copy().SetRateLimitRetries(0), "Scanning", func() error {
+			attempts++
+			return rateLimitErr
+		})
+		assert.ErrorIs(t, err, rateLimitErr)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Other errors aren't retried", func(t *testing.T) {
+		attempts := 0
+		err := runWithRateLimitRetries(params, "Scanning", func() error {
+			attempts++
+			return errors.New("server response: 500 Internal Server Error")
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Context done while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		attempts := 0
+		err := runWithRateLimitRetries(NewScanGraphParams().SetRateLimitRetryWait(time.Hour).SetContext(ctx), "Scanning", func() error {
+			attempts++
+			return rateLimitErr
+		})
+		assert.ErrorIs(t, err, rateLimitErr)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, attempts)
+	})
+}
@@ -6,6 +6,20 @@ import (
 	ioUtils "github.com/jfrog/jfrog-client-go/utils/io"
 )
 
+// The resolution fallback that resolves the dependencies from the public registry of the technology, instead of from an Artifactory repository.
+// Repository keys can't contain colons, so it can't be mistaken for a repository.
+const PublicRegistryResolutionFallback = ":public"
+
+// How to handle a dependency tree with more nodes than the maximum number of tree nodes.
+type TreeNodesLimitPolicy string
+
+const (
+	// Fail the scan of the tree, which is the default.
+	FailOnTreeNodesLimit TreeNodesLimitPolicy = "fail"
+	// Scan the tree in batches of at most the maximum number of nodes.
+	BatchOnTreeNodesLimit TreeNodesLimitPolicy = "batch"
+)
+
 type AuditParams interface {
 	DirectDependencies() []string
 	AppendDependenciesForApplicabilityScan(directDependencies []string) *AuditBasicParams
@@ -13,8 +27,14 @@ type AuditParams interface {
 	SetServerDetails(serverDetails *config.ServerDetails) *AuditBasicParams
 	PipRequirementsFile() string
 	SetPipRequirementsFile(requirementsFile string) *AuditBasicParams
+	PythonVenvPath() string
+	SetPythonVenvPath(pythonVenvPath string) *AuditBasicParams
+	GoProxy() string
+	SetGoProxy(goProxy string) *AuditBasicParams
 	ExcludeTestDependencies() bool
 	SetExcludeTestDependencies(excludeTestDependencies bool) *AuditBasicParams
+	ExcludeDevDependencies() bool
+	SetExcludeDevDependencies(excludeDevDependencies bool) *AuditBasicParams
 	UseWrapper() bool
 	SetUseWrapper(useWrapper bool) *AuditBasicParams
 	InsecureTls() bool
@@ -34,6 +54,30 @@ type AuditParams interface {
 	SetIgnoreConfigFile(ignoreConfigFile bool) *AuditBasicParams
 	IsMavenDepTreeInstalled() bool
 	SetIsMavenDepTreeInstalled(isMavenDepTreeInstalled bool) *AuditBasicParams
+	LogCommands() bool
+	SetLogCommands(logCommands bool) *AuditBasicParams
+	ScanExclusions() []string
+	SetScanExclusions(scanExclusions []string) *AuditBasicParams
+	ScanHelmImages() bool
+	SetScanHelmImages(scanHelmImages bool) *AuditBasicParams
+	MavenOffline() bool
+	SetMavenOffline(mavenOffline bool) *AuditBasicParams
+	ScanVendored() bool
+	SetScanVendored(scanVendored bool) *AuditBasicParams
+	ScanDockerfileBaseImages() bool
+	SetScanDockerfileBaseImages(scanDockerfileBaseImages bool) *AuditBasicParams
+	UseGradleDaemon() bool
+	SetUseGradleDaemon(useGradleDaemon bool) *AuditBasicParams
+	UseGradleConfigurationCache() bool
+	SetUseGradleConfigurationCache(useGradleConfigurationCache bool) *AuditBasicParams
+	ResolutionFallback() string
+	SetResolutionFallback(repo string) *AuditBasicParams
+	MaxTreeNodes() int
+	SetMaxTreeNodes(maxTreeNodes int) *AuditBasicParams
+	TreeNodesLimitPolicy() TreeNodesLimitPolicy
+	SetTreeNodesLimitPolicy(treeNodesLimitPolicy TreeNodesLimitPolicy) *AuditBasicParams
+	MaxTreeDepth() int
+	SetMaxTreeDepth(maxTreeDepth int) *AuditBasicParams
 }
 
 type AuditBasicParams struct {
@@ -41,10 +85,12 @@ type AuditBasicParams struct {
 	outputFormat                     format.OutputFormat
 	progress                         ioUtils.ProgressMgr
 	excludeTestDependencies          bool
+	excludeDevDependencies           bool
 	useWrapper                       bool
 	insecureTls                      bool
 	ignoreConfigFile                 bool
 	isMavenDepTreeInstalled          bool
+	logCommands                      bool
 	pipRequirementsFile              string
 	depsRepo                         string
 	installCommandName               string
@@ -52,6 +98,37 @@ type AuditBasicParams struct {
 	args                             []string
 	installCommandArgs               []string
 	dependenciesForApplicabilityScan []string
+	// Patterns of paths to exclude from the dependencies walk of the detected projects. The npm, Yarn, Maven, Gradle and NuGet dependency tree builders exclude the packages, modules or projects in the matching paths.
+	// Other technologies don't support them, and are scanned with a warning. Unlike the audit exclusions, these paths are still walked when detecting the technologies.
+	scanExclusions []string
+	// Whether to add the container images referenced by the values of Helm charts to the scanned dependency trees.
+	scanHelmImages bool
+	// Whether to resolve the Maven dependencies from the pom.xml files, instead of running Maven.
+	mavenOffline bool
+	// Whether to scan the Go modules that are vendored in the vendor directory, instead of the modules that go.mod requires.
+	scanVendored bool
+	// Whether to scan the base images that are referenced by the FROM instructions of the Dockerfiles, alongside the projects.
+	scanDockerfileBaseImages bool
+	// Whether to let Gradle use its daemon and its configuration cache when building the Gradle dependency trees.
+	// By default, both are disabled, so the dependencies are resolved from a fresh build configuration rather than a stale cached one.
+	// Enabling them makes repeated audits faster, at the risk of inconsistent results when the build configuration changes between runs.
+	useGradleDaemon             bool
+	useGradleConfigurationCache bool
+	// The repository to resolve the dependencies from if resolving them from the resolution repository fails, or PublicRegistryResolutionFallback.
+	resolutionFallback string
+	// The maximum number of nodes of a scanned dependency tree, which protects against trees that are too large to scan at once. Unlimited if not positive.
+	// Trees with more nodes are handled by the tree nodes limit policy.
+	maxTreeNodes         int
+	treeNodesLimitPolicy TreeNodesLimitPolicy
+	// The virtual environment, or the Python interpreter inside it, whose installed packages are scanned as the Python dependencies, instead of installing them for the audit.
+	// The virtual environment isn't modified.
+	pythonVenvPath string
+	// The Go module proxy to resolve the Go modules from while building the Go dependency trees, after the resolution repository if there is one.
+	// Unlike the resolution repository, it is set only for the Go commands of the audit, rather than for the whole process.
+	goProxy string
+	// The maximum depth of the dependency trees, beyond which the dependencies are dropped from the trees and from the scan. Unlimited if not positive.
+	// The roots of the trees are at depth 0, and their direct dependencies are at depth 1.
+	maxTreeDepth int
 }
 
 func (abp *AuditBasicParams) DirectDependencies() []string {
@@ -91,6 +168,24 @@ func (abp *AuditBasicParams) SetPipRequirementsFile(requirementsFile string) *Au
 	return abp
 }
 
+func (abp *AuditBasicParams) PythonVenvPath() string {
+	return abp.pythonVenvPath
+}
+
+func (abp *AuditBasicParams) SetPythonVenvPath(pythonVenvPath string) *AuditBasicParams {
+	abp.pythonVenvPath = pythonVenvPath
+	return abp
+}
+
+func (abp *AuditBasicParams) GoProxy() string {
+	return abp.goProxy
+}
+
+func (abp *AuditBasicParams) SetGoProxy(goProxy string) *AuditBasicParams {
+	abp.goProxy = goProxy
+	return abp
+}
+
 func (abp *AuditBasicParams) ExcludeTestDependencies() bool {
 	return abp.excludeTestDependencies
 }
@@ -100,6 +195,17 @@ func (abp *AuditBasicParams) SetExcludeTestDependencies(excludeTestDependencies
 	return abp
 }
 
+// Whether the npm and Yarn dev dependencies are pruned from the dependency trees.
+// A dependency that is required by both a dev dependency and a production dependency is kept.
+func (abp *AuditBasicParams) ExcludeDevDependencies() bool {
+	return abp.excludeDevDependencies
+}
+
+func (abp *AuditBasicParams) SetExcludeDevDependencies(excludeDevDependencies bool) *AuditBasicParams {
+	abp.excludeDevDependencies = excludeDevDependencies
+	return abp
+}
+
 func (abp *AuditBasicParams) UseWrapper() bool {
 	return abp.useWrapper
 }
@@ -192,3 +298,114 @@ func (abp *AuditBasicParams) SetIsMavenDepTreeInstalled(isMavenDepTreeInstalled
 	abp.isMavenDepTreeInstalled = isMavenDepTreeInstalled
 	return abp
 }
+
+func (abp *AuditBasicParams) LogCommands() bool {
+	return abp.logCommands
+}
+
+func (abp *AuditBasicParams) SetLogCommands(logCommands bool) *AuditBasicParams {
+	abp.logCommands = logCommands
+	return abp
+}
+
+func (abp *AuditBasicParams) ScanExclusions() []string {
+	return abp.scanExclusions
+}
+
+func (abp *AuditBasicParams) SetScanExclusions(scanExclusions []string) *AuditBasicParams {
+	abp.scanExclusions = scanExclusions
+	return abp
+}
+
+func (abp *AuditBasicParams) ScanHelmImages() bool {
+	return abp.scanHelmImages
+}
+
+func (abp *AuditBasicParams) SetScanHelmImages(scanHelmImages bool) *AuditBasicParams {
+	abp.scanHelmImages = scanHelmImages
+	return abp
+}
+
+func (abp *AuditBasicParams) MavenOffline() bool {
+	return abp.mavenOffline
+}
+
+func (abp *AuditBasicParams) SetMavenOffline(mavenOffline bool) *AuditBasicParams {
+	abp.mavenOffline = mavenOffline
+	return abp
+}
+
+func (abp *AuditBasicParams) ScanVendored() bool {
+	return abp.scanVendored
+}
+
+func (abp *AuditBasicParams) SetScanVendored(scanVendored bool) *AuditBasicParams {
+	abp.scanVendored = scanVendored
+	return abp
+}
+
+func (abp *AuditBasicParams) ScanDockerfileBaseImages() bool {
+	return abp.scanDockerfileBaseImages
+}
+
+func (abp *AuditBasicParams) SetScanDockerfileBaseImages(scanDockerfileBaseImages bool) *AuditBasicParams {
+	abp.scanDockerfileBaseImages = scanDockerfileBaseImages
+	return abp
+}
+
+func (abp *AuditBasicParams) UseGradleDaemon() bool {
+	return abp.useGradleDaemon
+}
+
+func (abp *AuditBasicParams) SetUseGradleDaemon(useGradleDaemon bool) *AuditBasicParams {
+	abp.useGradleDaemon = useGradleDaemon
+	return abp
+}
+
+func (abp *AuditBasicParams) UseGradleConfigurationCache() bool {
+	return abp.useGradleConfigurationCache
+}
+
+func (abp *AuditBasicParams) SetUseGradleConfigurationCache(useGradleConfigurationCache bool) *AuditBasicParams {
+	abp.useGradleConfigurationCache = useGradleConfigurationCache
+	return abp
+}
+
+func (abp *AuditBasicParams) ResolutionFallback() string {
+	return abp.resolutionFallback
+}
+
+func (abp *AuditBasicParams) SetResolutionFallback(repo string) *AuditBasicParams {
+	abp.resolutionFallback = repo
+	return abp
+}
+
+func (abp *AuditBasicParams) MaxTreeNodes() int {
+	return abp.maxTreeNodes
+}
+
+func (abp *AuditBasicParams) SetMaxTreeNodes(maxTreeNodes int) *AuditBasicParams {
+	abp.maxTreeNodes = maxTreeNodes
+	return abp
+}
+
+func (abp *AuditBasicParams) TreeNodesLimitPolicy() TreeNodesLimitPolicy {
+	if abp.treeNodesLimitPolicy == "" {
+		return FailOnTreeNodesLimit
+	}
+	return abp.treeNodesLimitPolicy
+}
+
+func (abp *AuditBasicParams) SetTreeNodesLimitPolicy(treeNodesLimitPolicy TreeNodesLimitPolicy) *AuditBasicParams {
+	abp.treeNodesLimitPolicy = treeNodesLimitPolicy
+	return abp
+}
+
+func (abp *AuditBasicParams) MaxTreeDepth() int {
+	return abp.maxTreeDepth
+}
+
+func (abp *AuditBasicParams) SetMaxTreeDepth(maxTreeDepth int) *AuditBasicParams {
+	abp.maxTreeDepth = maxTreeDepth
+	return abp
+}
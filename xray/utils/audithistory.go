@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"golang.org/x/exp/slices"
+)
+
+const auditHistoryFileSuffix = ".json"
+
+// Replaces the characters that can't be used in file names, so a branch name can be used as the name of its history file.
+var branchFileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+// The findings of the last audit that ran on a branch.
+type AuditHistoryRecord struct {
+	Branch    string    `json:"branch"`
+	Timestamp time.Time `json:"timestamp"`
+	// The findings are identified by the issue ID and the impacted component, e.g. 'XRAY-1234|npm://lodash:4.17.0'.
+	Findings []string `json:"findings"`
+}
+
+// The difference between the findings of two consecutive audits on the same branch.
+type AuditHistoryDelta struct {
+	Branch            string    `json:"branch"`
+	PreviousTimestamp time.Time `json:"previous_timestamp"`
+	Added             []string  `json:"added"`
+	Fixed             []string  `json:"fixed"`
+}
+
+// Returns the sorted identifiers of the vulnerabilities and violations found by the SCA scans.
+func GetAuditFindings(results *Results) []string {
+	findingsSet := map[string]bool{}
+	addFindings := func(issueId string, components map[string]services.Component) {
+		for componentId := range components {
+			findingsSet[issueId+"|"+componentId] = true
+		}
+	}
+	for _, scanResponse := range results.GetScaScansXrayResults() {
+		for _, vulnerability := range scanResponse.Vulnerabilities {
+			addFindings(vulnerability.IssueId, vulnerability.Components)
+		}
+		for _, violation := range scanResponse.Violations {
+			addFindings(violation.IssueId, violation.Components)
+		}
+	}
+	findings := make([]string, 0, len(findingsSet))
+	for finding := range findingsSet {
+		findings = append(findings, finding)
+	}
+	sort.Strings(findings)
+	return findings
+}
+
+// Compares the findings of the current audit with the findings of the previous one.
+func CompareAuditFindings(previous *AuditHistoryRecord, currentFindings []string) *AuditHistoryDelta {
+	delta := &AuditHistoryDelta{Branch: previous.Branch, PreviousTimestamp: previous.Timestamp, Added: []string{}, Fixed: []string{}}
+	for _, finding := range currentFindings {
+		if !slices.Contains(previous.Findings, finding) {
+			delta.Added = append(delta.Added, finding)
+		}
+	}
+	for _, finding := range previous.Findings {
+		if !slices.Contains(currentFindings, finding) {
+			delta.Fixed = append(delta.Fixed, finding)
+		}
+	}
+	return delta
+}
+
+// Compares the audit results with the last results that were saved for the branch, and saves them instead.
+// Returns nil if there are no previous results for the branch.
+func TrackAuditHistory(historyDir, branch string, results *Results) (delta *AuditHistoryDelta, err error) {
+	previous, err := LoadAuditHistory(historyDir, branch)
+	if err != nil {
+		return
+	}
+	current := &AuditHistoryRecord{Branch: branch, Timestamp: time.Now(), Findings: GetAuditFindings(results)}
+	if previous != nil {
+		delta = CompareAuditFindings(previous, current.Findings)
+	}
+	err = SaveAuditHistory(historyDir, current)
+	return
+}
+
+// Loads the last audit results that were saved for the branch, or returns nil if there are none.
+func LoadAuditHistory(historyDir, branch string) (*AuditHistoryRecord, error) {
+	historyFilePath := getAuditHistoryFilePath(historyDir, branch)
+	exists, err := fileutils.IsFileExists(historyFilePath, false)
+	if err != nil || !exists {
+		return nil, err
+	}
+	content, err := os.ReadFile(historyFilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	record := &AuditHistoryRecord{}
+	if err = json.Unmarshal(content, record); err != nil {
+		return nil, errorutils.CheckErrorf("failed to parse the audit history file '%s': %s", historyFilePath, err.Error())
+	}
+	return record, nil
+}
+
+func SaveAuditHistory(historyDir string, record *AuditHistoryRecord) error {
+	if err := fileutils.CreateDirIfNotExist(historyDir); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(getAuditHistoryFilePath(historyDir, record.Branch), content, 0600))
+}
+
+func getAuditHistoryFilePath(historyDir, branch string) string {
+	return filepath.Join(historyDir, branchFileNameReplacer.Replace(branch)+auditHistoryFileSuffix)
+}
+
+// The summary is logged rather than printed to the standard output, so it doesn't interfere with the formatted results.
+func PrintAuditHistoryDelta(delta *AuditHistoryDelta) {
+	log.Info(fmt.Sprintf("Compared to the last audit on the '%s' branch (%s): %d new and %d fixed findings.", delta.Branch, delta.PreviousTimestamp.Format(time.RFC3339), len(delta.Added), len(delta.Fixed)))
+	for _, finding := range delta.Added {
+		log.Info("  + " + finding)
+	}
+	for _, finding := range delta.Fixed {
+		log.Info("  - " + finding)
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func createHistoryTestResults(vulnerabilities []services.Vulnerability, violations []services.Violation) *Results {
+	results := NewAuditResults()
+	results.ScaResults = []ScaScanResult{{XrayResults: []services.ScanResponse{{Vulnerabilities: vulnerabilities, Violations: violations}}}}
+	return results
+}
+
+func TestGetAuditFindings(t *testing.T) {
+	results := createHistoryTestResults(
+		[]services.Vulnerability{{IssueId: "XRAY-2", Components: map[string]services.Component{"npm://lodash:4.17.0": {}, "npm://minimist:1.2.0": {}}}},
+		[]services.Violation{{IssueId: "XRAY-1", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}}, {IssueId: "XRAY-2", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}}},
+	)
+	assert.Equal(t, []string{"XRAY-1|npm://lodash:4.17.0", "XRAY-2|npm://lodash:4.17.0", "XRAY-2|npm://minimist:1.2.0"}, GetAuditFindings(results))
+}
+
+func TestTrackAuditHistory(t *testing.T) {
+	historyDir := t.TempDir()
+
+	// First run on the branch, nothing to compare with
+	firstResults := createHistoryTestResults([]services.Vulnerability{
+		{IssueId: "XRAY-1", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}},
+		{IssueId: "XRAY-2", Components: map[string]services.Component{"npm://minimist:1.2.0": {}}},
+	}, nil)
+	delta, err := TrackAuditHistory(historyDir, "feature/history", firstResults)
+	assert.NoError(t, err)
+	assert.Nil(t, delta)
+
+	// Another branch doesn't affect the history of the first one
+	_, err = TrackAuditHistory(historyDir, "main", createHistoryTestResults(nil, nil))
+	assert.NoError(t, err)
+
+	// Second run on the branch, one vulnerability was fixed and two were added
+	secondResults := createHistoryTestResults([]services.Vulnerability{
+		{IssueId: "XRAY-1", Components: map[string]services.Component{"npm://lodash:4.17.0": {}}},
+		{IssueId: "XRAY-3", Components: map[string]services.Component{"npm://axios:0.21.0": {}}},
+		{IssueId: "XRAY-4", Components: map[string]services.Component{"npm://axios:0.21.0": {}}},
+	}, nil)
+	delta, err = TrackAuditHistory(historyDir, "feature/history", secondResults)
+	assert.NoError(t, err)
+	if assert.NotNil(t, delta) {
+		assert.Equal(t, "feature/history", delta.Branch)
+		assert.Equal(t, []string{"XRAY-3|npm://axios:0.21.0", "XRAY-4|npm://axios:0.21.0"}, delta.Added)
+		assert.Equal(t, []string{"XRAY-2|npm://minimist:1.2.0"}, delta.Fixed)
+	}
+
+	// The second run replaced the saved results of the branch
+	record, err := LoadAuditHistory(historyDir, "feature/history")
+	assert.NoError(t, err)
+	if assert.NotNil(t, record) {
+		assert.Equal(t, GetAuditFindings(secondResults), record.Findings)
+	}
+}
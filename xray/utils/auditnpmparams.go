@@ -4,6 +4,10 @@ type AuditNpmParams struct {
 	AuditParams
 	npmIgnoreNodeModules    bool
 	npmOverwritePackageLock bool
+	// Exclude the packages that are installed only as optional dependencies from the dependency tree.
+	npmExcludeOptionalDependencies bool
+	// Exclude the packages that are installed only as peer dependencies from the dependency tree.
+	npmExcludePeerDependencies bool
 }
 
 func (anp AuditNpmParams) SetNpmIgnoreNodeModules(ignoreNpmNodeModules bool) AuditNpmParams {
@@ -23,3 +27,21 @@ func (anp AuditNpmParams) NpmIgnoreNodeModules() bool {
 func (anp AuditNpmParams) NpmOverwritePackageLock() bool {
 	return anp.npmOverwritePackageLock
 }
+
+func (anp AuditNpmParams) SetNpmExcludeOptionalDependencies(excludeOptionalDependencies bool) AuditNpmParams {
+	anp.npmExcludeOptionalDependencies = excludeOptionalDependencies
+	return anp
+}
+
+func (anp AuditNpmParams) SetNpmExcludePeerDependencies(excludePeerDependencies bool) AuditNpmParams {
+	anp.npmExcludePeerDependencies = excludePeerDependencies
+	return anp
+}
+
+func (anp AuditNpmParams) NpmExcludeOptionalDependencies() bool {
+	return anp.npmExcludeOptionalDependencies
+}
+
+func (anp AuditNpmParams) NpmExcludePeerDependencies() bool {
+	return anp.npmExcludePeerDependencies
+}
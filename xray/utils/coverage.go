@@ -0,0 +1,71 @@
+package utils
+
+// Metrics of how much of the scanned project an SCA scan covered, to surface the gaps of the scan.
+type ScaScanCoverage struct {
+	// The descriptor files that the dependency trees were built from.
+	Descriptors []string `json:"Descriptors,omitempty"`
+	// The direct dependencies that the project declares, and how many of them were resolved to a version in the dependency trees.
+	DeclaredDependencies int `json:"DeclaredDependencies"`
+	ResolvedDependencies int `json:"ResolvedDependencies"`
+	// The components that were submitted to Xray, and how many of them Xray returned data for.
+	// Xray returns the licenses of all the components it knows, so the latter is counted only when the licenses are included in the scan.
+	SubmittedComponents int  `json:"SubmittedComponents"`
+	ComponentsWithData  *int `json:"ComponentsWithData,omitempty"`
+}
+
+// Creates the coverage of a scan of the given descriptors, whose dependency trees have the given direct dependencies.
+// A dependency whose version couldn't be resolved has an empty version in the dependency trees.
+func NewScaScanCoverage(descriptors, directDependencies []string) *ScaScanCoverage {
+	coverage := &ScaScanCoverage{Descriptors: descriptors, DeclaredDependencies: len(directDependencies)}
+	for _, dependency := range directDependencies {
+		if _, version, _ := SplitComponentId(dependency); version != "" {
+			coverage.ResolvedDependencies++
+		}
+	}
+	return coverage
+}
+
+// Returns the covered fraction of the scan, between 0 and 1: the fraction of the resolved dependencies, multiplied by the fraction of the submitted components that Xray returned data for.
+// Metrics that weren't measured, or that have nothing to measure, don't reduce the coverage.
+func (c *ScaScanCoverage) Coverage() float64 {
+	coverage := 1.0
+	if c.DeclaredDependencies > 0 {
+		coverage *= float64(c.ResolvedDependencies) / float64(c.DeclaredDependencies)
+	}
+	if c.ComponentsWithData != nil && c.SubmittedComponents > 0 {
+		coverage *= float64(*c.ComponentsWithData) / float64(c.SubmittedComponents)
+	}
+	return coverage
+}
+
+// Returns the coverage of all the SCA scans, by summing up their metrics, or nil if no scan measured its coverage.
+// Xray's data is counted only if all the scans counted it, so the components of the other scans don't dilute it.
+func (r *Results) GetScaScansCoverage() *ScaScanCoverage {
+	var aggregated *ScaScanCoverage
+	componentsWithDataCounted := true
+	componentsWithData := 0
+	for _, scan := range r.ScaResults {
+		if scan.Coverage == nil {
+			continue
+		}
+		if aggregated == nil {
+			aggregated = &ScaScanCoverage{}
+		}
+		aggregated.Descriptors = append(aggregated.Descriptors, scan.Coverage.Descriptors...)
+		aggregated.DeclaredDependencies += scan.Coverage.DeclaredDependencies
+		aggregated.ResolvedDependencies += scan.Coverage.ResolvedDependencies
+		aggregated.SubmittedComponents += scan.Coverage.SubmittedComponents
+		if scan.Coverage.ComponentsWithData == nil {
+			// Scans that submitted no components have nothing to count.
+			if scan.Coverage.SubmittedComponents > 0 {
+				componentsWithDataCounted = false
+			}
+			continue
+		}
+		componentsWithData += *scan.Coverage.ComponentsWithData
+	}
+	if aggregated != nil && componentsWithDataCounted {
+		aggregated.ComponentsWithData = &componentsWithData
+	}
+	return aggregated
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaScanCoverage(t *testing.T) {
+	// One of the four declared dependencies wasn't resolved to a version
+	coverage := NewScaScanCoverage([]string{"/project/package.json"}, []string{"npm://express:4.18.2", "npm://lodash:4.17.21", "npm://missing:", "npm://react:18.2.0"})
+	assert.Equal(t, &ScaScanCoverage{Descriptors: []string{"/project/package.json"}, DeclaredDependencies: 4, ResolvedDependencies: 3}, coverage)
+	assert.Equal(t, 0.75, coverage.Coverage())
+
+	// Xray returned data for 8 of the 10 submitted components
+	coverage.SubmittedComponents = 10
+	coverage.ComponentsWithData = utils.Pointer(8)
+	assert.InDelta(t, 0.6, coverage.Coverage(), 0.0001)
+
+	// Nothing to measure
+	assert.Equal(t, 1.0, (&ScaScanCoverage{}).Coverage())
+}
+
+func TestGetScaScansCoverage(t *testing.T) {
+	assert.Nil(t, (&Results{ScaResults: []ScaScanResult{{}}}).GetScaScansCoverage())
+
+	results := &Results{ScaResults: []ScaScanResult{
+		{Coverage: &ScaScanCoverage{Descriptors: []string{"/project/package.json"}, DeclaredDependencies: 4, ResolvedDependencies: 3, SubmittedComponents: 10, ComponentsWithData: utils.Pointer(8)}},
+		{Coverage: &ScaScanCoverage{Descriptors: []string{"/project/go.mod"}, DeclaredDependencies: 4, ResolvedDependencies: 4, SubmittedComponents: 6, ComponentsWithData: utils.Pointer(6)}},
+		// All the components of this scan were filtered out before the submission
+		{Coverage: &ScaScanCoverage{Descriptors: []string{"/project/pom.xml"}, DeclaredDependencies: 2, ResolvedDependencies: 2}},
+	}}
+	coverage := results.GetScaScansCoverage()
+	assert.Equal(t, &ScaScanCoverage{
+		Descriptors:          []string{"/project/package.json", "/project/go.mod", "/project/pom.xml"},
+		DeclaredDependencies: 10,
+		ResolvedDependencies: 9,
+		SubmittedComponents:  16,
+		ComponentsWithData:   utils.Pointer(14),
+	}, coverage)
+	assert.InDelta(t, 0.9*14/16, coverage.Coverage(), 0.0001)
+
+	// Xray's data isn't counted if a scan that submitted components didn't count it
+	results.ScaResults[1].Coverage.ComponentsWithData = nil
+	coverage = results.GetScaScansCoverage()
+	assert.Nil(t, coverage.ComponentsWithData)
+	assert.InDelta(t, 0.9, coverage.Coverage(), 0.0001)
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"os/exec"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/slices"
+)
+
+// The information that a dependency tree builder records while building the dependency trees of a project, and returns with them.
+// Each build records its own information, so builds of different projects don't share it.
+type DependencyTreesInfo struct {
+	// The subprocess commands that were executed directly by the builder, with their secrets masked. Recorded only if logging commands was requested.
+	ExecutedCommands []string
+	// Maps the IDs of the dependencies to their scopes (such as prod/dev for npm, or compile/test for Maven), as reported by the dependency tree builders that support it.
+	DependenciesScopes map[string][]string
+	// Maps the IDs of the dependencies that were fetched from git repositories, rather than from a package registry, to their git references, as reported by the dependency tree builders that support it.
+	GitSourcedDependencies map[string]string
+	logCommands            bool
+}
+
+func NewDependencyTreesInfo(logCommands bool) *DependencyTreesInfo {
+	return &DependencyTreesInfo{logCommands: logCommands}
+}
+
+// If logging commands was requested, logs the given subprocess command with its secrets masked, and records it in the executed commands list.
+func (dti *DependencyTreesInfo) RecordExecutedCommand(executable string, args ...string) {
+	if !dti.logCommands {
+		return
+	}
+	maskedCommand := coreutils.GetMaskedCommandString(exec.Command(executable, args...))
+	log.Info("Executing command:", maskedCommand)
+	dti.ExecutedCommands = append(dti.ExecutedCommands, maskedCommand)
+}
+
+// Records the given scopes of the dependency, in addition to its previously recorded scopes.
+func (dti *DependencyTreesInfo) AddDependencyScopes(dependencyId string, scopes ...string) {
+	if dti.DependenciesScopes == nil {
+		dti.DependenciesScopes = map[string][]string{}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(dti.DependenciesScopes[dependencyId], scope) {
+			dti.DependenciesScopes[dependencyId] = append(dti.DependenciesScopes[dependencyId], scope)
+		}
+	}
+}
+
+// Records that the dependency was fetched from the given git reference, such as a git URL with a commit, rather than from a package registry.
+func (dti *DependencyTreesInfo) AddGitSourcedDependency(dependencyId, gitReference string) {
+	if dti.GitSourcedDependencies == nil {
+		dti.GitSourcedDependencies = map[string]string{}
+	}
+	dti.GitSourcedDependencies[dependencyId] = gitReference
+}
+
+// Adds the information of another build, such as a build that was retried, to this information. Does nothing if the other information is nil.
+func (dti *DependencyTreesInfo) Add(other *DependencyTreesInfo) {
+	if other == nil {
+		return
+	}
+	dti.ExecutedCommands = append(dti.ExecutedCommands, other.ExecutedCommands...)
+	for dependencyId, scopes := range other.DependenciesScopes {
+		dti.AddDependencyScopes(dependencyId, scopes...)
+	}
+	for dependencyId, gitReference := range other.GitSourcedDependencies {
+		dti.AddGitSourcedDependency(dependencyId, gitReference)
+	}
+}
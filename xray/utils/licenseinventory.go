@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/jfrog/jfrog-cli-core/v2/common/format"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/exp/maps"
+)
+
+// A list of strings, written as a single semicolon separated value in CSV.
+type StringList []string
+
+func (sl StringList) MarshalCSV() (string, error) {
+	return strings.Join(sl, ";"), nil
+}
+
+// A single component of the license inventory, with its declared licenses.
+type LicenseInventoryRow struct {
+	Component   string     `json:"component" csv:"Component"`
+	Version     string     `json:"version" csv:"Version"`
+	Licenses    StringList `json:"licenses" csv:"Licenses"`
+	Technology  string     `json:"technology" csv:"Technology"`
+	Descriptors StringList `json:"descriptors" csv:"Descriptors"`
+}
+
+// Returns the license inventory of all the scanned components, sorted by the scans order and then by the component name and version.
+// Only the licenses data of the scan results is used, vulnerabilities and violations are ignored.
+func GetLicenseInventory(results *Results) (inventory []LicenseInventoryRow) {
+	for _, scan := range results.ScaResults {
+		inventory = append(inventory, getScanLicenseInventory(scan)...)
+	}
+	return
+}
+
+func getScanLicenseInventory(scan ScaScanResult) []LicenseInventoryRow {
+	componentsLicenses := map[string]map[string]bool{}
+	for _, xrayResult := range scan.XrayResults {
+		for _, license := range xrayResult.Licenses {
+			for componentId := range license.Components {
+				if _, exists := componentsLicenses[componentId]; !exists {
+					componentsLicenses[componentId] = map[string]bool{}
+				}
+				componentsLicenses[componentId][license.Key] = true
+			}
+		}
+	}
+	var rows []LicenseInventoryRow
+	for componentId, licenses := range componentsLicenses {
+		name, version, _ := SplitComponentId(componentId)
+		licenseKeys := maps.Keys(licenses)
+		sort.Strings(licenseKeys)
+		rows = append(rows, LicenseInventoryRow{
+			Component:   name,
+			Version:     version,
+			Licenses:    licenseKeys,
+			Technology:  scan.Technology.ToFormal(),
+			Descriptors: scan.Descriptors,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Component != rows[j].Component {
+			return rows[i].Component < rows[j].Component
+		}
+		return rows[i].Version < rows[j].Version
+	})
+	return rows
+}
+
+// Print the license inventory as JSON for the JSON output formats, and as CSV otherwise.
+func PrintLicenseInventory(inventory []LicenseInventoryRow, outputFormat format.OutputFormat) error {
+	if outputFormat == format.Json || outputFormat == format.SimpleJson {
+		if inventory == nil {
+			inventory = []LicenseInventoryRow{}
+		}
+		return PrintJson(inventory)
+	}
+	csvContent, err := ConvertLicenseInventoryToCsv(inventory)
+	if err != nil {
+		return err
+	}
+	log.Output(csvContent)
+	return nil
+}
+
+func ConvertLicenseInventoryToCsv(inventory []LicenseInventoryRow) (string, error) {
+	var content bytes.Buffer
+	if inventory == nil {
+		// An empty inventory is written with the header only
+		inventory = []LicenseInventoryRow{}
+	}
+	if err := gocsv.Marshal(inventory, &content); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return content.String(), nil
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLicenseInventory(t *testing.T) {
+	results := &Results{ScaResults: []ScaScanResult{
+		{
+			Technology:  coreutils.Npm,
+			Descriptors: []string{"/project/package.json"},
+			XrayResults: []services.ScanResponse{{
+				Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Components: map[string]services.Component{"npm://lodash:4.17.20": {}}}},
+				Licenses: []services.License{
+					{Key: "MIT", Components: map[string]services.Component{"npm://lodash:4.17.20": {}, "npm://@jfrog/lib:1.0.0": {}}},
+					{Key: "Apache-2.0", Components: map[string]services.Component{"npm://@jfrog/lib:1.0.0": {}}},
+				},
+			}},
+		},
+		{
+			Technology:  coreutils.Go,
+			Descriptors: []string{"/project/go/go.mod"},
+			XrayResults: []services.ScanResponse{{
+				Licenses: []services.License{{Key: "BSD-3-Clause", Components: map[string]services.Component{"go://golang.org/x/text:v0.3.3": {}}}},
+			}},
+		},
+	}}
+
+	inventory := GetLicenseInventory(results)
+	assert.Equal(t, []LicenseInventoryRow{
+		{Component: "@jfrog/lib", Version: "1.0.0", Licenses: StringList{"Apache-2.0", "MIT"}, Technology: "npm", Descriptors: StringList{"/project/package.json"}},
+		{Component: "lodash", Version: "4.17.20", Licenses: StringList{"MIT"}, Technology: "npm", Descriptors: StringList{"/project/package.json"}},
+		{Component: "golang.org/x/text", Version: "v0.3.3", Licenses: StringList{"BSD-3-Clause"}, Technology: "Go", Descriptors: StringList{"/project/go/go.mod"}},
+	}, inventory)
+
+	csvContent, err := ConvertLicenseInventoryToCsv(inventory)
+	assert.NoError(t, err)
+	assert.Equal(t, "Component,Version,Licenses,Technology,Descriptors\n"+
+		"@jfrog/lib,1.0.0,Apache-2.0;MIT,npm,/project/package.json\n"+
+		"lodash,4.17.20,MIT,npm,/project/package.json\n"+
+		"golang.org/x/text,v0.3.3,BSD-3-Clause,Go,/project/go/go.mod\n", csvContent)
+
+	csvContent, err = ConvertLicenseInventoryToCsv(GetLicenseInventory(&Results{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Component,Version,Licenses,Technology,Descriptors\n", csvContent)
+}
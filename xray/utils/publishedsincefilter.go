@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+// Returns a copy of the results, in which the vulnerabilities and violations are filtered to the findings with CVEs that were published since the given time.
+// The publication dates of the CVEs are taken from each scan's recorded CVEs publication dates. Findings without CVEs, or whose CVEs have no known publication dates,
+// are included only if includeUndated is true. The license findings and the given results are not modified.
+func GetCvesPublishedSinceResults(results *Results, since time.Time, includeUndated bool) *Results {
+	filteredResults := *results
+	filteredResults.ScaResults = make([]ScaScanResult, 0, len(results.ScaResults))
+	for _, scan := range results.ScaResults {
+		var filteredXrayResults []services.ScanResponse
+		for _, xrayResult := range scan.XrayResults {
+			var vulnerabilities []services.Vulnerability
+			for _, vulnerability := range xrayResult.Vulnerabilities {
+				if isPublishedSince(vulnerability.Cves, scan.CvesPublicationDates, since, includeUndated) {
+					vulnerabilities = append(vulnerabilities, vulnerability)
+				}
+			}
+			var violations []services.Violation
+			for _, violation := range xrayResult.Violations {
+				// License violations have no CVEs, and aren't filtered.
+				if violation.LicenseKey != "" || isPublishedSince(violation.Cves, scan.CvesPublicationDates, since, includeUndated) {
+					violations = append(violations, violation)
+				}
+			}
+			xrayResult.Vulnerabilities, xrayResult.Violations = vulnerabilities, violations
+			filteredXrayResults = append(filteredXrayResults, xrayResult)
+		}
+		scan.XrayResults = filteredXrayResults
+		filteredResults.ScaResults = append(filteredResults.ScaResults, scan)
+	}
+	return &filteredResults
+}
+
+// Returns whether any of the given CVEs with a known publication date was published since the given time.
+// If none of them has a known publication date, returns includeUndated.
+func isPublishedSince(cves []services.Cve, publicationDates map[string]time.Time, since time.Time, includeUndated bool) bool {
+	dated := false
+	for _, cve := range cves {
+		publicationDate, found := publicationDates[cve.Id]
+		if !found {
+			continue
+		}
+		if !publicationDate.Before(since) {
+			return true
+		}
+		dated = true
+	}
+	return !dated && includeUndated
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCvesPublishedSinceResults(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	results := &Results{ScaResults: []ScaScanResult{{
+		XrayResults: []services.ScanResponse{{
+			Vulnerabilities: []services.Vulnerability{
+				{IssueId: "XRAY-1", Cves: []services.Cve{{Id: "CVE-2024-0001"}}},
+				{IssueId: "XRAY-2", Cves: []services.Cve{{Id: "CVE-2023-0002"}}},
+				// One of the CVEs is recent.
+				{IssueId: "XRAY-3", Cves: []services.Cve{{Id: "CVE-2023-0002"}, {Id: "CVE-2024-0003"}}},
+				// No known publication date.
+				{IssueId: "XRAY-4", Cves: []services.Cve{{Id: "CVE-2024-0004"}}},
+				// No CVEs.
+				{IssueId: "XRAY-5"},
+				// The known publication date decides, even if another CVE has no date.
+				{IssueId: "XRAY-6", Cves: []services.Cve{{Id: "CVE-2023-0002"}, {Id: "CVE-2024-0004"}}},
+			},
+			Violations: []services.Violation{
+				{IssueId: "XRAY-1", Cves: []services.Cve{{Id: "CVE-2024-0001"}}},
+				{IssueId: "XRAY-2", Cves: []services.Cve{{Id: "CVE-2023-0002"}}},
+				{IssueId: "MIT", LicenseKey: "MIT"},
+			},
+			Licenses: []services.License{{Key: "MIT"}},
+		}},
+		CvesPublicationDates: map[string]time.Time{
+			// Published exactly at the given time.
+			"CVE-2024-0001": since,
+			"CVE-2023-0002": time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+			"CVE-2024-0003": time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+	}}}
+
+	getIssueIds := func(results *Results) (vulnerabilities, violations []string) {
+		for _, vulnerability := range results.ScaResults[0].XrayResults[0].Vulnerabilities {
+			vulnerabilities = append(vulnerabilities, vulnerability.IssueId)
+		}
+		for _, violation := range results.ScaResults[0].XrayResults[0].Violations {
+			violations = append(violations, violation.IssueId)
+		}
+		return
+	}
+
+	t.Run("Undated excluded", func(t *testing.T) {
+		filteredResults := GetCvesPublishedSinceResults(results, since, false)
+		vulnerabilities, violations := getIssueIds(filteredResults)
+		assert.Equal(t, []string{"XRAY-1", "XRAY-3"}, vulnerabilities)
+		// The license violations aren't filtered.
+		assert.Equal(t, []string{"XRAY-1", "MIT"}, violations)
+		assert.Len(t, filteredResults.ScaResults[0].XrayResults[0].Licenses, 1)
+	})
+
+	t.Run("Undated included", func(t *testing.T) {
+		vulnerabilities, _ := getIssueIds(GetCvesPublishedSinceResults(results, since, true))
+		assert.Equal(t, []string{"XRAY-1", "XRAY-3", "XRAY-4", "XRAY-5"}, vulnerabilities)
+	})
+
+	// The full results are kept.
+	vulnerabilities, violations := getIssueIds(results)
+	assert.Len(t, vulnerabilities, 6)
+	assert.Len(t, violations, 3)
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"time"
+
 	"github.com/jfrog/gofrog/datastructures"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-client-go/xray/services"
@@ -11,6 +13,11 @@ type Results struct {
 	ScaResults  []ScaScanResult
 	XrayVersion string
 	ScaError    error
+	// The outcomes of the SCA scans, including the scans that failed, in the order of the scans.
+	// ScaError joins the errors of the failed scans, while the outcomes associate each of them with its working directory and technology.
+	ScaScansOutcomes []ScaScanOutcome
+	// The exclude patterns that were applied when detecting the technologies of the SCA scans.
+	EffectiveExclusions []string
 
 	ExtendedScanResults *ExtendedScanResults
 	JasError            error
@@ -35,6 +42,16 @@ func (r *Results) GetScaScannedTechnologies() []coreutils.Technology {
 	return technologies.ToSlice()
 }
 
+// Returns the outcomes of the SCA scans that failed.
+func (r *Results) GetFailedScaScans() (failedScans []ScaScanOutcome) {
+	for _, outcome := range r.ScaScansOutcomes {
+		if !outcome.Succeeded() {
+			failedScans = append(failedScans, outcome)
+		}
+	}
+	return
+}
+
 func (r *Results) IsMultipleProject() bool {
 	if len(r.ScaResults) == 0 {
 		return false
@@ -67,12 +84,81 @@ func (r *Results) IsIssuesFound() bool {
 	return false
 }
 
+// The dependency scopes that are common to several ecosystems.
+// Other scopes, such as the Maven scopes and the Gradle configurations, are reported by their names in the ecosystem.
+const (
+	ProdScope     = "prod"
+	DevScope      = "dev"
+	OptionalScope = "optional"
+	PeerScope     = "peer"
+)
+
 type ScaScanResult struct {
 	Technology            coreutils.Technology    `json:"Technology"`
 	WorkingDirectory      string                  `json:"WorkingDirectory"`
 	XrayResults           []services.ScanResponse `json:"XrayResults,omitempty"`
 	Descriptors           []string                `json:"Descriptors,omitempty"`
 	IsMultipleRootProject *bool                   `json:"IsMultipleRootProject,omitempty"`
+	// The subprocess commands that were executed directly by the audit while building the dependency trees.
+	// Recorded only if logging commands was requested.
+	ExecutedCommands []string `json:"ExecutedCommands,omitempty"`
+	// Maps the IDs of the dependencies to their scopes, such as prod/dev for npm, or compile/test for Maven.
+	// Reported by the dependency tree builders that support it, so the results can be filtered by scope.
+	DependenciesScopes map[string][]string `json:"DependenciesScopes,omitempty"`
+	// Maps the IDs of the dependencies that were fetched from git repositories to their git references.
+	// Xray knows packages by their registry coordinates, so these dependencies may be unscannable, and are flagged to make their presence visible.
+	GitSourcedDependencies map[string]string `json:"GitSourcedDependencies,omitempty"`
+	// When scanning with multiple Xray servers, maps each finding to the servers that reported it.
+	IssueReporters map[string][]string `json:"IssueReporters,omitempty"`
+	// The submitted components that Xray returned no data for, since it doesn't know them.
+	// Recorded only when the licenses are included in the scan.
+	UnscannedComponents []string `json:"UnscannedComponents,omitempty"`
+	// When identical modules are deduplicated, the working directory of the module whose scan results are shared with this module.
+	SharedScanWorkingDirectory string `json:"SharedScanWorkingDirectory,omitempty"`
+	// Maps the IDs of the direct dependencies to the locations of their declarations in the descriptors.
+	// Computed for the technologies that support it, for example to annotate the descriptors in IDEs.
+	DirectDependenciesLocations map[string]DescriptorLocation `json:"DirectDependenciesLocations,omitempty"`
+	// The IDs of the direct dependencies of the scanned project, so the findings can be reported by whether they are direct or transitive.
+	DirectDependencies []string `json:"DirectDependencies,omitempty"`
+	// Metrics of how much of the project the scan covered.
+	Coverage *ScaScanCoverage `json:"Coverage,omitempty"`
+	// Maps the IDs of the direct dependencies to how far behind their latest versions they are.
+	// Computed only if checking the staleness of the dependencies was requested, since it requires querying their registries.
+	DependenciesStaleness map[string]DependencyStaleness `json:"DependenciesStaleness,omitempty"`
+	// Maps the IDs of the CVEs of the findings to their publication dates.
+	// Recorded only when the findings are filtered by the publication dates of their CVEs, for the CVEs whose publication dates were found.
+	CvesPublicationDates map[string]time.Time `json:"CvesPublicationDates,omitempty"`
+}
+
+// Whether the SCA scan of a working directory succeeded, and the error that failed it otherwise.
+type ScaScanOutcome struct {
+	Technology       coreutils.Technology
+	WorkingDirectory string
+	// The error that failed the scan, or nil if the scan succeeded.
+	Err error
+}
+
+func (o ScaScanOutcome) Succeeded() bool {
+	return o.Err == nil
+}
+
+// How far behind its latest available version a dependency is, according to the release metadata of its registry.
+type DependencyStaleness struct {
+	LatestVersion string `json:"LatestVersion"`
+	// The release time of the used version, if the registry reports it.
+	ReleaseTime *time.Time `json:"ReleaseTime,omitempty"`
+	// The age of the used version, in days, at the time of the audit.
+	ReleaseAgeDays int `json:"ReleaseAgeDays"`
+	// The number of versions that were released after the used version, up to the latest version, and the number of days between the releases of the used version and the latest version.
+	VersionsBehind   int `json:"VersionsBehind"`
+	DaysBehindLatest int `json:"DaysBehindLatest"`
+}
+
+// The location of a dependency declaration in a descriptor file.
+type DescriptorLocation struct {
+	File string `json:"File"`
+	// The 1-based number of the declaring line.
+	Line int `json:"Line"`
 }
 
 func (s ScaScanResult) HasInformation() bool {
@@ -277,6 +277,19 @@ func PrintLicensesTable(licenses []services.License, printExtended bool, scanTyp
 	return coreutils.PrintTable(formats.ConvertToLicenseTableRow(licensesRows), "Licenses", "No licenses were found", printExtended)
 }
 
+// Prints the components whose licenses aren't in the allowed licenses. This local policy is evaluated on the licenses that Xray returned, regardless of the Xray watches.
+func PrintAllowedLicensesViolationsTable(licenses []services.License, allowedLicenses []string, printExtended bool, scanType services.ScanType) error {
+	licensesRows, err := PrepareLicenses(licenses)
+	if err != nil {
+		return err
+	}
+	violatedLicensesRows := GetViolatedLicenses(allowedLicenses, licensesRows)
+	if scanType == services.Binary {
+		return coreutils.PrintTable(formats.ConvertToLicenseScanTableRow(violatedLicensesRows), "Allowed Licenses Policy Violations", "All the licenses are allowed", printExtended)
+	}
+	return coreutils.PrintTable(formats.ConvertToLicenseTableRow(violatedLicensesRows), "Allowed Licenses Policy Violations", "All the licenses are allowed", printExtended)
+}
+
 func PrepareLicenses(licenses []services.License) ([]formats.LicenseRow, error) {
 	var licensesRows []formats.LicenseRow
 	for _, license := range licenses {
@@ -527,8 +540,12 @@ var packageTypes = map[string]string{
 	"pip":      "Python",
 	"pypi":     "Python",
 	"composer": "Composer",
+	"conan":    "Conan",
 	"go":       "Go",
 	"alpine":   "Alpine",
+	"helm":     "Helm",
+	"gem":      "RubyGems",
+	"swift":    "Swift",
 }
 
 // SplitComponentId splits a Xray component ID to the component name, version and package type.
@@ -51,6 +51,8 @@ func TestSplitComponentId(t *testing.T) {
 		{"npm://mocha:2.4.5", "mocha", "2.4.5", "npm"},
 		{"pip://raven:5.13.0", "raven", "5.13.0", "Python"},
 		{"composer://nunomaduro/collision:1.1", "nunomaduro/collision", "1.1", "Composer"},
+		{"conan://zlib:1.2.13", "zlib", "1.2.13", "Conan"},
+		{"swift://github.com/apple/swift-nio:2.50.0", "github.com/apple/swift-nio", "2.50.0", "Swift"},
 		{"go://github.com/ethereum/go-ethereum:1.8.2", "github.com/ethereum/go-ethereum", "1.8.2", "Go"},
 		{"alpine://3.7:htop:2.0.2-r0", "3.7:htop", "2.0.2-r0", "Alpine"},
 		{"invalid-component-id:1.0.0", "invalid-component-id:1.0.0", "", ""},
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jfrog/jfrog-cli-core/v2/common/format"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
@@ -45,6 +46,16 @@ type ResultsWriter struct {
 	scanType services.ScanType
 	// Messages - Option array of messages, to be displayed if the format is Table
 	messages []string
+	// SeverityMapping - Maps Xray severities to the SARIF levels of their issues, overriding the default mapping.
+	severityMapping map[string]string
+	// TransitiveOnly - If true, the findings of the direct dependencies are not reported. They are still available in the full scan results.
+	transitiveOnly bool
+	// AllowedLicenses - The licenses that the components may have. Components with other licenses are reported as violations of this local policy, regardless of the Xray watches.
+	allowedLicenses []string
+	// CvesPublishedSince - If set, only the vulnerabilities and violations with CVEs that were published since this time are reported. They are all still available in the full scan results.
+	cvesPublishedSince time.Time
+	// IncludeUndatedCves - If true, the findings whose CVEs have no known publication dates are reported when filtering by the publication dates of the CVEs.
+	includeUndatedCves bool
 }
 
 func NewResultsWriter(scanResults *Results) *ResultsWriter {
@@ -93,6 +104,31 @@ func (rw *ResultsWriter) SetExtraMessages(messages []string) *ResultsWriter {
 
 // PrintScanResults prints the scan results in the specified format.
 // Note that errors are printed only with SimpleJson format.
+func (rw *ResultsWriter) SetSeverityMapping(severityMapping map[string]string) *ResultsWriter {
+	rw.severityMapping = severityMapping
+	return rw
+}
+
+func (rw *ResultsWriter) SetTransitiveOnly(transitiveOnly bool) *ResultsWriter {
+	rw.transitiveOnly = transitiveOnly
+	return rw
+}
+
+func (rw *ResultsWriter) SetAllowedLicenses(allowedLicenses []string) *ResultsWriter {
+	rw.allowedLicenses = allowedLicenses
+	return rw
+}
+
+func (rw *ResultsWriter) SetCvesPublishedSince(cvesPublishedSince time.Time) *ResultsWriter {
+	rw.cvesPublishedSince = cvesPublishedSince
+	return rw
+}
+
+func (rw *ResultsWriter) SetIncludeUndatedCves(includeUndatedCves bool) *ResultsWriter {
+	rw.includeUndatedCves = includeUndatedCves
+	return rw
+}
+
 func (rw *ResultsWriter) PrintScanResults() error {
 	switch rw.format {
 	case format.Table:
@@ -104,27 +140,47 @@ func (rw *ResultsWriter) PrintScanResults() error {
 		}
 		return PrintJson(jsonTable)
 	case format.Json:
-		return PrintJson(rw.results.GetScaScansXrayResults())
+		return PrintJson(rw.reportedResults().GetScaScansXrayResults())
 	case format.Sarif:
-		return PrintSarif(rw.results, rw.isMultipleRoots, rw.includeLicenses)
+		return printSarif(rw.reportedResults(), rw.isMultipleRoots, rw.includeLicenses, rw.allowedLicenses, rw.severityMapping)
 	}
 	return nil
 }
+
+// Returns the results to report, which are filtered to the findings of the transitive dependencies, and to the findings of recently published CVEs, if requested.
+func (rw *ResultsWriter) reportedResults() *Results {
+	reportedResults := rw.results
+	if rw.transitiveOnly {
+		reportedResults = GetTransitiveOnlyResults(reportedResults)
+	}
+	if !rw.cvesPublishedSince.IsZero() {
+		reportedResults = GetCvesPublishedSinceResults(reportedResults, rw.cvesPublishedSince, rw.includeUndatedCves)
+	}
+	return reportedResults
+}
+
 func (rw *ResultsWriter) printScanResultsTables() (err error) {
 	printMessages(rw.messages)
-	violations, vulnerabilities, licenses := SplitScanResults(rw.results.ScaResults)
+	reportedResults := rw.reportedResults()
+	violations, vulnerabilities, licenses := SplitScanResults(reportedResults.ScaResults)
 	if rw.results.IsIssuesFound() {
 		var resultsPath string
 		if resultsPath, err = writeJsonResults(rw.results); err != nil {
 			return
 		}
 		printMessage(coreutils.PrintTitle("The full scan results are available here: ") + coreutils.PrintLink(resultsPath))
+		if rw.transitiveOnly {
+			printMessage("Only the issues of transitive dependencies are shown. The issues of direct dependencies are available in the full scan results.")
+		}
+		if !rw.cvesPublishedSince.IsZero() {
+			printMessage(fmt.Sprintf("Only the issues of CVEs that were published since %s are shown. The other issues are available in the full scan results.", rw.cvesPublishedSince.Format(time.DateOnly)))
+		}
 	}
 	log.Output()
 	if rw.includeVulnerabilities {
-		err = PrintVulnerabilitiesTable(vulnerabilities, rw.results, rw.isMultipleRoots, rw.printExtended, rw.scanType)
+		err = PrintVulnerabilitiesTable(vulnerabilities, reportedResults, rw.isMultipleRoots, rw.printExtended, rw.scanType)
 	} else {
-		err = PrintViolationsTable(violations, rw.results, rw.isMultipleRoots, rw.printExtended, rw.scanType)
+		err = PrintViolationsTable(violations, reportedResults, rw.isMultipleRoots, rw.printExtended, rw.scanType)
 	}
 	if err != nil {
 		return
@@ -134,6 +190,11 @@ func (rw *ResultsWriter) printScanResultsTables() (err error) {
 			return
 		}
 	}
+	if len(rw.allowedLicenses) > 0 {
+		if err = PrintAllowedLicensesViolationsTable(licenses, rw.allowedLicenses, rw.printExtended, rw.scanType); err != nil {
+			return
+		}
+	}
 	if err = PrintSecretsTable(rw.results.ExtendedScanResults.SecretsScanResults, rw.results.ExtendedScanResults.EntitledForJas); err != nil {
 		return
 	}
@@ -157,11 +218,17 @@ func printMessage(message string) {
 }
 
 func GenereateSarifReportFromResults(results *Results, isMultipleRoots, includeLicenses bool, allowedLicenses []string) (report *sarif.Report, err error) {
+	return GenerateSarifReportWithSeverityMapping(results, isMultipleRoots, includeLicenses, allowedLicenses, nil)
+}
+
+// Generates a SARIF report from the results, in which the levels of the Xray issues are set according to the given mapping of Xray severities to SARIF levels.
+// Severities that are not in the mapping get the default level. The levels of the Advanced Security issues are set by the scanners, and are not affected.
+func GenerateSarifReportWithSeverityMapping(results *Results, isMultipleRoots, includeLicenses bool, allowedLicenses []string, severityMapping map[string]string) (report *sarif.Report, err error) {
 	report, err = NewReport()
 	if err != nil {
 		return
 	}
-	xrayRun, err := convertXrayResponsesToSarifRun(results, isMultipleRoots, includeLicenses, allowedLicenses)
+	xrayRun, err := convertXrayResponsesToSarifRun(results, isMultipleRoots, includeLicenses, allowedLicenses, severityMapping)
 	if err != nil {
 		return
 	}
@@ -183,7 +250,7 @@ func ConvertSarifReportToString(report *sarif.Report) (sarifStr string, err erro
 	return clientUtils.IndentJson(out), nil
 }
 
-func convertXrayResponsesToSarifRun(results *Results, isMultipleRoots, includeLicenses bool, allowedLicenses []string) (run *sarif.Run, err error) {
+func convertXrayResponsesToSarifRun(results *Results, isMultipleRoots, includeLicenses bool, allowedLicenses []string, severityMapping map[string]string) (run *sarif.Run, err error) {
 	xrayJson, err := ConvertXrayScanToSimpleJson(results, isMultipleRoots, includeLicenses, true, allowedLicenses)
 	if err != nil {
 		return
@@ -191,7 +258,7 @@ func convertXrayResponsesToSarifRun(results *Results, isMultipleRoots, includeLi
 	xrayRun := sarif.NewRunWithInformationURI("JFrog Xray SCA", BaseDocumentationURL+"sca")
 	xrayRun.Tool.Driver.Version = &results.XrayVersion
 	if len(xrayJson.Vulnerabilities) > 0 || len(xrayJson.SecurityViolations) > 0 || len(xrayJson.LicensesViolations) > 0 {
-		if err = extractXrayIssuesToSarifRun(xrayRun, xrayJson); err != nil {
+		if err = extractXrayIssuesToSarifRun(xrayRun, xrayJson, severityMapping); err != nil {
 			return
 		}
 	}
@@ -199,26 +266,26 @@ func convertXrayResponsesToSarifRun(results *Results, isMultipleRoots, includeLi
 	return
 }
 
-func extractXrayIssuesToSarifRun(run *sarif.Run, xrayJson formats.SimpleJsonResults) error {
+func extractXrayIssuesToSarifRun(run *sarif.Run, xrayJson formats.SimpleJsonResults, severityMapping map[string]string) error {
 	for _, vulnerability := range xrayJson.Vulnerabilities {
-		if err := addXrayCveIssueToSarifRun(vulnerability, run); err != nil {
+		if err := addXrayCveIssueToSarifRun(vulnerability, run, severityMapping); err != nil {
 			return err
 		}
 	}
 	for _, violation := range xrayJson.SecurityViolations {
-		if err := addXrayCveIssueToSarifRun(violation, run); err != nil {
+		if err := addXrayCveIssueToSarifRun(violation, run, severityMapping); err != nil {
 			return err
 		}
 	}
 	for _, license := range xrayJson.LicensesViolations {
-		if err := addXrayLicenseViolationToSarifRun(license, run); err != nil {
+		if err := addXrayLicenseViolationToSarifRun(license, run, severityMapping); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func addXrayCveIssueToSarifRun(issue formats.VulnerabilityOrViolationRow, run *sarif.Run) (err error) {
+func addXrayCveIssueToSarifRun(issue formats.VulnerabilityOrViolationRow, run *sarif.Run, severityMapping map[string]string) (err error) {
 	maxCveScore, err := findMaxCVEScore(issue.Cves)
 	if err != nil {
 		return
@@ -237,7 +304,7 @@ func addXrayCveIssueToSarifRun(issue formats.VulnerabilityOrViolationRow, run *s
 		cveId,
 		issue.ImpactedDependencyName,
 		issue.ImpactedDependencyVersion,
-		issue.Severity,
+		ConvertToSarifLevelWithMapping(issue.Severity, severityMapping),
 		maxCveScore,
 		issue.Summary,
 		getXrayIssueSarifHeadline(issue.ImpactedDependencyName, issue.ImpactedDependencyVersion, cveId),
@@ -249,7 +316,7 @@ func addXrayCveIssueToSarifRun(issue formats.VulnerabilityOrViolationRow, run *s
 	return
 }
 
-func addXrayLicenseViolationToSarifRun(license formats.LicenseRow, run *sarif.Run) (err error) {
+func addXrayLicenseViolationToSarifRun(license formats.LicenseRow, run *sarif.Run, severityMapping map[string]string) (err error) {
 	formattedDirectDependencies, err := getDirectDependenciesFormatted(license.Components)
 	if err != nil {
 		return
@@ -258,7 +325,7 @@ func addXrayLicenseViolationToSarifRun(license formats.LicenseRow, run *sarif.Ru
 		license.LicenseKey,
 		license.ImpactedDependencyName,
 		license.ImpactedDependencyVersion,
-		license.Severity,
+		ConvertToSarifLevelWithMapping(license.Severity, severityMapping),
 		MissingCveScore,
 		getLicenseViolationSummary(license.ImpactedDependencyName, license.ImpactedDependencyVersion, license.LicenseKey),
 		getXrayLicenseSarifHeadline(license.ImpactedDependencyName, license.ImpactedDependencyVersion, license.LicenseKey),
@@ -270,7 +337,7 @@ func addXrayLicenseViolationToSarifRun(license formats.LicenseRow, run *sarif.Ru
 	return
 }
 
-func addXrayIssueToSarifRun(issueId, impactedDependencyName, impactedDependencyVersion, severity, severityScore, summary, title, markdownDescription string, components []formats.ComponentRow, location *sarif.Location, run *sarif.Run) {
+func addXrayIssueToSarifRun(issueId, impactedDependencyName, impactedDependencyVersion, level, severityScore, summary, title, markdownDescription string, components []formats.ComponentRow, location *sarif.Location, run *sarif.Run) {
 	// Add rule if not exists
 	ruleId := getXrayIssueSarifRuleId(impactedDependencyName, impactedDependencyVersion, issueId)
 	if rule, _ := run.GetRuleById(ruleId); rule == nil {
@@ -279,7 +346,7 @@ func addXrayIssueToSarifRun(issueId, impactedDependencyName, impactedDependencyV
 	// Add result for each component
 	for _, directDependency := range components {
 		msg := getXrayIssueSarifHeadline(directDependency.Name, directDependency.Version, issueId)
-		if result := run.CreateResultForRule(ruleId).WithMessage(sarif.NewTextMessage(msg)).WithLevel(ConvertToSarifLevel(severity)); location != nil {
+		if result := run.CreateResultForRule(ruleId).WithMessage(sarif.NewTextMessage(msg)).WithLevel(level); location != nil {
 			result.AddLocation(location)
 		}
 	}
@@ -381,7 +448,7 @@ func GetViolatedLicenses(allowedLicenses []string, licenses []formats.LicenseRow
 }
 
 func (rw *ResultsWriter) convertScanToSimpleJson() (formats.SimpleJsonResults, error) {
-	jsonTable, err := ConvertXrayScanToSimpleJson(rw.results, rw.isMultipleRoots, rw.includeLicenses, false, nil)
+	jsonTable, err := ConvertXrayScanToSimpleJson(rw.reportedResults(), rw.isMultipleRoots, rw.includeLicenses, false, rw.allowedLicenses)
 	if err != nil {
 		return formats.SimpleJsonResults{}, err
 	}
@@ -534,7 +601,11 @@ func PrintJson(output interface{}) error {
 }
 
 func PrintSarif(results *Results, isMultipleRoots, includeLicenses bool) error {
-	sarifReport, err := GenereateSarifReportFromResults(results, isMultipleRoots, includeLicenses, nil)
+	return printSarif(results, isMultipleRoots, includeLicenses, nil, nil)
+}
+
+func printSarif(results *Results, isMultipleRoots, includeLicenses bool, allowedLicenses []string, severityMapping map[string]string) error {
+	sarifReport, err := GenerateSarifReportWithSeverityMapping(results, isMultipleRoots, includeLicenses, allowedLicenses, severityMapping)
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,7 @@ package utils
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
@@ -377,3 +378,76 @@ func TestConvertXrayScanToSimpleJson(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSarifReportWithSeverityMapping(t *testing.T) {
+	results := NewAuditResults()
+	results.ScaResults = []ScaScanResult{{
+		Technology: coreutils.Npm,
+		XrayResults: []services.ScanResponse{{
+			Vulnerabilities: []services.Vulnerability{
+				{IssueId: "XRAY-1", Severity: "Critical", Components: map[string]services.Component{"npm://lodash:4.17.0": {ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "root"}, {ComponentId: "npm://lodash:4.17.0"}}}}}},
+				{IssueId: "XRAY-2", Severity: "High", Components: map[string]services.Component{"npm://minimist:1.2.0": {ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "root"}, {ComponentId: "npm://minimist:1.2.0"}}}}}},
+				{IssueId: "XRAY-3", Severity: "Low", Components: map[string]services.Component{"npm://axios:0.21.0": {ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "root"}, {ComponentId: "npm://axios:0.21.0"}}}}}},
+			},
+		}},
+	}}
+
+	testCases := []struct {
+		name            string
+		severityMapping map[string]string
+		expectedLevels  map[string]string
+	}{
+		{
+			name:           "Default mapping",
+			expectedLevels: map[string]string{"XRAY-1": "error", "XRAY-2": "error", "XRAY-3": "note"},
+		},
+		{
+			name:            "Overridden mapping",
+			severityMapping: map[string]string{"high": "warning", "LOW": "none"},
+			expectedLevels:  map[string]string{"XRAY-1": "error", "XRAY-2": "warning", "XRAY-3": "none"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := GenerateSarifReportWithSeverityMapping(results, false, false, nil, tc.severityMapping)
+			assert.NoError(t, err)
+			levels := map[string]string{}
+			for _, result := range report.Runs[0].Results {
+				levels[strings.Split(*result.RuleID, "_")[0]] = *result.Level
+			}
+			assert.Equal(t, tc.expectedLevels, levels)
+		})
+	}
+}
+
+func TestConvertScanToSimpleJsonWithAllowedLicenses(t *testing.T) {
+	results := NewAuditResults()
+	results.ScaResults = []ScaScanResult{{XrayResults: []services.ScanResponse{{
+		Licenses: []services.License{
+			{Key: "MIT", Components: map[string]services.Component{"npm://lodash:4.17.21": {}}},
+			{Key: "GPL-3.0", Components: map[string]services.Component{"npm://copyleft:1.0.0": {}}},
+		},
+	}}}}
+	testCases := []struct {
+		name                       string
+		allowedLicenses            []string
+		expectedViolatedComponents []string
+	}{
+		{name: "No allowed licenses", allowedLicenses: nil},
+		{name: "All licenses allowed", allowedLicenses: []string{"MIT", "GPL-3.0"}},
+		{name: "Disallowed license", allowedLicenses: []string{"MIT"}, expectedViolatedComponents: []string{"copyleft"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jsonTable, err := NewResultsWriter(results).SetAllowedLicenses(tc.allowedLicenses).convertScanToSimpleJson()
+			assert.NoError(t, err)
+			var violatedComponents []string
+			for _, violation := range jsonTable.LicensesViolations {
+				violatedComponents = append(violatedComponents, violation.ImpactedDependencyName)
+			}
+			assert.Equal(t, tc.expectedViolatedComponents, violatedComponents)
+			// The licenses themselves are reported only if requested.
+			assert.Empty(t, jsonTable.Licenses)
+		})
+	}
+}
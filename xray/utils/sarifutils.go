@@ -233,6 +233,17 @@ func ConvertToSarifLevel(severity string) string {
 	return string(noneLevel)
 }
 
+// Converts the severity to a SARIF level according to the given mapping of severities to levels, in which the severities are case-insensitive.
+// Severities that are not in the mapping are converted by the default mapping.
+func ConvertToSarifLevelWithMapping(severity string, severityMapping map[string]string) string {
+	for mappedSeverity, level := range severityMapping {
+		if strings.EqualFold(mappedSeverity, severity) {
+			return level
+		}
+	}
+	return ConvertToSarifLevel(severity)
+}
+
 func IsApplicableResult(result *sarif.Result) bool {
 	return !(result.Kind != nil && *result.Kind == "pass")
 }
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"golang.org/x/exp/slices"
+)
+
+// Returns a copy of the results, in which the SCA findings are filtered to the components that are not direct dependencies of the scanned projects.
+// The direct dependencies of each scan are taken from the scan's recorded direct dependencies. The given results are not modified.
+func GetTransitiveOnlyResults(results *Results) *Results {
+	filteredResults := *results
+	filteredResults.ScaResults = make([]ScaScanResult, 0, len(results.ScaResults))
+	for _, scan := range results.ScaResults {
+		var filteredXrayResults []services.ScanResponse
+		for _, xrayResult := range scan.XrayResults {
+			xrayResult.Violations = filterTransitiveViolations(xrayResult.Violations, scan.DirectDependencies)
+			xrayResult.Vulnerabilities = filterTransitiveVulnerabilities(xrayResult.Vulnerabilities, scan.DirectDependencies)
+			xrayResult.Licenses = filterTransitiveLicenses(xrayResult.Licenses, scan.DirectDependencies)
+			filteredXrayResults = append(filteredXrayResults, xrayResult)
+		}
+		scan.XrayResults = filteredXrayResults
+		filteredResults.ScaResults = append(filteredResults.ScaResults, scan)
+	}
+	return &filteredResults
+}
+
+func filterTransitiveViolations(violations []services.Violation, directDependencies []string) []services.Violation {
+	var filteredViolations []services.Violation
+	for _, violation := range violations {
+		if violation.Components = getTransitiveComponents(violation.Components, directDependencies); len(violation.Components) > 0 {
+			filteredViolations = append(filteredViolations, violation)
+		}
+	}
+	return filteredViolations
+}
+
+func filterTransitiveVulnerabilities(vulnerabilities []services.Vulnerability, directDependencies []string) []services.Vulnerability {
+	var filteredVulnerabilities []services.Vulnerability
+	for _, vulnerability := range vulnerabilities {
+		if vulnerability.Components = getTransitiveComponents(vulnerability.Components, directDependencies); len(vulnerability.Components) > 0 {
+			filteredVulnerabilities = append(filteredVulnerabilities, vulnerability)
+		}
+	}
+	return filteredVulnerabilities
+}
+
+func filterTransitiveLicenses(licenses []services.License, directDependencies []string) []services.License {
+	var filteredLicenses []services.License
+	for _, license := range licenses {
+		if license.Components = getTransitiveComponents(license.Components, directDependencies); len(license.Components) > 0 {
+			filteredLicenses = append(filteredLicenses, license)
+		}
+	}
+	return filteredLicenses
+}
+
+// Returns the impacted components of a finding that are not direct dependencies.
+func getTransitiveComponents(components map[string]services.Component, directDependencies []string) map[string]services.Component {
+	transitiveComponents := make(map[string]services.Component)
+	for componentId, component := range components {
+		if !slices.Contains(directDependencies, componentId) {
+			transitiveComponents[componentId] = component
+		}
+	}
+	return transitiveComponents
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransitiveOnlyResults(t *testing.T) {
+	direct := map[string]services.Component{"npm://express:4.17.1": {}}
+	transitive := map[string]services.Component{"npm://qs:6.7.0": {}}
+	directAndTransitive := map[string]services.Component{"npm://express:4.17.1": {}, "npm://qs:6.7.0": {}}
+	results := &Results{ScaResults: []ScaScanResult{
+		{
+			Technology:         coreutils.Npm,
+			DirectDependencies: []string{"npm://express:4.17.1"},
+			XrayResults: []services.ScanResponse{{
+				Vulnerabilities: []services.Vulnerability{
+					{IssueId: "XRAY-1", Components: direct},
+					{IssueId: "XRAY-2", Components: transitive},
+					{IssueId: "XRAY-3", Components: directAndTransitive},
+				},
+				Violations: []services.Violation{
+					{IssueId: "XRAY-1", Components: direct},
+					{IssueId: "XRAY-2", Components: transitive},
+				},
+				Licenses: []services.License{
+					{Key: "MIT", Components: directAndTransitive},
+					{Key: "Apache-2.0", Components: direct},
+				},
+			}},
+		},
+		{
+			// Without recorded direct dependencies, all the findings are reported.
+			Technology: coreutils.Go,
+			XrayResults: []services.ScanResponse{{
+				Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-4", Components: map[string]services.Component{"go://golang.org/x/text:v0.3.3": {}}}},
+			}},
+		},
+	}}
+
+	filteredResults := GetTransitiveOnlyResults(results)
+	assert.Len(t, filteredResults.ScaResults, 2)
+	npmResults := filteredResults.ScaResults[0].XrayResults[0]
+	assert.Equal(t, []services.Vulnerability{
+		{IssueId: "XRAY-2", Components: transitive},
+		{IssueId: "XRAY-3", Components: transitive},
+	}, npmResults.Vulnerabilities)
+	assert.Equal(t, []services.Violation{{IssueId: "XRAY-2", Components: transitive}}, npmResults.Violations)
+	assert.Equal(t, []services.License{{Key: "MIT", Components: transitive}}, npmResults.Licenses)
+	assert.Equal(t, results.ScaResults[1].XrayResults, filteredResults.ScaResults[1].XrayResults)
+
+	// The direct findings are still available in the full results.
+	fullNpmResults := results.ScaResults[0].XrayResults[0]
+	assert.Len(t, fullNpmResults.Vulnerabilities, 3)
+	assert.Equal(t, directAndTransitive, fullNpmResults.Vulnerabilities[2].Components)
+	assert.Len(t, fullNpmResults.Violations, 2)
+	assert.Len(t, fullNpmResults.Licenses, 2)
+}
@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"context"
+	"time"
+
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	clientconfig "github.com/jfrog/jfrog-client-go/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/xray"
 )
 
@@ -31,3 +35,16 @@ func CreateXrayServiceManagerAndGetVersion(serviceDetails *config.ServerDetails)
 	}
 	return xrayManager, xrayVersion, nil
 }
+
+// Waits the given duration before a request to Xray is retried.
+// Returns the error of the given context if it's done before the wait is over, so the request isn't retried.
+func WaitBeforeRetry(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return errorutils.CheckError(ctx.Err())
+	}
+}